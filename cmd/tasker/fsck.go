@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/fsck"
+	"github.com/imarrche/tasker/internal/logger"
+)
+
+// fsckUsage describes fsck subcommands.
+const fsckUsage = `usage: tasker fsck [flags] [command]
+
+Commands:
+  check    report integrity issues of boards, the default
+  repair   report and repair integrity issues of boards`
+
+// sqlConnector is implemented by SQL stores which can be opened without migrating schema.
+type sqlConnector interface {
+	Connect() error
+	DB() *sql.DB
+}
+
+// runFsck checks the configured store and returns exit code, 1 is returned if
+// unrepaired issues are found.
+func runFsck(name string, args []string, stdout, stderr io.Writer) int {
+	c, args, err := config.Load(name, args)
+	if err == flag.ErrHelp {
+		fmt.Fprintln(stderr, fsckUsage)
+		return 0
+	} else if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	repair, err := parseFsckCommand(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s\n\n%s\n", err, fsckUsage)
+		return 2
+	}
+
+	// SQL stores aren't migrated and are checked with queries of the initial schema, so
+	// boards can be repaired before migrations adding constraints they violate.
+	s := newStore(c, logger.New(stderr, logger.WarnLevel, logger.TextFormat))
+	conn, isSQL := s.(sqlConnector)
+	if isSQL {
+		err = conn.Connect()
+	} else {
+		err = s.Open()
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer s.Close()
+	fs := fsck.FromStore(s)
+	if isSQL {
+		fs = fsck.NewSQLStore(conn.DB(), c.Storage.Driver)
+	}
+
+	r, err := fsck.Check(context.Background(), fs, repair)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	repaired := 0
+	for _, i := range r.Issues {
+		fmt.Fprintln(stdout, i)
+		if i.Repaired {
+			repaired++
+		}
+	}
+	fmt.Fprintf(stdout, "%d projects checked, %d issues found, %d repaired\n", r.Projects, len(r.Issues), repaired)
+	if !r.OK() {
+		return 1
+	}
+
+	return 0
+}
+
+// parseFsckCommand parses fsck subcommand and returns whether issues must be repaired.
+func parseFsckCommand(args []string) (bool, error) {
+	if len(args) > 1 {
+		return false, fmt.Errorf("unexpected argument %q", args[1])
+	}
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "check":
+		return false, nil
+	case "repair":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q", args[0])
+	}
+}
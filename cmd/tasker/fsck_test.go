@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store/sqlite"
+)
+
+func TestRunFsck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tasker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tasker.db")
+	flags := []string{"-storage-driver", "sqlite", "-sqlite-path", path}
+
+	s := sqlite.New(config.SQLite{Path: path})
+	require.NoError(t, s.Open())
+	_, err = s.Projects().Create(context.Background(), model.Project{Name: "Project"})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	testcases := []struct {
+		name      string
+		args      []string
+		expCode   int
+		expOutput string
+		expError  string
+	}{
+		{
+			name:    "issues are reported",
+			args:    []string{},
+			expCode: 1,
+			expOutput: "project 1: no_columns: project has no columns\n" +
+				"1 projects checked, 1 issues found, 0 repaired\n",
+		},
+		{
+			name:    "issues are repaired",
+			args:    []string{"repair"},
+			expCode: 0,
+			expOutput: "project 1: no_columns: project has no columns (repaired)\n" +
+				"1 projects checked, 1 issues found, 1 repaired\n",
+		},
+		{
+			name:      "no issues are left",
+			args:      []string{"check"},
+			expCode:   0,
+			expOutput: "1 projects checked, 0 issues found, 0 repaired\n",
+		},
+		{
+			name:     "command is unknown",
+			args:     []string{"fix"},
+			expCode:  2,
+			expError: `unknown command "fix"`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := runFsck("tasker fsck", append(flags, tc.args...), &stdout, &stderr)
+
+			assert.Equal(t, tc.expCode, code)
+			assert.Equal(t, tc.expOutput, stdout.String())
+			assert.Contains(t, stderr.String(), tc.expError)
+		})
+	}
+}
+
+func TestRunFsck_InitialSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tasker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tasker.db")
+	flags := []string{"-storage-driver", "sqlite", "-sqlite-path", path}
+
+	// The board violates constraints of the next migration.
+	s := sqlite.New(config.SQLite{Path: path})
+	require.NoError(t, s.Connect())
+	m, err := s.Migrator()
+	require.NoError(t, err)
+	require.NoError(t, m.Migrate(20201227073627))
+	for _, query := range []string{
+		`INSERT INTO projects (id, name, description) VALUES (1, 'Project', '');`,
+		`INSERT INTO columns (id, name, "index", project_id) VALUES (1, 'Column', 1, 1);`,
+		`INSERT INTO tasks (id, name, description, "index", column_id) VALUES (1, 'Task 1', '', 1, 1);`,
+		`INSERT INTO tasks (id, name, description, "index", column_id) VALUES (2, 'Task 2', '', 1, 1);`,
+		`INSERT INTO projects (id, name, description) VALUES (2, 'Empty', '');`,
+	} {
+		_, err = s.DB().Exec(query)
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Close())
+
+	var stdout, stderr bytes.Buffer
+	code := runFsck("tasker fsck", append(flags, "repair"), &stdout, &stderr)
+
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, "project 1 column 1: duplicate_index: tasks 1, 2 share index 1 (repaired)\n"+
+		"project 2: no_columns: project has no columns (repaired)\n"+
+		"2 projects checked, 2 issues found, 2 repaired\n", stdout.String())
+
+	// The repaired board is migrated to the latest schema.
+	s = sqlite.New(config.SQLite{Path: path})
+	require.NoError(t, s.Open())
+	defer s.Close()
+	ts, err := s.Tasks().GetByColumnID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, []int{ts[0].Index, ts[1].Index})
+}
@@ -2,29 +2,66 @@
 package main
 
 import (
-	"log"
+	"flag"
+	"fmt"
 	"os"
 
 	"github.com/imarrche/tasker/internal/api"
 	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/inmem"
 	"github.com/imarrche/tasker/internal/store/pg"
+	"github.com/imarrche/tasker/internal/store/sqlite"
 )
 
 func main() {
-	// Main logger.
-	l := log.New(os.Stdout, "", log.LstdFlags)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[0]+" migrate", os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fsck" {
+		os.Exit(runFsck(os.Args[0]+" fsck", os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	// Reading config from file, environment and flags.
+	c, args, err := config.Load(os.Args[0], os.Args[1:])
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	} else if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected argument %q\n", args[0])
+		os.Exit(2)
+	}
 
-	// Reading config from environment.
-	c := config.New()
+	// Main logger, level and format are already validated with config.
+	level, _ := logger.ParseLevel(c.Log.Level)
+	format, _ := logger.ParseFormat(c.Log.Format)
+	l := logger.New(os.Stdout, level, format)
 
-	// Opening PostgreSQL store.
-	s := pg.New(c.PostgreSQL)
+	// Opening the store selected with storage driver.
+	s := newStore(c, l)
 	if err := s.Open(); err != nil {
-		l.Fatal(err)
+		l.Fatal(err.Error())
 	}
 
 	// Starting the server.
 	if err := api.NewServer(l, c, s).Start(); err != nil {
-		l.Fatal(err)
+		l.Fatal(err.Error())
+	}
+}
+
+// newStore creates the store for the configured storage driver.
+func newStore(c *config.Config, l *logger.Logger) store.Store {
+	switch c.Storage.Driver {
+	case config.DriverInMemory:
+		s := inmem.New(c.InMemory)
+		s.SetLogger(l)
+		return s
+	case config.DriverSQLite:
+		return sqlite.New(c.SQLite)
+	default:
+		return pg.New(c.PostgreSQL)
 	}
 }
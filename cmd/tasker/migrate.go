@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
+)
+
+// migrateUsage describes migrate subcommands.
+const migrateUsage = `usage: tasker migrate [flags] <command>
+
+Commands:
+  up [N]     apply all or N next migrations
+  down [N]   roll back N migrations, 1 by default
+  version    print the current schema version
+  force V    set schema version without running migrations, clearing dirty state`
+
+// migrator is implemented by stores managing schema migrations.
+type migrator interface {
+	Connect() error
+	Migrator() (*migrate.Migrate, error)
+	Close() error
+}
+
+// migrateCommand is a migrate subcommand applied to the schema.
+type migrateCommand func(m *migrate.Migrate) error
+
+// runMigrate runs migrate command for the configured store and returns exit code.
+func runMigrate(name string, args []string, stdout, stderr io.Writer) int {
+	c, args, err := config.Load(name, args)
+	if err == flag.ErrHelp {
+		fmt.Fprintln(stderr, migrateUsage)
+		return 0
+	} else if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	cmd, err := parseMigrateCommand(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s\n\n%s\n", err, migrateUsage)
+		return 2
+	}
+	s, ok := newStore(c, logger.New(stderr, logger.WarnLevel, logger.TextFormat)).(migrator)
+	if !ok {
+		fmt.Fprintf(stderr, "storage driver %q doesn't have schema migrations\n", c.Storage.Driver)
+		return 2
+	}
+
+	if err := s.Connect(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer s.Close()
+	m, err := s.Migrator()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if err := cmd(m); err == migrate.ErrNoChange {
+		fmt.Fprintln(stdout, "no change")
+	} else if errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(stderr, "no migrations to apply")
+		return 1
+	} else if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := printVersion(m, stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// parseMigrateCommand parses migrate subcommand with its arguments.
+func parseMigrateCommand(args []string) (migrateCommand, error) {
+	if len(args) == 0 {
+		return nil, errors.New("command is required")
+	}
+
+	name, args := args[0], args[1:]
+	switch name {
+	case "up":
+		n, err := parseSteps(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return func(m *migrate.Migrate) error { return m.Up() }, nil
+		}
+		return func(m *migrate.Migrate) error { return m.Steps(n) }, nil
+	case "down":
+		n, err := parseSteps(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return func(m *migrate.Migrate) error { return m.Steps(-n) }, nil
+	case "version":
+		if len(args) != 0 {
+			return nil, fmt.Errorf("version: unexpected argument %q", args[0])
+		}
+		return func(m *migrate.Migrate) error { return nil }, nil
+	case "force":
+		if len(args) != 1 {
+			return nil, errors.New("force: version is required")
+		}
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("force: %q is not a version", args[0])
+		}
+		return func(m *migrate.Migrate) error { return m.Force(v) }, nil
+	default:
+		return nil, fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// parseSteps parses optional positive number of migrations.
+func parseSteps(args []string, def int) (int, error) {
+	switch len(args) {
+	case 0:
+		return def, nil
+	case 1:
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("%q is not a positive number of migrations", args[0])
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("unexpected argument %q", args[1])
+	}
+}
+
+// printVersion prints the current schema version.
+func printVersion(m *migrate.Migrate, w io.Writer) error {
+	v, dirty, err := m.Version()
+	if err == migrate.ErrNilVersion {
+		fmt.Fprintln(w, "version: none")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if dirty {
+		fmt.Fprintf(w, "version: %d (dirty)\n", v)
+	} else {
+		fmt.Fprintf(w, "version: %d\n", v)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/schema"
+)
+
+func TestRunMigrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tasker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	flags := []string{"-storage-driver", "sqlite", "-sqlite-path", filepath.Join(dir, "tasker.db")}
+	versions, err := schema.Versions(schema.SQLite, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, previous := versions[len(versions)-1], versions[len(versions)-2]
+
+	testcases := []struct {
+		name      string
+		args      []string
+		expCode   int
+		expOutput string
+		expError  string
+	}{
+		{
+			name:      "version of empty database",
+			args:      []string{"version"},
+			expCode:   0,
+			expOutput: "version: none\n",
+		},
+		{
+			name:      "migrations are applied",
+			args:      []string{"up"},
+			expCode:   0,
+			expOutput: fmt.Sprintf("version: %d\n", latest),
+		},
+		{
+			name:      "no migrations are left to apply",
+			args:      []string{"up"},
+			expCode:   0,
+			expOutput: fmt.Sprintf("no change\nversion: %d\n", latest),
+		},
+		{
+			name:      "migration is rolled back",
+			args:      []string{"down"},
+			expCode:   0,
+			expOutput: fmt.Sprintf("version: %d\n", previous),
+		},
+		{
+			name:      "migrations are rolled back",
+			args:      []string{"down", strconv.Itoa(len(versions) - 1)},
+			expCode:   0,
+			expOutput: "version: none\n",
+		},
+		{
+			name:     "no migrations are left to roll back",
+			args:     []string{"down", "1"},
+			expCode:  1,
+			expError: "no migrations to apply\n",
+		},
+		{
+			name:      "version is forced",
+			args:      []string{"force", "20201227073627"},
+			expCode:   0,
+			expOutput: "version: 20201227073627\n",
+		},
+		{
+			name:     "command is required",
+			args:     []string{},
+			expCode:  2,
+			expError: "command is required",
+		},
+		{
+			name:     "number of migrations is invalid",
+			args:     []string{"down", "0"},
+			expCode:  2,
+			expError: `"0" is not a positive number of migrations`,
+		},
+		{
+			name:     "command is unknown",
+			args:     []string{"redo"},
+			expCode:  2,
+			expError: `unknown command "redo"`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := runMigrate("tasker migrate", append(flags, tc.args...), &stdout, &stderr)
+
+			assert.Equal(t, tc.expCode, code)
+			assert.Equal(t, tc.expOutput, stdout.String())
+			assert.Contains(t, stderr.String(), tc.expError)
+		})
+	}
+}
+
+func TestRunMigrate_InMemory(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := runMigrate("tasker migrate", []string{"-storage-driver", "inmem", "up"}, &stdout, &stderr)
+
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr.String(), `storage driver "inmem" doesn't have schema migrations`)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/imarrche/tasker/internal/fsck"
+)
+
+// errInvalidAdminToken is returned for admin requests without valid bearer token.
+var errInvalidAdminToken = errors.New("invalid admin token")
+
+// adminOnly allows requests authenticated with the admin bearer token. Admin endpoints
+// aren't found unless the token is configured.
+func (s *Server) adminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.config == nil || s.config.AdminToken == "" {
+			s.error(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AdminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			s.error(w, r, http.StatusUnauthorized, errInvalidAdminToken)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// adminFsck reports integrity issues of all boards, POST requests also repair them.
+func (s *Server) adminFsck() http.HandlerFunc {
+	return s.adminOnly(func(w http.ResponseWriter, r *http.Request) {
+		report, err := fsck.Check(r.Context(), fsck.FromStore(s.store), r.Method == http.MethodPost)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respond(w, r, http.StatusOK, report)
+	})
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store/inmem"
+)
+
+func TestServer_AdminFsck(t *testing.T) {
+	testcases := []struct {
+		name    string
+		token   string
+		method  string
+		auth    string
+		expCode int
+		expBody string
+	}{
+		{
+			name:    "admin endpoints are disabled",
+			token:   "",
+			method:  http.MethodGet,
+			auth:    "Bearer ",
+			expCode: http.StatusNotFound,
+		},
+		{
+			name:    "admin token is invalid",
+			token:   "secret",
+			method:  http.MethodGet,
+			auth:    "Bearer wrong",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "admin token is missing",
+			token:   "secret",
+			method:  http.MethodGet,
+			auth:    "",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "issues are reported",
+			token:   "secret",
+			method:  http.MethodGet,
+			auth:    "Bearer secret",
+			expCode: http.StatusOK,
+			expBody: `{"projects": 3, "issues": [
+				{"kind": "no_columns", "project_id": 3, "detail": "project has no columns", "repaired": false}
+			]}`,
+		},
+		{
+			name:    "issues are repaired",
+			token:   "secret",
+			method:  http.MethodPost,
+			auth:    "Bearer secret",
+			expCode: http.StatusOK,
+			expBody: `{"projects": 3, "issues": [
+				{"kind": "no_columns", "project_id": 3, "detail": "project has no columns", "repaired": true}
+			]}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			s.Projects().Create(context.Background(), model.Project{Name: "Project 3"})
+			c := config.New()
+			c.AdminToken = tc.token
+			server := &Server{router: mux.NewRouter(), config: c, store: s}
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(tc.method, "/api/v1/admin/fsck", nil)
+			r.Header.Set("Authorization", tc.auth)
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, w.Body.String())
+			}
+		})
+	}
+}
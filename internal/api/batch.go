@@ -0,0 +1,485 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/imarrche/tasker/internal/logger"
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/service"
+	"github.com/imarrche/tasker/internal/service/web"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// maxBatchSize is the maximum number of operations of a batch or task IDs of a bulk request.
+const maxBatchSize = 100
+
+var (
+	// errEmptyBatch is thrown when a batch has no operations.
+	errEmptyBatch = errors.New("batch must contain at least one operation")
+	// errBatchTooLarge is thrown when a batch has more than maxBatchSize operations.
+	errBatchTooLarge = fmt.Errorf("batch can't contain more than %d operations", maxBatchSize)
+	// errNoTaskIDs is thrown when a bulk request has no task IDs.
+	errNoTaskIDs = errors.New("ids must contain at least one task ID")
+	// errTooManyTaskIDs is thrown when a bulk request has more than maxBatchSize task IDs.
+	errTooManyTaskIDs = fmt.Errorf("ids can't contain more than %d task IDs", maxBatchSize)
+	// errDuplicateTaskIDs is thrown when a bulk request has a task ID more than once.
+	errDuplicateTaskIDs = errors.New("ids must be unique")
+	// errInvalidMoveData is thrown when task move sets other than one of its fields.
+	errInvalidMoveData = errors.New("task move must set exactly one of column_id, left and up")
+)
+
+// batchOperation is a single operation of a batch.
+type batchOperation struct {
+	// Op is one of create, update, move and delete.
+	Op string `json:"op"`
+	// Entity is one of project, column, task and comment.
+	Entity string `json:"entity"`
+	// ID is the ID of updated, moved or deleted entity.
+	ID int `json:"id"`
+	// ParentID is the ID of project, column or task a column, task or comment is created in.
+	ParentID int `json:"parent_id"`
+	// Data is the request body of the equivalent single request.
+	Data json.RawMessage `json:"data"`
+}
+
+// batchResult is the result of a successful operation.
+type batchResult struct {
+	Status int         `json:"status"`
+	Body   interface{} `json:"body,omitempty"`
+}
+
+// batchProblem is the problem details of a failed batch with index of the failed operation.
+type batchProblem struct {
+	problem
+	Operation int `json:"operation"`
+}
+
+// batchOperationError is the error of a batch operation with its response status code.
+type batchOperationError struct {
+	index int
+	code  int
+	err   error
+}
+
+// Error returns the operation error message.
+func (e *batchOperationError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.index, e.err)
+}
+
+// batch runs operations in order in a single transaction. Either all operations succeed
+// and their results are returned or none of them is applied.
+func (s *Server) batch() http.HandlerFunc {
+	type request struct {
+		Operations []batchOperation `json:"operations"`
+	}
+	type response struct {
+		Results []batchResult `json:"results"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+		if len(req.Operations) == 0 {
+			s.error(w, r, http.StatusBadRequest, errEmptyBatch)
+			return
+		} else if len(req.Operations) > maxBatchSize {
+			s.error(w, r, http.StatusBadRequest, errBatchTooLarge)
+			return
+		}
+
+		var results []batchResult
+		err := s.service.InTx(r.Context(), func(tx service.Service) error {
+			results = make([]batchResult, 0, len(req.Operations))
+			for i, op := range req.Operations {
+				res, err := runBatchOperation(r.Context(), tx, op)
+				if err != nil {
+					return &batchOperationError{index: i, code: operationErrorStatus(err), err: err}
+				}
+				results = append(results, res)
+			}
+
+			return nil
+		})
+
+		var opErr *batchOperationError
+		if errors.As(err, &opErr) {
+			s.batchError(w, r, opErr)
+		} else if err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, response{Results: results})
+		}
+	}
+}
+
+// taskBulkMove moves tasks to the end of a column in order of their IDs and returns them.
+func (s *Server) taskBulkMove() http.HandlerFunc {
+	type request struct {
+		IDs      []int `json:"ids"`
+		ColumnID int   `json:"column_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+		if err := validateTaskIDs(req.IDs); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		var ts []model.Task
+		err := s.service.InTx(r.Context(), func(tx service.Service) error {
+			ts = make([]model.Task, 0, len(req.IDs))
+			for _, id := range req.IDs {
+				t, err := tx.Tasks().MoveToColumn(r.Context(), id, req.ColumnID)
+				if err != nil {
+					return err
+				}
+				ts = append(ts, t)
+			}
+
+			return nil
+		})
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if err == web.ErrInvalidMove {
+			s.error(w, r, http.StatusBadRequest, err)
+		} else if web.IsWIPLimitError(err) || err == web.ErrTaskIsBlocked || err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, ts)
+		}
+	}
+}
+
+// taskBulkDelete deletes tasks.
+func (s *Server) taskBulkDelete() http.HandlerFunc {
+	type request struct {
+		IDs []int `json:"ids"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+		if err := validateTaskIDs(req.IDs); err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err := s.service.InTx(r.Context(), func(tx service.Service) error {
+			for _, id := range req.IDs {
+				if err := tx.Tasks().DeleteByID(r.Context(), id); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err == store.ErrNotFound {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusNoContent, nil)
+		}
+	}
+}
+
+// batchError responds with problem details of the failed operation.
+func (s *Server) batchError(w http.ResponseWriter, r *http.Request, e *batchOperationError) {
+	err := e.err
+	if e.code == http.StatusNotFound {
+		err = nil
+	} else if e.code >= 500 {
+		s.requestLogger(r).WithFields(logger.Fields{
+			"status":    e.code,
+			"operation": e.index,
+			"error":     err,
+		}).Error("server error")
+		err = nil // Do not show server error to users for security reasons.
+	}
+
+	p := batchProblem{problem: newProblem(r, e.code, err), Operation: e.index}
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(e.code)
+	json.NewEncoder(w).Encode(p)
+}
+
+// runBatchOperation runs the operation with the services of batch transaction.
+func runBatchOperation(ctx context.Context, tx service.Service, op batchOperation) (batchResult, error) {
+	switch op.Op {
+	case "create":
+		return runBatchCreate(ctx, tx, op)
+	case "update":
+		return runBatchUpdate(ctx, tx, op)
+	case "move":
+		return runBatchMove(ctx, tx, op)
+	case "delete":
+		return runBatchDelete(ctx, tx, op)
+	}
+
+	return batchResult{}, errInvalidOperation(fmt.Errorf("unknown op %q", op.Op))
+}
+
+// runBatchCreate creates the entity in its parent like POST request to parent's collection.
+func runBatchCreate(ctx context.Context, tx service.Service, op batchOperation) (batchResult, error) {
+	var body interface{}
+	var err error
+	switch op.Entity {
+	case "project":
+		var data struct {
+			Name               string `json:"name"`
+			Description        string `json:"description"`
+			SoftWIPLimits      bool   `json:"soft_wip_limits"`
+			StrictDependencies bool   `json:"strict_dependencies"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		p := model.Project{
+			Name: data.Name, Description: data.Description, SoftWIPLimits: data.SoftWIPLimits,
+			StrictDependencies: data.StrictDependencies,
+		}
+		body, err = tx.Projects().Create(ctx, p)
+	case "column":
+		var data struct {
+			Name     string           `json:"name"`
+			Type     model.ColumnType `json:"type"`
+			WIPLimit int              `json:"wip_limit"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		c := model.Column{
+			Name: data.Name, ProjectID: op.ParentID, Type: data.Type, WIPLimit: data.WIPLimit,
+		}
+		body, err = tx.Columns().Create(ctx, c)
+	case "task":
+		var data struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		t := model.Task{Name: data.Name, Description: data.Description, ColumnID: op.ParentID}
+		body, err = tx.Tasks().Create(ctx, t)
+	case "comment":
+		var data struct {
+			Text string `json:"text"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		body, err = tx.Comments().Create(ctx, model.Comment{Text: data.Text, TaskID: op.ParentID})
+	default:
+		return batchResult{}, errUnknownEntity(op)
+	}
+	if err != nil {
+		return batchResult{}, err
+	}
+
+	return batchResult{Status: http.StatusCreated, Body: body}, nil
+}
+
+// runBatchUpdate updates the entity like PUT request.
+func runBatchUpdate(ctx context.Context, tx service.Service, op batchOperation) (batchResult, error) {
+	var body interface{}
+	var err error
+	switch op.Entity {
+	case "project":
+		var data struct {
+			Name               string `json:"name"`
+			Description        string `json:"description"`
+			SoftWIPLimits      bool   `json:"soft_wip_limits"`
+			StrictDependencies bool   `json:"strict_dependencies"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		p := model.Project{
+			ID: op.ID, Name: data.Name, Description: data.Description, SoftWIPLimits: data.SoftWIPLimits,
+			StrictDependencies: data.StrictDependencies,
+		}
+		body, err = tx.Projects().Update(ctx, p)
+	case "column":
+		var data struct {
+			Name     string           `json:"name"`
+			Type     model.ColumnType `json:"type"`
+			WIPLimit int              `json:"wip_limit"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		c := model.Column{ID: op.ID, Name: data.Name, Type: data.Type, WIPLimit: data.WIPLimit}
+		body, err = tx.Columns().Update(ctx, c)
+	case "task":
+		var data struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		body, err = tx.Tasks().Update(ctx, model.Task{ID: op.ID, Name: data.Name, Description: data.Description})
+	case "comment":
+		var data struct {
+			Text string `json:"text"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		body, err = tx.Comments().Update(ctx, model.Comment{ID: op.ID, Text: data.Text})
+	default:
+		return batchResult{}, errUnknownEntity(op)
+	}
+	if err != nil {
+		return batchResult{}, err
+	}
+
+	return batchResult{Status: http.StatusOK, Body: body}, nil
+}
+
+// runBatchMove moves a column left or right, or a task to another column or up or down.
+func runBatchMove(ctx context.Context, tx service.Service, op batchOperation) (batchResult, error) {
+	var body interface{}
+	var err error
+	switch op.Entity {
+	case "column":
+		var data struct {
+			Left bool `json:"left"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		err = tx.Columns().MoveByID(ctx, op.ID, data.Left)
+	case "task":
+		var data struct {
+			ColumnID *int  `json:"column_id"`
+			Left     *bool `json:"left"`
+			Up       *bool `json:"up"`
+		}
+		if err := decodeOperationData(op, &data); err != nil {
+			return batchResult{}, err
+		}
+		if data.ColumnID != nil && data.Left == nil && data.Up == nil {
+			body, err = tx.Tasks().MoveToColumn(ctx, op.ID, *data.ColumnID)
+		} else if data.ColumnID == nil && data.Left != nil && data.Up == nil {
+			body, err = tx.Tasks().MoveToColumnByID(ctx, op.ID, *data.Left)
+		} else if data.ColumnID == nil && data.Left == nil && data.Up != nil {
+			err = tx.Tasks().MoveByID(ctx, op.ID, *data.Up)
+		} else {
+			return batchResult{}, errInvalidOperation(errInvalidMoveData)
+		}
+	default:
+		return batchResult{}, errUnknownEntity(op)
+	}
+	if err != nil {
+		return batchResult{}, err
+	}
+
+	return batchResult{Status: http.StatusOK, Body: body}, nil
+}
+
+// runBatchDelete deletes the entity like DELETE request.
+func runBatchDelete(ctx context.Context, tx service.Service, op batchOperation) (batchResult, error) {
+	var err error
+	switch op.Entity {
+	case "project":
+		err = tx.Projects().DeleteByID(ctx, op.ID)
+	case "column":
+		err = tx.Columns().DeleteByID(ctx, op.ID)
+	case "task":
+		err = tx.Tasks().DeleteByID(ctx, op.ID)
+	case "comment":
+		err = tx.Comments().DeleteByID(ctx, op.ID)
+	default:
+		return batchResult{}, errUnknownEntity(op)
+	}
+	if err != nil {
+		return batchResult{}, err
+	}
+
+	return batchResult{Status: http.StatusNoContent}, nil
+}
+
+// invalidOperationError is the error of a malformed batch operation.
+type invalidOperationError struct {
+	err error
+}
+
+// Error returns the error message.
+func (e invalidOperationError) Error() string { return e.err.Error() }
+
+// errInvalidOperation wraps err so the operation fails with 400 Bad Request.
+func errInvalidOperation(err error) error { return invalidOperationError{err: err} }
+
+// errUnknownEntity is the error of an operation on an entity it doesn't support.
+func errUnknownEntity(op batchOperation) error {
+	return errInvalidOperation(fmt.Errorf("op %q doesn't support entity %q", op.Op, op.Entity))
+}
+
+// decodeOperationData strictly decodes data of the operation into v.
+func decodeOperationData(op batchOperation, v interface{}) error {
+	if err := decodeStrict(bytes.NewReader(op.Data), v); err != nil {
+		return errInvalidOperation(fmt.Errorf("data: %v", err))
+	}
+
+	return nil
+}
+
+// operationErrorStatus returns response status code for the error of batch operation,
+// the same the equivalent single request responds with.
+func operationErrorStatus(err error) int {
+	var invalid invalidOperationError
+	if err == store.ErrNotFound || err == store.ErrInvalidReference {
+		return http.StatusNotFound
+	} else if web.IsValidationError(err) {
+		return http.StatusUnprocessableEntity
+	} else if errors.As(err, &invalid) || err == web.ErrInvalidMove || err == web.ErrLastColumn {
+		return http.StatusBadRequest
+	} else if web.IsWIPLimitError(err) || err == web.ErrTaskIsBlocked || err == store.ErrConflict {
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
+
+// validateTaskIDs checks task IDs of a bulk request.
+func validateTaskIDs(ids []int) error {
+	if len(ids) == 0 {
+		return errNoTaskIDs
+	} else if len(ids) > maxBatchSize {
+		return errTooManyTaskIDs
+	}
+
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return errDuplicateTaskIDs
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
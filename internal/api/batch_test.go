@@ -0,0 +1,287 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/service/web"
+	"github.com/imarrche/tasker/internal/store/inmem"
+)
+
+// columnTasks returns IDs of the column's tasks in order.
+func columnTasks(t *testing.T, s *inmem.Store, columnID int) []int {
+	ts, err := web.NewService(s).Tasks().GetByColumnID(context.Background(), columnID)
+	assert.NoError(t, err)
+	ids := []int{}
+	for _, task := range ts {
+		ids = append(ids, task.ID)
+	}
+
+	return ids
+}
+
+func TestServer_Batch(t *testing.T) {
+	testcases := []struct {
+		name       string
+		body       string
+		expCode    int
+		expBody    string
+		expColumn1 []int
+		expColumn2 []int
+	}{
+		{
+			name: "operations are applied",
+			body: `{"operations": [
+				{"op": "create", "entity": "task", "parent_id": 1, "data": {"name": "Task 4"}},
+				{"op": "update", "entity": "task", "id": 1, "data": {"name": "Task 1", "description": "Done"}},
+				{"op": "move", "entity": "task", "id": 1, "data": {"column_id": 2}},
+				{"op": "move", "entity": "task", "id": 2, "data": {"up": false}},
+				{"op": "delete", "entity": "comment", "id": 3}
+			]}`,
+			expCode: http.StatusOK,
+			expBody: `{"results": [
+				{"status": 201, "body": {"id": 4, "name": "Task 4", "description": "", "index": 3,
+					"column_id": 1, "blocked": false}},
+				{"status": 200, "body": {"id": 1, "name": "Task 1", "description": "Done", "index": 1,
+					"column_id": 1, "blocked": false}},
+				{"status": 200, "body": {"id": 1, "name": "Task 1", "description": "Done", "index": 2,
+					"column_id": 2, "blocked": false}},
+				{"status": 200},
+				{"status": 204}
+			]}`,
+			expColumn1: []int{4, 2},
+			expColumn2: []int{3, 1},
+		},
+		{
+			name: "entity isn't found",
+			body: `{"operations": [
+				{"op": "move", "entity": "task", "id": 1, "data": {"column_id": 2}},
+				{"op": "delete", "entity": "task", "id": 99}
+			]}`,
+			expCode: http.StatusNotFound,
+			expBody: `{"type": "about:blank", "title": "Not Found", "status": 404,
+				"instance": "/api/v1/batch", "operation": 1}`,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name: "validation fails",
+			body: `{"operations": [
+				{"op": "delete", "entity": "task", "id": 1},
+				{"op": "create", "entity": "task", "parent_id": 1, "data": {"name": ""}}
+			]}`,
+			expCode: http.StatusUnprocessableEntity,
+			expBody: `{"type": "/problems/validation-error", "title": "Validation failed", "status": 422,
+				"detail": "name is required", "instance": "/api/v1/batch", "operation": 1,
+				"errors": [{"field": "name", "code": "required", "message": "name is required"}]}`,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name: "move is invalid",
+			body: `{"operations": [
+				{"op": "move", "entity": "task", "id": 1, "data": {"up": true}}
+			]}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name: "task move is ambiguous",
+			body: `{"operations": [
+				{"op": "move", "entity": "task", "id": 1, "data": {"up": true, "left": false}}
+			]}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name: "op is unknown",
+			body: `{"operations": [
+				{"op": "rename", "entity": "task", "id": 1}
+			]}`,
+			expCode: http.StatusBadRequest,
+			expBody: `{"type": "about:blank", "title": "Bad Request", "status": 400,
+				"detail": "unknown op \"rename\"", "instance": "/api/v1/batch", "operation": 0}`,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name: "entity isn't supported",
+			body: `{"operations": [
+				{"op": "move", "entity": "comment", "id": 1, "data": {}}
+			]}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name: "data has unknown fields",
+			body: `{"operations": [
+				{"op": "update", "entity": "task", "id": 1, "data": {"title": "Task"}}
+			]}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name:       "batch is empty",
+			body:       `{"operations": []}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name:       "batch is too large",
+			body:       `{"operations": [` + strings.Repeat(`{"op": "delete", "entity": "task", "id": 1},`, 100) + `{}]}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			server := &Server{router: mux.NewRouter(), store: s, service: web.NewService(s)}
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/api/v1/batch", strings.NewReader(tc.body))
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, w.Body.String())
+			}
+			assert.Equal(t, tc.expColumn1, columnTasks(t, s, 1))
+			assert.Equal(t, tc.expColumn2, columnTasks(t, s, 2))
+		})
+	}
+}
+
+func TestServer_TaskBulkMove(t *testing.T) {
+	testcases := []struct {
+		name       string
+		body       string
+		expCode    int
+		expColumn1 []int
+		expColumn2 []int
+	}{
+		{
+			name:       "tasks are moved",
+			body:       `{"ids": [2, 1], "column_id": 2}`,
+			expCode:    http.StatusOK,
+			expColumn1: []int{},
+			expColumn2: []int{3, 2, 1},
+		},
+		{
+			name:       "task in the column stays in place",
+			body:       `{"ids": [3, 1], "column_id": 2}`,
+			expCode:    http.StatusOK,
+			expColumn1: []int{2},
+			expColumn2: []int{3, 1},
+		},
+		{
+			name:       "column is in another project",
+			body:       `{"ids": [1, 2], "column_id": 3}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name:       "task isn't found",
+			body:       `{"ids": [1, 99], "column_id": 2}`,
+			expCode:    http.StatusNotFound,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name:       "IDs are duplicated",
+			body:       `{"ids": [1, 1], "column_id": 2}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+		{
+			name:       "IDs are missing",
+			body:       `{"column_id": 2}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+			expColumn2: []int{3},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			server := &Server{router: mux.NewRouter(), store: s, service: web.NewService(s)}
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/api/v1/tasks/move", strings.NewReader(tc.body))
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			assert.Equal(t, tc.expColumn1, columnTasks(t, s, 1))
+			assert.Equal(t, tc.expColumn2, columnTasks(t, s, 2))
+		})
+	}
+}
+
+func TestServer_TaskBulkDelete(t *testing.T) {
+	testcases := []struct {
+		name       string
+		body       string
+		expCode    int
+		expColumn1 []int
+	}{
+		{
+			name:       "tasks are deleted",
+			body:       `{"ids": [1, 3]}`,
+			expCode:    http.StatusNoContent,
+			expColumn1: []int{2},
+		},
+		{
+			name:       "task isn't found",
+			body:       `{"ids": [1, 99]}`,
+			expCode:    http.StatusNotFound,
+			expColumn1: []int{1, 2},
+		},
+		{
+			name:       "IDs are duplicated",
+			body:       `{"ids": [1, 1]}`,
+			expCode:    http.StatusBadRequest,
+			expColumn1: []int{1, 2},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			server := &Server{router: mux.NewRouter(), store: s, service: web.NewService(s)}
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/api/v1/tasks/delete", strings.NewReader(tc.body))
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			assert.Equal(t, tc.expColumn1, columnTasks(t, s, 1))
+			ts, err := web.NewService(s).Tasks().GetByColumnID(context.Background(), 1)
+			assert.NoError(t, err)
+			for i, task := range ts {
+				assert.Equal(t, i+1, task.Index)
+			}
+		})
+	}
+}
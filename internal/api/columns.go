@@ -1,7 +1,6 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -20,7 +19,7 @@ func (s *Server) columnList() http.HandlerFunc {
 			return
 		}
 
-		cs, err := s.service.Columns().GetByProjectID(projectID)
+		cs, err := s.service.Columns().GetByProjectID(r.Context(), projectID)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
@@ -33,7 +32,9 @@ func (s *Server) columnList() http.HandlerFunc {
 
 func (s *Server) columnCreate() http.HandlerFunc {
 	type request struct {
-		Name string `json:"name"`
+		Name     string           `json:"name"`
+		Type     model.ColumnType `json:"type"`
+		WIPLimit int              `json:"wip_limit"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -44,17 +45,19 @@ func (s *Server) columnCreate() http.HandlerFunc {
 		}
 
 		var req request
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
-		c := model.Column{Name: req.Name, ProjectID: projectID}
-		c, err = s.service.Columns().Create(c)
-		if err == store.ErrNotFound {
+		c := model.Column{Name: req.Name, ProjectID: projectID, Type: req.Type, WIPLimit: req.WIPLimit}
+		c, err = s.service.Columns().Create(r.Context(), c)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if web.IsValidationError(err) {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
+		} else if err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
 		} else if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 		} else {
@@ -71,7 +74,7 @@ func (s *Server) columnDetail() http.HandlerFunc {
 			return
 		}
 
-		c, err := s.service.Columns().GetByID(id)
+		c, err := s.service.Columns().GetByID(r.Context(), id)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
@@ -95,16 +98,18 @@ func (s *Server) columnMove() http.HandlerFunc {
 		}
 
 		var req request
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
-		err = s.service.Columns().MoveByID(id, req.Left)
-		if err == store.ErrNotFound {
+		err = s.service.Columns().MoveByID(r.Context(), id, req.Left)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err == web.ErrInvalidMove {
 			s.error(w, r, http.StatusBadRequest, err)
+		} else if err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
 		} else if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 		} else {
@@ -115,7 +120,9 @@ func (s *Server) columnMove() http.HandlerFunc {
 
 func (s *Server) columnUpdate() http.HandlerFunc {
 	type request struct {
-		Name string `json:"name"`
+		Name     string           `json:"name"`
+		Type     model.ColumnType `json:"type"`
+		WIPLimit int              `json:"wip_limit"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -126,17 +133,55 @@ func (s *Server) columnUpdate() http.HandlerFunc {
 		}
 
 		var req request
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+
+		c := model.Column{ID: id, Name: req.Name, Type: req.Type, WIPLimit: req.WIPLimit}
+		c, err = s.service.Columns().Update(r.Context(), c)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if web.IsValidationError(err) {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+		} else if err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, c)
+		}
+	}
+}
+
+func (s *Server) columnPatch() http.HandlerFunc {
+	type request struct {
+		Name     *string           `json:"name"`
+		Type     *model.ColumnType `json:"type"`
+		WIPLimit *int              `json:"wip_limit"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["column_id"])
+		if err != nil {
 			s.error(w, r, http.StatusBadRequest, err)
 			return
 		}
 
-		c := model.Column{ID: id, Name: req.Name}
-		c, err = s.service.Columns().Update(c)
-		if err == store.ErrNotFound {
+		var req request
+		if err := s.decodePatch(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+
+		patch := model.ColumnPatch{Name: req.Name, Type: req.Type, WIPLimit: req.WIPLimit}
+		c, err := s.service.Columns().Patch(r.Context(), id, patch)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if web.IsValidationError(err) {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
+		} else if err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
 		} else if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 		} else {
@@ -153,11 +198,13 @@ func (s *Server) columnDelete() http.HandlerFunc {
 			return
 		}
 
-		err = s.service.Columns().DeleteByID(id)
-		if err == store.ErrNotFound {
+		err = s.service.Columns().DeleteByID(r.Context(), id)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err == web.ErrLastColumn {
 			s.error(w, r, http.StatusBadRequest, err)
+		} else if web.IsWIPLimitError(err) || err == web.ErrTaskIsBlocked || err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
 		} else if err != nil {
 			s.error(w, r, http.StatusInternalServerError, nil)
 		} else {
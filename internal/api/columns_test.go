@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/imarrche/tasker/internal/model"
 	mock_service "github.com/imarrche/tasker/internal/service/mocks"
+	"github.com/imarrche/tasker/internal/store"
 )
 
 func TestServer_ColumnList(t *testing.T) {
@@ -31,7 +33,7 @@ func TestServer_ColumnList(t *testing.T) {
 			name: "column list is retrieved",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, pID int, columns []model.Column) {
 				cs := mock_service.NewMockColumnService(c)
-				cs.EXPECT().GetByProjectID(pID).Return(columns, nil)
+				cs.EXPECT().GetByProjectID(gomock.Any(), pID).Return(columns, nil)
 				s.EXPECT().Columns().Return(cs)
 			},
 			projectID: 1,
@@ -88,7 +90,7 @@ func TestServer_ColumnCreate(t *testing.T) {
 					ID: 1, Name: column.Name, Index: 1, ProjectID: column.ProjectID,
 				}
 				cs := mock_service.NewMockColumnService(c)
-				cs.EXPECT().Create(column).Return(createdColumn, nil)
+				cs.EXPECT().Create(gomock.Any(), column).Return(createdColumn, nil)
 				s.EXPECT().Columns().Return(cs)
 			},
 			projectID: 1,
@@ -108,7 +110,7 @@ func TestServer_ColumnCreate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.column)
+			json.NewEncoder(b).Encode(map[string]string{"name": tc.column.Name})
 			r, _ := http.NewRequest(http.MethodPost, "/api/v1/projects/1/columns", b)
 
 			server.router.ServeHTTP(w, r)
@@ -137,7 +139,7 @@ func TestServer_ColumnDetail(t *testing.T) {
 			name: "column is retrieved",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, column model.Column) {
 				cs := mock_service.NewMockColumnService(c)
-				cs.EXPECT().GetByID(column.ID).Return(column, nil)
+				cs.EXPECT().GetByID(gomock.Any(), column.ID).Return(column, nil)
 				s.EXPECT().Columns().Return(cs)
 			},
 			column:  model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
@@ -183,7 +185,7 @@ func TestServer_ColumnMove(t *testing.T) {
 			name: "column is moved right",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, left bool, column model.Column) {
 				cs := mock_service.NewMockColumnService(c)
-				cs.EXPECT().MoveByID(column.ID, left).Return(nil)
+				cs.EXPECT().MoveByID(gomock.Any(), column.ID, left).Return(nil)
 				s.EXPECT().Columns().Return(cs)
 			},
 			column:  model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
@@ -194,13 +196,24 @@ func TestServer_ColumnMove(t *testing.T) {
 			name: "column is moved left",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, left bool, column model.Column) {
 				cs := mock_service.NewMockColumnService(c)
-				cs.EXPECT().MoveByID(column.ID, left).Return(nil)
+				cs.EXPECT().MoveByID(gomock.Any(), column.ID, left).Return(nil)
 				s.EXPECT().Columns().Return(cs)
 			},
 			column:  model.Column{ID: 1, Name: "Column 1", Index: 2, ProjectID: 1},
 			left:    true,
 			expCode: http.StatusOK,
 		},
+		{
+			name: "column isn't moved because of concurrent move",
+			mock: func(c *gomock.Controller, s *mock_service.MockService, left bool, column model.Column) {
+				cs := mock_service.NewMockColumnService(c)
+				cs.EXPECT().MoveByID(gomock.Any(), column.ID, left).Return(store.ErrConflict)
+				s.EXPECT().Columns().Return(cs)
+			},
+			column:  model.Column{ID: 1, Name: "Column 1", Index: 2, ProjectID: 1},
+			left:    true,
+			expCode: http.StatusConflict,
+		},
 	}
 
 	type request struct {
@@ -245,7 +258,7 @@ func TestServer_ColumnUpdate(t *testing.T) {
 					ID: column.ID, Name: column.Name, Index: 1, ProjectID: 1,
 				}
 				cs := mock_service.NewMockColumnService(c)
-				cs.EXPECT().Update(column).Return(updatedColumn, nil)
+				cs.EXPECT().Update(gomock.Any(), column).Return(updatedColumn, nil)
 				s.EXPECT().Columns().Return(cs)
 			},
 			column:  model.Column{ID: 1, Name: "Updated column"},
@@ -264,7 +277,7 @@ func TestServer_ColumnUpdate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.column)
+			json.NewEncoder(b).Encode(map[string]string{"name": tc.column.Name})
 			r, _ := http.NewRequest(http.MethodPut, "/api/v1/columns/1", b)
 
 			server.router.ServeHTTP(w, r)
@@ -292,7 +305,7 @@ func TestServer_ColumnDelete(t *testing.T) {
 			name: "column is deleted",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, column model.Column) {
 				cs := mock_service.NewMockColumnService(c)
-				cs.EXPECT().DeleteByID(column.ID).Return(nil)
+				cs.EXPECT().DeleteByID(gomock.Any(), column.ID).Return(nil)
 				s.EXPECT().Columns().Return(cs)
 			},
 			column:  model.Column{ID: 1, Name: "Column 1"},
@@ -317,3 +330,62 @@ func TestServer_ColumnDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_ColumnPatch(t *testing.T) {
+	server := &Server{router: mux.NewRouter()}
+	server.configureRouter()
+
+	testcases := []struct {
+		name    string
+		body    string
+		mock    func(*gomock.Controller, *mock_service.MockService)
+		expCode int
+		expBody string
+	}{
+		{
+			name: "column is patched",
+			body: `{"name": "Done"}`,
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				name := "Done"
+				cs := mock_service.NewMockColumnService(c)
+				cs.EXPECT().Patch(gomock.Any(), 1, model.ColumnPatch{Name: &name}).Return(
+					model.Column{ID: 1, Name: name, Index: 1, ProjectID: 1, Type: model.ColumnTypeBacklog}, nil,
+				)
+				s.EXPECT().Columns().Return(cs)
+			},
+			expCode: http.StatusOK,
+			expBody: `{"id": 1, "name": "Done", "index": 1, "project_id": 1, "type": "backlog", "wip_limit": 0}`,
+		},
+		{
+			name: "column name conflicts",
+			body: `{"name": "Done"}`,
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				cs := mock_service.NewMockColumnService(c)
+				cs.EXPECT().Patch(gomock.Any(), 1, gomock.Any()).Return(model.Column{}, store.ErrConflict)
+				s.EXPECT().Columns().Return(cs)
+			},
+			expCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			s := mock_service.NewMockService(c)
+			tc.mock(c, s)
+			server.service = s
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPatch, "/api/v1/columns/1", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", "application/merge-patch+json")
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, w.Body.String())
+			}
+		})
+	}
+}
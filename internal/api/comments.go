@@ -1,7 +1,6 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -20,7 +19,7 @@ func (s *Server) commentList() http.HandlerFunc {
 			return
 		}
 
-		cs, err := s.service.Comments().GetByTaskID(taskID)
+		cs, err := s.service.Comments().GetByTaskID(r.Context(), taskID)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
@@ -44,14 +43,14 @@ func (s *Server) commentCreate() http.HandlerFunc {
 		}
 
 		var req request
-		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
 		c := model.Comment{Text: req.Text, TaskID: taskID}
-		c, err = s.service.Comments().Create(c)
-		if err == store.ErrNotFound {
+		c, err = s.service.Comments().Create(r.Context(), c)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if web.IsValidationError(err) {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
@@ -71,7 +70,7 @@ func (s *Server) commentDetail() http.HandlerFunc {
 			return
 		}
 
-		c, err := s.service.Comments().GetByID(id)
+		c, err := s.service.Comments().GetByID(r.Context(), id)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
@@ -95,14 +94,46 @@ func (s *Server) commentUpdate() http.HandlerFunc {
 		}
 
 		var req request
-		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
 		c := model.Comment{ID: id, Text: req.Text}
-		c, err = s.service.Comments().Update(c)
-		if err == store.ErrNotFound {
+		c, err = s.service.Comments().Update(r.Context(), c)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if web.IsValidationError(err) {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, c)
+		}
+	}
+}
+
+func (s *Server) commentPatch() http.HandlerFunc {
+	type request struct {
+		Text *string `json:"text"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["comment_id"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		var req request
+		if err := s.decodePatch(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+
+		patch := model.CommentPatch{Text: req.Text}
+		c, err := s.service.Comments().Patch(r.Context(), id, patch)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if web.IsValidationError(err) {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
@@ -122,7 +153,7 @@ func (s *Server) commentDelete() http.HandlerFunc {
 			return
 		}
 
-		err = s.service.Comments().DeleteByID(id)
+		err = s.service.Comments().DeleteByID(r.Context(), id)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
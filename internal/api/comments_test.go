@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -31,7 +33,7 @@ func TestServer_CommentList(t *testing.T) {
 			name: "comment list is retrieved",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, tID int, comments []model.Comment) {
 				ts := mock_service.NewMockCommentService(c)
-				ts.EXPECT().GetByTaskID(tID).Return(comments, nil)
+				ts.EXPECT().GetByTaskID(gomock.Any(), tID).Return(comments, nil)
 				s.EXPECT().Comments().Return(ts)
 			},
 			taskID: 1,
@@ -84,7 +86,7 @@ func TestServer_CommentCreate(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_service.MockService, tID int, comment model.Comment) {
 				createdComment := model.Comment{ID: 1, Text: comment.Text, TaskID: comment.TaskID}
 				ts := mock_service.NewMockCommentService(c)
-				ts.EXPECT().Create(comment).Return(createdComment, nil)
+				ts.EXPECT().Create(gomock.Any(), comment).Return(createdComment, nil)
 				s.EXPECT().Comments().Return(ts)
 			},
 			taskID:  1,
@@ -104,7 +106,7 @@ func TestServer_CommentCreate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.comment)
+			json.NewEncoder(b).Encode(map[string]string{"text": tc.comment.Text})
 			r, _ := http.NewRequest(http.MethodPost, "/api/v1/tasks/1/comments", b)
 
 			server.router.ServeHTTP(w, r)
@@ -133,7 +135,7 @@ func TestServer_CommentDetail(t *testing.T) {
 			name: "comment is retrieved",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, comment model.Comment) {
 				ts := mock_service.NewMockCommentService(c)
-				ts.EXPECT().GetByID(comment.ID).Return(comment, nil)
+				ts.EXPECT().GetByID(gomock.Any(), comment.ID).Return(comment, nil)
 				s.EXPECT().Comments().Return(ts)
 			},
 			comment: model.Comment{ID: 1, Text: "Comment 1"},
@@ -179,7 +181,7 @@ func TestServer_CommentUpdate(t *testing.T) {
 			name: "comment is updated",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, comment model.Comment) {
 				ts := mock_service.NewMockCommentService(c)
-				ts.EXPECT().Update(comment).Return(comment, nil)
+				ts.EXPECT().Update(gomock.Any(), comment).Return(comment, nil)
 				s.EXPECT().Comments().Return(ts)
 			},
 			comment: model.Comment{ID: 1, Text: "Updated comment"},
@@ -198,7 +200,7 @@ func TestServer_CommentUpdate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.comment)
+			json.NewEncoder(b).Encode(map[string]string{"text": tc.comment.Text})
 			r, _ := http.NewRequest(http.MethodPut, "/api/v1/comments/1", b)
 
 			server.router.ServeHTTP(w, r)
@@ -226,7 +228,7 @@ func TestServer_CommentDelete(t *testing.T) {
 			name: "comment is deleted",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, comment model.Comment) {
 				ts := mock_service.NewMockCommentService(c)
-				ts.EXPECT().DeleteByID(comment.ID).Return(nil)
+				ts.EXPECT().DeleteByID(gomock.Any(), comment.ID).Return(nil)
 				s.EXPECT().Comments().Return(ts)
 			},
 			comment: model.Comment{ID: 1, Text: "Comment 1"},
@@ -251,3 +253,52 @@ func TestServer_CommentDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_CommentPatch(t *testing.T) {
+	server := &Server{router: mux.NewRouter()}
+	server.configureRouter()
+
+	testcases := []struct {
+		name    string
+		body    string
+		mock    func(*gomock.Controller, *mock_service.MockService)
+		expCode int
+		expBody string
+	}{
+		{
+			name: "comment is patched",
+			body: `{"text": "Edited"}`,
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				text := "Edited"
+				cs := mock_service.NewMockCommentService(c)
+				cs.EXPECT().Patch(gomock.Any(), 1, model.CommentPatch{Text: &text}).Return(
+					model.Comment{ID: 1, Text: text, CreatedAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), TaskID: 1}, nil,
+				)
+				s.EXPECT().Comments().Return(cs)
+			},
+			expCode: http.StatusOK,
+			expBody: `{"id": 1, "text": "Edited", "created_at": "2021-01-02T03:04:05Z", "task_id": 1}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			s := mock_service.NewMockService(c)
+			tc.mock(c, s)
+			server.service = s
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPatch, "/api/v1/comments/1", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", "application/merge-patch+json")
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// cors adds CORS headers to responses for allowed origins.
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !s.isAllowedOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c := s.config.CORS
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if c.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Set("Access-Control-Allow-Credentials", "true")
+		} else if contains(c.AllowedOrigins, "*") {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if len(c.ExposedHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ", "))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight answers CORS preflight requests.
+func (s *Server) preflight() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		method := r.Header.Get("Access-Control-Request-Method")
+		if origin == "" || method == "" {
+			s.respond(w, r, http.StatusNoContent, nil)
+			return
+		}
+
+		c := s.config.CORS
+		if !s.isAllowedOrigin(origin) || !containsFold(c.AllowedMethods, method) {
+			s.error(w, r, http.StatusForbidden, nil)
+			return
+		}
+		for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if header = strings.TrimSpace(header); header != "" && !containsFold(c.AllowedHeaders, header) {
+				s.error(w, r, http.StatusForbidden, nil)
+				return
+			}
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", strings.Join(c.AllowedMethods, ", "))
+		if len(c.AllowedHeaders) > 0 {
+			h.Set("Access-Control-Allow-Headers", strings.Join(c.AllowedHeaders, ", "))
+		}
+		if c.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(c.MaxAge))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// isAllowedOrigin checks whether browsers may call the API from origin. The wildcard
+// doesn't match any origin when credentials are allowed, as the origin is reflected then.
+func (s *Server) isAllowedOrigin(origin string) bool {
+	if s.config == nil {
+		return false
+	}
+	for _, o := range s.config.CORS.AllowedOrigins {
+		if (o == "*" && !s.config.CORS.AllowCredentials) || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contains checks whether list contains s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsFold checks whether list contains s ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
+)
+
+func TestServer_CORS(t *testing.T) {
+	testcases := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		method      string
+		headers     map[string]string
+		expCode     int
+		expHeaders  map[string]string
+	}{
+		{
+			name:    "preflight from allowed origin",
+			origins: []string{"http://app.test"},
+			method:  http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "http://app.test",
+				"Access-Control-Request-Method":  http.MethodPut,
+				"Access-Control-Request-Headers": "content-type, x-request-id",
+			},
+			expCode: http.StatusNoContent,
+			expHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "http://app.test",
+				"Access-Control-Allow-Methods": "GET, POST, PUT, PATCH, DELETE",
+				"Access-Control-Allow-Headers": "Content-Type, X-Request-ID, Idempotency-Key",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:    "preflight from disallowed origin",
+			origins: []string{"http://app.test"},
+			method:  http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "http://evil.test",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			expCode:    http.StatusForbidden,
+			expHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:    "preflight with disallowed header",
+			origins: []string{"*"},
+			method:  http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "http://app.test",
+				"Access-Control-Request-Method":  http.MethodGet,
+				"Access-Control-Request-Headers": "X-Secret",
+			},
+			expCode: http.StatusForbidden,
+		},
+		{
+			name:    "simple request with wildcard origin",
+			origins: []string{"*"},
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "http://app.test"},
+			expCode: http.StatusOK,
+			expHeaders: map[string]string{
+				"Access-Control-Allow-Origin":   "*",
+				"Access-Control-Expose-Headers": "X-Request-ID, Retry-After, Idempotent-Replayed",
+			},
+		},
+		{
+			name:        "simple request with credentials",
+			origins:     []string{"http://app.test"},
+			credentials: true,
+			method:      http.MethodGet,
+			headers:     map[string]string{"Origin": "http://app.test"},
+			expCode:     http.StatusOK,
+			expHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "http://app.test",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			name:        "wildcard origin with credentials",
+			origins:     []string{"*"},
+			credentials: true,
+			method:      http.MethodGet,
+			headers:     map[string]string{"Origin": "http://app.test"},
+			expCode:     http.StatusOK,
+			expHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		{
+			name:       "CORS is disabled",
+			method:     http.MethodGet,
+			headers:    map[string]string{"Origin": "http://app.test"},
+			expCode:    http.StatusOK,
+			expHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := config.New()
+			c.CORS.AllowedOrigins = tc.origins
+			c.CORS.AllowCredentials = tc.credentials
+			l := logger.New(&bytes.Buffer{}, logger.ErrorLevel, logger.JSONFormat)
+			server := &Server{router: mux.NewRouter(), config: c, l: l}
+			server.configureRouter()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(tc.method, "/api/v1/projects", nil)
+			if tc.method == http.MethodGet {
+				r, _ = http.NewRequest(tc.method, "/healthz", nil)
+			}
+			for k, v := range tc.headers {
+				r.Header.Set(k, v)
+			}
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			for k, v := range tc.expHeaders {
+				assert.Equal(t, v, w.Header().Get(k), k)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+var (
+	// errRequestBodyTooLarge is thrown when request body exceeds the size limit.
+	errRequestBodyTooLarge = errors.New("http: request body too large")
+	// errTrailingData is thrown when request body contains more than one JSON value.
+	errTrailingData = errors.New("request body must contain a single JSON object")
+)
+
+// decode strictly decodes JSON request body into v rejecting unknown fields and
+// trailing data.
+func (s *Server) decode(r *http.Request, v interface{}) error {
+	return decodeStrict(r.Body, v)
+}
+
+// decodePatch strictly decodes JSON Merge Patch (RFC 7396) request body into v, which
+// must be a pointer to a struct of pointer fields. Absent members leave fields nil,
+// null members set fields to pointers to zero values, i.e. they remove values.
+func (s *Server) decodePatch(r *http.Request, v interface{}) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if err := decodeStrict(bytes.NewReader(body), v); err != nil {
+		return err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]
+		if raw, ok := members[name]; ok && string(raw) == "null" {
+			f := rv.Field(i)
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+	}
+
+	return nil
+}
+
+// decodeStrict decodes JSON from r into v rejecting unknown fields and trailing data.
+func decodeStrict(r io.Reader, v interface{}) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errTrailingData
+	}
+
+	return nil
+}
+
+// decodeErrorStatus returns response status code for request body decoding error.
+func decodeErrorStatus(err error) int {
+	if isBodyTooLarge(err) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
+
+// isBodyTooLarge checks whether the error is caused by request body exceeding the size
+// limit. The error of http.MaxBytesReader isn't exported, so it's matched by the message
+// errRequestBodyTooLarge shares with it.
+func isBodyTooLarge(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if err == errRequestBodyTooLarge || err.Error() == errRequestBodyTooLarge.Error() {
+			return true
+		}
+	}
+
+	return false
+}
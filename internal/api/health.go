@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// readinessTimeout limits the time spent checking dependencies on readiness probe.
+const readinessTimeout = 2 * time.Second
+
+// migrationVersioner is implemented by stores managing schema migrations.
+type migrationVersioner interface {
+	MigrationVersion(context.Context) (uint, bool, error)
+}
+
+// setReady marks the server ready or not ready to serve traffic.
+func (s *Server) setReady(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+	atomic.StoreInt32(&s.ready, v)
+}
+
+// isReady checks whether the server is ready to serve traffic.
+func (s *Server) isReady() bool { return atomic.LoadInt32(&s.ready) == 1 }
+
+// healthz reports that the process is alive.
+func (s *Server) healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.respond(w, r, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// readyz reports whether the server and its dependencies are ready to serve traffic.
+func (s *Server) readyz() http.HandlerFunc {
+	type migration struct {
+		Version uint `json:"version"`
+		Dirty   bool `json:"dirty"`
+	}
+	type response struct {
+		Status    string            `json:"status"`
+		Checks    map[string]string `json:"checks"`
+		Migration *migration        `json:"migration,omitempty"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+
+		res := response{Status: "ready", Checks: map[string]string{"server": "ok", "store": "ok"}}
+		if !s.isReady() {
+			res.Status, res.Checks["server"] = "not ready", "shutting down"
+		}
+		// Errors may contain connection details, so they are only logged.
+		if err := s.store.Ping(ctx); err != nil {
+			s.requestLogger(r).WithField("error", err).Warn("store isn't ready")
+			res.Status, res.Checks["store"] = "not ready", "unavailable"
+		}
+		if s.migrations != nil {
+			version, dirty, err := s.migrations.MigrationVersion(ctx)
+			if err != nil {
+				s.requestLogger(r).WithField("error", err).Warn("couldn't get migration version")
+				res.Status, res.Checks["migrations"] = "not ready", "unavailable"
+			} else if dirty {
+				res.Status, res.Checks["migrations"] = "not ready", "dirty"
+			} else {
+				res.Checks["migrations"] = "ok"
+			}
+			res.Migration = &migration{Version: version, Dirty: dirty}
+		}
+
+		code := http.StatusOK
+		if res.Status != "ready" {
+			code = http.StatusServiceUnavailable
+		}
+		s.respond(w, r, code, res)
+	}
+}
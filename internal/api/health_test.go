@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/logger"
+	mock_store "github.com/imarrche/tasker/internal/store/mocks"
+)
+
+type testMigrations struct {
+	version uint
+	dirty   bool
+	err     error
+}
+
+func (m testMigrations) MigrationVersion(context.Context) (uint, bool, error) {
+	return m.version, m.dirty, m.err
+}
+
+func TestServer_Healthz(t *testing.T) {
+	server := &Server{router: mux.NewRouter()}
+	server.configureRouter()
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+
+	server.router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+}
+
+func TestServer_Readyz(t *testing.T) {
+	testcases := []struct {
+		name       string
+		mock       func(*mock_store.MockStore)
+		ready      bool
+		migrations migrationVersioner
+		expCode    int
+		expBody    string
+		expLog     string
+	}{
+		{
+			name:       "server is ready",
+			mock:       func(s *mock_store.MockStore) { s.EXPECT().Ping(gomock.Any()).Return(nil) },
+			ready:      true,
+			migrations: testMigrations{version: 20201227073627},
+			expCode:    http.StatusOK,
+			expBody: `{
+				"status": "ready",
+				"checks": {"server": "ok", "store": "ok", "migrations": "ok"},
+				"migration": {"version": 20201227073627, "dirty": false}
+			}`,
+		},
+		{
+			name:    "server isn't ready because it's shutting down",
+			mock:    func(s *mock_store.MockStore) { s.EXPECT().Ping(gomock.Any()).Return(nil) },
+			ready:   false,
+			expCode: http.StatusServiceUnavailable,
+			expBody: `{"status": "not ready", "checks": {"server": "shutting down", "store": "ok"}}`,
+		},
+		{
+			name: "server isn't ready because store is unreachable",
+			mock: func(s *mock_store.MockStore) {
+				s.EXPECT().Ping(gomock.Any()).Return(errors.New("dial tcp db.internal:5432: connection refused"))
+			},
+			ready:   true,
+			expCode: http.StatusServiceUnavailable,
+			expBody: `{"status": "not ready", "checks": {"server": "ok", "store": "unavailable"}}`,
+			expLog:  "dial tcp db.internal:5432: connection refused",
+		},
+		{
+			name:       "server isn't ready because migration version can't be read",
+			mock:       func(s *mock_store.MockStore) { s.EXPECT().Ping(gomock.Any()).Return(nil) },
+			migrations: testMigrations{err: errors.New("relation schema_migrations is missing")},
+			ready:      true,
+			expCode:    http.StatusServiceUnavailable,
+			expBody: `{
+				"status": "not ready",
+				"checks": {"server": "ok", "store": "ok", "migrations": "unavailable"},
+				"migration": {"version": 0, "dirty": false}
+			}`,
+			expLog: "relation schema_migrations is missing",
+		},
+		{
+			name:       "server isn't ready because schema is dirty",
+			mock:       func(s *mock_store.MockStore) { s.EXPECT().Ping(gomock.Any()).Return(nil) },
+			ready:      true,
+			migrations: testMigrations{version: 2, dirty: true},
+			expCode:    http.StatusServiceUnavailable,
+			expBody: `{
+				"status": "not ready",
+				"checks": {"server": "ok", "store": "ok", "migrations": "dirty"},
+				"migration": {"version": 2, "dirty": true}
+			}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			s := mock_store.NewMockStore(c)
+			tc.mock(s)
+			var out bytes.Buffer
+			l := logger.New(&out, logger.WarnLevel, logger.TextFormat)
+			server := &Server{router: mux.NewRouter(), l: l, store: s, migrations: tc.migrations}
+			server.setReady(tc.ready)
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			assert.JSONEq(t, tc.expBody, w.Body.String())
+			if tc.expLog != "" {
+				assert.Contains(t, out.String(), tc.expLog)
+			}
+		})
+	}
+}
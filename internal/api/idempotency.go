@@ -0,0 +1,278 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// Headers of idempotent requests.
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	// maxIdempotencyKeyLength is the maximum length of idempotency key.
+	maxIdempotencyKeyLength = 255
+	// idempotencyStoreTimeout is the timeout of saving a response after it's written.
+	idempotencyStoreTimeout = 5 * time.Second
+	// idempotencyCleanupPeriod is how often expired idempotency keys are deleted.
+	idempotencyCleanupPeriod = time.Hour
+)
+
+var (
+	// errInvalidIdempotencyKey is thrown when Idempotency-Key header is malformed.
+	errInvalidIdempotencyKey = errors.New("idempotency key must be 1 to 255 printable characters")
+	// errIdempotencyKeyReused is thrown when a key is sent with a different request.
+	errIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+	// errIdempotencyKeyInProgress is thrown when a retry arrives before the request
+	// with the same key is handled.
+	errIdempotencyKeyInProgress = errors.New("request with this idempotency key is in progress")
+)
+
+// idempotency makes POST requests with Idempotency-Key header safe to retry: the first
+// response is stored and replayed to requests with the same key, path and body within
+// idempotency window. Server errors and panics aren't stored so such requests can be
+// retried.
+func (s *Server) idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" || s.config == nil || s.config.IdempotencyWindow <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !isValidIdempotencyKey(key) {
+			s.error(w, r, http.StatusBadRequest, errInvalidIdempotencyKey)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		key, fp := idempotencyStoreKey(r, key), fingerprint(r, body)
+		k, created, err := s.reserveIdempotencyKey(r.Context(), key, fp)
+		if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !created {
+			s.replay(w, r, k, fp)
+			return
+		}
+
+		// The response can't be written after write timeout, the handler is stopped by then
+		// so it doesn't outlive the reservation.
+		ctx, cancel := context.WithTimeout(r.Context(), s.config.WriteTimeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		rw := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+		before := w.Header().Clone()
+		handled := false
+		// Deferred so the key is released when the handler panics as well.
+		defer func() {
+			// Request context may be canceled by now, the outcome must be saved anyway.
+			ctx, cancel := context.WithTimeout(context.Background(), idempotencyStoreTimeout)
+			defer cancel()
+			var err error
+			if !handled || rw.status >= 500 {
+				err = s.store.IdempotencyKeys().Delete(ctx, k)
+			} else {
+				header := rw.writtenHeader()
+				k.Status, k.ContentType, k.Body = rw.status, header.Get("Content-Type"), rw.body.Bytes()
+				k.Headers = handlerHeaders(before, header)
+				_, err = s.store.IdempotencyKeys().Update(ctx, k)
+			}
+			if err == store.ErrNotFound {
+				s.requestLogger(r).Warn("idempotency key was taken over by a retry")
+			} else if err != nil {
+				s.requestLogger(r).WithField("error", err).Error("couldn't save idempotency key")
+			}
+		}()
+		next.ServeHTTP(rw, r)
+		handled = true
+	})
+}
+
+// reserveIdempotencyKey creates the key for a request being handled. If the key exists
+// it's returned with created false, unless it's expired or its reservation lease is over
+// and it's replaced.
+func (s *Server) reserveIdempotencyKey(
+	ctx context.Context, key, fp string,
+) (k model.IdempotencyKey, created bool, err error) {
+	repo := s.store.IdempotencyKeys()
+	for i := 0; i < 2; i++ {
+		k, err = repo.Create(ctx, model.IdempotencyKey{Key: key, Fingerprint: fp, CreatedAt: time.Now()})
+		if err == nil {
+			return k, true, nil
+		} else if err != store.ErrConflict {
+			return k, false, err
+		}
+
+		k, err = repo.GetByKey(ctx, key)
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return k, false, err
+		}
+		if k.Status == 0 && time.Since(k.CreatedAt) < s.idempotencyLease() ||
+			k.Status != 0 && time.Since(k.CreatedAt) < s.config.IdempotencyWindow {
+			return k, false, nil
+		}
+		if err := repo.Delete(ctx, k); err != nil && err != store.ErrNotFound {
+			return k, false, err
+		}
+	}
+
+	// The key keeps being recreated by concurrent requests.
+	return k, false, store.ErrConflict
+}
+
+// idempotencyLease is how long a key stays reserved for a request being handled: its
+// handler is stopped after write timeout and the response is saved within store timeout.
+// Reservations of requests which didn't finish by then, e.g. because the server crashed,
+// are taken over by retries.
+func (s *Server) idempotencyLease() time.Duration {
+	return s.config.WriteTimeout + idempotencyStoreTimeout
+}
+
+// replay writes the stored response of the key to a retried request.
+func (s *Server) replay(w http.ResponseWriter, r *http.Request, k model.IdempotencyKey, fp string) {
+	if k.Fingerprint != fp {
+		s.error(w, r, http.StatusUnprocessableEntity, errIdempotencyKeyReused)
+		return
+	} else if k.Status == 0 {
+		s.error(w, r, http.StatusConflict, errIdempotencyKeyInProgress)
+		return
+	}
+
+	for name, values := range k.Headers {
+		w.Header()[name] = values
+	}
+	if k.ContentType != "" {
+		w.Header().Set("Content-Type", k.ContentType)
+	}
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(k.Status)
+	w.Write(k.Body)
+}
+
+// cleanIdempotencyKeys periodically deletes expired idempotency keys until ctx is done.
+func (s *Server) cleanIdempotencyKeys(ctx context.Context) {
+	if s.config.IdempotencyWindow <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(idempotencyCleanupPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			before := time.Now().Add(-s.config.IdempotencyWindow)
+			if err := s.store.IdempotencyKeys().DeleteCreatedBefore(ctx, before); err != nil && ctx.Err() == nil {
+				s.l.WithField("error", err).Error("couldn't delete expired idempotency keys")
+			}
+		}
+	}
+}
+
+// idempotencyStoreKey returns the stored key of the client provided key, keys are scoped
+// to request method and path so the same key may be used for different endpoints.
+func idempotencyStoreKey(r *http.Request, key string) string {
+	h := sha256.Sum256([]byte(r.Method + " " + r.URL.Path + "\n" + key))
+
+	return hex.EncodeToString(h[:])
+}
+
+// fingerprint identifies the request so a key can't be reused for another one.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// handlerHeaders returns the response headers set by the handler, which are different
+// from the headers set before it, except Content-Type stored on its own.
+func handlerHeaders(before, after http.Header) map[string][]string {
+	headers := map[string][]string{}
+	for name, values := range after {
+		if name != "Content-Type" && !reflect.DeepEqual(before[name], values) {
+			headers[name] = values
+		}
+	}
+
+	return headers
+}
+
+// isValidIdempotencyKey checks whether the key is 1 to 255 printable ASCII characters.
+func isValidIdempotencyKey(key string) bool {
+	if len(key) == 0 || len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for _, c := range key {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// recordingWriter is the http.ResponseWriter keeping a copy of response status, headers
+// and body.
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+// WriteHeader records status code and headers and writes them.
+func (w *recordingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.header = w.Header().Clone()
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the headers and bytes and writes them.
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.header = w.Header().Clone()
+		w.wroteHeader = true
+	}
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+// writtenHeader returns the headers sent with the response, changes made after it was
+// written aren't sent.
+func (w *recordingWriter) writtenHeader() http.Header {
+	if w.header == nil {
+		return w.Header()
+	}
+
+	return w.header
+}
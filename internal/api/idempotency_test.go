@@ -0,0 +1,325 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/service/web"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/inmem"
+)
+
+func TestServer_Idempotency(t *testing.T) {
+	projectsRequest := httptest.NewRequest(http.MethodPost, "/api/v1/projects", nil)
+	type request struct {
+		key         string
+		body        string
+		expCode     int
+		expReplayed bool
+	}
+
+	testcases := []struct {
+		name        string
+		window      time.Duration
+		stored      *model.IdempotencyKey
+		requests    []request
+		expProjects int
+	}{
+		{
+			name:   "retry gets the stored response",
+			window: time.Hour,
+			requests: []request{
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated},
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated, expReplayed: true},
+			},
+			expProjects: 3,
+		},
+		{
+			name:   "client errors are replayed",
+			window: time.Hour,
+			requests: []request{
+				{key: "k1", body: `{"name": ""}`, expCode: http.StatusUnprocessableEntity},
+				{key: "k1", body: `{"name": ""}`, expCode: http.StatusUnprocessableEntity, expReplayed: true},
+			},
+			expProjects: 2,
+		},
+		{
+			name:   "key is reused with a different body",
+			window: time.Hour,
+			requests: []request{
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated},
+				{key: "k1", body: `{"name": "Other"}`, expCode: http.StatusUnprocessableEntity},
+			},
+			expProjects: 3,
+		},
+		{
+			name:   "different keys create different projects",
+			window: time.Hour,
+			requests: []request{
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated},
+				{key: "k2", body: `{"name": "Project"}`, expCode: http.StatusCreated},
+			},
+			expProjects: 4,
+		},
+		{
+			name:   "request with the key is in progress",
+			window: time.Hour,
+			stored: &model.IdempotencyKey{
+				Key:         idempotencyStoreKey(projectsRequest, "k1"),
+				Fingerprint: fingerprint(projectsRequest, []byte(`{"name": "Project"}`)),
+				CreatedAt:   time.Now(),
+			},
+			requests: []request{
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusConflict},
+			},
+			expProjects: 2,
+		},
+		{
+			name:   "abandoned reservation is taken over",
+			window: time.Hour,
+			stored: &model.IdempotencyKey{
+				Key:         idempotencyStoreKey(projectsRequest, "k1"),
+				Fingerprint: fingerprint(projectsRequest, []byte(`{"name": "Project"}`)),
+				CreatedAt:   time.Now().Add(-time.Hour),
+			},
+			requests: []request{
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated},
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated, expReplayed: true},
+			},
+			expProjects: 3,
+		},
+		{
+			name:   "expired key is replaced",
+			window: time.Hour,
+			stored: &model.IdempotencyKey{
+				Key:         idempotencyStoreKey(projectsRequest, "k1"),
+				Fingerprint: "other",
+				Status:      http.StatusCreated,
+				CreatedAt:   time.Now().Add(-2 * time.Hour),
+			},
+			requests: []request{
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated},
+			},
+			expProjects: 3,
+		},
+		{
+			name:   "key is invalid",
+			window: time.Hour,
+			requests: []request{
+				{key: strings.Repeat("k", 256), body: `{"name": "Project"}`, expCode: http.StatusBadRequest},
+			},
+			expProjects: 2,
+		},
+		{
+			name:   "requests without key aren't deduplicated",
+			window: time.Hour,
+			requests: []request{
+				{body: `{"name": "Project"}`, expCode: http.StatusCreated},
+				{body: `{"name": "Project"}`, expCode: http.StatusCreated},
+			},
+			expProjects: 4,
+		},
+		{
+			name:   "idempotency keys are disabled",
+			window: 0,
+			requests: []request{
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated},
+				{key: "k1", body: `{"name": "Project"}`, expCode: http.StatusCreated},
+			},
+			expProjects: 4,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			if tc.stored != nil {
+				s.IdempotencyKeys().Create(context.Background(), *tc.stored)
+			}
+			c := config.New()
+			c.IdempotencyWindow = tc.window
+			server := &Server{router: mux.NewRouter(), config: c, store: s, service: web.NewService(s)}
+			server.configureRouter()
+
+			var first string
+			for i, req := range tc.requests {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest(http.MethodPost, "/api/v1/projects", strings.NewReader(req.body))
+				if req.key != "" {
+					r.Header.Set(idempotencyKeyHeader, req.key)
+				}
+
+				server.router.ServeHTTP(w, r)
+
+				assert.Equal(t, req.expCode, w.Code)
+				if req.expReplayed {
+					assert.Equal(t, "true", w.Header().Get(idempotentReplayedHeader))
+					assert.Equal(t, first, w.Body.String())
+				} else {
+					assert.Empty(t, w.Header().Get(idempotentReplayedHeader))
+				}
+				if i == 0 {
+					first = w.Body.String()
+				}
+			}
+
+			ps, err := s.Projects().GetAll(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, ps, tc.expProjects)
+		})
+	}
+}
+
+func TestServer_IdempotencyServerError(t *testing.T) {
+	s := inmem.TestStoreWithFixtures()
+	c := config.New()
+	server := &Server{router: mux.NewRouter(), config: c, store: s}
+	server.router.Use(server.idempotency)
+	server.router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}).Methods(http.MethodPost)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		r.Header.Set(idempotencyKeyHeader, "k1")
+
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get(idempotentReplayedHeader))
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	_, err := s.IdempotencyKeys().GetByKey(context.Background(), idempotencyStoreKey(r, "k1"))
+	assert.Equal(t, store.ErrNotFound, err)
+}
+
+func TestServer_IdempotencyPanic(t *testing.T) {
+	s := inmem.TestStoreWithFixtures()
+	c := config.New()
+	server := &Server{router: mux.NewRouter(), config: c, store: s}
+	server.router.Use(server.idempotency)
+	panics := true
+	server.router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if panics {
+			panic("handler failed")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}).Methods(http.MethodPost)
+
+	newRequest := func() *http.Request {
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		r.Header.Set(idempotencyKeyHeader, "k1")
+		return r
+	}
+	assert.Panics(t, func() { server.router.ServeHTTP(httptest.NewRecorder(), newRequest()) })
+
+	// Retry isn't refused as in progress as the key is released.
+	panics = false
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, newRequest())
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(idempotentReplayedHeader))
+}
+
+func TestServer_IdempotencyScope(t *testing.T) {
+	s := inmem.TestStoreWithFixtures()
+	server := &Server{router: mux.NewRouter(), config: config.New(), store: s, service: web.NewService(s)}
+	server.configureRouter()
+
+	// The same key is used for requests to different endpoints.
+	for _, path := range []string{"/api/v1/projects", "/api/v1/projects/1/columns"} {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(`{"name": "Name"}`))
+		r.Header.Set(idempotencyKeyHeader, "k1")
+
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Header().Get(idempotentReplayedHeader))
+	}
+}
+
+func TestServer_IdempotencyHeaders(t *testing.T) {
+	s := inmem.TestStoreWithFixtures()
+	server := &Server{router: mux.NewRouter(), config: config.New(), store: s}
+	server.router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Vary", "Origin")
+			next.ServeHTTP(w, r)
+		})
+	})
+	server.router.Use(server.idempotency)
+	server.router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/projects/3")
+		w.WriteHeader(http.StatusCreated)
+		w.Header().Set("X-Late", "not sent")
+	}).Methods(http.MethodPost)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		r.Header.Set(idempotencyKeyHeader, "k1")
+
+		server.router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/projects/3", w.Header().Get("Location"))
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	k, err := s.IdempotencyKeys().GetByKey(context.Background(), idempotencyStoreKey(r, "k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"Location": {"/projects/3"}}, k.Headers, "only headers set by the handler")
+}
+
+func TestServer_IdempotencyTakenOver(t *testing.T) {
+	s := inmem.TestStoreWithFixtures()
+	var out bytes.Buffer
+	l := logger.New(&out, logger.WarnLevel, logger.TextFormat)
+	server := &Server{router: mux.NewRouter(), config: config.New(), store: s, l: l}
+	server.router.Use(server.idempotency)
+	var retry model.IdempotencyKey
+	server.router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// The lease is over and a retry takes over the key while the request is handled.
+		ctx := context.Background()
+		k, err := s.IdempotencyKeys().GetByKey(ctx, idempotencyStoreKey(r, "k1"))
+		assert.NoError(t, err)
+		assert.NoError(t, s.IdempotencyKeys().Delete(ctx, k))
+		retry, err = s.IdempotencyKeys().Create(ctx, model.IdempotencyKey{
+			Key: k.Key, Fingerprint: k.Fingerprint, CreatedAt: k.CreatedAt.Add(time.Minute),
+		})
+		assert.NoError(t, err)
+		w.WriteHeader(http.StatusCreated)
+	}).Methods(http.MethodPost)
+
+	r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	r.Header.Set(idempotencyKeyHeader, "k1")
+	server.router.ServeHTTP(httptest.NewRecorder(), r)
+
+	k, err := s.IdempotencyKeys().GetByKey(context.Background(), idempotencyStoreKey(r, "k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, retry, k, "reservation of the retry isn't overwritten")
+	assert.Contains(t, out.String(), "idempotency key was taken over by a retry")
+}
+
+func TestServer_IdempotencyLease(t *testing.T) {
+	c := config.New()
+	c.WriteTimeout = 30 * time.Second
+	server := &Server{config: c}
+
+	assert.Equal(t, 30*time.Second+idempotencyStoreTimeout, server.idempotencyLease())
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/imarrche/tasker/internal/metrics"
+	"github.com/imarrche/tasker/internal/store/instrumented"
+)
+
+// boardMetricsTimeout limits the time spent computing board metrics on scrape.
+const boardMetricsTimeout = 5 * time.Second
+
+// dbStatser is implemented by stores backed by sql.DB.
+type dbStatser interface {
+	Stats() sql.DBStats
+}
+
+// serverMetrics are the metrics exposed by Server.
+type serverMetrics struct {
+	registry     *metrics.Registry
+	httpRequests *metrics.CounterVec
+	httpDuration *metrics.HistogramVec
+	storeLatency *metrics.HistogramVec
+}
+
+// newServerMetrics creates and registers all server metrics.
+func newServerMetrics() *serverMetrics {
+	m := &serverMetrics{
+		registry: metrics.NewRegistry(),
+		httpRequests: metrics.NewCounterVec(
+			"tasker_http_requests_total",
+			"Total number of HTTP requests by route and status.",
+			"method", "route", "status",
+		),
+		httpDuration: metrics.NewHistogramVec(
+			"tasker_http_request_duration_seconds",
+			"Latency of HTTP requests by route.",
+			nil,
+			"method", "route",
+		),
+		storeLatency: instrumented.NewLatencyHistogram(),
+	}
+	m.registry.MustRegister(m.httpRequests, m.httpDuration, m.storeLatency)
+
+	return m
+}
+
+// metricsHandler returns the handler exposing metrics.
+func (s *Server) metricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.metrics == nil {
+			s.error(w, r, http.StatusNotFound, nil)
+			return
+		}
+		s.metrics.registry.Handler().ServeHTTP(w, r)
+	})
+}
+
+// collectBoardMetrics computes business gauges: number of projects, columns and tasks
+// per project. Counts are read with a single query so scrapes don't skew store latency.
+func (s *Server) collectBoardMetrics() []metrics.Family {
+	ctx, cancel := context.WithTimeout(context.Background(), boardMetricsTimeout)
+	defer cancel()
+
+	pcs, err := s.store.Projects().GetCounts(ctx)
+	if err != nil {
+		s.l.WithField("error", err).Error("couldn't collect board metrics")
+		return nil
+	}
+
+	projects := metrics.Family{
+		Name: "tasker_projects", Help: "Number of projects.", Type: metrics.GaugeType,
+		Samples: []metrics.Sample{{Value: float64(len(pcs))}},
+	}
+	columns := metrics.Family{
+		Name: "tasker_columns", Help: "Number of columns per project.", Type: metrics.GaugeType,
+	}
+	tasks := metrics.Family{
+		Name: "tasker_tasks", Help: "Number of tasks per project.", Type: metrics.GaugeType,
+	}
+	for _, pc := range pcs {
+		labels := []metrics.Label{{Name: "project_id", Value: strconv.Itoa(pc.ProjectID)}}
+		columns.Samples = append(columns.Samples, metrics.Sample{Labels: labels, Value: float64(pc.Columns)})
+		tasks.Samples = append(tasks.Samples, metrics.Sample{Labels: labels, Value: float64(pc.Tasks)})
+	}
+
+	return []metrics.Family{projects, columns, tasks}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
+	"github.com/imarrche/tasker/internal/store/inmem"
+)
+
+func TestServer_Metrics(t *testing.T) {
+	l := logger.New(&bytes.Buffer{}, logger.ErrorLevel, logger.JSONFormat)
+	server := newServer(l, config.New(), inmem.TestStoreWithFixtures())
+	server.configureRouter()
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/api/v1/projects/1", nil)
+	server.handler().ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodGet, "/metrics", nil)
+	server.handler().ServeHTTP(w, r)
+	body := w.Body.String()
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, body,
+		`tasker_http_requests_total{method="GET",route="/api/v1/projects/{project_id:[0-9]+}",status="200"} 1`,
+	)
+	assert.Contains(t, body,
+		`tasker_http_request_duration_seconds_count{method="GET",route="/api/v1/projects/{project_id:[0-9]+}"} 1`,
+	)
+	assert.Contains(t, body,
+		`tasker_store_operation_duration_seconds_count{repo="project",method="GetByID",status="ok"} 1`,
+	)
+	assert.Contains(t, body, "tasker_projects 2\n")
+	assert.Contains(t, body, `tasker_tasks{project_id="1"} 3`)
+	assert.Contains(t, body, `tasker_columns{project_id="2"} 1`)
+	assert.Contains(t, body, `tasker_tasks{project_id="2"} 0`)
+
+	// Board metrics are read with a single store operation per scrape.
+	w = httptest.NewRecorder()
+	server.handler().ServeHTTP(w, r)
+	body = w.Body.String()
+
+	assert.Contains(t, body,
+		`tasker_store_operation_duration_seconds_count{repo="project",method="GetCounts",status="ok"} 1`,
+	)
+	assert.NotContains(t, body, `method="GetByColumnID"`)
+}
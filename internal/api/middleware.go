@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/imarrche/tasker/internal/logger"
+)
+
+// requestIDHeader is the header carrying request ID.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of client provided request ID.
+const maxRequestIDLength = 128
+
+// ctxKey is the type of context keys used by api package.
+type ctxKey int
+
+const (
+	ctxKeyRequestID ctxKey = iota
+)
+
+// handler returns the router wrapped into all server middlewares.
+func (s *Server) handler() http.Handler {
+	return s.requestID(s.accessLog(s.instrument(s.rateLimit(s.limitBody(s.router)))))
+}
+
+// requestID assigns an ID to every request or propagates one provided by a client.
+func (s *Server) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), ctxKeyRequestID, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// accessLog logs every request with its route, status, latency and response size.
+func (s *Server) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rw, r)
+
+		s.requestLogger(r).WithFields(logger.Fields{
+			"method":     r.Method,
+			"route":      s.routeTemplate(r),
+			"path":       r.URL.Path,
+			"status":     rw.status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      rw.bytes,
+			"remote":     r.RemoteAddr,
+		}).Info("request handled")
+	})
+}
+
+// instrument records request count and latency by route and status.
+func (s *Server) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.metrics == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rw, r)
+
+		route := s.routeTemplate(r)
+		s.metrics.httpRequests.Inc(r.Method, route, strconv.Itoa(rw.status))
+		s.metrics.httpDuration.Observe(time.Since(start).Seconds(), r.Method, route)
+	})
+}
+
+// requestLogger returns the server logger with request ID attached.
+func (s *Server) requestLogger(r *http.Request) *logger.Logger {
+	if id := requestIDFromContext(r.Context()); id != "" {
+		return s.l.WithField("request_id", id)
+	}
+
+	return s.l
+}
+
+// routeTemplate returns path template of the route matching request.
+func (s *Server) routeTemplate(r *http.Request) string {
+	var m mux.RouteMatch
+	if !s.router.Match(r, &m) || m.Route == nil {
+		return "unmatched"
+	}
+	tpl, err := m.Route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+
+	return tpl
+}
+
+// requestIDFromContext returns request ID stored in context.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKeyRequestID).(string)
+	return id
+}
+
+// isValidRequestID checks whether client provided request ID can be trusted.
+func isValidRequestID(id string) bool {
+	if len(id) == 0 || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestID generates a random request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// responseWriter is the http.ResponseWriter recording response status and size.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+// WriteHeader records status code and writes it.
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records number of written bytes and writes them.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/logger"
+	"github.com/imarrche/tasker/internal/model"
+	mock_service "github.com/imarrche/tasker/internal/service/mocks"
+)
+
+func TestServer_RequestID(t *testing.T) {
+	l := logger.New(&bytes.Buffer{}, logger.ErrorLevel, logger.JSONFormat)
+	server := &Server{router: mux.NewRouter(), l: l}
+	server.configureRouter()
+
+	testcases := []struct {
+		name      string
+		requestID string
+		expSame   bool
+	}{
+		{name: "request ID is generated", requestID: "", expSame: false},
+		{name: "request ID is propagated", requestID: "client-id-1", expSame: true},
+		{name: "invalid request ID is replaced", requestID: "id with spaces", expSame: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/unknown", nil)
+			r.Header.Set(requestIDHeader, tc.requestID)
+
+			server.handler().ServeHTTP(w, r)
+			id := w.Header().Get(requestIDHeader)
+
+			assert.NotEmpty(t, id)
+			assert.Equal(t, tc.expSame, id == tc.requestID)
+		})
+	}
+}
+
+func TestServer_AccessLog(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := logger.New(b, logger.InfoLevel, logger.JSONFormat)
+	server := &Server{router: mux.NewRouter(), l: l}
+	server.configureRouter()
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+	s := mock_service.NewMockService(c)
+	ps := mock_service.NewMockProjectService(c)
+	ps.EXPECT().GetByID(gomock.Any(), 1).Return(model.Project{ID: 1, Name: "Project 1"}, nil)
+	s.EXPECT().Projects().Return(ps)
+	server.service = s
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/api/v1/projects/1", nil)
+	r.Header.Set(requestIDHeader, "req-1")
+
+	server.handler().ServeHTTP(w, r)
+	var entry map[string]interface{}
+	err := json.NewDecoder(strings.NewReader(b.String())).Decode(&entry)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "request handled", entry["msg"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, http.MethodGet, entry["method"])
+	assert.Equal(t, "/api/v1/projects/{project_id:[0-9]+}", entry["route"])
+	assert.Equal(t, float64(http.StatusOK), entry["status"])
+	assert.Equal(t, float64(w.Body.Len()), entry["bytes"])
+}
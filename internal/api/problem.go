@@ -0,0 +1,48 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/imarrche/tasker/internal/service/web"
+)
+
+// problemContentType is the media type of RFC 7807 problem details.
+const problemContentType = "application/problem+json"
+
+// problemTypeValidation is the problem type of requests that failed validation.
+const problemTypeValidation = "/problems/validation-error"
+
+// problem is the RFC 7807 problem details response body.
+type problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Errors   web.ValidationErrors `json:"errors,omitempty"`
+}
+
+// newProblem creates problem details for the request failed with code and err.
+func newProblem(r *http.Request, code int, err error) problem {
+	p := problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Instance: r.URL.Path,
+	}
+	if err == nil {
+		return p
+	}
+
+	var es web.ValidationErrors
+	var e web.ValidationError
+	if errors.As(err, &es) {
+		p.Type, p.Title, p.Errors = problemTypeValidation, "Validation failed", es
+	} else if errors.As(err, &e) {
+		p.Type, p.Title, p.Errors = problemTypeValidation, "Validation failed", web.ValidationErrors{e}
+	}
+	p.Detail = err.Error()
+
+	return p
+}
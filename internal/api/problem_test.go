@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	mock_service "github.com/imarrche/tasker/internal/service/mocks"
+	"github.com/imarrche/tasker/internal/service/web"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+func TestServer_Error(t *testing.T) {
+	server := &Server{router: mux.NewRouter()}
+	server.configureRouter()
+
+	validationErr := web.ValidationErrors{
+		{Field: "name", Code: web.CodeRequired, Message: "name is required"},
+		{
+			Field:      "description",
+			Code:       web.CodeTooLong,
+			Constraint: "max length is 1000",
+			Message:    "description is too long",
+		},
+	}
+
+	testcases := []struct {
+		name       string
+		mock       func(*gomock.Controller, *mock_service.MockService)
+		method     string
+		path       string
+		body       string
+		expCode    int
+		expProblem problem
+	}{
+		{
+			name: "validation errors are rendered with field details",
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				ps := mock_service.NewMockProjectService(c)
+				ps.EXPECT().Create(gomock.Any(), model.Project{}).Return(model.Project{}, validationErr)
+				s.EXPECT().Projects().Return(ps)
+			},
+			method:  http.MethodPost,
+			path:    "/api/v1/projects",
+			body:    `{}`,
+			expCode: http.StatusUnprocessableEntity,
+			expProblem: problem{
+				Type:     problemTypeValidation,
+				Title:    "Validation failed",
+				Status:   http.StatusUnprocessableEntity,
+				Detail:   validationErr.Error(),
+				Instance: "/api/v1/projects",
+				Errors:   validationErr,
+			},
+		},
+		{
+			name: "not found error is rendered without details",
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				ps := mock_service.NewMockProjectService(c)
+				ps.EXPECT().GetByID(gomock.Any(), 1).Return(model.Project{}, store.ErrNotFound)
+				s.EXPECT().Projects().Return(ps)
+			},
+			method:  http.MethodGet,
+			path:    "/api/v1/projects/1",
+			expCode: http.StatusNotFound,
+			expProblem: problem{
+				Type:     "about:blank",
+				Title:    http.StatusText(http.StatusNotFound),
+				Status:   http.StatusNotFound,
+				Instance: "/api/v1/projects/1",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			s := mock_service.NewMockService(c)
+			tc.mock(c, s)
+			server.service = s
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+
+			server.router.ServeHTTP(w, r)
+			var p problem
+			err := json.NewDecoder(w.Body).Decode(&p)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expCode, w.Code)
+			assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.expProblem, p)
+		})
+	}
+}
@@ -1,9 +1,10 @@
 package api
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -14,7 +15,7 @@ import (
 
 func (s *Server) projectList() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ps, err := s.service.Projects().GetAll()
+		ps, err := s.service.Projects().GetAll(r.Context())
 		if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 		} else {
@@ -25,19 +26,24 @@ func (s *Server) projectList() http.HandlerFunc {
 
 func (s *Server) projectCreate() http.HandlerFunc {
 	type request struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
+		Name               string `json:"name"`
+		Description        string `json:"description"`
+		SoftWIPLimits      bool   `json:"soft_wip_limits"`
+		StrictDependencies bool   `json:"strict_dependencies"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req request
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
-		p := model.Project{Name: req.Name, Description: req.Description}
-		p, err := s.service.Projects().Create(p)
+		p := model.Project{
+			Name: req.Name, Description: req.Description, SoftWIPLimits: req.SoftWIPLimits,
+			StrictDependencies: req.StrictDependencies,
+		}
+		p, err := s.service.Projects().Create(r.Context(), p)
 		if web.IsValidationError(err) {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
 		} else if err != nil {
@@ -56,7 +62,7 @@ func (s *Server) projectDetail() http.HandlerFunc {
 			return
 		}
 
-		p, err := s.service.Projects().GetByID(id)
+		p, err := s.service.Projects().GetByID(r.Context(), id)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
@@ -69,8 +75,10 @@ func (s *Server) projectDetail() http.HandlerFunc {
 
 func (s *Server) projectUpdate() http.HandlerFunc {
 	type request struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
+		Name               string `json:"name"`
+		Description        string `json:"description"`
+		SoftWIPLimits      bool   `json:"soft_wip_limits"`
+		StrictDependencies bool   `json:"strict_dependencies"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -81,13 +89,54 @@ func (s *Server) projectUpdate() http.HandlerFunc {
 		}
 
 		var req request
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+
+		p := model.Project{
+			ID: id, Name: req.Name, Description: req.Description, SoftWIPLimits: req.SoftWIPLimits,
+			StrictDependencies: req.StrictDependencies,
+		}
+		p, err = s.service.Projects().Update(r.Context(), p)
+		if err == store.ErrNotFound {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if web.IsValidationError(err) {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, p)
+		}
+	}
+}
+
+func (s *Server) projectPatch() http.HandlerFunc {
+	type request struct {
+		Name               *string `json:"name"`
+		Description        *string `json:"description"`
+		SoftWIPLimits      *bool   `json:"soft_wip_limits"`
+		StrictDependencies *bool   `json:"strict_dependencies"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["project_id"])
+		if err != nil {
 			s.error(w, r, http.StatusBadRequest, err)
 			return
 		}
 
-		p := model.Project{ID: id, Name: req.Name, Description: req.Description}
-		p, err = s.service.Projects().Update(p)
+		var req request
+		if err := s.decodePatch(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+
+		patch := model.ProjectPatch{
+			Name: req.Name, Description: req.Description, SoftWIPLimits: req.SoftWIPLimits,
+			StrictDependencies: req.StrictDependencies,
+		}
+		p, err := s.service.Projects().Patch(r.Context(), id, patch)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if web.IsValidationError(err) {
@@ -108,7 +157,7 @@ func (s *Server) projectDelete() http.HandlerFunc {
 			return
 		}
 
-		err = s.service.Projects().DeleteByID(id)
+		err = s.service.Projects().DeleteByID(r.Context(), id)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
@@ -118,3 +167,111 @@ func (s *Server) projectDelete() http.HandlerFunc {
 		}
 	}
 }
+
+// analyticsPeriod is the period of project analytics when from isn't set.
+const analyticsPeriod = 30 * 24 * time.Hour
+
+func (s *Server) projectAnalytics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["project_id"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		from, to, err := parsePeriod(r, time.Now())
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		a, err := s.service.Projects().Analytics(r.Context(), id, from, to)
+		if err == store.ErrNotFound {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if err == web.ErrInvalidPeriod {
+			s.error(w, r, http.StatusBadRequest, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, a)
+		}
+	}
+}
+
+// parsePeriod reads the period from from and to RFC 3339 times. The period ends now and
+// starts analyticsPeriod before its end by default.
+func parsePeriod(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	q := r.URL.Query()
+	to := now
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be an RFC 3339 time")
+		}
+		to = t
+	}
+	from := to.Add(-analyticsPeriod)
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be an RFC 3339 time")
+		}
+		from = t
+	}
+
+	return from, to, nil
+}
+
+// cfdDays is the number of days of project CFD when from isn't set.
+const cfdDays = 30
+
+func (s *Server) projectCFD() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["project_id"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		from, to, err := parseDays(r, time.Now())
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		cfd, err := s.service.Projects().CFD(r.Context(), id, from, to)
+		if err == store.ErrNotFound {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if err == web.ErrInvalidPeriod {
+			s.error(w, r, http.StatusBadRequest, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, cfd)
+		}
+	}
+}
+
+// parseDays reads the days from from and to dates, both inclusive. The days end today
+// in UTC and start cfdDays before by default.
+func parseDays(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	q := r.URL.Query()
+	to := now.UTC()
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse(model.DateLayout, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date like 2021-01-31")
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, 1-cfdDays)
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse(model.DateLayout, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date like 2021-01-31")
+		}
+		from = t
+	}
+
+	return from, to, nil
+}
@@ -2,10 +2,14 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -13,6 +17,8 @@ import (
 
 	"github.com/imarrche/tasker/internal/model"
 	mock_service "github.com/imarrche/tasker/internal/service/mocks"
+	"github.com/imarrche/tasker/internal/service/web"
+	"github.com/imarrche/tasker/internal/store/inmem"
 )
 
 func TestServer_ProjectList(t *testing.T) {
@@ -30,7 +36,7 @@ func TestServer_ProjectList(t *testing.T) {
 			name: "project list is retrieved",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, projects []model.Project) {
 				ps := mock_service.NewMockProjectService(c)
-				ps.EXPECT().GetAll().Return(projects, nil)
+				ps.EXPECT().GetAll(gomock.Any()).Return(projects, nil)
 				s.EXPECT().Projects().Return(ps)
 			},
 			expCode: http.StatusOK,
@@ -82,7 +88,7 @@ func TestServer_ProjectCreate(t *testing.T) {
 				createdProject := p
 				createdProject.ID = 1
 				ps := mock_service.NewMockProjectService(c)
-				ps.EXPECT().Create(p).Return(createdProject, nil)
+				ps.EXPECT().Create(gomock.Any(), p).Return(createdProject, nil)
 				s.EXPECT().Projects().Return(ps)
 			},
 			project: model.Project{Name: "Project 1", Description: "Project description."},
@@ -101,7 +107,9 @@ func TestServer_ProjectCreate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.project)
+			json.NewEncoder(b).Encode(map[string]string{
+				"name": tc.project.Name, "description": tc.project.Description,
+			})
 			r, _ := http.NewRequest(http.MethodPost, "/api/v1/projects", b)
 
 			server.router.ServeHTTP(w, r)
@@ -130,7 +138,7 @@ func TestServer_ProjectDetail(t *testing.T) {
 			name: "project is retrieved",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, p model.Project) {
 				ps := mock_service.NewMockProjectService(c)
-				ps.EXPECT().GetByID(p.ID).Return(p, nil)
+				ps.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
 				s.EXPECT().Projects().Return(ps)
 			},
 			project: model.Project{ID: 1, Name: "Project 1"},
@@ -176,7 +184,7 @@ func TestServer_ProjectUpdate(t *testing.T) {
 			name: "project is updated",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, p model.Project) {
 				ps := mock_service.NewMockProjectService(c)
-				ps.EXPECT().Update(p).Return(p, nil)
+				ps.EXPECT().Update(gomock.Any(), p).Return(p, nil)
 				s.EXPECT().Projects().Return(ps)
 			},
 			project: model.Project{ID: 1, Name: "Updated project", Description: "Updated description"},
@@ -195,7 +203,9 @@ func TestServer_ProjectUpdate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.project)
+			json.NewEncoder(b).Encode(map[string]string{
+				"name": tc.project.Name, "description": tc.project.Description,
+			})
 			r, _ := http.NewRequest(http.MethodPut, "/api/v1/projects/1", b)
 
 			server.router.ServeHTTP(w, r)
@@ -223,7 +233,7 @@ func TestServer_ProjectDelete(t *testing.T) {
 			name: "project is deleted",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, p model.Project) {
 				ps := mock_service.NewMockProjectService(c)
-				ps.EXPECT().DeleteByID(p.ID).Return(nil)
+				ps.EXPECT().DeleteByID(gomock.Any(), p.ID).Return(nil)
 				s.EXPECT().Projects().Return(ps)
 			},
 			project: model.Project{ID: 1, Name: "Project 1"},
@@ -248,3 +258,239 @@ func TestServer_ProjectDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_ProjectPatch(t *testing.T) {
+	server := &Server{router: mux.NewRouter()}
+	server.configureRouter()
+
+	testcases := []struct {
+		name    string
+		body    string
+		mock    func(*gomock.Controller, *mock_service.MockService)
+		expCode int
+		expBody string
+	}{
+		{
+			name: "only present fields are patched",
+			body: `{"name": "Updated project"}`,
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				name := "Updated project"
+				ps := mock_service.NewMockProjectService(c)
+				ps.EXPECT().Patch(gomock.Any(), 1, model.ProjectPatch{Name: &name}).Return(
+					model.Project{ID: 1, Name: name, Description: "Description"}, nil,
+				)
+				s.EXPECT().Projects().Return(ps)
+			},
+			expCode: http.StatusOK,
+			expBody: `{"id": 1, "name": "Updated project", "description": "Description", "soft_wip_limits": false,
+				"strict_dependencies": false}`,
+		},
+		{
+			name: "null field is removed",
+			body: `{"description": null}`,
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				empty := ""
+				ps := mock_service.NewMockProjectService(c)
+				ps.EXPECT().Patch(gomock.Any(), 1, model.ProjectPatch{Description: &empty}).Return(
+					model.Project{ID: 1, Name: "Project"}, nil,
+				)
+				s.EXPECT().Projects().Return(ps)
+			},
+			expCode: http.StatusOK,
+			expBody: `{"id": 1, "name": "Project", "description": "", "soft_wip_limits": false, "strict_dependencies": false}`,
+		},
+		{
+			name: "patched project is invalid",
+			body: `{"name": null}`,
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				ps := mock_service.NewMockProjectService(c)
+				ps.EXPECT().Patch(gomock.Any(), 1, gomock.Any()).Return(
+					model.Project{}, web.ValidationErrors{web.ValidationError{Field: "name"}},
+				)
+				s.EXPECT().Projects().Return(ps)
+			},
+			expCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "unknown field is rejected",
+			body:    `{"id": 2}`,
+			mock:    func(c *gomock.Controller, s *mock_service.MockService) {},
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name:    "patch isn't an object",
+			body:    `["name"]`,
+			mock:    func(c *gomock.Controller, s *mock_service.MockService) {},
+			expCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			s := mock_service.NewMockService(c)
+			tc.mock(c, s)
+			server.service = s
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPatch, "/api/v1/projects/1", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", "application/merge-patch+json")
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_ProjectAnalytics(t *testing.T) {
+	testcases := []struct {
+		name       string
+		query      string
+		projectID  int
+		expCode    int
+		expColumns []model.ColumnDwell
+		expCount   int
+	}{
+		{
+			name:       "analytics of last 30 days are computed",
+			query:      "",
+			projectID:  1,
+			expCode:    http.StatusOK,
+			expColumns: []model.ColumnDwell{{ColumnID: 1, Count: 1}},
+			expCount:   1,
+		},
+		{
+			name:       "analytics of period are computed",
+			query:      "?from=2020-01-01T00:00:00Z&to=2020-02-01T00:00:00Z",
+			projectID:  1,
+			expCode:    http.StatusOK,
+			expColumns: []model.ColumnDwell{},
+			expCount:   0,
+		},
+		{
+			name:      "period is empty",
+			query:     "?from=2020-01-01T00:00:00Z&to=2020-01-01T00:00:00Z",
+			projectID: 1,
+			expCode:   http.StatusBadRequest,
+		},
+		{name: "time is invalid", query: "?to=2020-01-01", projectID: 1, expCode: http.StatusBadRequest},
+		{name: "project doesn't exist", query: "", projectID: 10, expCode: http.StatusNotFound},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			ctx := context.Background()
+			service := web.NewService(s)
+			done, err := service.Columns().Create(ctx, model.Column{Name: "Done", ProjectID: 1, Type: model.ColumnTypeDone})
+			assert.NoError(t, err)
+			task, err := service.Tasks().Create(ctx, model.Task{Name: "Release", ColumnID: 1})
+			assert.NoError(t, err)
+			_, err = service.Tasks().MoveToColumn(ctx, task.ID, done.ID)
+			assert.NoError(t, err)
+			server := &Server{router: mux.NewRouter(), store: s, service: service}
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/projects/%d/analytics%s", tc.projectID, tc.query), nil)
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if w.Code != http.StatusOK {
+				return
+			}
+			var a model.Analytics
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&a))
+			for i := range a.Columns {
+				a.Columns[i].AverageSeconds = 0
+			}
+			assert.Equal(t, tc.expColumns, a.Columns)
+			assert.Equal(t, tc.expCount, a.LeadTime.Count)
+			assert.Equal(t, tc.expCount, a.CycleTime.Count)
+		})
+	}
+}
+
+func TestServer_ProjectCFD(t *testing.T) {
+	testcases := []struct {
+		name      string
+		query     string
+		projectID int
+		expCode   int
+		expCFD    model.CFD
+	}{
+		{
+			name:      "columns renamed, moved and deleted are counted",
+			query:     "?from=2021-01-01&to=2021-01-02",
+			projectID: 1,
+			expCode:   http.StatusOK,
+			expCFD: model.CFD{
+				From: "2021-01-01",
+				To:   "2021-01-02",
+				Columns: []model.CFDColumn{
+					{ID: 2, Name: "Doing", Index: 1},
+					{ID: 4, Name: "Done", Index: 2},
+					{ID: 1, Name: "Column 1", Index: 1, Deleted: true},
+				},
+				Days: []model.CFDDay{
+					{Date: "2021-01-01", Counts: []int{1, 0, 2}},
+					{Date: "2021-01-02", Counts: []int{3, 0, 0}},
+				},
+			},
+		},
+		{
+			name:      "days without snapshots are skipped",
+			query:     "?from=2020-01-01&to=2020-01-31",
+			projectID: 1,
+			expCode:   http.StatusOK,
+			expCFD: model.CFD{
+				From: "2020-01-01", To: "2020-01-31", Columns: []model.CFDColumn{}, Days: []model.CFDDay{},
+			},
+		},
+		{
+			name:      "period ends before it starts",
+			query:     "?from=2021-01-02&to=2021-01-01",
+			projectID: 1,
+			expCode:   http.StatusBadRequest,
+		},
+		{name: "date is invalid", query: "?from=2021-01-01T00:00:00Z", projectID: 1, expCode: http.StatusBadRequest},
+		{name: "project doesn't exist", query: "", projectID: 10, expCode: http.StatusNotFound},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			ctx := context.Background()
+			service := web.NewService(s)
+			day := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+			assert.NoError(t, service.Projects().TakeSnapshots(ctx, day))
+			_, err := service.Columns().Create(ctx, model.Column{Name: "Done", ProjectID: 1, Type: model.ColumnTypeDone})
+			assert.NoError(t, err)
+			assert.NoError(t, service.Columns().DeleteByID(ctx, 1))
+			_, err = service.Columns().Update(ctx, model.Column{ID: 2, Name: "Doing"})
+			assert.NoError(t, err)
+			assert.NoError(t, service.Projects().TakeSnapshots(ctx, day.AddDate(0, 0, 1)))
+			server := &Server{router: mux.NewRouter(), store: s, service: service}
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/projects/%d/cfd%s", tc.projectID, tc.query), nil)
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if w.Code != http.StatusOK {
+				return
+			}
+			var cfd model.CFD
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&cfd))
+			assert.Equal(t, tc.expCFD, cfd)
+		})
+	}
+}
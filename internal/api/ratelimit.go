@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterTTL is the time after which idle client limiter is forgotten.
+const rateLimiterTTL = 10 * time.Minute
+
+// errRateLimited is thrown when client exceeds its request rate.
+var errRateLimited = errors.New("rate limit exceeded")
+
+// rateLimiter is the per client token bucket rate limiter.
+type rateLimiter struct {
+	m         sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*rateLimiterClient
+	lastSweep time.Time
+}
+
+// rateLimiterClient is the token bucket of a single client.
+type rateLimiterClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRateLimiter creates and returns a new rateLimiter instance allowing rps requests
+// per second with bursts of burst requests.
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		limit:   rate.Limit(rps),
+		burst:   burst,
+		clients: map[string]*rateLimiterClient{},
+	}
+}
+
+// allow takes a token from client's bucket. If there are no tokens it returns false
+// and the time after which the request can be retried.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if now.Sub(l.lastSweep) > rateLimiterTTL {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > rateLimiterTTL {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &rateLimiterClient{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+
+	res := c.limiter.ReserveN(now, 1)
+	if !res.OK() {
+		return false, rateLimiterTTL
+	}
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return false, delay
+	}
+
+	return true, 0
+}
+
+// rateLimit rejects requests of clients exceeding configured rate with 429.
+func (s *Server) rateLimit(next http.Handler) http.Handler {
+	if s.config == nil || s.config.RateLimit <= 0 {
+		return next
+	}
+	l := newRateLimiter(s.config.RateLimit, s.config.RateBurst)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, retryAfter := l.allow(s.clientKey(r), time.Now())
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			s.error(w, r, http.StatusTooManyRequests, errRateLimited)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitBody caps the size of request bodies.
+func (s *Server) limitBody(next http.Handler) http.Handler {
+	if s.config == nil || s.config.MaxBodyBytes <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > s.config.MaxBodyBytes {
+			s.error(w, r, http.StatusRequestEntityTooLarge, errRequestBodyTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, s.config.MaxBodyBytes)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientKey identifies the client rate limits are applied to. Requests are keyed by
+// client IP, taken from X-Forwarded-For when the server runs behind a trusted proxy.
+// The proxy appends the address it got the request from, entries before it are sent
+// by the client and can't be trusted.
+func (s *Server) clientKey(r *http.Request) string {
+	if s.config.TrustProxy {
+		fwd := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+		if ip := strings.TrimSpace(fwd[len(fwd)-1]); ip != "" {
+			return "ip:" + ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	l := newRateLimiter(1, 2)
+	now := time.Now()
+
+	ok, _ := l.allow("a", now)
+	assert.True(t, ok)
+	ok, _ = l.allow("a", now)
+	assert.True(t, ok)
+	ok, retryAfter := l.allow("a", now)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	ok, _ = l.allow("b", now)
+	assert.True(t, ok, "clients have separate buckets")
+	ok, _ = l.allow("a", now.Add(time.Second))
+	assert.True(t, ok, "bucket is refilled")
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	c := config.New()
+	c.RateLimit, c.RateBurst = 1, 1
+	l := logger.New(&bytes.Buffer{}, logger.ErrorLevel, logger.JSONFormat)
+	server := &Server{router: mux.NewRouter(), config: c, l: l}
+	server.configureRouter()
+	h := server.handler()
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	r.RemoteAddr = "10.0.0.2:1234"
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestServer_ClientKey(t *testing.T) {
+	testcases := []struct {
+		name       string
+		trustProxy bool
+		forwarded  []string
+		expKey     string
+	}{
+		{name: "remote address", expKey: "ip:10.0.0.1"},
+		{name: "proxy isn't trusted", forwarded: []string{"10.0.0.2"}, expKey: "ip:10.0.0.1"},
+		{name: "address appended by proxy", trustProxy: true, forwarded: []string{"10.0.0.2"}, expKey: "ip:10.0.0.2"},
+		{
+			name:       "address sent by client is ignored",
+			trustProxy: true,
+			forwarded:  []string{"1.2.3.4, 10.0.0.2"},
+			expKey:     "ip:10.0.0.2",
+		},
+		{
+			name:       "address in another header line is ignored",
+			trustProxy: true,
+			forwarded:  []string{"1.2.3.4", "10.0.0.2"},
+			expKey:     "ip:10.0.0.2",
+		},
+		{name: "header is empty", trustProxy: true, forwarded: []string{""}, expKey: "ip:10.0.0.1"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := config.New()
+			c.TrustProxy = tc.trustProxy
+			server := &Server{config: c}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "10.0.0.1:1234"
+			for _, f := range tc.forwarded {
+				r.Header.Add("X-Forwarded-For", f)
+			}
+
+			assert.Equal(t, tc.expKey, server.clientKey(r))
+		})
+	}
+}
+
+func TestIsBodyTooLarge(t *testing.T) {
+	assert.True(t, isBodyTooLarge(errRequestBodyTooLarge))
+	assert.True(t, isBodyTooLarge(fmt.Errorf("decode: %w", errRequestBodyTooLarge)))
+	assert.False(t, isBodyTooLarge(errTrailingData))
+
+	body := http.MaxBytesReader(httptest.NewRecorder(), ioutil.NopCloser(strings.NewReader("abc")), 2)
+	_, err := ioutil.ReadAll(body)
+	assert.True(t, isBodyTooLarge(err))
+}
+
+func TestServer_Decode(t *testing.T) {
+	c := config.New()
+	c.RateLimit, c.MaxBodyBytes = 0, 64
+	l := logger.New(&bytes.Buffer{}, logger.ErrorLevel, logger.JSONFormat)
+	server := &Server{router: mux.NewRouter(), config: c, l: l}
+	server.configureRouter()
+
+	testcases := []struct {
+		name    string
+		body    string
+		chunked bool
+		expCode int
+	}{
+		{
+			name:    "unknown fields are rejected",
+			body:    `{"name": "Project", "owner": "me"}`,
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name:    "trailing data is rejected",
+			body:    `{"name": "Project"} {"name": "Project"}`,
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name:    "too large body is rejected",
+			body:    `{"name": "` + strings.Repeat("a", 100) + `"}`,
+			expCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "too large body of unknown length is rejected",
+			body:    `{"name": "` + strings.Repeat("a", 100) + `"}`,
+			chunked: true,
+			expCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/api/v1/projects", strings.NewReader(tc.body))
+			if tc.chunked {
+				r.ContentLength = -1
+			}
+
+			server.handler().ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -4,58 +4,90 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"time"
+	"sync"
 
 	"github.com/gorilla/mux"
 
 	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
+	"github.com/imarrche/tasker/internal/metrics"
 	"github.com/imarrche/tasker/internal/service"
 	"github.com/imarrche/tasker/internal/service/web"
 	"github.com/imarrche/tasker/internal/store"
 	"github.com/imarrche/tasker/internal/store/inmem"
+	"github.com/imarrche/tasker/internal/store/instrumented"
 )
 
 // Server is the REST API server for Tasker.
 type Server struct {
-	l       *log.Logger
+	l       *logger.Logger
 	config  *config.Config
 	store   store.Store
 	router  *mux.Router
 	service service.Service
+	metrics *serverMetrics
+
+	migrations migrationVersioner
+	ready      int32
 }
 
 // NewServer creates a new Server instance.
-func NewServer(l *log.Logger, c *config.Config, store store.Store) *Server {
-	r := mux.NewRouter()
-	service := web.NewService(store)
-
-	return &Server{l: l, config: c, store: store, router: r, service: service}
+func NewServer(l *logger.Logger, c *config.Config, store store.Store) *Server {
+	return newServer(l, c, store)
 }
 
 // NewTestServer creates a new test Server instance.
 func NewTestServer() *Server {
-	l := log.New(os.Stdout, "", log.LstdFlags)
+	l := logger.New(os.Stdout, logger.DebugLevel, logger.TextFormat)
 	c := config.New()
 	store := inmem.TestStoreWithFixtures()
-	r := mux.NewRouter()
-	service := web.NewService(store)
 
-	return &Server{l: l, config: c, store: store, router: r, service: service}
+	return newServer(l, c, store)
+}
+
+// newServer creates a new Server instance with store instrumented.
+func newServer(l *logger.Logger, c *config.Config, s store.Store) *Server {
+	m := newServerMetrics()
+	if db, ok := s.(dbStatser); ok {
+		m.registry.MustRegister(metrics.NewDBStatsCollector("tasker_db_", db.Stats))
+	}
+	mv, _ := s.(migrationVersioner)
+	s = instrumented.New(s, m.storeLatency)
+
+	server := &Server{
+		l:       l,
+		config:  c,
+		store:   s,
+		router:  mux.NewRouter(),
+		service: web.NewService(s),
+		metrics: m,
+
+		migrations: mv,
+	}
+	m.registry.MustRegister(metrics.CollectorFunc(server.collectBoardMetrics))
+
+	return server
 }
 
 // Start starts the server.
 func (s *Server) Start() error {
+	// Base context of all requests, canceled when shutdown deadline is exceeded
+	// so in-flight store queries are aborted.
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
 	// Initializing HTTP server.
 	server := &http.Server{
 		Addr:         s.config.Addr,
-		Handler:      s.router,
-		IdleTimeout:  60 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
+		Handler:      s.handler(),
+		IdleTimeout:  s.config.IdleTimeout,
+		ReadTimeout:  s.config.ReadTimeout,
+		WriteTimeout: s.config.WriteTimeout,
+		BaseContext:  func(net.Listener) context.Context { return baseCtx },
 	}
 
 	// Setting up router.
@@ -68,62 +100,103 @@ func (s *Server) Start() error {
 	// Starting the server.
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			s.l.Fatal("couldn't start the server")
+			s.l.WithField("error", err).Fatal("couldn't start the server")
 		}
 	}()
-	s.l.Println("server started")
+	s.setReady(true)
+	s.l.WithField("addr", s.config.Addr).Info("server started")
+
+	// Deleting expired idempotency keys and taking column snapshots in the background.
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	for _, job := range []func(context.Context){s.cleanIdempotencyKeys, s.takeSnapshots} {
+		jobs.Add(1)
+		go func(job func(context.Context)) {
+			defer jobs.Done()
+			job(jobsCtx)
+		}(job)
+	}
 
 	<-done
+	// Failing readiness probe so no new traffic is routed to the server.
+	s.setReady(false)
 	// Gracefully shutting down the server.
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
 	defer func() {
 		cancel()
 	}()
 	if err := server.Shutdown(ctx); err != nil {
+		cancelRequests()
 		return errors.New("server couldn't gracefully shut down")
 	}
+	stopJobs()
+	jobs.Wait()
 	if err := s.store.Close(); err != nil {
 		return errors.New("couldn't close the store")
 	}
 
-	s.l.Println("server shutted down gracefully")
+	s.l.Info("server shutted down gracefully")
 
 	return nil
 }
 
 func (s *Server) configureRouter() {
+	s.router.Use(s.cors)
+	s.router.Handle("/metrics", s.metricsHandler()).Methods(http.MethodGet)
+	s.router.HandleFunc("/healthz", s.healthz()).Methods(http.MethodGet)
+	s.router.HandleFunc("/readyz", s.readyz()).Methods(http.MethodGet)
+
 	v1Router := s.router.PathPrefix("/api/v1").Subrouter()
+	v1Router.Use(s.idempotency)
+	v1Router.PathPrefix("/").Methods(http.MethodOptions).HandlerFunc(s.preflight())
 
 	projects := v1Router.PathPrefix("/projects").Subrouter()
 	projects.HandleFunc("", s.projectList()).Methods(http.MethodGet)
 	projects.HandleFunc("", s.projectCreate()).Methods(http.MethodPost)
 	projects.HandleFunc("/{project_id:[0-9]+}", s.projectDetail()).Methods(http.MethodGet)
 	projects.HandleFunc("/{project_id:[0-9]+}", s.projectUpdate()).Methods(http.MethodPut)
+	projects.HandleFunc("/{project_id:[0-9]+}", s.projectPatch()).Methods(http.MethodPatch)
 	projects.HandleFunc("/{project_id:[0-9]+}", s.projectDelete()).Methods(http.MethodDelete)
+	projects.HandleFunc("/{project_id:[0-9]+}/analytics", s.projectAnalytics()).Methods(http.MethodGet)
+	projects.HandleFunc("/{project_id:[0-9]+}/cfd", s.projectCFD()).Methods(http.MethodGet)
 	projects.HandleFunc("/{project_id:[0-9]+}/columns", s.columnList()).Methods(http.MethodGet)
 	projects.HandleFunc("/{project_id:[0-9]+}/columns", s.columnCreate()).Methods(http.MethodPost)
 
 	columns := v1Router.PathPrefix("/columns").Subrouter()
 	columns.HandleFunc("/{column_id:[0-9]+}", s.columnDetail()).Methods(http.MethodGet)
 	columns.HandleFunc("/{column_id:[0-9]+}", s.columnUpdate()).Methods(http.MethodPut)
+	columns.HandleFunc("/{column_id:[0-9]+}", s.columnPatch()).Methods(http.MethodPatch)
 	columns.HandleFunc("/{column_id:[0-9]+}/move", s.columnMove()).Methods(http.MethodPost)
 	columns.HandleFunc("/{column_id:[0-9]+}", s.columnDelete()).Methods(http.MethodDelete)
 	columns.HandleFunc("/{column_id:[0-9]+}/tasks", s.taskList()).Methods(http.MethodGet)
 	columns.HandleFunc("/{column_id:[0-9]+}/tasks", s.taskCreate()).Methods(http.MethodPost)
 
 	tasks := v1Router.PathPrefix("/tasks").Subrouter()
+	tasks.HandleFunc("/move", s.taskBulkMove()).Methods(http.MethodPost)
+	tasks.HandleFunc("/delete", s.taskBulkDelete()).Methods(http.MethodPost)
 	tasks.HandleFunc("/{task_id:[0-9]+}", s.taskDetail()).Methods(http.MethodGet)
 	tasks.HandleFunc("/{task_id:[0-9]+}", s.taskUpdate()).Methods(http.MethodPut)
+	tasks.HandleFunc("/{task_id:[0-9]+}", s.taskPatch()).Methods(http.MethodPatch)
 	tasks.HandleFunc("/{task_id:[0-9]+}/movex", s.taskMoveX()).Methods(http.MethodPost)
 	tasks.HandleFunc("/{task_id:[0-9]+}/movey", s.taskMoveY()).Methods(http.MethodPost)
 	tasks.HandleFunc("/{task_id:[0-9]+}", s.taskDelete()).Methods(http.MethodDelete)
+	tasks.HandleFunc("/{task_id:[0-9]+}/links", s.taskLinkList()).Methods(http.MethodGet)
+	tasks.HandleFunc("/{task_id:[0-9]+}/blockers", s.taskBlockerCreate()).Methods(http.MethodPost)
+	tasks.HandleFunc("/{task_id:[0-9]+}/blockers/{blocker_id:[0-9]+}", s.taskBlockerDelete()).Methods(http.MethodDelete)
 	tasks.HandleFunc("/{task_id:[0-9]+}/comments", s.commentList()).Methods(http.MethodGet)
 	tasks.HandleFunc("/{task_id:[0-9]+}/comments", s.commentCreate()).Methods(http.MethodPost)
 
 	comments := v1Router.PathPrefix("/comments").Subrouter()
 	comments.HandleFunc("/{comment_id:[0-9]+}", s.commentDetail()).Methods(http.MethodGet)
 	comments.HandleFunc("/{comment_id:[0-9]+}", s.commentUpdate()).Methods(http.MethodPut)
+	comments.HandleFunc("/{comment_id:[0-9]+}", s.commentPatch()).Methods(http.MethodPatch)
 	comments.HandleFunc("/{comment_id:[0-9]+}", s.commentDelete()).Methods(http.MethodDelete)
+
+	v1Router.HandleFunc("/batch", s.batch()).Methods(http.MethodPost)
+
+	admin := v1Router.PathPrefix("/admin").Subrouter()
+	admin.HandleFunc("/fsck", s.adminFsck()).Methods(http.MethodGet, http.MethodPost)
 }
 
 func (s *Server) respond(w http.ResponseWriter, r *http.Request, code int, data interface{}) {
@@ -137,12 +210,16 @@ func (s *Server) respond(w http.ResponseWriter, r *http.Request, code int, data
 
 func (s *Server) error(w http.ResponseWriter, r *http.Request, code int, err error) {
 	if code >= 500 {
-		s.l.Printf("[SERVER ERROR]: %s\n", err.Error())
+		l := s.requestLogger(r).WithField("status", code)
+		if err != nil {
+			l = l.WithField("error", err)
+		}
+		l.Error("server error")
 		err = nil // Do not show server error to users for security reasons.
 	}
-	if err != nil {
-		s.respond(w, r, code, map[string]string{"error": err.Error()})
-		return
-	}
-	s.respond(w, r, code, nil)
+
+	p := newProblem(r, code, err)
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(p)
 }
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// snapshotPeriod is how often the number of tasks in columns is saved for cumulative flow
+// diagrams. The last snapshot of a day is kept, so it shows the board at most
+// snapshotPeriod before the day ends.
+const snapshotPeriod = time.Hour
+
+// takeSnapshots saves the number of tasks in columns of all projects on start and then
+// periodically until ctx is done.
+func (s *Server) takeSnapshots(ctx context.Context) {
+	take := func() {
+		if err := s.service.Projects().TakeSnapshots(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			s.l.WithField("error", err).Error("couldn't take column snapshots")
+		}
+	}
+
+	take()
+	ticker := time.NewTicker(snapshotPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			take()
+		}
+	}
+}
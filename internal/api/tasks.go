@@ -1,9 +1,10 @@
 package api
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -20,7 +21,13 @@ func (s *Server) taskList() http.HandlerFunc {
 			return
 		}
 
-		ts, err := s.service.Tasks().GetByColumnID(columnID)
+		f, err := parseTaskFilter(r)
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ts, err := s.service.Tasks().GetByColumnIDAndFilter(r.Context(), columnID, f)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
@@ -45,17 +52,19 @@ func (s *Server) taskCreate() http.HandlerFunc {
 		}
 
 		var req request
-		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
 		t := model.Task{Name: req.Name, Description: req.Description, ColumnID: columnID}
-		t, err = s.service.Tasks().Create(t)
-		if err == store.ErrNotFound {
+		t, err = s.service.Tasks().Create(r.Context(), t)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if web.IsValidationError(err) {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
+		} else if web.IsWIPLimitError(err) || err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
 		} else if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 		} else {
@@ -72,7 +81,7 @@ func (s *Server) taskDetail() http.HandlerFunc {
 			return
 		}
 
-		t, err := s.service.Tasks().GetByID(id)
+		t, err := s.service.Tasks().GetByID(r.Context(), id)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err != nil {
@@ -96,20 +105,22 @@ func (s *Server) taskMoveX() http.HandlerFunc {
 		}
 
 		var req request
-		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
-		err = s.service.Tasks().MoveToColumnByID(id, req.Left)
-		if err == store.ErrNotFound {
+		t, err := s.service.Tasks().MoveToColumnByID(r.Context(), id, req.Left)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err == web.ErrInvalidMove {
 			s.error(w, r, http.StatusBadRequest, err)
+		} else if web.IsWIPLimitError(err) || err == web.ErrTaskIsBlocked || err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
 		} else if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 		} else {
-			s.respond(w, r, http.StatusOK, nil)
+			s.respond(w, r, http.StatusOK, t)
 		}
 	}
 }
@@ -127,16 +138,18 @@ func (s *Server) taskMoveY() http.HandlerFunc {
 		}
 
 		var req request
-		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
-		err = s.service.Tasks().MoveByID(id, req.Up)
+		err = s.service.Tasks().MoveByID(r.Context(), id, req.Up)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if err == web.ErrInvalidMove {
 			s.error(w, r, http.StatusBadRequest, err)
+		} else if err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
 		} else if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 		} else {
@@ -159,14 +172,47 @@ func (s *Server) taskUpdate() http.HandlerFunc {
 		}
 
 		var req request
-		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.error(w, r, http.StatusBadRequest, err)
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
 			return
 		}
 
 		t := model.Task{ID: id, Name: req.Name, Description: req.Description}
-		t, err = s.service.Tasks().Update(t)
-		if err == store.ErrNotFound {
+		t, err = s.service.Tasks().Update(r.Context(), t)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if web.IsValidationError(err) {
+			s.error(w, r, http.StatusUnprocessableEntity, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, t)
+		}
+	}
+}
+
+func (s *Server) taskPatch() http.HandlerFunc {
+	type request struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		var req request
+		if err := s.decodePatch(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+
+		patch := model.TaskPatch{Name: req.Name, Description: req.Description}
+		t, err := s.service.Tasks().Patch(r.Context(), id, patch)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
 			s.error(w, r, http.StatusNotFound, nil)
 		} else if web.IsValidationError(err) {
 			s.error(w, r, http.StatusUnprocessableEntity, err)
@@ -186,9 +232,11 @@ func (s *Server) taskDelete() http.HandlerFunc {
 			return
 		}
 
-		err = s.service.Tasks().DeleteByID(id)
+		err = s.service.Tasks().DeleteByID(r.Context(), id)
 		if err == store.ErrNotFound {
 			s.error(w, r, http.StatusNotFound, nil)
+		} else if err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
 		} else if err != nil {
 			s.error(w, r, http.StatusInternalServerError, err)
 		} else {
@@ -196,3 +244,111 @@ func (s *Server) taskDelete() http.HandlerFunc {
 		}
 	}
 }
+
+func (s *Server) taskLinkList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ls, err := s.service.Tasks().GetLinksByID(r.Context(), id)
+		if err == store.ErrNotFound {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusOK, ls)
+		}
+	}
+}
+
+func (s *Server) taskBlockerCreate() http.HandlerFunc {
+	type request struct {
+		BlockerID int `json:"blocker_id"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		var req request
+		if err := s.decode(r, &req); err != nil {
+			s.error(w, r, decodeErrorStatus(err), err)
+			return
+		}
+
+		l := model.TaskLink{BlockerID: req.BlockerID, BlockedID: id}
+		l, err = s.service.Tasks().CreateLink(r.Context(), l)
+		if err == store.ErrNotFound || err == store.ErrInvalidReference {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if err == web.ErrDependencyCycle || err == store.ErrConflict {
+			s.error(w, r, http.StatusConflict, err)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusCreated, l)
+		}
+	}
+}
+
+func (s *Server) taskBlockerDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["task_id"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+		blockerID, err := strconv.Atoi(mux.Vars(r)["blocker_id"])
+		if err != nil {
+			s.error(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		err = s.service.Tasks().DeleteLink(r.Context(), model.TaskLink{BlockerID: blockerID, BlockedID: id})
+		if err == store.ErrNotFound {
+			s.error(w, r, http.StatusNotFound, nil)
+		} else if err != nil {
+			s.error(w, r, http.StatusInternalServerError, err)
+		} else {
+			s.respond(w, r, http.StatusNoContent, nil)
+		}
+	}
+}
+
+// parseTaskFilter reads the task filter from started and completed booleans and
+// completed_after and completed_before RFC 3339 times of the query string.
+func parseTaskFilter(r *http.Request) (model.TaskFilter, error) {
+	var f model.TaskFilter
+	q := r.URL.Query()
+	for _, param := range []struct {
+		name string
+		dst  **bool
+	}{{"started", &f.Started}, {"completed", &f.Completed}} {
+		if v := q.Get(param.name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return model.TaskFilter{}, fmt.Errorf("%s must be true or false", param.name)
+			}
+			*param.dst = &b
+		}
+	}
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{{"completed_after", &f.CompletedAfter}, {"completed_before", &f.CompletedBefore}} {
+		if v := q.Get(param.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return model.TaskFilter{}, fmt.Errorf("%s must be an RFC 3339 time", param.name)
+			}
+			*param.dst = &t
+		}
+	}
+
+	return f, nil
+}
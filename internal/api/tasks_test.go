@@ -2,9 +2,12 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -13,6 +16,9 @@ import (
 
 	"github.com/imarrche/tasker/internal/model"
 	mock_service "github.com/imarrche/tasker/internal/service/mocks"
+	"github.com/imarrche/tasker/internal/service/web"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/inmem"
 )
 
 func TestServer_TaskList(t *testing.T) {
@@ -31,7 +37,7 @@ func TestServer_TaskList(t *testing.T) {
 			name: "task list is retrieved",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, cID int, tasks []model.Task) {
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().GetByColumnID(cID).Return(tasks, nil)
+				ts.EXPECT().GetByColumnIDAndFilter(gomock.Any(), cID, model.TaskFilter{}).Return(tasks, nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			columnID: 1,
@@ -88,7 +94,7 @@ func TestServer_TaskСreate(t *testing.T) {
 					ID: 1, Name: task.Name, Index: 1, ColumnID: task.ColumnID,
 				}
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().Create(task).Return(createdTask, nil)
+				ts.EXPECT().Create(gomock.Any(), task).Return(createdTask, nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			columnID: 1,
@@ -96,6 +102,18 @@ func TestServer_TaskСreate(t *testing.T) {
 			expCode:  http.StatusCreated,
 			expBody:  model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
 		},
+		{
+			name: "column is deleted meanwhile",
+			mock: func(c *gomock.Controller, s *mock_service.MockService, cID int, task model.Task) {
+				ts := mock_service.NewMockTaskService(c)
+				ts.EXPECT().Create(gomock.Any(), task).Return(model.Task{}, store.ErrInvalidReference)
+				s.EXPECT().Tasks().Return(ts)
+			},
+			columnID: 1,
+			task:     model.Task{Name: "Task 1", ColumnID: 1},
+			expCode:  http.StatusNotFound,
+			expBody:  model.Task{},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -108,7 +126,9 @@ func TestServer_TaskСreate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.task)
+			json.NewEncoder(b).Encode(map[string]string{
+				"name": tc.task.Name, "description": tc.task.Description,
+			})
 			r, _ := http.NewRequest(http.MethodPost, "/api/v1/columns/1/tasks", b)
 
 			server.router.ServeHTTP(w, r)
@@ -122,6 +142,77 @@ func TestServer_TaskСreate(t *testing.T) {
 	}
 }
 
+func TestServer_TaskListFilter(t *testing.T) {
+	testcases := []struct {
+		name     string
+		query    string
+		expCode  int
+		expTasks []int
+	}{
+		{name: "all tasks are listed", query: "", expCode: http.StatusOK, expTasks: []int{1, 2, 3}},
+		{name: "completed tasks are listed", query: "?completed=true", expCode: http.StatusOK, expTasks: []int{3}},
+		{name: "started tasks are listed", query: "?started=1", expCode: http.StatusOK, expTasks: []int{2, 3}},
+		{
+			name:     "tasks in progress are listed",
+			query:    "?started=true&completed=false",
+			expCode:  http.StatusOK,
+			expTasks: []int{2},
+		},
+		{
+			name:     "tasks completed within period are listed",
+			query:    "?completed_after=2021-01-01T00:00:00Z&completed_before=2100-01-01T00:00:00Z",
+			expCode:  http.StatusOK,
+			expTasks: []int{3},
+		},
+		{
+			name:     "no tasks are completed after the time",
+			query:    "?completed_after=2100-01-01T00:00:00%2B03:00",
+			expCode:  http.StatusOK,
+			expTasks: []int{},
+		},
+		{name: "boolean is invalid", query: "?completed=yes", expCode: http.StatusBadRequest},
+		{name: "time is invalid", query: "?completed_before=2021-01-01", expCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			ctx := context.Background()
+			service := web.NewService(s)
+			_, err := service.Columns().Update(ctx, model.Column{ID: 2, Name: "Column 2", Type: model.ColumnTypeActive})
+			assert.NoError(t, err)
+			_, err = service.Columns().Create(ctx, model.Column{Name: "Done", ProjectID: 1, Type: model.ColumnTypeDone})
+			assert.NoError(t, err)
+			// Task 1 is completed and moved back to backlog, task 2 is started and task 3 is completed.
+			for _, move := range []struct{ id, columnID int }{{1, 4}, {1, 1}, {2, 2}, {3, 4}} {
+				_, err = service.Tasks().MoveToColumn(ctx, move.id, move.columnID)
+				assert.NoError(t, err)
+			}
+			server := &Server{router: mux.NewRouter(), store: s, service: service}
+			server.configureRouter()
+
+			ids := []int{}
+			for _, columnID := range []int{1, 2, 4} {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/columns/%d/tasks%s", columnID, tc.query), nil)
+
+				server.router.ServeHTTP(w, r)
+
+				assert.Equal(t, tc.expCode, w.Code)
+				if w.Code != http.StatusOK {
+					return
+				}
+				var ts []model.Task
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&ts))
+				for _, task := range ts {
+					ids = append(ids, task.ID)
+				}
+			}
+			assert.Equal(t, tc.expTasks, ids)
+		})
+	}
+}
+
 func TestServer_TaskDetail(t *testing.T) {
 	server := &Server{router: mux.NewRouter()}
 	server.configureRouter()
@@ -137,7 +228,7 @@ func TestServer_TaskDetail(t *testing.T) {
 			name: "task is retrieved",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, task model.Task) {
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().GetByID(task.ID).Return(task, nil)
+				ts.EXPECT().GetByID(gomock.Any(), task.ID).Return(task, nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			task:    model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
@@ -183,7 +274,7 @@ func TestServer_TaskMoveX(t *testing.T) {
 			name: "task is moved right",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, left bool, task model.Task) {
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().MoveToColumnByID(task.ID, left).Return(nil)
+				ts.EXPECT().MoveToColumnByID(gomock.Any(), task.ID, left).Return(task, nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			task:    model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
@@ -194,13 +285,26 @@ func TestServer_TaskMoveX(t *testing.T) {
 			name: "task is moved left",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, left bool, task model.Task) {
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().MoveToColumnByID(task.ID, left).Return(nil)
+				ts.EXPECT().MoveToColumnByID(gomock.Any(), task.ID, left).Return(task, nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			task:    model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 2},
 			left:    true,
 			expCode: http.StatusOK,
 		},
+		{
+			name: "column has reached its wip limit",
+			mock: func(c *gomock.Controller, s *mock_service.MockService, left bool, task model.Task) {
+				ts := mock_service.NewMockTaskService(c)
+				ts.EXPECT().MoveToColumnByID(gomock.Any(), task.ID, left).Return(
+					model.Task{}, &web.WIPLimitError{ColumnID: 2, Limit: 1},
+				)
+				s.EXPECT().Tasks().Return(ts)
+			},
+			task:    model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
+			left:    false,
+			expCode: http.StatusConflict,
+		},
 	}
 
 	type request struct {
@@ -242,7 +346,7 @@ func TestServer_TaskMoveY(t *testing.T) {
 			name: "task is moved down",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, up bool, task model.Task) {
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().MoveByID(task.ID, up).Return(nil)
+				ts.EXPECT().MoveByID(gomock.Any(), task.ID, up).Return(nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			task:    model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
@@ -253,13 +357,24 @@ func TestServer_TaskMoveY(t *testing.T) {
 			name: "task is moved up",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, up bool, task model.Task) {
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().MoveByID(task.ID, up).Return(nil)
+				ts.EXPECT().MoveByID(gomock.Any(), task.ID, up).Return(nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			task:    model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: 1},
 			up:      true,
 			expCode: http.StatusOK,
 		},
+		{
+			name: "task isn't moved because of concurrent move",
+			mock: func(c *gomock.Controller, s *mock_service.MockService, up bool, task model.Task) {
+				ts := mock_service.NewMockTaskService(c)
+				ts.EXPECT().MoveByID(gomock.Any(), task.ID, up).Return(store.ErrConflict)
+				s.EXPECT().Tasks().Return(ts)
+			},
+			task:    model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: 1},
+			up:      true,
+			expCode: http.StatusConflict,
+		},
 	}
 
 	type request struct {
@@ -304,7 +419,7 @@ func TestServer_TaskUpdate(t *testing.T) {
 					ID: 1, Name: task.Name, Description: task.Description, Index: 1, ColumnID: 1,
 				}
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().Update(task).Return(updatedTask, nil)
+				ts.EXPECT().Update(gomock.Any(), task).Return(updatedTask, nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			task:    model.Task{ID: 1, Name: "Updated task", Description: "Task description."},
@@ -323,7 +438,9 @@ func TestServer_TaskUpdate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			b := &bytes.Buffer{}
-			json.NewEncoder(b).Encode(tc.task)
+			json.NewEncoder(b).Encode(map[string]string{
+				"name": tc.task.Name, "description": tc.task.Description,
+			})
 			r, _ := http.NewRequest(http.MethodPut, "/api/v1/tasks/1", b)
 
 			server.router.ServeHTTP(w, r)
@@ -351,7 +468,7 @@ func TestServer_TaskDelete(t *testing.T) {
 			name: "task is deleted",
 			mock: func(c *gomock.Controller, s *mock_service.MockService, task model.Task) {
 				ts := mock_service.NewMockTaskService(c)
-				ts.EXPECT().DeleteByID(task.ID).Return(nil)
+				ts.EXPECT().DeleteByID(gomock.Any(), task.ID).Return(nil)
 				s.EXPECT().Tasks().Return(ts)
 			},
 			task:    model.Task{ID: 1, Name: "Task 1"},
@@ -376,3 +493,246 @@ func TestServer_TaskDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_TaskPatch(t *testing.T) {
+	server := &Server{router: mux.NewRouter()}
+	server.configureRouter()
+
+	testcases := []struct {
+		name    string
+		body    string
+		mock    func(*gomock.Controller, *mock_service.MockService)
+		expCode int
+		expBody string
+	}{
+		{
+			name: "only present fields are patched",
+			body: `{"description": "Details"}`,
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				description := "Details"
+				ts := mock_service.NewMockTaskService(c)
+				ts.EXPECT().Patch(gomock.Any(), 1, model.TaskPatch{Description: &description}).Return(
+					model.Task{ID: 1, Name: "Task", Description: description, Index: 1, ColumnID: 1}, nil,
+				)
+				s.EXPECT().Tasks().Return(ts)
+			},
+			expCode: http.StatusOK,
+			expBody: `{"id": 1, "name": "Task", "description": "Details", "index": 1, "column_id": 1, "blocked": false}`,
+		},
+		{
+			name: "task isn't found",
+			body: `{"name": "Task"}`,
+			mock: func(c *gomock.Controller, s *mock_service.MockService) {
+				ts := mock_service.NewMockTaskService(c)
+				ts.EXPECT().Patch(gomock.Any(), 1, gomock.Any()).Return(model.Task{}, store.ErrNotFound)
+				s.EXPECT().Tasks().Return(ts)
+			},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			s := mock_service.NewMockService(c)
+			tc.mock(c, s)
+			server.service = s
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPatch, "/api/v1/tasks/1", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", "application/merge-patch+json")
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_TaskWIPLimit(t *testing.T) {
+	testcases := []struct {
+		name       string
+		soft       bool
+		method     string
+		path       string
+		body       string
+		expCode    int
+		expBody    string
+		expColumn2 []int
+	}{
+		{
+			name:       "task can't be created in full column",
+			method:     http.MethodPost,
+			path:       "/api/v1/columns/2/tasks",
+			body:       `{"name": "Task 4"}`,
+			expCode:    http.StatusConflict,
+			expColumn2: []int{3},
+		},
+		{
+			name:       "task can't be moved to full column",
+			method:     http.MethodPost,
+			path:       "/api/v1/tasks/1/movex",
+			body:       `{"left": false}`,
+			expCode:    http.StatusConflict,
+			expColumn2: []int{3},
+		},
+		{
+			name:    "task is created with a warning",
+			soft:    true,
+			method:  http.MethodPost,
+			path:    "/api/v1/columns/2/tasks",
+			body:    `{"name": "Task 4"}`,
+			expCode: http.StatusCreated,
+			expBody: `{"id": 4, "name": "Task 4", "description": "", "index": 2, "column_id": 2, "blocked": false,
+				"warnings": [{"code": "wip_limit_exceeded", "message": "column 2 has reached its wip limit of 1 tasks"}]}`,
+			expColumn2: []int{3, 4},
+		},
+		{
+			name:    "task is moved with a warning",
+			soft:    true,
+			method:  http.MethodPost,
+			path:    "/api/v1/tasks/1/movex",
+			body:    `{"left": false}`,
+			expCode: http.StatusOK,
+			expBody: `{"id": 1, "name": "Task 1", "description": "", "index": 2, "column_id": 2, "blocked": false,
+				"warnings": [{"code": "wip_limit_exceeded", "message": "column 2 has reached its wip limit of 1 tasks"}]}`,
+			expColumn2: []int{3, 1},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			ctx := context.Background()
+			p, _ := s.Projects().GetByID(ctx, 1)
+			p.SoftWIPLimits = tc.soft
+			s.Projects().Update(ctx, p)
+			c, _ := s.Columns().GetByID(ctx, 2)
+			c.WIPLimit = 1
+			s.Columns().Update(ctx, c)
+			server := &Server{router: mux.NewRouter(), store: s, service: web.NewService(s)}
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, w.Body.String())
+			}
+			assert.Equal(t, tc.expColumn2, columnTasks(t, s, 2))
+		})
+	}
+}
+
+func TestServer_TaskDependencies(t *testing.T) {
+	testcases := []struct {
+		name    string
+		strict  bool
+		method  string
+		path    string
+		body    string
+		expCode int
+		expBody string
+	}{
+		{
+			name:    "blocker is added",
+			method:  http.MethodPost,
+			path:    "/api/v1/tasks/2/blockers",
+			body:    `{"blocker_id": 1}`,
+			expCode: http.StatusCreated,
+			expBody: `{"blocker_id": 1, "blocked_id": 2}`,
+		},
+		{
+			name:    "blocker makes a cycle",
+			method:  http.MethodPost,
+			path:    "/api/v1/tasks/3/blockers",
+			body:    `{"blocker_id": 1}`,
+			expCode: http.StatusConflict,
+		},
+		{
+			name:    "blocker is added twice",
+			method:  http.MethodPost,
+			path:    "/api/v1/tasks/1/blockers",
+			body:    `{"blocker_id": 3}`,
+			expCode: http.StatusConflict,
+		},
+		{
+			name:    "blocker doesn't exist",
+			method:  http.MethodPost,
+			path:    "/api/v1/tasks/2/blockers",
+			body:    `{"blocker_id": 99}`,
+			expCode: http.StatusNotFound,
+		},
+		{
+			name:    "blocker is removed",
+			method:  http.MethodDelete,
+			path:    "/api/v1/tasks/1/blockers/3",
+			expCode: http.StatusNoContent,
+		},
+		{
+			name:    "removed blocker doesn't exist",
+			method:  http.MethodDelete,
+			path:    "/api/v1/tasks/2/blockers/3",
+			expCode: http.StatusNotFound,
+		},
+		{
+			name:    "links are listed",
+			method:  http.MethodGet,
+			path:    "/api/v1/tasks/3/links",
+			expCode: http.StatusOK,
+			expBody: `[{"blocker_id": 3, "blocked_id": 1}]`,
+		},
+		{
+			name:    "task is blocked",
+			method:  http.MethodGet,
+			path:    "/api/v1/tasks/1",
+			expCode: http.StatusOK,
+			expBody: `{"id": 1, "name": "Task 1", "description": "", "index": 1, "column_id": 1, "blocked": true}`,
+		},
+		{
+			name:    "blocked task is moved to the last column",
+			method:  http.MethodPost,
+			path:    "/api/v1/tasks/1/movex",
+			body:    `{"left": false}`,
+			expCode: http.StatusOK,
+		},
+		{
+			name:    "blocked task isn't moved to the last column",
+			strict:  true,
+			method:  http.MethodPost,
+			path:    "/api/v1/tasks/1/movex",
+			body:    `{"left": false}`,
+			expCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := inmem.TestStoreWithFixtures()
+			ctx := context.Background()
+			p, _ := s.Projects().GetByID(ctx, 1)
+			p.StrictDependencies = tc.strict
+			s.Projects().Update(ctx, p)
+			s.TaskLinks().Create(ctx, model.TaskLink{BlockerID: 3, BlockedID: 1})
+			server := &Server{router: mux.NewRouter(), store: s, service: web.NewService(s)}
+			server.configureRouter()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+
+			server.router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expCode, w.Code)
+			if tc.expBody != "" {
+				assert.JSONEq(t, tc.expBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -1,36 +1,222 @@
 package config
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Config is the global project config.
 type Config struct {
-	Server
-	PostgreSQL
+	Server     `yaml:"server"`
+	Storage    `yaml:"storage"`
+	PostgreSQL `yaml:"postgres"`
+	SQLite     `yaml:"sqlite"`
+	InMemory   `yaml:"inmem"`
+	Log        `yaml:"log"`
 }
 
-// New creates a new Config instance.
-func New() *Config {
+// Default creates a new Config instance with default values.
+func Default() *Config {
 	return &Config{
 		Server: Server{
-			Addr: getEnv("SERVER_ADDR", ":8080"),
+			Addr:              ":8080",
+			ReadTimeout:       5 * time.Second,
+			WriteTimeout:      10 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   5 * time.Second,
+			RateLimit:         10,
+			RateBurst:         20,
+			MaxBodyBytes:      1 << 20,
+			IdempotencyWindow: 24 * time.Hour,
+			CORS: CORS{
+				AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+				AllowedHeaders: []string{"Content-Type", "X-Request-ID", "Idempotency-Key"},
+				ExposedHeaders: []string{"X-Request-ID", "Retry-After", "Idempotent-Replayed"},
+				MaxAge:         600,
+			},
+		},
+		Storage: Storage{
+			Driver: DriverPostgreSQL,
 		},
 		PostgreSQL: PostgreSQL{
-			Host:     getEnv("POSTGRES_HOST", "locahost"),
-			Port:     getEnv("POSTGRES_PORT", "5432"),
-			User:     getEnv("POSTGRES_USER", "postgres"),
-			Password: getEnv("POSTGRES_PASSWORD", "123"),
-			DbName:   getEnv("POSTGRES_DBNAME", "tasker"),
-			SSLMode:  getEnv("POSTGRES_SSLMODE", "disable"),
+			Host:         "localhost",
+			Port:         "5432",
+			User:         "postgres",
+			DbName:       "tasker",
+			SSLMode:      "disable",
+			MaxOpenConns: 25,
+			MaxIdleConns: 25,
+		},
+		SQLite: SQLite{
+			Path: "tasker.db",
 		},
+		InMemory: InMemory{
+			Fsync:         FsyncAlways,
+			FsyncInterval: time.Second,
+			SnapshotEvery: 1000,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+	}
+}
+
+// New creates a new Config instance with default values overridden by environment.
+// Malformed environment variables are ignored, use Load to report them.
+func New() *Config {
+	c := Default()
+	c.readEnv()
+
+	return c
+}
+
+// readEnv overrides config values with environment variables.
+func (c *Config) readEnv() error {
+	e := &env{}
+
+	e.string("SERVER_ADDR", &c.Addr)
+	e.duration("SERVER_READ_TIMEOUT", &c.ReadTimeout)
+	e.duration("SERVER_WRITE_TIMEOUT", &c.WriteTimeout)
+	e.duration("SERVER_IDLE_TIMEOUT", &c.IdleTimeout)
+	e.duration("SERVER_SHUTDOWN_TIMEOUT", &c.ShutdownTimeout)
+	e.float("SERVER_RATE_LIMIT", &c.RateLimit)
+	e.int("SERVER_RATE_BURST", &c.RateBurst)
+	e.int64("SERVER_MAX_BODY_BYTES", &c.MaxBodyBytes)
+	e.bool("SERVER_TRUST_PROXY", &c.TrustProxy)
+	e.duration("SERVER_IDEMPOTENCY_WINDOW", &c.IdempotencyWindow)
+	e.string("SERVER_ADMIN_TOKEN", &c.AdminToken)
+	e.list("SERVER_CORS_ALLOWED_ORIGINS", &c.CORS.AllowedOrigins)
+	e.list("SERVER_CORS_ALLOWED_METHODS", &c.CORS.AllowedMethods)
+	e.list("SERVER_CORS_ALLOWED_HEADERS", &c.CORS.AllowedHeaders)
+	e.list("SERVER_CORS_EXPOSED_HEADERS", &c.CORS.ExposedHeaders)
+	e.bool("SERVER_CORS_ALLOW_CREDENTIALS", &c.CORS.AllowCredentials)
+	e.int("SERVER_CORS_MAX_AGE", &c.CORS.MaxAge)
+
+	e.string("STORAGE_DRIVER", &c.Storage.Driver)
+
+	e.string("POSTGRES_HOST", &c.PostgreSQL.Host)
+	e.string("POSTGRES_PORT", &c.PostgreSQL.Port)
+	e.string("POSTGRES_USER", &c.PostgreSQL.User)
+	e.string("POSTGRES_PASSWORD", &c.PostgreSQL.Password)
+	e.string("POSTGRES_DBNAME", &c.PostgreSQL.DbName)
+	e.string("POSTGRES_SSLMODE", &c.PostgreSQL.SSLMode)
+	e.int("POSTGRES_MAX_OPEN_CONNS", &c.PostgreSQL.MaxOpenConns)
+	e.int("POSTGRES_MAX_IDLE_CONNS", &c.PostgreSQL.MaxIdleConns)
+	e.duration("POSTGRES_CONN_MAX_LIFETIME", &c.PostgreSQL.ConnMaxLifetime)
+
+	e.string("SQLITE_PATH", &c.SQLite.Path)
+
+	e.string("INMEM_DIR", &c.InMemory.Dir)
+	e.string("INMEM_FSYNC", &c.InMemory.Fsync)
+	e.duration("INMEM_FSYNC_INTERVAL", &c.InMemory.FsyncInterval)
+	e.int("INMEM_SNAPSHOT_EVERY", &c.InMemory.SnapshotEvery)
+
+	e.string("LOG_LEVEL", &c.Log.Level)
+	e.string("LOG_FORMAT", &c.Log.Format)
+
+	if len(e.errs) > 0 {
+		return fmt.Errorf("invalid environment: %s", strings.Join(e.errs, "; "))
 	}
+
+	return nil
+}
+
+// env reads environment variables into config values collecting parsing errors.
+// Variables that aren't set leave values untouched.
+type env struct {
+	errs []string
 }
 
-// getEnv is the os.Getenv but with default value if environment variable wasn't set.
-func getEnv(key, defaultValue string) string {
+func (e *env) string(key string, v *string) {
+	if value := os.Getenv(key); len(value) != 0 {
+		*v = value
+	}
+}
+
+func (e *env) int(key string, v *int) {
+	e.parse(key, "an integer", func(s string) error {
+		value, err := strconv.Atoi(s)
+		if err == nil {
+			*v = value
+		}
+
+		return err
+	})
+}
+
+func (e *env) int64(key string, v *int64) {
+	e.parse(key, "an integer", func(s string) error {
+		value, err := strconv.ParseInt(s, 10, 64)
+		if err == nil {
+			*v = value
+		}
+
+		return err
+	})
+}
+
+func (e *env) float(key string, v *float64) {
+	e.parse(key, "a number", func(s string) error {
+		value, err := strconv.ParseFloat(s, 64)
+		if err == nil {
+			*v = value
+		}
+
+		return err
+	})
+}
+
+func (e *env) bool(key string, v *bool) {
+	e.parse(key, "a boolean", func(s string) error {
+		value, err := strconv.ParseBool(s)
+		if err == nil {
+			*v = value
+		}
+
+		return err
+	})
+}
+
+func (e *env) duration(key string, v *time.Duration) {
+	e.parse(key, "a duration", func(s string) error {
+		value, err := time.ParseDuration(s)
+		if err == nil {
+			*v = value
+		}
+
+		return err
+	})
+}
+
+func (e *env) list(key string, v *[]string) {
+	if value := os.Getenv(key); len(value) != 0 {
+		*v = splitList(value)
+	}
+}
+
+// parse sets value of the environment variable with set if it's set.
+func (e *env) parse(key, kind string, set func(string) error) {
 	value := os.Getenv(key)
 	if len(value) == 0 {
-		return defaultValue
+		return
+	}
+	if err := set(value); err != nil {
+		e.errs = append(e.errs, fmt.Sprintf("%s: %q is not %s", key, value, kind))
+	}
+}
+
+// splitList splits comma separated list skipping empty elements.
+func splitList(value string) []string {
+	list := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
 	}
 
-	return value
+	return list
 }
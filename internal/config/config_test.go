@@ -0,0 +1,164 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFile writes config file to temporary directory and returns its path.
+func writeFile(t *testing.T, name, data string) string {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// setEnv sets environment variable for the duration of the test.
+func setEnv(t *testing.T, key, value string) {
+	prev, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	path := writeFile(t, "tasker.yaml", `
+server:
+  addr: ":9000"
+  read_timeout: 7s
+  rate_burst: 50
+  cors:
+    allowed_origins: ["http://app.test"]
+postgres:
+  host: db
+  port: "6543"
+  max_open_conns: 10
+log:
+  level: debug
+`)
+	setEnv(t, "SERVER_ADDR", ":9001")
+	setEnv(t, "POSTGRES_HOST", "env-db")
+
+	c, args, err := Load("tasker", []string{"-config", path, "-addr", ":9002", "-log-format", "text", "up", "2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"up", "2"}, args)
+	// Default value.
+	assert.Equal(t, 10*time.Second, c.WriteTimeout)
+	// File value.
+	assert.Equal(t, 7*time.Second, c.ReadTimeout)
+	assert.Equal(t, 50, c.RateBurst)
+	assert.Equal(t, []string{"http://app.test"}, c.CORS.AllowedOrigins)
+	assert.Equal(t, "6543", c.PostgreSQL.Port)
+	assert.Equal(t, 10, c.PostgreSQL.MaxOpenConns)
+	assert.Equal(t, "debug", c.Log.Level)
+	// Environment overrides file.
+	assert.Equal(t, "env-db", c.PostgreSQL.Host)
+	// Flags override environment.
+	assert.Equal(t, ":9002", c.Addr)
+	assert.Equal(t, "text", c.Log.Format)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	testcases := []struct {
+		name   string
+		file   string
+		data   string
+		env    map[string]string
+		args   []string
+		expErr string
+	}{
+		{
+			name:   "unknown file key",
+			file:   "tasker.yaml",
+			data:   "server:\n  adr: \":8080\"\n",
+			expErr: "field adr not found",
+		},
+		{
+			name:   "unsupported file format",
+			file:   "tasker.json",
+			data:   "{}",
+			expErr: `unsupported format ".json"`,
+		},
+		{
+			name:   "malformed environment variable",
+			env:    map[string]string{"SERVER_READ_TIMEOUT": "soon"},
+			expErr: `SERVER_READ_TIMEOUT: "soon" is not a duration`,
+		},
+		{
+			name:   "unknown flag",
+			args:   []string{"-verbose"},
+			expErr: "flag provided but not defined: -verbose",
+		},
+		{
+			name:   "unknown storage driver",
+			args:   []string{"-storage-driver", "mongo"},
+			expErr: `storage.driver "mongo" must be one of pg, inmem, sqlite`,
+		},
+		{
+			name:   "invalid value",
+			args:   []string{"-db-sslmode", "sometimes", "-rate-limit", "-1"},
+			expErr: `invalid config: server.rate_limit can't be negative; postgres.sslmode "sometimes"`,
+		},
+		{
+			name:   "negative idempotency window",
+			env:    map[string]string{"SERVER_IDEMPOTENCY_WINDOW": "-1h"},
+			expErr: "invalid config: server.idempotency_window can't be negative",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"-config", writeFile(t, tc.file, tc.data)}, args...)
+			}
+			for k, v := range tc.env {
+				setEnv(t, k, v)
+			}
+
+			_, _, err := Load("tasker", args)
+
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+
+	c := Default()
+	c.Addr = "8080"
+	c.ReadTimeout = 0
+	c.PostgreSQL.Host = ""
+	c.CORS.AllowedOrigins, c.CORS.AllowCredentials = []string{"*"}, true
+	c.Log.Level = "loud"
+
+	assert.EqualError(
+		t, c.Validate(),
+		`invalid config: server.addr "8080" must be in host:port form; `+
+			`server.read_timeout must be positive; `+
+			`server.cors.allowed_origins can't contain "*" when server.cors.allow_credentials is set; `+
+			`postgres.host is required; `+
+			`log.level: unknown log level "loud"`,
+	)
+}
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+// Fsync policies of in memory store write-ahead log.
+const (
+	// FsyncAlways flushes the log to disk after every mutation.
+	FsyncAlways = "always"
+	// FsyncInterval flushes the log to disk periodically, a crash loses at most
+	// the last interval of mutations.
+	FsyncInterval = "interval"
+	// FsyncNever leaves flushing to the operating system.
+	FsyncNever = "never"
+)
+
+// InMemory is the config for in memory store.
+type InMemory struct {
+	// Dir is the directory for write-ahead log and snapshots. Empty Dir keeps data
+	// only in memory.
+	Dir string `yaml:"dir"`
+	// Fsync is one of FsyncAlways, FsyncInterval and FsyncNever.
+	Fsync string `yaml:"fsync"`
+	// FsyncInterval is the period of flushing the log with FsyncInterval policy.
+	FsyncInterval time.Duration `yaml:"fsync_interval"`
+	// SnapshotEvery is the number of logged mutations after which a snapshot is written
+	// and the log is truncated, 0 writes snapshots only on close.
+	SnapshotEvery int `yaml:"snapshot_every"`
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load creates a new Config instance layering default values, YAML config file, environment
+// variables and command-line flags, each overriding the previous one. Config file is set with
+// -config flag or TASKER_CONFIG environment variable. Loaded config is validated, arguments
+// left after flags are returned.
+func Load(name string, args []string) (*Config, []string, error) {
+	// Parsing flags into a scratch config first to find the config file.
+	path := os.Getenv("TASKER_CONFIG")
+	fs := newFlagSet(name, Default(), &path)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	c := Default()
+	if path != "" {
+		if err := c.readFile(path); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := c.readEnv(); err != nil {
+		return nil, nil, err
+	}
+
+	// Parsing flags again on top of file and environment, only set flags override values.
+	fs = newFlagSet(name, c, &path)
+	fs.SetOutput(ioutil.Discard)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return c, fs.Args(), nil
+}
+
+// readFile overrides config values with ones from YAML file, unknown keys are rejected.
+func (c *Config) readFile(path string) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q", path, ext)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// newFlagSet creates flag set binding flags to config values, current values are defaults.
+func newFlagSet(name string, c *Config, path *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	fs.StringVar(path, "config", *path, "path to YAML config file")
+
+	fs.StringVar(&c.Addr, "addr", c.Addr, "address to listen on")
+	fs.DurationVar(&c.ReadTimeout, "read-timeout", c.ReadTimeout, "request read timeout")
+	fs.DurationVar(&c.WriteTimeout, "write-timeout", c.WriteTimeout, "response write timeout")
+	fs.DurationVar(&c.IdleTimeout, "idle-timeout", c.IdleTimeout, "keep-alive idle timeout")
+	fs.DurationVar(
+		&c.ShutdownTimeout, "shutdown-timeout", c.ShutdownTimeout, "graceful shutdown timeout",
+	)
+	fs.Float64Var(&c.RateLimit, "rate-limit", c.RateLimit, "requests per second per client")
+	fs.IntVar(&c.RateBurst, "rate-burst", c.RateBurst, "request burst per client")
+	fs.Int64Var(&c.MaxBodyBytes, "max-body-bytes", c.MaxBodyBytes, "maximum request body size")
+	fs.DurationVar(
+		&c.IdempotencyWindow, "idempotency-window", c.IdempotencyWindow,
+		"how long responses to requests with Idempotency-Key are replayed",
+	)
+	fs.Var(
+		(*listValue)(&c.CORS.AllowedOrigins), "cors-allowed-origins",
+		"comma separated origins allowed to call the API",
+	)
+
+	fs.StringVar(&c.Storage.Driver, "storage-driver", c.Storage.Driver, "store: pg, inmem, sqlite")
+
+	fs.StringVar(&c.PostgreSQL.Host, "db-host", c.PostgreSQL.Host, "PostgreSQL host")
+	fs.StringVar(&c.PostgreSQL.Port, "db-port", c.PostgreSQL.Port, "PostgreSQL port")
+	fs.StringVar(&c.PostgreSQL.User, "db-user", c.PostgreSQL.User, "PostgreSQL user")
+	fs.StringVar(&c.PostgreSQL.DbName, "db-name", c.PostgreSQL.DbName, "PostgreSQL database")
+	fs.StringVar(&c.PostgreSQL.SSLMode, "db-sslmode", c.PostgreSQL.SSLMode, "PostgreSQL SSL mode")
+	fs.IntVar(
+		&c.PostgreSQL.MaxOpenConns, "db-max-open-conns", c.PostgreSQL.MaxOpenConns,
+		"maximum number of open database connections",
+	)
+	fs.IntVar(
+		&c.PostgreSQL.MaxIdleConns, "db-max-idle-conns", c.PostgreSQL.MaxIdleConns,
+		"maximum number of idle database connections",
+	)
+	fs.DurationVar(
+		&c.PostgreSQL.ConnMaxLifetime, "db-conn-max-lifetime", c.PostgreSQL.ConnMaxLifetime,
+		"maximum amount of time a database connection may be reused",
+	)
+
+	fs.StringVar(&c.SQLite.Path, "sqlite-path", c.SQLite.Path, "SQLite database file")
+
+	fs.StringVar(
+		&c.InMemory.Dir, "inmem-dir", c.InMemory.Dir,
+		"directory to persist in memory store to, empty keeps data only in memory",
+	)
+	fs.StringVar(
+		&c.InMemory.Fsync, "inmem-fsync", c.InMemory.Fsync,
+		"in memory store log fsync policy: always, interval, never",
+	)
+
+	fs.StringVar(&c.Log.Level, "log-level", c.Log.Level, "log level: debug, info, warn, error")
+	fs.StringVar(&c.Log.Format, "log-format", c.Log.Format, "log format: text, json")
+
+	return fs
+}
+
+// listValue is the flag.Value for comma separated lists.
+type listValue []string
+
+func (v *listValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return strings.Join(*v, ",")
+}
+
+func (v *listValue) Set(s string) error {
+	*v = splitList(s)
+
+	return nil
+}
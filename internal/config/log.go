@@ -0,0 +1,7 @@
+package config
+
+// Log is the config for logging.
+type Log struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
@@ -1,11 +1,38 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // PostgreSQL is the config for PostgreSQL database.
 type PostgreSQL struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DbName   string
-	SSLMode  string
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DbName   string `yaml:"dbname"`
+	SSLMode  string `yaml:"sslmode"`
+	// MaxOpenConns is the maximum number of open connections, 0 means unlimited.
+	MaxOpenConns int `yaml:"max_open_conns"`
+	// MaxIdleConns is the maximum number of idle connections kept in the pool.
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	// ConnMaxLifetime is the maximum amount of time a connection may be reused,
+	// 0 means forever.
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+// DSN returns the connection string for PostgreSQL driver.
+func (c PostgreSQL) DSN() string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		quoteDSN(c.Host), quoteDSN(c.Port), quoteDSN(c.User),
+		quoteDSN(c.Password), quoteDSN(c.DbName), quoteDSN(c.SSLMode),
+	)
+}
+
+// quoteDSN quotes connection string value so it may be empty or contain spaces.
+func quoteDSN(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
 }
@@ -1,6 +1,46 @@
 package config
 
+import "time"
+
 // Server is the config for REST API server.
 type Server struct {
-	Addr string
+	Addr string `yaml:"addr"`
+	// ReadTimeout is the maximum duration for reading the entire request.
+	ReadTimeout time.Duration `yaml:"read_timeout"`
+	// WriteTimeout is the maximum duration before timing out writes of the response.
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	// IdleTimeout is the maximum amount of time to wait for the next keep-alive request.
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout is the time given to in-flight requests on graceful shutdown.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// RateLimit is the number of requests per second allowed for a client, 0 disables
+	// rate limiting.
+	RateLimit float64 `yaml:"rate_limit"`
+	// RateBurst is the number of requests a client can make at once.
+	RateBurst int `yaml:"rate_burst"`
+	// MaxBodyBytes is the maximum size of request body.
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	// TrustProxy makes the server identify clients by the last X-Forwarded-For entry,
+	// which is appended by the proxy.
+	TrustProxy bool `yaml:"trust_proxy"`
+	// IdempotencyWindow is how long responses of POST requests with Idempotency-Key
+	// header are replayed to retries, 0 disables idempotency keys.
+	IdempotencyWindow time.Duration `yaml:"idempotency_window"`
+	// AdminToken is the bearer token of admin endpoints, empty token disables them.
+	AdminToken string `yaml:"admin_token"`
+	CORS       CORS   `yaml:"cors"`
+}
+
+// CORS is the config for cross-origin resource sharing.
+type CORS struct {
+	// AllowedOrigins are the origins browsers may call the API from, "*" allows any.
+	// Empty list disables CORS.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	AllowedMethods []string `yaml:"allowed_methods"`
+	AllowedHeaders []string `yaml:"allowed_headers"`
+	ExposedHeaders []string `yaml:"exposed_headers"`
+	// AllowCredentials lets browsers send cookies, it requires listing origins explicitly.
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// MaxAge is the number of seconds preflight response can be cached.
+	MaxAge int `yaml:"max_age"`
 }
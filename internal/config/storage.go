@@ -0,0 +1,23 @@
+package config
+
+// Storage drivers.
+const (
+	// DriverPostgreSQL stores data in PostgreSQL database.
+	DriverPostgreSQL = "pg"
+	// DriverInMemory keeps data in memory, it's lost on restart.
+	DriverInMemory = "inmem"
+	// DriverSQLite stores data in local SQLite database file.
+	DriverSQLite = "sqlite"
+)
+
+// Storage is the config for selecting the store.
+type Storage struct {
+	// Driver is one of DriverPostgreSQL, DriverInMemory and DriverSQLite.
+	Driver string `yaml:"driver"`
+}
+
+// SQLite is the config for SQLite database.
+type SQLite struct {
+	// Path is the path to database file, it's created if it doesn't exist.
+	Path string `yaml:"path"`
+}
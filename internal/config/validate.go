@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/imarrche/tasker/internal/logger"
+)
+
+// sslModes are the SSL modes supported by PostgreSQL driver.
+var sslModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
+// drivers are the supported storage drivers.
+var drivers = []string{DriverPostgreSQL, DriverInMemory, DriverSQLite}
+
+// fsyncPolicies are the supported in memory store fsync policies.
+var fsyncPolicies = []string{FsyncAlways, FsyncInterval, FsyncNever}
+
+// Validate checks whether config values are valid and returns all problems found.
+func (c *Config) Validate() error {
+	var errs []string
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if _, _, err := net.SplitHostPort(c.Addr); err != nil {
+		fail("server.addr %q must be in host:port form", c.Addr)
+	}
+	if c.ReadTimeout <= 0 {
+		fail("server.read_timeout must be positive")
+	}
+	if c.WriteTimeout <= 0 {
+		fail("server.write_timeout must be positive")
+	}
+	if c.IdleTimeout < 0 {
+		fail("server.idle_timeout can't be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		fail("server.shutdown_timeout must be positive")
+	}
+	if c.RateLimit < 0 {
+		fail("server.rate_limit can't be negative")
+	}
+	if c.RateLimit > 0 && c.RateBurst < 1 {
+		fail("server.rate_burst must be at least 1 when rate limiting is enabled")
+	}
+	if c.MaxBodyBytes <= 0 {
+		fail("server.max_body_bytes must be positive")
+	}
+	if c.IdempotencyWindow < 0 {
+		fail("server.idempotency_window can't be negative")
+	}
+	if c.CORS.AllowCredentials && contains(c.CORS.AllowedOrigins, "*") {
+		fail(`server.cors.allowed_origins can't contain "*" when server.cors.allow_credentials is set`)
+	}
+	if c.CORS.MaxAge < 0 {
+		fail("server.cors.max_age can't be negative")
+	}
+
+	switch c.Storage.Driver {
+	case DriverPostgreSQL:
+		c.validatePostgreSQL(fail)
+	case DriverSQLite:
+		if c.SQLite.Path == "" {
+			fail("sqlite.path is required")
+		}
+	case DriverInMemory:
+		c.validateInMemory(fail)
+	default:
+		fail(
+			"storage.driver %q must be one of %s",
+			c.Storage.Driver, strings.Join(drivers, ", "),
+		)
+	}
+
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		fail("log.level: %v", err)
+	}
+	if _, err := logger.ParseFormat(c.Log.Format); err != nil {
+		fail("log.format: %v", err)
+	}
+
+	if len(errs) > 0 {
+		return errors.New("invalid config: " + strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// validatePostgreSQL checks whether PostgreSQL config values are valid.
+func (c *Config) validatePostgreSQL(fail func(string, ...interface{})) {
+	if c.PostgreSQL.Host == "" {
+		fail("postgres.host is required")
+	}
+	if port, err := strconv.Atoi(c.PostgreSQL.Port); err != nil || port < 1 || port > 65535 {
+		fail("postgres.port %q must be a number between 1 and 65535", c.PostgreSQL.Port)
+	}
+	if c.PostgreSQL.User == "" {
+		fail("postgres.user is required")
+	}
+	if c.PostgreSQL.DbName == "" {
+		fail("postgres.dbname is required")
+	}
+	if !contains(sslModes, c.PostgreSQL.SSLMode) {
+		fail(
+			"postgres.sslmode %q must be one of %s",
+			c.PostgreSQL.SSLMode, strings.Join(sslModes, ", "),
+		)
+	}
+	if c.PostgreSQL.MaxOpenConns < 0 {
+		fail("postgres.max_open_conns can't be negative")
+	}
+	if c.PostgreSQL.MaxIdleConns < 0 {
+		fail("postgres.max_idle_conns can't be negative")
+	}
+	if c.PostgreSQL.ConnMaxLifetime < 0 {
+		fail("postgres.conn_max_lifetime can't be negative")
+	}
+}
+
+// validateInMemory checks whether in memory store config values are valid.
+func (c *Config) validateInMemory(fail func(string, ...interface{})) {
+	if c.InMemory.Dir == "" {
+		return
+	}
+	if !contains(fsyncPolicies, c.InMemory.Fsync) {
+		fail(
+			"inmem.fsync %q must be one of %s",
+			c.InMemory.Fsync, strings.Join(fsyncPolicies, ", "),
+		)
+	}
+	if c.InMemory.Fsync == FsyncInterval && c.InMemory.FsyncInterval <= 0 {
+		fail("inmem.fsync_interval must be positive")
+	}
+	if c.InMemory.SnapshotEvery < 0 {
+		fail("inmem.snapshot_every can't be negative")
+	}
+}
+
+// contains checks whether list contains s.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
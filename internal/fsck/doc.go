@@ -0,0 +1,3 @@
+// Package fsck checks boards for integrity issues left by older versions or manual edits
+// and optionally repairs them.
+package fsck
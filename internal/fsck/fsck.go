@@ -0,0 +1,324 @@
+package fsck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// Kind is the kind of integrity issue.
+type Kind string
+
+// Kinds of integrity issues.
+const (
+	DuplicateIndex  Kind = "duplicate_index"
+	IndexGap        Kind = "index_gap"
+	OrphanedTask    Kind = "orphaned_task"
+	OrphanedComment Kind = "orphaned_comment"
+	NoColumns       Kind = "no_columns"
+)
+
+// defaultColumn is the name of the column created for projects without columns.
+const defaultColumn = "default"
+
+// Issue is the integrity issue found in the store.
+type Issue struct {
+	Kind      Kind   `json:"kind"`
+	ProjectID int    `json:"project_id,omitempty"`
+	ColumnID  int    `json:"column_id,omitempty"`
+	TaskID    int    `json:"task_id,omitempty"`
+	CommentID int    `json:"comment_id,omitempty"`
+	Detail    string `json:"detail"`
+	Repaired  bool   `json:"repaired"`
+}
+
+// String returns the issue description.
+func (i Issue) String() string {
+	var where []string
+	for _, ref := range []struct {
+		name string
+		id   int
+	}{{"project", i.ProjectID}, {"column", i.ColumnID}, {"task", i.TaskID}, {"comment", i.CommentID}} {
+		if ref.id != 0 {
+			where = append(where, ref.name+" "+strconv.Itoa(ref.id))
+		}
+	}
+
+	s := fmt.Sprintf("%s: %s: %s", strings.Join(where, " "), i.Kind, i.Detail)
+	if i.Repaired {
+		s += " (repaired)"
+	}
+
+	return s
+}
+
+// Report is the result of checking the store.
+type Report struct {
+	Projects int     `json:"projects"`
+	Issues   []Issue `json:"issues"`
+}
+
+// OK checks whether the store has no unrepaired issues.
+func (r Report) OK() bool {
+	for _, i := range r.Issues {
+		if !i.Repaired {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check scans every project for duplicate and missing indices of columns and tasks and
+// for projects without columns, then the whole store for tasks and comments referencing
+// deleted records. With repair, indices are renumbered in the current order, the default
+// column is created and orphaned records are deleted, every project is repaired in its
+// own transaction.
+func Check(ctx context.Context, s Store, repair bool) (Report, error) {
+	run := func(fn func(Repos) error) error {
+		if repair {
+			return s.InTx(ctx, fn)
+		}
+		return fn(s)
+	}
+
+	ids, err := s.ProjectIDs(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	r := Report{Projects: len(ids), Issues: []Issue{}}
+	columns := map[int]bool{}
+	for _, id := range ids {
+		var issues []Issue
+		err := run(func(tx Repos) error {
+			var err error
+			issues, err = checkProject(ctx, tx, id, columns, repair)
+			return err
+		})
+		if err != nil {
+			return Report{}, fmt.Errorf("project %d: %w", id, err)
+		}
+		r.Issues = append(r.Issues, issues...)
+	}
+
+	var issues []Issue
+	err = run(func(tx Repos) error {
+		var err error
+		issues, err = checkOrphans(ctx, tx, columns, repair)
+		return err
+	})
+	if err != nil {
+		return Report{}, err
+	}
+	r.Issues = append(r.Issues, issues...)
+
+	return r, nil
+}
+
+// checkProject checks columns and tasks of the project adding IDs of its columns to
+// columns.
+func checkProject(ctx context.Context, tx Repos, id int, columns map[int]bool, repair bool) ([]Issue, error) {
+	cs, err := tx.Columns(ctx, id)
+	if err == store.ErrNotFound {
+		// The project was deleted after it had been listed.
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(cs) == 0 {
+		issue := Issue{Kind: NoColumns, ProjectID: id, Detail: "project has no columns"}
+		if repair {
+			columnID, err := tx.CreateColumn(ctx, id, defaultColumn)
+			if err != nil {
+				return nil, err
+			}
+			columns[columnID] = true
+			issue.Repaired = true
+		}
+		return []Issue{issue}, nil
+	}
+
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].Index != cs[j].Index {
+			return cs[i].Index < cs[j].Index
+		}
+		return cs[i].ID < cs[j].ID
+	})
+	items := make([]item, len(cs))
+	for i, c := range cs {
+		items[i] = item{id: c.ID, index: c.Index}
+		columns[c.ID] = true
+	}
+	issues := checkIndices("column", items, Issue{ProjectID: id})
+	if repair && len(issues) > 0 {
+		for i, c := range cs {
+			if c.Index == i+1 {
+				continue
+			}
+			if err := tx.SetColumnIndex(ctx, c.ID, i+1); err != nil {
+				return nil, err
+			}
+		}
+		markRepaired(issues)
+	}
+
+	for _, c := range cs {
+		columnIssues, err := checkTasks(ctx, tx, id, c.ID, repair)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, columnIssues...)
+	}
+
+	return issues, nil
+}
+
+// checkTasks checks indices of tasks in the column.
+func checkTasks(ctx context.Context, tx Repos, projectID, id int, repair bool) ([]Issue, error) {
+	ts, err := tx.Tasks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ts, func(i, j int) bool {
+		if ts[i].Index != ts[j].Index {
+			return ts[i].Index < ts[j].Index
+		}
+		return ts[i].ID < ts[j].ID
+	})
+	items := make([]item, len(ts))
+	for i, t := range ts {
+		items[i] = item{id: t.ID, index: t.Index}
+	}
+	issues := checkIndices("task", items, Issue{ProjectID: projectID, ColumnID: id})
+	if repair && len(issues) > 0 {
+		for i, t := range ts {
+			if t.Index == i+1 {
+				continue
+			}
+			if err := tx.SetTaskIndex(ctx, t.ID, i+1); err != nil {
+				return nil, err
+			}
+		}
+		markRepaired(issues)
+	}
+
+	return issues, nil
+}
+
+// checkOrphans checks tasks and comments referencing deleted records. Tasks in columns
+// which aren't in columns are looked up again, they may have been created during the
+// check.
+func checkOrphans(ctx context.Context, tx Repos, columns map[int]bool, repair bool) ([]Issue, error) {
+	var issues []Issue
+
+	ts, err := tx.AllTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks := map[int]bool{}
+	for _, t := range ts {
+		tasks[t.ID] = true
+		if columns[t.ParentID] {
+			continue
+		}
+		if ok, err := tx.ColumnExists(ctx, t.ParentID); err != nil {
+			return nil, err
+		} else if ok {
+			continue
+		}
+
+		issue := Issue{Kind: OrphanedTask, TaskID: t.ID, Detail: fmt.Sprintf("column %d doesn't exist", t.ParentID)}
+		if repair {
+			// Comments of the task are deleted with it.
+			if err := tx.DeleteTask(ctx, t.ID); err != nil {
+				return nil, err
+			}
+			issue.Repaired = true
+		}
+		issues = append(issues, issue)
+	}
+
+	cs, err := tx.AllComments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cs {
+		if tasks[c.ParentID] {
+			continue
+		}
+		if ok, err := tx.TaskExists(ctx, c.ParentID); err != nil {
+			return nil, err
+		} else if ok {
+			continue
+		}
+
+		issue := Issue{Kind: OrphanedComment, CommentID: c.ID, Detail: fmt.Sprintf("task %d doesn't exist", c.ParentID)}
+		if repair {
+			if err := tx.DeleteComment(ctx, c.ID); err != nil {
+				return nil, err
+			}
+			issue.Repaired = true
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// item is a column or a task positioned by index.
+type item struct {
+	id, index int
+}
+
+// checkIndices checks that indices of items sorted by index and ID are 1 to n. Issues
+// are reported for every duplicate index and once for missing indices, base holds
+// references to the items' parent.
+func checkIndices(name string, items []item, base Issue) []Issue {
+	var issues []Issue
+	var indices []string
+	gap := false
+	for i := 0; i < len(items); {
+		j := i + 1
+		for j < len(items) && items[j].index == items[i].index {
+			j++
+		}
+		indices = append(indices, strconv.Itoa(items[i].index))
+		if items[i].index != len(indices) {
+			gap = true
+		}
+		if j-i > 1 {
+			ids := make([]string, 0, j-i)
+			for _, it := range items[i:j] {
+				ids = append(ids, strconv.Itoa(it.id))
+			}
+			issue := base
+			issue.Kind = DuplicateIndex
+			issue.Detail = fmt.Sprintf("%ss %s share index %d", name, strings.Join(ids, ", "), items[i].index)
+			issues = append(issues, issue)
+		}
+		i = j
+	}
+
+	if gap {
+		issue := base
+		issue.Kind = IndexGap
+		issue.Detail = fmt.Sprintf("%s indices %s aren't 1 to %d", name, strings.Join(indices, ", "), len(indices))
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// markRepaired marks issues as repaired.
+func markRepaired(issues []Issue) {
+	for i := range issues {
+		issues[i].Repaired = true
+	}
+}
@@ -0,0 +1,149 @@
+package fsck
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store/sqlite"
+)
+
+// testStore returns SQLite store with a broken board, SQLite doesn't enforce unique
+// indices outside of transactions and foreign keys are disabled to leave orphans.
+func testStore(t *testing.T) *sqlite.Store {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "tasker")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "tasker.db")
+	s := sqlite.New(config.SQLite{Path: path})
+	require.NoError(t, s.Open())
+	t.Cleanup(func() {
+		s.Close()
+		os.RemoveAll(dir)
+	})
+
+	p, err := s.Projects().Create(ctx, model.Project{Name: "Broken"})
+	require.NoError(t, err)
+	for _, c := range []model.Column{
+		{Name: "To do", Index: 1, ProjectID: p.ID},
+		{Name: "Doing", Index: 3, ProjectID: p.ID},
+	} {
+		_, err = s.Columns().Create(ctx, c)
+		require.NoError(t, err)
+	}
+	for _, task := range []model.Task{
+		{Name: "Task 1", Index: 2, ColumnID: 1},
+		{Name: "Task 2", Index: 2, ColumnID: 1},
+		{Name: "Task 3", Index: 1, ColumnID: 1},
+		{Name: "Task 4", Index: 1, ColumnID: 2},
+	} {
+		_, err = s.Tasks().Create(ctx, task)
+		require.NoError(t, err)
+	}
+	_, err = s.Projects().Create(ctx, model.Project{Name: "Empty"})
+	require.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=off")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`INSERT INTO tasks (id, name, description, "index", column_id) VALUES (10, 'Orphan', '', 1, 99);`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO comments (id, text, created_at, task_id) VALUES (20, 'Orphan', '2021-01-01', 99);`)
+	require.NoError(t, err)
+
+	return s
+}
+
+// checkedStores are the constructors of stores Check is tested with.
+var checkedStores = []struct {
+	name string
+	new  func(*sqlite.Store) Store
+}{
+	{"repositories", func(s *sqlite.Store) Store { return FromStore(s) }},
+	{"SQL", func(s *sqlite.Store) Store { return NewSQLStore(s.DB(), config.DriverSQLite) }},
+}
+
+func TestCheck(t *testing.T) {
+	expIssues := []Issue{
+		{Kind: IndexGap, ProjectID: 1, Detail: "column indices 1, 3 aren't 1 to 2"},
+		{Kind: DuplicateIndex, ProjectID: 1, ColumnID: 1, Detail: "tasks 1, 2 share index 2"},
+		{Kind: NoColumns, ProjectID: 2, Detail: "project has no columns"},
+		{Kind: OrphanedTask, TaskID: 10, Detail: "column 99 doesn't exist"},
+		{Kind: OrphanedComment, CommentID: 20, Detail: "task 99 doesn't exist"},
+	}
+
+	for _, cs := range checkedStores {
+		t.Run(cs.name, func(t *testing.T) {
+			r, err := Check(context.Background(), cs.new(testStore(t)), false)
+
+			assert.NoError(t, err)
+			assert.Equal(t, Report{Projects: 2, Issues: expIssues}, r)
+			assert.False(t, r.OK())
+		})
+	}
+}
+
+func TestCheck_Repair(t *testing.T) {
+	for _, cs := range checkedStores {
+		t.Run(cs.name, func(t *testing.T) {
+			testRepair(t, cs.new)
+		})
+	}
+}
+
+func testRepair(t *testing.T, newStore func(*sqlite.Store) Store) {
+	ctx := context.Background()
+	s := testStore(t)
+
+	r, err := Check(ctx, newStore(s), true)
+
+	assert.NoError(t, err)
+	assert.Len(t, r.Issues, 5)
+	assert.True(t, r.OK())
+
+	cs, err := s.Columns().GetByProjectID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, []int{cs[0].Index, cs[1].Index})
+	ts, err := s.Tasks().GetByColumnID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Task{
+		{ID: 1, Name: "Task 1", Index: 2, ColumnID: 1},
+		{ID: 2, Name: "Task 2", Index: 3, ColumnID: 1},
+		{ID: 3, Name: "Task 3", Index: 1, ColumnID: 1},
+	}, ts)
+	cs, err = s.Columns().GetByProjectID(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Column{{ID: 3, Name: "default", Index: 1, ProjectID: 2, Type: model.ColumnTypeBacklog}}, cs)
+	ts, err = s.Tasks().GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, ts, 4)
+	comments, err := s.Comments().GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, comments)
+
+	// The repaired store has no issues.
+	r, err = Check(ctx, newStore(s), false)
+	assert.NoError(t, err)
+	assert.Equal(t, Report{Projects: 2, Issues: []Issue{}}, r)
+}
+
+func TestIssue_String(t *testing.T) {
+	i := Issue{Kind: DuplicateIndex, ProjectID: 1, ColumnID: 2, Detail: "tasks 3, 4 share index 1", Repaired: true}
+
+	assert.Equal(t, "project 1 column 2: duplicate_index: tasks 3, 4 share index 1 (repaired)", i.String())
+}
+
+func TestSQLRepos_Rebind(t *testing.T) {
+	query := `UPDATE tasks SET "index" = ? WHERE id = ?;`
+
+	assert.Equal(t, `UPDATE tasks SET "index" = $1 WHERE id = $2;`, sqlRepos{pg: true}.rebind(query))
+	assert.Equal(t, query, sqlRepos{}.rebind(query))
+}
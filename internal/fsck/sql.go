@@ -0,0 +1,211 @@
+package fsck
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// sqlStore is the Store reading and repairing records of a SQL database. Queries use
+// only columns of the initial schema, so boards can be checked and repaired before
+// migrations adding constraints they violate.
+type sqlStore struct {
+	sqlRepos
+	db *sql.DB
+}
+
+// NewSQLStore returns the Store checking the database of the storage driver, either
+// config.DriverPostgreSQL or config.DriverSQLite, at any schema version.
+func NewSQLStore(db *sql.DB, driver string) Store {
+	return &sqlStore{sqlRepos: sqlRepos{q: db, pg: driver == config.DriverPostgreSQL}, db: db}
+}
+
+// InTx runs fn with repositories bound to a database transaction.
+func (s *sqlStore) InTx(ctx context.Context, fn func(Repos) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(sqlRepos{q: tx, pg: s.pg}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// querier is implemented by *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// sqlRepos implements Repos with SQL queries.
+type sqlRepos struct {
+	q  querier
+	pg bool
+}
+
+// rebind replaces ? placeholders with $n ones for PostgreSQL.
+func (r sqlRepos) rebind(query string) string {
+	if !r.pg {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
+
+// records returns records selected with the query, it must select ID, index and parent ID.
+func (r sqlRepos) records(ctx context.Context, query string, args ...interface{}) ([]Record, error) {
+	rows, err := r.q.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rs, rec := []Record{}, Record{}
+	for rows.Next() {
+		if err = rows.Scan(&rec.ID, &rec.Index, &rec.ParentID); err != nil {
+			return nil, err
+		}
+		rs = append(rs, rec)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+// exists checks whether the query selects a row.
+func (r sqlRepos) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var one int
+	err := r.q.QueryRowContext(ctx, r.rebind(query), args...).Scan(&one)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// exec executes the query.
+func (r sqlRepos) exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := r.q.ExecContext(ctx, r.rebind(query), args...)
+	return err
+}
+
+// ProjectIDs returns IDs of all projects.
+func (r sqlRepos) ProjectIDs(ctx context.Context) ([]int, error) {
+	rows, err := r.q.QueryContext(ctx, "SELECT id FROM projects ORDER BY id;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids, id := []int{}, 0
+	for rows.Next() {
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Columns returns columns of the project.
+func (r sqlRepos) Columns(ctx context.Context, projectID int) ([]Record, error) {
+	if ok, err := r.exists(ctx, "SELECT 1 FROM projects WHERE id = ?;", projectID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	return r.records(ctx, `SELECT id, "index", project_id FROM columns WHERE project_id = ? ORDER BY id;`, projectID)
+}
+
+// Tasks returns tasks of the column.
+func (r sqlRepos) Tasks(ctx context.Context, columnID int) ([]Record, error) {
+	return r.records(ctx, `SELECT id, "index", column_id FROM tasks WHERE column_id = ? ORDER BY id;`, columnID)
+}
+
+// AllTasks returns all tasks.
+func (r sqlRepos) AllTasks(ctx context.Context) ([]Record, error) {
+	return r.records(ctx, `SELECT id, "index", column_id FROM tasks ORDER BY id;`)
+}
+
+// AllComments returns all comments.
+func (r sqlRepos) AllComments(ctx context.Context) ([]Record, error) {
+	return r.records(ctx, "SELECT id, 0, task_id FROM comments ORDER BY id;")
+}
+
+// ColumnExists checks whether the column exists.
+func (r sqlRepos) ColumnExists(ctx context.Context, id int) (bool, error) {
+	return r.exists(ctx, "SELECT 1 FROM columns WHERE id = ?;", id)
+}
+
+// TaskExists checks whether the task exists.
+func (r sqlRepos) TaskExists(ctx context.Context, id int) (bool, error) {
+	return r.exists(ctx, "SELECT 1 FROM tasks WHERE id = ?;", id)
+}
+
+// CreateColumn creates the first column of the project and returns its ID, columns added
+// by later migrations get their defaults.
+func (r sqlRepos) CreateColumn(ctx context.Context, projectID int, name string) (int, error) {
+	query := `INSERT INTO columns (name, "index", project_id) VALUES (?, 1, ?)`
+	if r.pg {
+		var id int
+		err := r.q.QueryRowContext(ctx, r.rebind(query+" RETURNING id;"), name, projectID).Scan(&id)
+		return id, err
+	}
+
+	res, err := r.q.ExecContext(ctx, query+";", name, projectID)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+
+	return int(id), err
+}
+
+// SetColumnIndex updates index of the column.
+func (r sqlRepos) SetColumnIndex(ctx context.Context, id, index int) error {
+	return r.exec(ctx, `UPDATE columns SET "index" = ? WHERE id = ?;`, index, id)
+}
+
+// SetTaskIndex updates index of the task.
+func (r sqlRepos) SetTaskIndex(ctx context.Context, id, index int) error {
+	return r.exec(ctx, `UPDATE tasks SET "index" = ? WHERE id = ?;`, index, id)
+}
+
+// DeleteTask deletes the task with its comments.
+func (r sqlRepos) DeleteTask(ctx context.Context, id int) error {
+	return r.exec(ctx, "DELETE FROM tasks WHERE id = ?;", id)
+}
+
+// DeleteComment deletes the comment.
+func (r sqlRepos) DeleteComment(ctx context.Context, id int) error {
+	return r.exec(ctx, "DELETE FROM comments WHERE id = ?;", id)
+}
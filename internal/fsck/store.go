@@ -0,0 +1,207 @@
+package fsck
+
+import (
+	"context"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// Record is a column, task or comment checked by fsck: its ID, index and the ID of the
+// project, column or task it belongs to. Comments don't have indices.
+type Record struct {
+	ID       int
+	Index    int
+	ParentID int
+}
+
+// Repos reads and repairs the records fsck checks. Columns returns store.ErrNotFound if
+// the project doesn't exist.
+type Repos interface {
+	ProjectIDs(context.Context) ([]int, error)
+	Columns(ctx context.Context, projectID int) ([]Record, error)
+	Tasks(ctx context.Context, columnID int) ([]Record, error)
+	AllTasks(context.Context) ([]Record, error)
+	AllComments(context.Context) ([]Record, error)
+	ColumnExists(ctx context.Context, id int) (bool, error)
+	TaskExists(ctx context.Context, id int) (bool, error)
+	CreateColumn(ctx context.Context, projectID int, name string) (int, error)
+	SetColumnIndex(ctx context.Context, id, index int) error
+	SetTaskIndex(ctx context.Context, id, index int) error
+	DeleteTask(ctx context.Context, id int) error
+	DeleteComment(ctx context.Context, id int) error
+}
+
+// Store is the store checked by Check, repairs of a project are made in a transaction.
+type Store interface {
+	Repos
+	InTx(context.Context, func(Repos) error) error
+}
+
+// repoStore is the Store reading and repairing records with store repositories.
+type repoStore struct {
+	repos
+	s store.Store
+}
+
+// FromStore returns the Store checking s with its repositories, s must be migrated to
+// the latest schema version.
+func FromStore(s store.Store) Store {
+	return &repoStore{repos: repos{tx: s}, s: s}
+}
+
+// InTx runs fn with repositories bound to a transaction of the store.
+func (s *repoStore) InTx(ctx context.Context, fn func(Repos) error) error {
+	return s.s.InTx(ctx, func(tx store.Tx) error { return fn(repos{tx: tx}) })
+}
+
+// repos implements Repos with store repositories.
+type repos struct {
+	tx store.Tx
+}
+
+// ProjectIDs returns IDs of all projects.
+func (r repos) ProjectIDs(ctx context.Context) ([]int, error) {
+	ps, err := r.tx.Projects().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(ps))
+	for i, p := range ps {
+		ids[i] = p.ID
+	}
+
+	return ids, nil
+}
+
+// Columns returns columns of the project.
+func (r repos) Columns(ctx context.Context, projectID int) ([]Record, error) {
+	cs, err := r.tx.Columns().GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]Record, len(cs))
+	for i, c := range cs {
+		rs[i] = Record{ID: c.ID, Index: c.Index, ParentID: c.ProjectID}
+	}
+
+	return rs, nil
+}
+
+// Tasks returns tasks of the column.
+func (r repos) Tasks(ctx context.Context, columnID int) ([]Record, error) {
+	ts, err := r.tx.Tasks().GetByColumnID(ctx, columnID)
+	if err != nil {
+		return nil, err
+	}
+
+	return taskRecords(ts), nil
+}
+
+// AllTasks returns all tasks.
+func (r repos) AllTasks(ctx context.Context) ([]Record, error) {
+	ts, err := r.tx.Tasks().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taskRecords(ts), nil
+}
+
+// AllComments returns all comments.
+func (r repos) AllComments(ctx context.Context) ([]Record, error) {
+	cs, err := r.tx.Comments().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]Record, len(cs))
+	for i, c := range cs {
+		rs[i] = Record{ID: c.ID, ParentID: c.TaskID}
+	}
+
+	return rs, nil
+}
+
+// ColumnExists checks whether the column exists.
+func (r repos) ColumnExists(ctx context.Context, id int) (bool, error) {
+	_, err := r.tx.Columns().GetByID(ctx, id)
+	return exists(err)
+}
+
+// TaskExists checks whether the task exists.
+func (r repos) TaskExists(ctx context.Context, id int) (bool, error) {
+	_, err := r.tx.Tasks().GetByID(ctx, id)
+	return exists(err)
+}
+
+// CreateColumn creates the first column of the project and returns its ID.
+func (r repos) CreateColumn(ctx context.Context, projectID int, name string) (int, error) {
+	c, err := r.tx.Columns().Create(ctx, model.Column{
+		Name: name, Index: 1, ProjectID: projectID, Type: model.ColumnTypeBacklog,
+	})
+
+	return c.ID, err
+}
+
+// SetColumnIndex updates index of the column.
+func (r repos) SetColumnIndex(ctx context.Context, id, index int) error {
+	c, err := r.tx.Columns().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	c.Index = index
+	_, err = r.tx.Columns().Update(ctx, c)
+
+	return err
+}
+
+// SetTaskIndex updates index of the task.
+func (r repos) SetTaskIndex(ctx context.Context, id, index int) error {
+	t, err := r.tx.Tasks().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	t.Index = index
+	_, err = r.tx.Tasks().Update(ctx, t)
+
+	return err
+}
+
+// DeleteTask deletes the task with its comments.
+func (r repos) DeleteTask(ctx context.Context, id int) error {
+	if err := r.tx.Tasks().DeleteByID(ctx, id); err != nil && err != store.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteComment deletes the comment.
+func (r repos) DeleteComment(ctx context.Context, id int) error {
+	if err := r.tx.Comments().DeleteByID(ctx, id); err != nil && err != store.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// taskRecords returns records of the tasks.
+func taskRecords(ts []model.Task) []Record {
+	rs := make([]Record, len(ts))
+	for i, t := range ts {
+		rs[i] = Record{ID: t.ID, Index: t.Index, ParentID: t.ColumnID}
+	}
+
+	return rs
+}
+
+// exists checks whether the error of getting a record means it exists.
+func exists(err error) (bool, error) {
+	if err == store.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
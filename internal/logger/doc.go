@@ -0,0 +1,2 @@
+// Package logger provides leveled structured logger with text and JSON output.
+package logger
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the logging level.
+type Level int
+
+// Logging levels from the most to the least verbose.
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+// String returns the level name.
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// ParseLevel parses level name.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown log level %q", s)
+	}
+}
+
+// Format is the output format of log entries.
+type Format string
+
+// Supported log formats.
+const (
+	TextFormat Format = "text"
+	JSONFormat Format = "json"
+)
+
+// ParseFormat parses format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case TextFormat, JSONFormat:
+		return f, nil
+	default:
+		return TextFormat, fmt.Errorf("unknown log format %q", s)
+	}
+}
+
+// Fields are the key-value pairs attached to log entries.
+type Fields map[string]interface{}
+
+// Logger is the leveled structured logger. It's safe for concurrent use.
+type Logger struct {
+	m      *sync.Mutex
+	out    io.Writer
+	level  Level
+	format Format
+	fields Fields
+	now    func() time.Time
+}
+
+// New creates and returns a new Logger instance.
+func New(out io.Writer, level Level, format Format) *Logger {
+	return &Logger{m: &sync.Mutex{}, out: out, level: level, format: format, now: time.Now}
+}
+
+// WithFields returns a logger that attaches fields to every entry.
+func (l *Logger) WithFields(fields Fields) *Logger {
+	fs := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		fs[k] = v
+	}
+	for k, v := range fields {
+		fs[k] = v
+	}
+
+	child := *l
+	child.fields = fs
+
+	return &child
+}
+
+// WithField returns a logger that attaches the field to every entry.
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	return l.WithFields(Fields{key: value})
+}
+
+// Debug logs a message at debug level.
+func (l *Logger) Debug(msg string) { l.log(DebugLevel, msg) }
+
+// Info logs a message at info level.
+func (l *Logger) Info(msg string) { l.log(InfoLevel, msg) }
+
+// Warn logs a message at warn level.
+func (l *Logger) Warn(msg string) { l.log(WarnLevel, msg) }
+
+// Error logs a message at error level.
+func (l *Logger) Error(msg string) { l.log(ErrorLevel, msg) }
+
+// Fatal logs a message at error level and exits the program.
+func (l *Logger) Fatal(msg string) {
+	l.log(ErrorLevel, msg)
+	os.Exit(1)
+}
+
+// log writes the entry if level is enabled.
+func (l *Logger) log(level Level, msg string) {
+	if level < l.level {
+		return
+	}
+
+	var b []byte
+	if l.format == JSONFormat {
+		b = l.formatJSON(level, msg)
+	} else {
+		b = l.formatText(level, msg)
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.out.Write(b)
+}
+
+// formatJSON formats the entry as a single JSON object line.
+func (l *Logger) formatJSON(level Level, msg string) []byte {
+	entry := make(map[string]interface{}, len(l.fields)+3)
+	for k, v := range l.fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		entry[k] = v
+	}
+	entry["time"] = l.now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		b, _ = json.Marshal(map[string]string{
+			"time":  l.now().UTC().Format(time.RFC3339Nano),
+			"level": ErrorLevel.String(),
+			"msg":   fmt.Sprintf("couldn't marshal log entry: %s", err),
+		})
+	}
+
+	return append(b, '\n')
+}
+
+// formatText formats the entry as a human readable line with sorted fields.
+func (l *Logger) formatText(level Level, msg string) []byte {
+	var sb strings.Builder
+	sb.WriteString(l.now().UTC().Format(time.RFC3339))
+	sb.WriteByte(' ')
+	sb.WriteString(strings.ToUpper(level.String()))
+	sb.WriteByte(' ')
+	sb.WriteString(msg)
+
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, " %s=%v", k, l.fields[k])
+	}
+	sb.WriteByte('\n')
+
+	return []byte(sb.String())
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLogger(level Level, format Format) (*Logger, *bytes.Buffer) {
+	b := &bytes.Buffer{}
+	l := New(b, level, format)
+	l.now = func() time.Time { return time.Date(2020, 12, 27, 7, 36, 27, 0, time.UTC) }
+
+	return l, b
+}
+
+func TestParseLevel(t *testing.T) {
+	testcases := []struct {
+		name     string
+		level    string
+		expLevel Level
+		expError bool
+	}{
+		{name: "debug level is parsed", level: "debug", expLevel: DebugLevel},
+		{name: "level is parsed case insensitively", level: "WARN", expLevel: WarnLevel},
+		{name: "unknown level isn't parsed", level: "verbose", expLevel: InfoLevel, expError: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := ParseLevel(tc.level)
+
+			assert.Equal(t, tc.expError, err != nil)
+			assert.Equal(t, tc.expLevel, l)
+		})
+	}
+}
+
+func TestLogger_JSON(t *testing.T) {
+	l, b := testLogger(InfoLevel, JSONFormat)
+
+	l.WithFields(Fields{"request_id": "abc", "status": 500}).
+		WithField("error", errors.New("boom")).
+		Error("request failed")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), &entry))
+	assert.Equal(t, map[string]interface{}{
+		"time":       "2020-12-27T07:36:27Z",
+		"level":      "error",
+		"msg":        "request failed",
+		"request_id": "abc",
+		"status":     float64(500),
+		"error":      "boom",
+	}, entry)
+}
+
+func TestLogger_Text(t *testing.T) {
+	l, b := testLogger(InfoLevel, TextFormat)
+
+	l.WithFields(Fields{"b": 2, "a": "x"}).Info("hello")
+
+	assert.Equal(t, "2020-12-27T07:36:27Z INFO hello a=x b=2\n", b.String())
+}
+
+func TestLogger_Level(t *testing.T) {
+	l, b := testLogger(WarnLevel, TextFormat)
+
+	l.Debug("debug")
+	l.Info("info")
+	assert.Equal(t, 0, b.Len())
+
+	l.Warn("warn")
+	assert.Equal(t, "2020-12-27T07:36:27Z WARN warn\n", b.String())
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import "database/sql"
+
+// NewDBStatsCollector creates a collector exposing database connection pool stats.
+func NewDBStatsCollector(prefix string, stats func() sql.DBStats) Collector {
+	return CollectorFunc(func() []Family {
+		s := stats()
+		gauge := func(name, help string, v float64) Family {
+			return Family{Name: prefix + name, Help: help, Type: GaugeType, Samples: []Sample{{Value: v}}}
+		}
+		counter := func(name, help string, v float64) Family {
+			return Family{Name: prefix + name, Help: help, Type: CounterType, Samples: []Sample{{Value: v}}}
+		}
+
+		return []Family{
+			gauge("max_open_connections", "Maximum number of open connections.", float64(s.MaxOpenConnections)),
+			gauge("open_connections", "Number of established connections.", float64(s.OpenConnections)),
+			gauge("in_use_connections", "Number of connections currently in use.", float64(s.InUse)),
+			gauge("idle_connections", "Number of idle connections.", float64(s.Idle)),
+			counter("wait_count_total", "Total number of connections waited for.", float64(s.WaitCount)),
+			counter(
+				"wait_duration_seconds_total",
+				"Total time blocked waiting for a new connection.",
+				s.WaitDuration.Seconds(),
+			),
+			counter(
+				"max_idle_closed_total",
+				"Total number of connections closed due to max idle limit.",
+				float64(s.MaxIdleClosed),
+			),
+			counter(
+				"max_lifetime_closed_total",
+				"Total number of connections closed due to max lifetime limit.",
+				float64(s.MaxLifetimeClosed),
+			),
+		}
+	})
+}
@@ -0,0 +1,3 @@
+// Package metrics provides minimal Prometheus compatible metrics: counters, histograms,
+// gauges computed on scrape and the text exposition format handler.
+package metrics
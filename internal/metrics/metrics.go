@@ -0,0 +1,332 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metric types.
+const (
+	CounterType   = "counter"
+	GaugeType     = "gauge"
+	HistogramType = "histogram"
+)
+
+// DefaultBuckets are the default histogram buckets in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Label is a metric label.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single metric value.
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Family is a group of samples sharing name, help and type.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector is the interface all metrics must implement.
+type Collector interface {
+	Collect() []Family
+}
+
+// Registry is the set of collectors exposed together.
+type Registry struct {
+	m          sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry creates and returns a new Registry instance.
+func NewRegistry() *Registry { return &Registry{} }
+
+// MustRegister registers collectors.
+func (r *Registry) MustRegister(cs ...Collector) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.collectors = append(r.collectors, cs...)
+}
+
+// Gather collects all families sorted by name.
+func (r *Registry) Gather() []Family {
+	r.m.RLock()
+	cs := append([]Collector{}, r.collectors...)
+	r.m.RUnlock()
+
+	fs := []Family{}
+	for _, c := range cs {
+		fs = append(fs, c.Collect()...)
+	}
+	sort.SliceStable(fs, func(i, j int) bool { return fs[i].Name < fs[j].Name })
+
+	return fs
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	for _, f := range r.Gather() {
+		_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.Name, escapeHelp(f.Help), f.Name, f.Type)
+		if err != nil {
+			return err
+		}
+		for _, s := range f.Samples {
+			_, err = fmt.Fprintf(
+				w, "%s%s%s %s\n", f.Name, s.Suffix, formatLabels(s.Labels), formatValue(s.Value),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Handler returns the HTTP handler exposing metrics.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// vec stores values of a metric per label values combination.
+type vec struct {
+	name       string
+	help       string
+	labelNames []string
+	m          sync.Mutex
+	keys       []string
+	labels     map[string][]string
+}
+
+func newVec(name, help string, labelNames []string) vec {
+	return vec{name: name, help: help, labelNames: labelNames, labels: map[string][]string{}}
+}
+
+// key returns the key of label values, registering it if it's new. Must be called
+// under lock.
+func (v *vec) key(values []string) string {
+	if len(values) != len(v.labelNames) {
+		panic(fmt.Sprintf(
+			"metrics: %s expects %d label values, got %d", v.name, len(v.labelNames), len(values),
+		))
+	}
+	k := strings.Join(values, "\xff")
+	if _, ok := v.labels[k]; !ok {
+		v.labels[k] = append([]string{}, values...)
+		v.keys = append(v.keys, k)
+		sort.Strings(v.keys)
+	}
+
+	return k
+}
+
+// pairs returns label pairs for label values key.
+func (v *vec) pairs(k string) []Label {
+	ls := make([]Label, len(v.labelNames))
+	for i, n := range v.labelNames {
+		ls[i] = Label{Name: n, Value: v.labels[k][i]}
+	}
+
+	return ls
+}
+
+// CounterVec is the counter partitioned by labels.
+type CounterVec struct {
+	vec
+	values map[string]float64
+}
+
+// NewCounterVec creates and returns a new CounterVec instance.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{vec: newVec(name, help, labelNames), values: map[string]float64{}}
+}
+
+// Inc increments the counter with label values by one.
+func (c *CounterVec) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add increases the counter with label values by delta.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.values[c.key(labelValues)] += delta
+}
+
+// Value returns the current counter value for label values.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.values[strings.Join(labelValues, "\xff")]
+}
+
+// Collect implements Collector.
+func (c *CounterVec) Collect() []Family {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	f := Family{Name: c.name, Help: c.help, Type: CounterType}
+	for _, k := range c.keys {
+		f.Samples = append(f.Samples, Sample{Labels: c.pairs(k), Value: c.values[k]})
+	}
+
+	return []Family{f}
+}
+
+// histogram is a single histogram state.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is the histogram partitioned by labels.
+type HistogramVec struct {
+	vec
+	buckets []float64
+	values  map[string]*histogram
+}
+
+// NewHistogramVec creates and returns a new HistogramVec instance. Nil buckets mean
+// DefaultBuckets.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+
+	return &HistogramVec{
+		vec: newVec(name, help, labelNames), buckets: buckets, values: map[string]*histogram{},
+	}
+}
+
+// Observe adds an observation to the histogram with label values.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	k := h.key(labelValues)
+	hist, ok := h.values[k]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[k] = hist
+	}
+	for i, b := range h.buckets {
+		if v <= b {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += v
+}
+
+// Count returns the number of observations for label values.
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if hist, ok := h.values[strings.Join(labelValues, "\xff")]; ok {
+		return hist.count
+	}
+
+	return 0
+}
+
+// Collect implements Collector.
+func (h *HistogramVec) Collect() []Family {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	f := Family{Name: h.name, Help: h.help, Type: HistogramType}
+	for _, k := range h.keys {
+		hist, ok := h.values[k]
+		if !ok {
+			continue
+		}
+		ls := h.pairs(k)
+		for i, b := range h.buckets {
+			f.Samples = append(f.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: append(append([]Label{}, ls...), Label{Name: "le", Value: formatValue(b)}),
+				Value:  float64(hist.counts[i]),
+			})
+		}
+		f.Samples = append(f.Samples,
+			Sample{
+				Suffix: "_bucket",
+				Labels: append(append([]Label{}, ls...), Label{Name: "le", Value: "+Inf"}),
+				Value:  float64(hist.count),
+			},
+			Sample{Suffix: "_sum", Labels: ls, Value: hist.sum},
+			Sample{Suffix: "_count", Labels: ls, Value: float64(hist.count)},
+		)
+	}
+
+	return []Family{f}
+}
+
+// CollectorFunc turns a function into Collector. It's used for metrics computed on
+// scrape.
+type CollectorFunc func() []Family
+
+// Collect implements Collector.
+func (f CollectorFunc) Collect() []Family { return f() }
+
+// formatLabels formats labels as {name="value",...}.
+func formatLabels(ls []Label) string {
+	if len(ls) == 0 {
+		return ""
+	}
+
+	ps := make([]string, len(ls))
+	for i, l := range ls {
+		ps[i] = l.Name + `="` + escapeLabelValue(l.Value) + `"`
+	}
+
+	return "{" + strings.Join(ps, ",") + "}"
+}
+
+// formatValue formats sample value.
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// escapeHelp escapes help text.
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// escapeLabelValue escapes label value, unlike Go quoting the format only escapes
+// backslash, double quote and line feed.
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	c := NewCounterVec("requests_total", "Total requests.", "method", "status")
+	h := NewHistogramVec("latency_seconds", "Request latency.", []float64{0.1, 1}, "method")
+	g := CollectorFunc(func() []Family {
+		return []Family{{Name: "tasks", Help: "Tasks count.", Type: GaugeType, Samples: []Sample{
+			{Labels: []Label{{Name: "project_id", Value: "1"}}, Value: 3},
+		}}}
+	})
+	r.MustRegister(c, h, g)
+
+	c.Inc("GET", "200")
+	c.Add(2, "GET", "200")
+	c.Inc("POST", "201")
+	h.Observe(0.05, "GET")
+	h.Observe(0.5, "GET")
+	b := &bytes.Buffer{}
+	err := r.WriteText(b)
+
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"# HELP latency_seconds Request latency.",
+		"# TYPE latency_seconds histogram",
+		`latency_seconds_bucket{method="GET",le="0.1"} 1`,
+		`latency_seconds_bucket{method="GET",le="1"} 2`,
+		`latency_seconds_bucket{method="GET",le="+Inf"} 2`,
+		`latency_seconds_sum{method="GET"} 0.55`,
+		`latency_seconds_count{method="GET"} 2`,
+		"# HELP requests_total Total requests.",
+		"# TYPE requests_total counter",
+		`requests_total{method="GET",status="200"} 3`,
+		`requests_total{method="POST",status="201"} 1`,
+		"# HELP tasks Tasks count.",
+		"# TYPE tasks gauge",
+		`tasks{project_id="1"} 3`,
+		"",
+	}, "\n"), b.String())
+}
+
+func TestRegistry_WriteTextEscaping(t *testing.T) {
+	r := NewRegistry()
+	c := NewCounterVec("requests_total", "Total\\requests\nby path.", "path")
+	r.MustRegister(c)
+
+	c.Inc("/projects/\"ünïcode\"\\\tname\n")
+	b := &bytes.Buffer{}
+	err := r.WriteText(b)
+
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		`# HELP requests_total Total\\requests\nby path.`,
+		"# TYPE requests_total counter",
+		"requests_total{path=\"/projects/\\\"ünïcode\\\"\\\\\tname\\n\"} 1",
+		"",
+	}, "\n"), b.String())
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(NewDBStatsCollector("db_", func() sql.DBStats {
+		return sql.DBStats{OpenConnections: 2, WaitDuration: 1500 * time.Millisecond}
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	r.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "version=0.0.4")
+	assert.Contains(t, w.Body.String(), "db_open_connections 2\n")
+	assert.Contains(t, w.Body.String(), "db_wait_duration_seconds_total 1.5\n")
+}
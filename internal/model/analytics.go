@@ -0,0 +1,60 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Analytics are the project flow metrics over a period. Durations are in seconds.
+type Analytics struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Columns are the stays of tasks in columns which ended within the period.
+	Columns []ColumnDwell `json:"columns"`
+	// LeadTime is the time from creation to completion of tasks completed within the
+	// period, CycleTime is the time from their start to completion.
+	LeadTime  Percentiles `json:"lead_time"`
+	CycleTime Percentiles `json:"cycle_time"`
+}
+
+// ColumnDwell is how long tasks stayed in a column.
+type ColumnDwell struct {
+	ColumnID       int     `json:"column_id"`
+	Count          int     `json:"count"`
+	AverageSeconds float64 `json:"average_seconds"`
+}
+
+// Percentiles are the percentiles of a number of durations in seconds.
+type Percentiles struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50"`
+	P85   float64 `json:"p85"`
+	P95   float64 `json:"p95"`
+}
+
+// NewPercentiles computes percentiles of the durations interpolating between the
+// closest ones like percentile_cont of SQL does. Percentiles of no durations are 0.
+func NewPercentiles(seconds []float64) Percentiles {
+	s := append([]float64{}, seconds...)
+	sort.Float64s(s)
+
+	return Percentiles{
+		Count: len(s),
+		P50:   percentile(s, 0.5),
+		P85:   percentile(s, 0.85),
+		P95:   percentile(s, 0.95),
+	}
+}
+
+// percentile returns the p-th percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	pos := p * float64(len(sorted)-1)
+	lo, hi := int(math.Floor(pos)), int(math.Ceil(pos))
+
+	return sorted[lo] + (pos-float64(lo))*(sorted[hi]-sorted[lo])
+}
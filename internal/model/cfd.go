@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// DateLayout is the layout of dates, days are in UTC.
+const DateLayout = "2006-01-02"
+
+// ColumnSnapshot is the number of tasks in a column on a day. The column's name and
+// index are kept as they were, so the snapshot outlives renaming, moving or deleting it.
+type ColumnSnapshot struct {
+	ID          int       `json:"id"`
+	ProjectID   int       `json:"project_id"`
+	ColumnID    int       `json:"column_id"`
+	ColumnName  string    `json:"column_name"`
+	ColumnIndex int       `json:"column_index"`
+	Day         time.Time `json:"day"`
+	Count       int       `json:"count"`
+}
+
+// CFD is the cumulative flow diagram data of a project, the number of tasks in its
+// columns on each day with snapshots from From to To inclusive.
+type CFD struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Columns []CFDColumn `json:"columns"`
+	Days    []CFDDay    `json:"days"`
+}
+
+// CFDColumn is a column counted in a CFD. Deleted columns have the name and index of
+// their last snapshot.
+type CFDColumn struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Index   int    `json:"index"`
+	Deleted bool   `json:"deleted"`
+}
+
+// CFDDay is the number of tasks in CFD columns, in the same order, on the day. Columns
+// which didn't exist on the day have no tasks.
+type CFDDay struct {
+	Date   string `json:"date"`
+	Counts []int  `json:"counts"`
+}
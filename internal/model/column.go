@@ -1,9 +1,55 @@
 package model
 
+// ColumnType tells what progress of tasks a column represents.
+type ColumnType string
+
+// Column types.
+const (
+	// ColumnTypeBacklog is a column of tasks which aren't started yet.
+	ColumnTypeBacklog ColumnType = "backlog"
+	// ColumnTypeActive is a column of tasks in progress.
+	ColumnTypeActive ColumnType = "active"
+	// ColumnTypeDone is a column of completed tasks.
+	ColumnTypeDone ColumnType = "done"
+)
+
+// IsValid checks whether the column type is one of the known types.
+func (t ColumnType) IsValid() bool {
+	switch t {
+	case ColumnTypeBacklog, ColumnTypeActive, ColumnTypeDone:
+		return true
+	}
+
+	return false
+}
+
 // Column is a column in a project board for grouping tasks by their progress.
 type Column struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	Index     int    `json:"index"`
-	ProjectID int    `json:"project_id"`
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Index     int        `json:"index"`
+	ProjectID int        `json:"project_id"`
+	Type      ColumnType `json:"type"`
+	// WIPLimit is the maximum number of tasks in the column, 0 means no limit.
+	WIPLimit int `json:"wip_limit"`
+}
+
+// ColumnPatch is a partial column update, nil fields are left unchanged.
+type ColumnPatch struct {
+	Name     *string
+	Type     *ColumnType
+	WIPLimit *int
+}
+
+// Apply sets the column fields present in the patch.
+func (patch ColumnPatch) Apply(c *Column) {
+	if patch.Name != nil {
+		c.Name = *patch.Name
+	}
+	if patch.Type != nil {
+		c.Type = *patch.Type
+	}
+	if patch.WIPLimit != nil {
+		c.WIPLimit = *patch.WIPLimit
+	}
 }
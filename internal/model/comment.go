@@ -9,3 +9,15 @@ type Comment struct {
 	CreatedAt time.Time `json:"created_at"`
 	TaskID    int       `json:"task_id"`
 }
+
+// CommentPatch is a partial comment update, nil fields are left unchanged.
+type CommentPatch struct {
+	Text *string
+}
+
+// Apply sets the comment fields present in the patch.
+func (patch CommentPatch) Apply(c *Comment) {
+	if patch.Text != nil {
+		c.Text = *patch.Text
+	}
+}
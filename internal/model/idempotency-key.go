@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// IdempotencyKey is the client provided key of a request which may be retried with the
+// response it got, so retries get the same response instead of repeating the request.
+// Status is 0 while the request is being handled. Headers are the response headers
+// set by the handler other than Content-Type, e.g. Location.
+type IdempotencyKey struct {
+	Key         string              `json:"key"`
+	Fingerprint string              `json:"fingerprint"`
+	Status      int                 `json:"status"`
+	ContentType string              `json:"content_type"`
+	Headers     map[string][]string `json:"headers"`
+	Body        []byte              `json:"body"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
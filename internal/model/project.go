@@ -6,4 +6,40 @@ type Project struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// SoftWIPLimits allows exceeding WIP limits of project columns with a warning instead
+	// of refusing to add tasks to full columns.
+	SoftWIPLimits bool `json:"soft_wip_limits"`
+	// StrictDependencies refuses moving blocked tasks to the last column of the project.
+	StrictDependencies bool `json:"strict_dependencies"`
+}
+
+// ProjectCounts is the number of columns and tasks of a project.
+type ProjectCounts struct {
+	ProjectID int
+	Columns   int
+	Tasks     int
+}
+
+// ProjectPatch is a partial project update, nil fields are left unchanged.
+type ProjectPatch struct {
+	Name               *string
+	Description        *string
+	SoftWIPLimits      *bool
+	StrictDependencies *bool
+}
+
+// Apply sets the project fields present in the patch.
+func (patch ProjectPatch) Apply(p *Project) {
+	if patch.Name != nil {
+		p.Name = *patch.Name
+	}
+	if patch.Description != nil {
+		p.Description = *patch.Description
+	}
+	if patch.SoftWIPLimits != nil {
+		p.SoftWIPLimits = *patch.SoftWIPLimits
+	}
+	if patch.StrictDependencies != nil {
+		p.StrictDependencies = *patch.StrictDependencies
+	}
 }
@@ -0,0 +1,8 @@
+package model
+
+// TaskLink is a dependency between tasks: the blocker task blocks the blocked one
+// until it's completed. Linked tasks can be in different projects.
+type TaskLink struct {
+	BlockerID int `json:"blocker_id"`
+	BlockedID int `json:"blocked_id"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // Task is a project task that's being moved across columns according to its progress
 // and within column according to its priority.
 type Task struct {
@@ -8,4 +10,56 @@ type Task struct {
 	Description string `json:"description"`
 	Index       int    `json:"index"`
 	ColumnID    int    `json:"column_id"`
+	// StartedAt is when the task entered an active or done column, CompletedAt is when
+	// it entered a done column. Both are cleared when the task is moved back.
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Blocked is whether any task blocking this one isn't completed yet, it isn't stored.
+	Blocked bool `json:"blocked"`
+
+	// Warnings are problems of the request which changed the task, they aren't stored.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// TaskPatch is a partial task update, nil fields are left unchanged.
+type TaskPatch struct {
+	Name        *string
+	Description *string
+}
+
+// Apply sets the task fields present in the patch.
+func (patch TaskPatch) Apply(t *Task) {
+	if patch.Name != nil {
+		t.Name = *patch.Name
+	}
+	if patch.Description != nil {
+		t.Description = *patch.Description
+	}
+}
+
+// TaskFilter selects tasks by their progress, nil fields match any task.
+type TaskFilter struct {
+	Started         *bool
+	Completed       *bool
+	CompletedAfter  *time.Time
+	CompletedBefore *time.Time
+}
+
+// Match checks whether the task passes the filter. Tasks completed exactly at
+// CompletedAfter or CompletedBefore match.
+func (f TaskFilter) Match(t Task) bool {
+	if f.Started != nil && *f.Started != (t.StartedAt != nil) {
+		return false
+	}
+	if f.Completed != nil && *f.Completed != (t.CompletedAt != nil) {
+		return false
+	}
+	if f.CompletedAfter != nil && (t.CompletedAt == nil || t.CompletedAt.Before(*f.CompletedAfter)) {
+		return false
+	}
+	if f.CompletedBefore != nil && (t.CompletedAt == nil || t.CompletedAt.After(*f.CompletedBefore)) {
+		return false
+	}
+
+	return true
 }
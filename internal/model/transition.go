@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Transition is a task entering a column. It's recorded when the task is created, with
+// FromColumnID 0, and every time the task is moved to another column.
+type Transition struct {
+	ID           int       `json:"id"`
+	TaskID       int       `json:"task_id"`
+	ProjectID    int       `json:"project_id"`
+	FromColumnID int       `json:"from_column_id"`
+	ToColumnID   int       `json:"to_column_id"`
+	At           time.Time `json:"at"`
+}
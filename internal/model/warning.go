@@ -0,0 +1,14 @@
+package model
+
+// Warning codes.
+const (
+	// WarningWIPLimitExceeded is used when a task is added to a column over its soft
+	// WIP limit.
+	WarningWIPLimitExceeded = "wip_limit_exceeded"
+)
+
+// Warning is a problem which didn't fail the request.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
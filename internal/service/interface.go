@@ -1,6 +1,11 @@
 package service
 
-import "github.com/imarrche/tasker/internal/model"
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+)
 
 //go:generate mockgen -source=interface.go -destination=mocks/mock.go
 
@@ -10,47 +15,60 @@ type Service interface {
 	Columns() ColumnService
 	Tasks() TaskService
 	Comments() CommentService
+	InTx(context.Context, func(Service) error) error
 }
 
 // ProjectService is the interface all project services must implement.
 type ProjectService interface {
-	GetAll() ([]model.Project, error)
-	Create(model.Project) (model.Project, error)
-	GetByID(int) (model.Project, error)
-	Update(model.Project) (model.Project, error)
-	DeleteByID(int) error
-	Validate(model.Project) error
+	GetAll(context.Context) ([]model.Project, error)
+	Create(context.Context, model.Project) (model.Project, error)
+	GetByID(context.Context, int) (model.Project, error)
+	Update(context.Context, model.Project) (model.Project, error)
+	Patch(context.Context, int, model.ProjectPatch) (model.Project, error)
+	DeleteByID(context.Context, int) error
+	Analytics(context.Context, int, time.Time, time.Time) (model.Analytics, error)
+	TakeSnapshots(context.Context, time.Time) error
+	CFD(context.Context, int, time.Time, time.Time) (model.CFD, error)
+	Validate(context.Context, model.Project) error
 }
 
 // ColumnService is the interface all column services must implement.
 type ColumnService interface {
-	GetByProjectID(int) ([]model.Column, error)
-	Create(model.Column) (model.Column, error)
-	GetByID(int) (model.Column, error)
-	Update(model.Column) (model.Column, error)
-	MoveByID(int, bool) error
-	DeleteByID(int) error
-	Validate(model.Column) error
+	GetByProjectID(context.Context, int) ([]model.Column, error)
+	Create(context.Context, model.Column) (model.Column, error)
+	GetByID(context.Context, int) (model.Column, error)
+	Update(context.Context, model.Column) (model.Column, error)
+	Patch(context.Context, int, model.ColumnPatch) (model.Column, error)
+	MoveByID(context.Context, int, bool) error
+	DeleteByID(context.Context, int) error
+	Validate(context.Context, model.Column) error
 }
 
 // TaskService is the interface all task services must implement.
 type TaskService interface {
-	GetByColumnID(int) ([]model.Task, error)
-	Create(model.Task) (model.Task, error)
-	GetByID(int) (model.Task, error)
-	Update(model.Task) (model.Task, error)
-	MoveToColumnByID(int, bool) error
-	MoveByID(int, bool) error
-	DeleteByID(int) error
-	Validate(model.Task) error
+	GetByColumnID(context.Context, int) ([]model.Task, error)
+	GetByColumnIDAndFilter(context.Context, int, model.TaskFilter) ([]model.Task, error)
+	Create(context.Context, model.Task) (model.Task, error)
+	GetByID(context.Context, int) (model.Task, error)
+	Update(context.Context, model.Task) (model.Task, error)
+	Patch(context.Context, int, model.TaskPatch) (model.Task, error)
+	MoveToColumnByID(context.Context, int, bool) (model.Task, error)
+	MoveToColumn(context.Context, int, int) (model.Task, error)
+	MoveByID(context.Context, int, bool) error
+	DeleteByID(context.Context, int) error
+	GetLinksByID(context.Context, int) ([]model.TaskLink, error)
+	CreateLink(context.Context, model.TaskLink) (model.TaskLink, error)
+	DeleteLink(context.Context, model.TaskLink) error
+	Validate(context.Context, model.Task) error
 }
 
 // CommentService is the interface all comment services must implement.
 type CommentService interface {
-	GetByTaskID(int) ([]model.Comment, error)
-	Create(model.Comment) (model.Comment, error)
-	GetByID(int) (model.Comment, error)
-	Update(model.Comment) (model.Comment, error)
-	DeleteByID(int) error
-	Validate(model.Comment) error
+	GetByTaskID(context.Context, int) ([]model.Comment, error)
+	Create(context.Context, model.Comment) (model.Comment, error)
+	GetByID(context.Context, int) (model.Comment, error)
+	Update(context.Context, model.Comment) (model.Comment, error)
+	Patch(context.Context, int, model.CommentPatch) (model.Comment, error)
+	DeleteByID(context.Context, int) error
+	Validate(context.Context, model.Comment) error
 }
@@ -5,10 +5,12 @@
 package mock_service
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/imarrche/tasker/internal/model"
 	service "github.com/imarrche/tasker/internal/service"
 	reflect "reflect"
+	time "time"
 )
 
 // MockService is a mock of Service interface
@@ -90,6 +92,20 @@ func (mr *MockServiceMockRecorder) Comments() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Comments", reflect.TypeOf((*MockService)(nil).Comments))
 }
 
+// InTx mocks base method
+func (m *MockService) InTx(arg0 context.Context, arg1 func(service.Service) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx
+func (mr *MockServiceMockRecorder) InTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockService)(nil).InTx), arg0, arg1)
+}
+
 // MockProjectService is a mock of ProjectService interface
 type MockProjectService struct {
 	ctrl     *gomock.Controller
@@ -114,91 +130,150 @@ func (m *MockProjectService) EXPECT() *MockProjectServiceMockRecorder {
 }
 
 // GetAll mocks base method
-func (m *MockProjectService) GetAll() ([]model.Project, error) {
+func (m *MockProjectService) GetAll(arg0 context.Context) ([]model.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", arg0)
 	ret0, _ := ret[0].([]model.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll
-func (mr *MockProjectServiceMockRecorder) GetAll() *gomock.Call {
+func (mr *MockProjectServiceMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProjectService)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProjectService)(nil).GetAll), arg0)
 }
 
 // Create mocks base method
-func (m *MockProjectService) Create(arg0 model.Project) (model.Project, error) {
+func (m *MockProjectService) Create(arg0 context.Context, arg1 model.Project) (model.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockProjectServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProjectService)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProjectService)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockProjectService) GetByID(arg0 int) (model.Project, error) {
+func (m *MockProjectService) GetByID(arg0 context.Context, arg1 int) (model.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockProjectServiceMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectServiceMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProjectService)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProjectService)(nil).GetByID), arg0, arg1)
 }
 
 // Update mocks base method
-func (m *MockProjectService) Update(arg0 model.Project) (model.Project, error) {
+func (m *MockProjectService) Update(arg0 context.Context, arg1 model.Project) (model.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockProjectServiceMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProjectService)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProjectService)(nil).Update), arg0, arg1)
+}
+
+// Patch mocks base method
+func (m *MockProjectService) Patch(arg0 context.Context, arg1 int, arg2 model.ProjectPatch) (model.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch
+func (mr *MockProjectServiceMockRecorder) Patch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockProjectService)(nil).Patch), arg0, arg1, arg2)
 }
 
 // DeleteByID mocks base method
-func (m *MockProjectService) DeleteByID(arg0 int) error {
+func (m *MockProjectService) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockProjectServiceMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectServiceMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockProjectService)(nil).DeleteByID), arg0, arg1)
+}
+
+// Analytics mocks base method
+func (m *MockProjectService) Analytics(arg0 context.Context, arg1 int, arg2, arg3 time.Time) (model.Analytics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Analytics", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(model.Analytics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Analytics indicates an expected call of Analytics
+func (mr *MockProjectServiceMockRecorder) Analytics(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Analytics", reflect.TypeOf((*MockProjectService)(nil).Analytics), arg0, arg1, arg2, arg3)
+}
+
+// TakeSnapshots mocks base method
+func (m *MockProjectService) TakeSnapshots(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeSnapshots", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TakeSnapshots indicates an expected call of TakeSnapshots
+func (mr *MockProjectServiceMockRecorder) TakeSnapshots(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockProjectService)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeSnapshots", reflect.TypeOf((*MockProjectService)(nil).TakeSnapshots), arg0, arg1)
+}
+
+// CFD mocks base method
+func (m *MockProjectService) CFD(arg0 context.Context, arg1 int, arg2, arg3 time.Time) (model.CFD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CFD", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(model.CFD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CFD indicates an expected call of CFD
+func (mr *MockProjectServiceMockRecorder) CFD(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CFD", reflect.TypeOf((*MockProjectService)(nil).CFD), arg0, arg1, arg2, arg3)
 }
 
 // Validate mocks base method
-func (m *MockProjectService) Validate(arg0 model.Project) error {
+func (m *MockProjectService) Validate(arg0 context.Context, arg1 model.Project) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Validate", arg0)
+	ret := m.ctrl.Call(m, "Validate", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Validate indicates an expected call of Validate
-func (mr *MockProjectServiceMockRecorder) Validate(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectServiceMockRecorder) Validate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockProjectService)(nil).Validate), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockProjectService)(nil).Validate), arg0, arg1)
 }
 
 // MockColumnService is a mock of ColumnService interface
@@ -225,105 +300,120 @@ func (m *MockColumnService) EXPECT() *MockColumnServiceMockRecorder {
 }
 
 // GetByProjectID mocks base method
-func (m *MockColumnService) GetByProjectID(arg0 int) ([]model.Column, error) {
+func (m *MockColumnService) GetByProjectID(arg0 context.Context, arg1 int) ([]model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByProjectID", arg0)
+	ret := m.ctrl.Call(m, "GetByProjectID", arg0, arg1)
 	ret0, _ := ret[0].([]model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByProjectID indicates an expected call of GetByProjectID
-func (mr *MockColumnServiceMockRecorder) GetByProjectID(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnServiceMockRecorder) GetByProjectID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProjectID", reflect.TypeOf((*MockColumnService)(nil).GetByProjectID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProjectID", reflect.TypeOf((*MockColumnService)(nil).GetByProjectID), arg0, arg1)
 }
 
 // Create mocks base method
-func (m *MockColumnService) Create(arg0 model.Column) (model.Column, error) {
+func (m *MockColumnService) Create(arg0 context.Context, arg1 model.Column) (model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockColumnServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockColumnService)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockColumnService)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockColumnService) GetByID(arg0 int) (model.Column, error) {
+func (m *MockColumnService) GetByID(arg0 context.Context, arg1 int) (model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockColumnServiceMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnServiceMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockColumnService)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockColumnService)(nil).GetByID), arg0, arg1)
 }
 
 // Update mocks base method
-func (m *MockColumnService) Update(arg0 model.Column) (model.Column, error) {
+func (m *MockColumnService) Update(arg0 context.Context, arg1 model.Column) (model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockColumnServiceMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockColumnService)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockColumnService)(nil).Update), arg0, arg1)
+}
+
+// Patch mocks base method
+func (m *MockColumnService) Patch(arg0 context.Context, arg1 int, arg2 model.ColumnPatch) (model.Column, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Column)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch
+func (mr *MockColumnServiceMockRecorder) Patch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockColumnService)(nil).Patch), arg0, arg1, arg2)
 }
 
 // MoveByID mocks base method
-func (m *MockColumnService) MoveByID(arg0 int, arg1 bool) error {
+func (m *MockColumnService) MoveByID(arg0 context.Context, arg1 int, arg2 bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MoveByID", arg0, arg1)
+	ret := m.ctrl.Call(m, "MoveByID", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // MoveByID indicates an expected call of MoveByID
-func (mr *MockColumnServiceMockRecorder) MoveByID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockColumnServiceMockRecorder) MoveByID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveByID", reflect.TypeOf((*MockColumnService)(nil).MoveByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveByID", reflect.TypeOf((*MockColumnService)(nil).MoveByID), arg0, arg1, arg2)
 }
 
 // DeleteByID mocks base method
-func (m *MockColumnService) DeleteByID(arg0 int) error {
+func (m *MockColumnService) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockColumnServiceMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnServiceMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockColumnService)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockColumnService)(nil).DeleteByID), arg0, arg1)
 }
 
 // Validate mocks base method
-func (m *MockColumnService) Validate(arg0 model.Column) error {
+func (m *MockColumnService) Validate(arg0 context.Context, arg1 model.Column) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Validate", arg0)
+	ret := m.ctrl.Call(m, "Validate", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Validate indicates an expected call of Validate
-func (mr *MockColumnServiceMockRecorder) Validate(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnServiceMockRecorder) Validate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockColumnService)(nil).Validate), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockColumnService)(nil).Validate), arg0, arg1)
 }
 
 // MockTaskService is a mock of TaskService interface
@@ -350,119 +440,209 @@ func (m *MockTaskService) EXPECT() *MockTaskServiceMockRecorder {
 }
 
 // GetByColumnID mocks base method
-func (m *MockTaskService) GetByColumnID(arg0 int) ([]model.Task, error) {
+func (m *MockTaskService) GetByColumnID(arg0 context.Context, arg1 int) ([]model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByColumnID", arg0)
+	ret := m.ctrl.Call(m, "GetByColumnID", arg0, arg1)
 	ret0, _ := ret[0].([]model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByColumnID indicates an expected call of GetByColumnID
-func (mr *MockTaskServiceMockRecorder) GetByColumnID(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) GetByColumnID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByColumnID", reflect.TypeOf((*MockTaskService)(nil).GetByColumnID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByColumnID", reflect.TypeOf((*MockTaskService)(nil).GetByColumnID), arg0, arg1)
+}
+
+// GetByColumnIDAndFilter mocks base method
+func (m *MockTaskService) GetByColumnIDAndFilter(arg0 context.Context, arg1 int, arg2 model.TaskFilter) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByColumnIDAndFilter", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByColumnIDAndFilter indicates an expected call of GetByColumnIDAndFilter
+func (mr *MockTaskServiceMockRecorder) GetByColumnIDAndFilter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByColumnIDAndFilter", reflect.TypeOf((*MockTaskService)(nil).GetByColumnIDAndFilter), arg0, arg1, arg2)
 }
 
 // Create mocks base method
-func (m *MockTaskService) Create(arg0 model.Task) (model.Task, error) {
+func (m *MockTaskService) Create(arg0 context.Context, arg1 model.Task) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockTaskServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskService)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskService)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockTaskService) GetByID(arg0 int) (model.Task, error) {
+func (m *MockTaskService) GetByID(arg0 context.Context, arg1 int) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockTaskServiceMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTaskService)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTaskService)(nil).GetByID), arg0, arg1)
 }
 
 // Update mocks base method
-func (m *MockTaskService) Update(arg0 model.Task) (model.Task, error) {
+func (m *MockTaskService) Update(arg0 context.Context, arg1 model.Task) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockTaskServiceMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskService)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskService)(nil).Update), arg0, arg1)
+}
+
+// Patch mocks base method
+func (m *MockTaskService) Patch(arg0 context.Context, arg1 int, arg2 model.TaskPatch) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch
+func (mr *MockTaskServiceMockRecorder) Patch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockTaskService)(nil).Patch), arg0, arg1, arg2)
 }
 
 // MoveToColumnByID mocks base method
-func (m *MockTaskService) MoveToColumnByID(arg0 int, arg1 bool) error {
+func (m *MockTaskService) MoveToColumnByID(arg0 context.Context, arg1 int, arg2 bool) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MoveToColumnByID", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MoveToColumnByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // MoveToColumnByID indicates an expected call of MoveToColumnByID
-func (mr *MockTaskServiceMockRecorder) MoveToColumnByID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) MoveToColumnByID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToColumnByID", reflect.TypeOf((*MockTaskService)(nil).MoveToColumnByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToColumnByID", reflect.TypeOf((*MockTaskService)(nil).MoveToColumnByID), arg0, arg1, arg2)
+}
+
+// MoveToColumn mocks base method
+func (m *MockTaskService) MoveToColumn(arg0 context.Context, arg1, arg2 int) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToColumn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveToColumn indicates an expected call of MoveToColumn
+func (mr *MockTaskServiceMockRecorder) MoveToColumn(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToColumn", reflect.TypeOf((*MockTaskService)(nil).MoveToColumn), arg0, arg1, arg2)
 }
 
 // MoveByID mocks base method
-func (m *MockTaskService) MoveByID(arg0 int, arg1 bool) error {
+func (m *MockTaskService) MoveByID(arg0 context.Context, arg1 int, arg2 bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MoveByID", arg0, arg1)
+	ret := m.ctrl.Call(m, "MoveByID", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // MoveByID indicates an expected call of MoveByID
-func (mr *MockTaskServiceMockRecorder) MoveByID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) MoveByID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveByID", reflect.TypeOf((*MockTaskService)(nil).MoveByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveByID", reflect.TypeOf((*MockTaskService)(nil).MoveByID), arg0, arg1, arg2)
 }
 
 // DeleteByID mocks base method
-func (m *MockTaskService) DeleteByID(arg0 int) error {
+func (m *MockTaskService) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockTaskServiceMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockTaskService)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockTaskService)(nil).DeleteByID), arg0, arg1)
+}
+
+// GetLinksByID mocks base method
+func (m *MockTaskService) GetLinksByID(arg0 context.Context, arg1 int) ([]model.TaskLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksByID", arg0, arg1)
+	ret0, _ := ret[0].([]model.TaskLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksByID indicates an expected call of GetLinksByID
+func (mr *MockTaskServiceMockRecorder) GetLinksByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByID", reflect.TypeOf((*MockTaskService)(nil).GetLinksByID), arg0, arg1)
+}
+
+// CreateLink mocks base method
+func (m *MockTaskService) CreateLink(arg0 context.Context, arg1 model.TaskLink) (model.TaskLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLink", arg0, arg1)
+	ret0, _ := ret[0].(model.TaskLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLink indicates an expected call of CreateLink
+func (mr *MockTaskServiceMockRecorder) CreateLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockTaskService)(nil).CreateLink), arg0, arg1)
+}
+
+// DeleteLink mocks base method
+func (m *MockTaskService) DeleteLink(arg0 context.Context, arg1 model.TaskLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLink", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLink indicates an expected call of DeleteLink
+func (mr *MockTaskServiceMockRecorder) DeleteLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLink", reflect.TypeOf((*MockTaskService)(nil).DeleteLink), arg0, arg1)
 }
 
 // Validate mocks base method
-func (m *MockTaskService) Validate(arg0 model.Task) error {
+func (m *MockTaskService) Validate(arg0 context.Context, arg1 model.Task) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Validate", arg0)
+	ret := m.ctrl.Call(m, "Validate", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Validate indicates an expected call of Validate
-func (mr *MockTaskServiceMockRecorder) Validate(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) Validate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockTaskService)(nil).Validate), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockTaskService)(nil).Validate), arg0, arg1)
 }
 
 // MockCommentService is a mock of CommentService interface
@@ -489,89 +669,104 @@ func (m *MockCommentService) EXPECT() *MockCommentServiceMockRecorder {
 }
 
 // GetByTaskID mocks base method
-func (m *MockCommentService) GetByTaskID(arg0 int) ([]model.Comment, error) {
+func (m *MockCommentService) GetByTaskID(arg0 context.Context, arg1 int) ([]model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByTaskID", arg0)
+	ret := m.ctrl.Call(m, "GetByTaskID", arg0, arg1)
 	ret0, _ := ret[0].([]model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByTaskID indicates an expected call of GetByTaskID
-func (mr *MockCommentServiceMockRecorder) GetByTaskID(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentServiceMockRecorder) GetByTaskID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTaskID", reflect.TypeOf((*MockCommentService)(nil).GetByTaskID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTaskID", reflect.TypeOf((*MockCommentService)(nil).GetByTaskID), arg0, arg1)
 }
 
 // Create mocks base method
-func (m *MockCommentService) Create(arg0 model.Comment) (model.Comment, error) {
+func (m *MockCommentService) Create(arg0 context.Context, arg1 model.Comment) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockCommentServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentService)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentService)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockCommentService) GetByID(arg0 int) (model.Comment, error) {
+func (m *MockCommentService) GetByID(arg0 context.Context, arg1 int) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockCommentServiceMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentServiceMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCommentService)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCommentService)(nil).GetByID), arg0, arg1)
 }
 
 // Update mocks base method
-func (m *MockCommentService) Update(arg0 model.Comment) (model.Comment, error) {
+func (m *MockCommentService) Update(arg0 context.Context, arg1 model.Comment) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockCommentServiceMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentService)(nil).Update), arg0, arg1)
+}
+
+// Patch mocks base method
+func (m *MockCommentService) Patch(arg0 context.Context, arg1 int, arg2 model.CommentPatch) (model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch
+func (mr *MockCommentServiceMockRecorder) Patch(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentService)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockCommentService)(nil).Patch), arg0, arg1, arg2)
 }
 
 // DeleteByID mocks base method
-func (m *MockCommentService) DeleteByID(arg0 int) error {
+func (m *MockCommentService) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockCommentServiceMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentServiceMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockCommentService)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockCommentService)(nil).DeleteByID), arg0, arg1)
 }
 
 // Validate mocks base method
-func (m *MockCommentService) Validate(arg0 model.Comment) error {
+func (m *MockCommentService) Validate(arg0 context.Context, arg1 model.Comment) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Validate", arg0)
+	ret := m.ctrl.Call(m, "Validate", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Validate indicates an expected call of Validate
-func (mr *MockCommentServiceMockRecorder) Validate(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentServiceMockRecorder) Validate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockCommentService)(nil).Validate), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockCommentService)(nil).Validate), arg0, arg1)
 }
@@ -1,25 +1,33 @@
 package web
 
 import (
+	"context"
 	"sort"
+	"time"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
 )
 
+// columnTypes are the valid column types listed in validation errors.
+var columnTypes = []string{
+	string(model.ColumnTypeBacklog), string(model.ColumnTypeActive), string(model.ColumnTypeDone),
+}
+
 // columnService is the web column service.
 type columnService struct {
 	store store.Store
+	now   func() time.Time
 }
 
 // newColumnService creates and returns a new columnService instance.
 func newColumnService(s store.Store) *columnService {
-	return &columnService{store: s}
+	return &columnService{store: s, now: time.Now}
 }
 
 // GetByProjectID returns all columns with specific project ID sorted by index.
-func (s *columnService) GetByProjectID(id int) ([]model.Column, error) {
-	cs, err := s.store.Columns().GetByProjectID(id)
+func (s *columnService) GetByProjectID(ctx context.Context, id int) ([]model.Column, error) {
+	cs, err := s.store.Columns().GetByProjectID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -31,146 +39,215 @@ func (s *columnService) GetByProjectID(id int) ([]model.Column, error) {
 	return cs, nil
 }
 
-// Create creates a new column.
-func (s *columnService) Create(c model.Column) (model.Column, error) {
-	if err := s.Validate(c); err != nil {
+// Create creates a new column, it's a backlog column unless another type is set.
+func (s *columnService) Create(ctx context.Context, c model.Column) (model.Column, error) {
+	setDefaultType(&c)
+	if err := s.Validate(ctx, c); err != nil {
 		return model.Column{}, err
 	}
 
-	cs, err := s.store.Columns().GetByProjectID(c.ProjectID)
+	err := s.store.InTx(ctx, func(tx store.Tx) error {
+		cs, err := tx.Columns().GetByProjectID(ctx, c.ProjectID)
+		if err != nil {
+			return err
+		}
+		c.Index = len(cs) + 1
+
+		c, err = tx.Columns().Create(ctx, c)
+		return err
+	})
 	if err != nil {
 		return model.Column{}, err
 	}
-	c.Index = len(cs) + 1
 
-	return s.store.Columns().Create(c)
+	return c, nil
 }
 
 // GetByID returns the column with specific ID.
-func (s *columnService) GetByID(id int) (model.Column, error) {
-	return s.store.Columns().GetByID(id)
+func (s *columnService) GetByID(ctx context.Context, id int) (model.Column, error) {
+	return s.store.Columns().GetByID(ctx, id)
 }
 
-// Update updates a column.
-func (s *columnService) Update(c model.Column) (model.Column, error) {
-	column, err := s.store.Columns().GetByID(c.ID)
+// Update updates a column, it becomes a backlog column unless another type is set.
+// Tasks already in the column keep their start and completion times.
+func (s *columnService) Update(ctx context.Context, c model.Column) (model.Column, error) {
+	column, err := s.store.Columns().GetByID(ctx, c.ID)
 	if err != nil {
 		return model.Column{}, err
 	}
 
 	column.Name = c.Name
-	if err := s.Validate(column); err != nil {
+	column.Type = c.Type
+	setDefaultType(&column)
+	column.WIPLimit = c.WIPLimit
+	if err := s.Validate(ctx, column); err != nil {
 		return model.Column{}, err
 	}
 
-	return s.store.Columns().Update(column)
+	return s.store.Columns().Update(ctx, column)
 }
 
-// MoveByID moves the column with specific ID left/right.
-func (s *columnService) MoveByID(id int, left bool) error {
-	c, err := s.store.Columns().GetByID(id)
+// Patch applies the patch to the column with specific ID. Removing the type makes it a
+// backlog column.
+func (s *columnService) Patch(ctx context.Context, id int, patch model.ColumnPatch) (model.Column, error) {
+	c, err := s.store.Columns().GetByID(ctx, id)
 	if err != nil {
-		return err
-	}
-
-	nextIdx := c.Index + 1
-	if left {
-		nextIdx = c.Index - 1
-	}
-	nextColumn, err := s.store.Columns().GetByIndexAndProjectID(nextIdx, c.ProjectID)
-	if err == store.ErrNotFound {
-		return ErrInvalidMove
-	} else if err != nil {
-		return err
+		return model.Column{}, err
 	}
 
-	if left {
-		c.Index--
-		nextColumn.Index++
-	} else {
-		c.Index++
-		nextColumn.Index--
-	}
-	if _, err = s.store.Columns().Update(nextColumn); err != nil {
-		return err
+	patch.Apply(&c)
+	setDefaultType(&c)
+	if err := s.Validate(ctx, c); err != nil {
+		return model.Column{}, err
 	}
-	_, err = s.store.Columns().Update(c)
 
-	return err
+	return s.store.Columns().Update(ctx, c)
 }
 
-// DeleteByID deletes the column with specific ID.
-func (s *columnService) DeleteByID(id int) error {
-	c, err := s.store.Columns().GetByID(id)
-	if err != nil {
-		return err
-	}
-	cs, err := s.store.Columns().GetByProjectID(c.ProjectID)
-	if err != nil {
-		return err
-	}
-	if len(cs) == 1 {
-		return ErrLastColumn
-	}
+// MoveByID moves the column with specific ID left/right.
+func (s *columnService) MoveByID(ctx context.Context, id int, left bool) error {
+	return s.store.InTx(ctx, func(tx store.Tx) error {
+		c, err := tx.Columns().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	nextIdx := c.Index - 1
-	if nextIdx == 0 {
-		nextIdx = 2
-	}
-	tasks, err := s.store.Tasks().GetByColumnID(c.ID)
-	if err != nil {
-		return err
-	}
-	var nextColumn model.Column
-	for _, column := range cs {
-		if column.Index == nextIdx {
-			nextColumn = column
-			break
+		nextIdx := c.Index + 1
+		if left {
+			nextIdx = c.Index - 1
 		}
-	}
-	nextColumnTasks, err := s.store.Tasks().GetByColumnID(nextColumn.ID)
-	if err != nil {
+		nextColumn, err := tx.Columns().GetByIndexAndProjectID(ctx, nextIdx, c.ProjectID)
+		if err == store.ErrNotFound {
+			return ErrInvalidMove
+		} else if err != nil {
+			return err
+		}
+
+		if left {
+			c.Index--
+			nextColumn.Index++
+		} else {
+			c.Index++
+			nextColumn.Index--
+		}
+		if _, err = tx.Columns().Update(ctx, nextColumn); err != nil {
+			return err
+		}
+		_, err = tx.Columns().Update(ctx, c)
+
 		return err
-	}
-	nextIdx = len(nextColumnTasks) + 1
-	for _, t := range tasks {
-		t.ColumnID = nextColumn.ID
-		t.Index = nextIdx
-		if _, err = s.store.Tasks().Update(t); err != nil {
+	})
+}
+
+// DeleteByID deletes the column with specific ID.
+func (s *columnService) DeleteByID(ctx context.Context, id int) error {
+	return s.store.InTx(ctx, func(tx store.Tx) error {
+		c, err := tx.Columns().GetByID(ctx, id)
+		if err != nil {
 			return err
 		}
-		nextIdx++
-	}
+		cs, err := tx.Columns().GetByProjectID(ctx, c.ProjectID)
+		if err != nil {
+			return err
+		}
+		if len(cs) == 1 {
+			return ErrLastColumn
+		}
 
-	for _, column := range cs {
-		if column.Index > c.Index {
-			column.Index--
-			if _, err = s.store.Columns().Update(column); err != nil {
+		nextIdx := c.Index - 1
+		if nextIdx == 0 {
+			nextIdx = 2
+		}
+		tasks, err := tx.Tasks().GetByColumnID(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		// Stores don't guarantee the order of tasks, moved tasks must keep their order.
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return tasks[i].Index < tasks[j].Index
+		})
+		var nextColumn model.Column
+		for _, column := range cs {
+			if column.Index == nextIdx {
+				nextColumn = column
+				break
+			}
+		}
+		nextColumnTasks, err := tx.Tasks().GetByColumnID(ctx, nextColumn.ID)
+		if err != nil {
+			return err
+		}
+		// Exceeding a soft limit isn't reported as deleting responds with no content.
+		if len(tasks) > 0 {
+			if _, err := checkWIPLimit(ctx, tx, nextColumn, len(nextColumnTasks)+len(tasks)); err != nil {
 				return err
 			}
 		}
-	}
+		nextIdx = len(nextColumnTasks) + 1
+		now := s.now().UTC()
+		for _, t := range tasks {
+			if err := checkDependencies(ctx, tx, t, nextColumn, c.ID); err != nil {
+				return err
+			}
+			t.ColumnID = nextColumn.ID
+			t.Index = nextIdx
+			trackProgress(&t, nextColumn, now)
+			if _, err = tx.Tasks().Update(ctx, t); err != nil {
+				return err
+			}
+			if err = recordTransition(ctx, tx, t, c.ID, nextColumn, now); err != nil {
+				return err
+			}
+			nextIdx++
+		}
+
+		for _, column := range cs {
+			if column.Index > c.Index {
+				column.Index--
+				if _, err = tx.Columns().Update(ctx, column); err != nil {
+					return err
+				}
+			}
+		}
 
-	return s.store.Columns().DeleteByID(id)
+		return tx.Columns().DeleteByID(ctx, id)
+	})
 }
 
 // Validate validates a column.
-func (s *columnService) Validate(c model.Column) error {
+func (s *columnService) Validate(ctx context.Context, c model.Column) error {
+	var es ValidationErrors
 	if len(c.Name) == 0 {
-		return ErrNameIsRequired
+		es = append(es, newRequiredError("name", ErrNameIsRequired))
 	} else if len(c.Name) > 255 {
-		return ErrNameIsTooLong
+		es = append(es, newTooLongError("name", 255, ErrNameIsTooLong))
+	} else {
+		cs, err := s.store.Columns().GetByProjectID(ctx, c.ProjectID)
+		if err != nil {
+			return err
+		}
+		for _, column := range cs {
+			if column.Name == c.Name && column.ID != c.ID {
+				es = append(es, newAlreadyExistsError("name", "project", ErrColumnAlreadyExists))
+				break
+			}
+		}
 	}
 
-	cs, err := s.store.Columns().GetByProjectID(c.ProjectID)
-	if err != nil {
-		return err
+	if !c.Type.IsValid() {
+		es = append(es, newInvalidError("type", columnTypes, ErrColumnTypeIsInvalid))
 	}
-	for _, column := range cs {
-		if column.Name == c.Name && column.ID != c.ID {
-			return ErrColumnAlreadyExists
-		}
+
+	if c.WIPLimit < 0 {
+		es = append(es, newTooSmallError("wip_limit", 0, ErrWIPLimitIsNegative))
 	}
 
-	return nil
+	return es.err()
+}
+
+// setDefaultType makes the column without a type a backlog column.
+func setDefaultType(c *model.Column) {
+	if c.Type == "" {
+		c.Type = model.ColumnTypeBacklog
+	}
 }
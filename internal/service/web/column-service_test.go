@@ -1,7 +1,9 @@
 package web
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -25,7 +27,7 @@ func TestColumnService_GetByProjectID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, id int, columns []model.Column) {
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByProjectID(id).Return(columns, nil)
+				cr.EXPECT().GetByProjectID(gomock.Any(), id).Return(columns, nil)
 				s.EXPECT().Columns().Return(cr)
 			},
 			projectID: 1,
@@ -51,7 +53,7 @@ func TestColumnService_GetByProjectID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.projectID, tc.columns)
 			s := newColumnService(store)
-			cs, err := s.GetByProjectID(tc.projectID)
+			cs, err := s.GetByProjectID(context.Background(), tc.projectID)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expColumns, cs)
@@ -70,19 +72,33 @@ func TestColumnService_Create(t *testing.T) {
 		{
 			name: "column is created",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
+				expectTx(s)
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByProjectID(column.ProjectID).Times(2).Return([]model.Column{}, nil)
-				cr.EXPECT().Create(column).Return(
-					model.Column{ID: 1, Name: column.Name, Index: column.Index, ProjectID: column.ProjectID},
+				cr.EXPECT().GetByProjectID(gomock.Any(), column.ProjectID).Times(2).Return([]model.Column{}, nil)
+				column.Type = model.ColumnTypeBacklog
+				cr.EXPECT().Create(gomock.Any(), column).Return(
+					model.Column{
+						ID: 1, Name: column.Name, Index: column.Index, ProjectID: column.ProjectID, Type: column.Type,
+					},
 					nil,
 				)
 				s.EXPECT().Columns().Times(3).Return(cr)
 			},
 			column:    model.Column{Name: "Column 1", Index: 1, ProjectID: 1},
-			expColumn: model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
+			expColumn: model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, Type: model.ColumnTypeBacklog},
 			expError:  nil,
 		},
+		{
+			name:      "column doesn't pass validation because of invalid type",
+			mock:      func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {},
+			column:    model.Column{Name: "", ProjectID: 1, Type: "finished"},
+			expColumn: model.Column{},
+			expError: ValidationErrors{
+				newRequiredError("name", ErrNameIsRequired),
+				newInvalidError("type", []string{"backlog", "active", "done"}, ErrColumnTypeIsInvalid),
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -93,7 +109,7 @@ func TestColumnService_Create(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.column)
 			s := newColumnService(store)
-			column, err := s.Create(tc.column)
+			column, err := s.Create(context.Background(), tc.column)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expColumn, column)
@@ -114,7 +130,7 @@ func TestColumnService_GetByID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByID(column.ID).Return(column, nil)
+				cr.EXPECT().GetByID(gomock.Any(), column.ID).Return(column, nil)
 				s.EXPECT().Columns().Return(cr)
 			},
 			column:    model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
@@ -131,7 +147,7 @@ func TestColumnService_GetByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.column)
 			s := newColumnService(store)
-			column, err := s.GetByID(tc.column.ID)
+			column, err := s.GetByID(context.Background(), tc.column.ID)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expColumn, column)
@@ -152,13 +168,13 @@ func TestColumnService_Update(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByID(column.ID).Return(column, nil)
-				cr.EXPECT().GetByProjectID(column.ProjectID).Return([]model.Column{}, nil)
-				cr.EXPECT().Update(column).Return(column, nil)
+				cr.EXPECT().GetByID(gomock.Any(), column.ID).Return(column, nil)
+				cr.EXPECT().GetByProjectID(gomock.Any(), column.ProjectID).Return([]model.Column{}, nil)
+				cr.EXPECT().Update(gomock.Any(), column).Return(column, nil)
 				s.EXPECT().Columns().Times(3).Return(cr)
 			},
-			column:    model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
-			expColumn: model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
+			column:    model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, Type: model.ColumnTypeActive},
+			expColumn: model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, Type: model.ColumnTypeActive},
 			expError:  nil,
 		},
 	}
@@ -171,7 +187,7 @@ func TestColumnService_Update(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.column)
 			s := newColumnService(store)
-			column, err := s.Update(tc.column)
+			column, err := s.Update(context.Background(), tc.column)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expColumn, column)
@@ -190,18 +206,19 @@ func TestColumnService_MoveByID(t *testing.T) {
 		{
 			name: "column is moved left",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
+				expectTx(s)
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByID(column.ID).Return(column, nil)
-				cr.EXPECT().GetByIndexAndProjectID(column.Index-1, column.ProjectID).Return(
+				cr.EXPECT().GetByID(gomock.Any(), column.ID).Return(column, nil)
+				cr.EXPECT().GetByIndexAndProjectID(gomock.Any(), column.Index-1, column.ProjectID).Return(
 					model.Column{ID: 1, Index: column.Index - 1},
 					nil,
 				)
-				cr.EXPECT().Update(model.Column{ID: 2, Index: 1}).Return(
+				cr.EXPECT().Update(gomock.Any(), model.Column{ID: 2, Index: 1}).Return(
 					model.Column{ID: 2, Index: 1},
 					nil,
 				)
-				cr.EXPECT().Update(model.Column{ID: 1, Index: 2}).Return(
+				cr.EXPECT().Update(gomock.Any(), model.Column{ID: 1, Index: 2}).Return(
 					model.Column{ID: 1, Index: 2},
 					nil,
 				)
@@ -214,18 +231,19 @@ func TestColumnService_MoveByID(t *testing.T) {
 		{
 			name: "column is moved right",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
+				expectTx(s)
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByID(column.ID).Return(column, nil)
-				cr.EXPECT().GetByIndexAndProjectID(column.Index+1, column.ProjectID).Return(
+				cr.EXPECT().GetByID(gomock.Any(), column.ID).Return(column, nil)
+				cr.EXPECT().GetByIndexAndProjectID(gomock.Any(), column.Index+1, column.ProjectID).Return(
 					model.Column{ID: 2, Index: column.Index + 1},
 					nil,
 				)
-				cr.EXPECT().Update(model.Column{ID: 2, Index: 1}).Return(
+				cr.EXPECT().Update(gomock.Any(), model.Column{ID: 2, Index: 1}).Return(
 					model.Column{ID: 2, Index: 1},
 					nil,
 				)
-				cr.EXPECT().Update(model.Column{ID: 1, Index: 2}).Return(
+				cr.EXPECT().Update(gomock.Any(), model.Column{ID: 1, Index: 2}).Return(
 					model.Column{ID: 1, Index: 2},
 					nil,
 				)
@@ -245,7 +263,7 @@ func TestColumnService_MoveByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.column)
 			s := newColumnService(store)
-			err := s.MoveByID(tc.column.ID, tc.left)
+			err := s.MoveByID(context.Background(), tc.column.ID, tc.left)
 
 			assert.Equal(t, tc.expError, err)
 		})
@@ -262,42 +280,119 @@ func TestColumnService_DeleteByID(t *testing.T) {
 		{
 			name: "column is deleted",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
+				expectTx(s)
 				cr := mock_store.NewMockColumnRepo(c)
 				tr := mock_store.NewMockTaskRepo(c)
 
-				cr.EXPECT().GetByID(column.ID).Return(column, nil)
-				cr.EXPECT().GetByProjectID(column.ProjectID).Return(
+				cr.EXPECT().GetByID(gomock.Any(), column.ID).Return(column, nil)
+				cr.EXPECT().GetByProjectID(gomock.Any(), column.ProjectID).Return(
 					[]model.Column{
 						column,
 						{ID: 2, Name: "Column 2", Index: 2, ProjectID: column.ProjectID},
 					},
 					nil,
 				)
-				tr.EXPECT().GetByColumnID(1).Return(
+				tr.EXPECT().GetByColumnID(gomock.Any(), 1).Return(
 					[]model.Task{{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1}},
 					nil,
 				)
-				tr.EXPECT().GetByColumnID(2).Return(
+				tr.EXPECT().GetByColumnID(gomock.Any(), 2).Return(
 					[]model.Task{{ID: 2, Name: "Task 2", Index: 1, ColumnID: 2}},
 					nil,
 				)
-				tr.EXPECT().Update(model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: 2}).Return(
+				tr.EXPECT().Update(gomock.Any(), model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: 2}).Return(
 					model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: 2},
 					nil,
 				)
-				cr.EXPECT().Update(
+				cr.EXPECT().Update(gomock.Any(),
 					model.Column{ID: 2, Name: "Column 2", Index: 1, ProjectID: column.ProjectID},
 				).Return(
 					model.Column{ID: 2, Name: "Column 2", Index: 1, ProjectID: column.ProjectID},
 					nil,
 				)
-				cr.EXPECT().DeleteByID(column.ID).Return(nil)
+				cr.EXPECT().DeleteByID(gomock.Any(), column.ID).Return(nil)
 				s.EXPECT().Columns().Times(4).Return(cr)
 				s.EXPECT().Tasks().Times(3).Return(tr)
+				expectTransition(c, s, model.Transition{TaskID: 1, ProjectID: 1, FromColumnID: 1, ToColumnID: 2, At: testNow})
 			},
 			column:   model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
 			expError: nil,
 		},
+		{
+			name: "moved tasks keep their order",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
+				expectTx(s)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				cr.EXPECT().GetByID(gomock.Any(), column.ID).Return(column, nil)
+				cr.EXPECT().GetByProjectID(gomock.Any(), column.ProjectID).Return(
+					[]model.Column{
+						column,
+						{ID: 2, Name: "Column 2", Index: 2, ProjectID: column.ProjectID},
+					},
+					nil,
+				)
+				tr.EXPECT().GetByColumnID(gomock.Any(), 1).Return(
+					[]model.Task{
+						{ID: 2, Name: "Task 2", Index: 2, ColumnID: 1},
+						{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
+					},
+					nil,
+				)
+				tr.EXPECT().GetByColumnID(gomock.Any(), 2).Return([]model.Task{}, nil)
+				gomock.InOrder(
+					tr.EXPECT().Update(gomock.Any(), model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 2}).Return(
+						model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 2},
+						nil,
+					),
+					tr.EXPECT().Update(gomock.Any(), model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 2}).Return(
+						model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 2},
+						nil,
+					),
+				)
+				cr.EXPECT().Update(gomock.Any(),
+					model.Column{ID: 2, Name: "Column 2", Index: 1, ProjectID: column.ProjectID},
+				).Return(
+					model.Column{ID: 2, Name: "Column 2", Index: 1, ProjectID: column.ProjectID},
+					nil,
+				)
+				cr.EXPECT().DeleteByID(gomock.Any(), column.ID).Return(nil)
+				s.EXPECT().Columns().Times(4).Return(cr)
+				s.EXPECT().Tasks().Times(4).Return(tr)
+				expectTransition(c, s, model.Transition{TaskID: 1, ProjectID: 1, FromColumnID: 1, ToColumnID: 2, At: testNow})
+				expectTransition(c, s, model.Transition{TaskID: 2, ProjectID: 1, FromColumnID: 1, ToColumnID: 2, At: testNow})
+			},
+			column:   model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
+			expError: nil,
+		},
+		{
+			name: "blocked task isn't moved to the column becoming last",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
+				expectTx(s)
+				pr := mock_store.NewMockProjectRepo(c)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				cs := []model.Column{{ID: 1, Name: "Column 1", Index: 1, ProjectID: column.ProjectID}, column}
+				cr.EXPECT().GetByID(gomock.Any(), column.ID).Return(column, nil)
+				cr.EXPECT().GetByProjectID(gomock.Any(), column.ProjectID).Times(2).Return(cs, nil)
+				tr.EXPECT().GetByColumnID(gomock.Any(), column.ID).Return(
+					[]model.Task{{ID: 1, Name: "Task 1", Index: 1, ColumnID: column.ID, Blocked: true}},
+					nil,
+				)
+				tr.EXPECT().GetByColumnID(gomock.Any(), 1).Return([]model.Task{}, nil)
+				pr.EXPECT().GetByID(gomock.Any(), column.ProjectID).Return(
+					model.Project{ID: column.ProjectID, Name: "Project 1", StrictDependencies: true},
+					nil,
+				)
+				s.EXPECT().Columns().Times(3).Return(cr)
+				s.EXPECT().Tasks().Times(2).Return(tr)
+				s.EXPECT().Projects().Return(pr)
+			},
+			column:   model.Column{ID: 2, Name: "Column 2", Index: 2, ProjectID: 1},
+			expError: ErrTaskIsBlocked,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -308,8 +403,9 @@ func TestColumnService_DeleteByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.column)
 			s := newColumnService(store)
+			s.now = func() time.Time { return testNow }
 
-			err := s.DeleteByID(tc.column.ID)
+			err := s.DeleteByID(context.Background(), tc.column.ID)
 			assert.Equal(t, tc.expError, err)
 		})
 	}
@@ -327,33 +423,42 @@ func TestColumnService_Validate(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByProjectID(column.ProjectID).Return([]model.Column{}, nil)
+				cr.EXPECT().GetByProjectID(gomock.Any(), column.ProjectID).Return([]model.Column{}, nil)
 				s.EXPECT().Columns().Return(cr)
 			},
-			column:   model.Column{Name: "Column 1", ProjectID: 1},
+			column:   model.Column{Name: "Column 1", ProjectID: 1, Type: model.ColumnTypeBacklog},
 			expError: nil,
 		},
 		{
 			name:     "column doesn't pass validation because of empty name",
 			mock:     func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {},
-			column:   model.Column{Name: ""},
-			expError: ErrNameIsRequired,
+			column:   model.Column{Name: "", Type: model.ColumnTypeBacklog},
+			expError: ValidationErrors{newRequiredError("name", ErrNameIsRequired)},
 		},
 		{
 			name:     "column doesn't pass validation because of too long name",
 			mock:     func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {},
-			column:   model.Column{Name: fixedLengthString(256)},
-			expError: ErrNameIsTooLong,
+			column:   model.Column{Name: fixedLengthString(256), Type: model.ColumnTypeBacklog},
+			expError: ValidationErrors{newTooLongError("name", 255, ErrNameIsTooLong)},
+		},
+		{
+			name:   "column doesn't pass validation because of negative wip limit",
+			mock:   func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {},
+			column: model.Column{Name: "", Type: model.ColumnTypeActive, WIPLimit: -1},
+			expError: ValidationErrors{
+				newRequiredError("name", ErrNameIsRequired),
+				newTooSmallError("wip_limit", 0, ErrWIPLimitIsNegative),
+			},
 		},
 		{
 			name: "column doesn't pass validation because of invalid project ID",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByProjectID(column.ProjectID).Return([]model.Column{}, store.ErrDbQuery)
+				cr.EXPECT().GetByProjectID(gomock.Any(), column.ProjectID).Return([]model.Column{}, store.ErrDbQuery)
 				s.EXPECT().Columns().Return(cr)
 			},
-			column:   model.Column{Name: "Column 1", ProjectID: 1},
+			column:   model.Column{Name: "Column 1", ProjectID: 1, Type: model.ColumnTypeBacklog},
 			expError: store.ErrDbQuery,
 		},
 		{
@@ -361,7 +466,7 @@ func TestColumnService_Validate(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, column model.Column) {
 				cr := mock_store.NewMockColumnRepo(c)
 
-				cr.EXPECT().GetByProjectID(column.ProjectID).Return(
+				cr.EXPECT().GetByProjectID(gomock.Any(), column.ProjectID).Return(
 					[]model.Column{
 						{ID: 1, Name: column.Name, Index: 1, ProjectID: column.ProjectID},
 					},
@@ -369,8 +474,10 @@ func TestColumnService_Validate(t *testing.T) {
 				)
 				s.EXPECT().Columns().Return(cr)
 			},
-			column:   model.Column{Name: "Column 1", ProjectID: 1},
-			expError: ErrColumnAlreadyExists,
+			column: model.Column{Name: "Column 1", ProjectID: 1, Type: model.ColumnTypeBacklog},
+			expError: ValidationErrors{
+				newAlreadyExistsError("name", "project", ErrColumnAlreadyExists),
+			},
 		},
 	}
 
@@ -382,9 +489,79 @@ func TestColumnService_Validate(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.column)
 			s := newColumnService(store)
-			err := s.Validate(tc.column)
+			err := s.Validate(context.Background(), tc.column)
+
+			assert.Equal(t, tc.expError, err)
+		})
+	}
+}
+
+func TestColumnService_Patch(t *testing.T) {
+	testcases := []struct {
+		name      string
+		mock      func(*gomock.Controller, *mock_store.MockStore)
+		patch     model.ColumnPatch
+		expColumn model.Column
+		expError  error
+	}{
+		{
+			name: "column is patched",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				cr := mock_store.NewMockColumnRepo(c)
+				column := model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, Type: model.ColumnTypeBacklog}
+				updated := model.Column{ID: 1, Name: "Done", Index: 1, ProjectID: 1, Type: model.ColumnTypeDone}
+
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(column, nil)
+				cr.EXPECT().GetByProjectID(gomock.Any(), 1).Return([]model.Column{column}, nil)
+				cr.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+				s.EXPECT().Columns().Times(3).Return(cr)
+			},
+			patch:     model.ColumnPatch{Name: strPtr("Done"), Type: columnTypePtr(model.ColumnTypeDone)},
+			expColumn: model.Column{ID: 1, Name: "Done", Index: 1, ProjectID: 1, Type: model.ColumnTypeDone},
+			expError:  nil,
+		},
+		{
+			name: "removed type is reset to backlog",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				cr := mock_store.NewMockColumnRepo(c)
+				column := model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, Type: model.ColumnTypeDone}
+				updated := model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, Type: model.ColumnTypeBacklog}
+
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(column, nil)
+				cr.EXPECT().GetByProjectID(gomock.Any(), 1).Return([]model.Column{column}, nil)
+				cr.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+				s.EXPECT().Columns().Times(3).Return(cr)
+			},
+			patch:     model.ColumnPatch{Type: columnTypePtr("")},
+			expColumn: model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, Type: model.ColumnTypeBacklog},
+			expError:  nil,
+		},
+		{
+			name: "column isn't found",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				cr := mock_store.NewMockColumnRepo(c)
+
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Column{}, store.ErrNotFound)
+				s.EXPECT().Columns().Return(cr)
+			},
+			patch:     model.ColumnPatch{Name: strPtr("Done")},
+			expColumn: model.Column{},
+			expError:  store.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			store := mock_store.NewMockStore(c)
+			tc.mock(c, store)
+			s := newColumnService(store)
+			column, err := s.Patch(context.Background(), 1, tc.patch)
 
 			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expColumn, column)
 		})
 	}
 }
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"sort"
 	"time"
 
@@ -20,8 +21,8 @@ func newCommentService(s store.Store) *commentService {
 
 // GetByTaskID returns all comments with specific task ID sorted by creation time
 // (from newest to oldest).
-func (s *commentService) GetByTaskID(id int) ([]model.Comment, error) {
-	cs, err := s.store.Comments().GetByTaskID(id)
+func (s *commentService) GetByTaskID(ctx context.Context, id int) ([]model.Comment, error) {
+	cs, err := s.store.Comments().GetByTaskID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -34,47 +35,63 @@ func (s *commentService) GetByTaskID(id int) ([]model.Comment, error) {
 }
 
 // Create creates a new comment.
-func (s *commentService) Create(c model.Comment) (model.Comment, error) {
+func (s *commentService) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
 	c.CreatedAt = time.Now()
-	if err := s.Validate(c); err != nil {
+	if err := s.Validate(ctx, c); err != nil {
 		return model.Comment{}, err
 	}
 
-	return s.store.Comments().Create(c)
+	return s.store.Comments().Create(ctx, c)
 }
 
 // GetByID returns the comment with specific ID.
-func (s *commentService) GetByID(id int) (model.Comment, error) {
-	return s.store.Comments().GetByID(id)
+func (s *commentService) GetByID(ctx context.Context, id int) (model.Comment, error) {
+	return s.store.Comments().GetByID(ctx, id)
 }
 
 // Update updates a comment.
-func (s *commentService) Update(c model.Comment) (model.Comment, error) {
-	comment, err := s.store.Comments().GetByID(c.ID)
+func (s *commentService) Update(ctx context.Context, c model.Comment) (model.Comment, error) {
+	comment, err := s.store.Comments().GetByID(ctx, c.ID)
 	if err != nil {
 		return model.Comment{}, err
 	}
 
 	comment.Text = c.Text
-	if err := s.Validate(comment); err != nil {
+	if err := s.Validate(ctx, comment); err != nil {
 		return model.Comment{}, err
 	}
 
-	return s.store.Comments().Update(comment)
+	return s.store.Comments().Update(ctx, comment)
+}
+
+// Patch applies the patch to the comment with specific ID.
+func (s *commentService) Patch(ctx context.Context, id int, patch model.CommentPatch) (model.Comment, error) {
+	c, err := s.store.Comments().GetByID(ctx, id)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	patch.Apply(&c)
+	if err := s.Validate(ctx, c); err != nil {
+		return model.Comment{}, err
+	}
+
+	return s.store.Comments().Update(ctx, c)
 }
 
 // DeleteByID deletes the comment with specific ID.
-func (s *commentService) DeleteByID(id int) error {
-	return s.store.Comments().DeleteByID(id)
+func (s *commentService) DeleteByID(ctx context.Context, id int) error {
+	return s.store.Comments().DeleteByID(ctx, id)
 }
 
 // Validate validates a comment.
-func (s *commentService) Validate(c model.Comment) error {
+func (s *commentService) Validate(ctx context.Context, c model.Comment) error {
+	var es ValidationErrors
 	if len(c.Text) == 0 {
-		return ErrTextIsRequired
+		es = append(es, newRequiredError("text", ErrTextIsRequired))
 	} else if len(c.Text) > 5000 {
-		return ErrTextIsTooLong
+		es = append(es, newTooLongError("text", 5000, ErrTextIsTooLong))
 	}
 
-	return nil
+	return es.err()
 }
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -25,7 +26,7 @@ func TestCommentService_GetByTaskID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, id int, cs []model.Comment) {
 				cr := mock_store.NewMockCommentRepo(c)
 
-				cr.EXPECT().GetByTaskID(id).Return(cs, nil)
+				cr.EXPECT().GetByTaskID(gomock.Any(), id).Return(cs, nil)
 				s.EXPECT().Comments().Return(cr)
 			},
 			taskID: 1,
@@ -81,7 +82,7 @@ func TestCommentService_GetByTaskID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.taskID, tc.comments)
 			s := newCommentService(store)
-			cs, err := s.GetByTaskID(tc.taskID)
+			cs, err := s.GetByTaskID(context.Background(), tc.taskID)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expComments, cs)
@@ -102,7 +103,7 @@ func TestCommentService_Create(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, comment model.Comment) {
 				cr := mock_store.NewMockCommentRepo(c)
 
-				cr.EXPECT().Create(gomock.Any()).Return(
+				cr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(
 					model.Comment{ID: 1, Text: comment.Text, TaskID: comment.TaskID},
 					nil,
 				)
@@ -122,7 +123,7 @@ func TestCommentService_Create(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.comment)
 			s := newCommentService(store)
-			comment, err := s.Create(tc.comment)
+			comment, err := s.Create(context.Background(), tc.comment)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expComment, comment)
@@ -143,7 +144,7 @@ func TestCommentService_GetByID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, comment model.Comment) {
 				cr := mock_store.NewMockCommentRepo(c)
 
-				cr.EXPECT().GetByID(comment.ID).Return(comment, nil)
+				cr.EXPECT().GetByID(gomock.Any(), comment.ID).Return(comment, nil)
 				s.EXPECT().Comments().Return(cr)
 			},
 			comment:    model.Comment{ID: 1, Text: "Comment 1", CreatedAt: time.Time{}, TaskID: 1},
@@ -160,7 +161,7 @@ func TestCommentService_GetByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.comment)
 			s := newCommentService(store)
-			comment, err := s.GetByID(tc.comment.ID)
+			comment, err := s.GetByID(context.Background(), tc.comment.ID)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expComment, comment)
@@ -181,8 +182,8 @@ func TestCommentService_Update(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, comment model.Comment) {
 				cr := mock_store.NewMockCommentRepo(c)
 
-				cr.EXPECT().GetByID(comment.ID).Return(comment, nil)
-				cr.EXPECT().Update(comment).Return(comment, nil)
+				cr.EXPECT().GetByID(gomock.Any(), comment.ID).Return(comment, nil)
+				cr.EXPECT().Update(gomock.Any(), comment).Return(comment, nil)
 				s.EXPECT().Comments().Times(2).Return(cr)
 			},
 			comment:    model.Comment{ID: 1, Text: "Comment 1", CreatedAt: time.Time{}, TaskID: 1},
@@ -199,7 +200,7 @@ func TestCommentService_Update(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.comment)
 			s := newCommentService(store)
-			comment, err := s.Update(tc.comment)
+			comment, err := s.Update(context.Background(), tc.comment)
 
 			assert.Equal(t, tc.expComment, comment)
 			assert.Equal(t, tc.expError, err)
@@ -219,7 +220,7 @@ func TestCommentService_DeleteByID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, comment model.Comment) {
 				cr := mock_store.NewMockCommentRepo(c)
 
-				cr.EXPECT().DeleteByID(comment.ID).Return(nil)
+				cr.EXPECT().DeleteByID(gomock.Any(), comment.ID).Return(nil)
 				s.EXPECT().Comments().Return(cr)
 			},
 			comment:  model.Comment{ID: 1, Text: "Comment 1", CreatedAt: time.Time{}, TaskID: 1},
@@ -235,7 +236,7 @@ func TestCommentService_DeleteByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.comment)
 			s := newCommentService(store)
-			err := s.DeleteByID(tc.comment.ID)
+			err := s.DeleteByID(context.Background(), tc.comment.ID)
 
 			assert.Equal(t, tc.expError, err)
 		})
@@ -259,13 +260,13 @@ func TestCommentService_Validate(t *testing.T) {
 			name:     "comment doesn't pass validation because of empty text",
 			mock:     func(c *gomock.Controller, s *mock_store.MockStore, comment model.Comment) {},
 			comment:  model.Comment{},
-			expError: ErrTextIsRequired,
+			expError: ValidationErrors{newRequiredError("text", ErrTextIsRequired)},
 		},
 		{
 			name:     "comment doesn't pass validation bacause of too long text",
 			mock:     func(c *gomock.Controller, s *mock_store.MockStore, comment model.Comment) {},
 			comment:  model.Comment{Text: fixedLengthString(5001)},
-			expError: ErrTextIsTooLong,
+			expError: ValidationErrors{newTooLongError("text", 5000, ErrTextIsTooLong)},
 		},
 	}
 
@@ -277,9 +278,49 @@ func TestCommentService_Validate(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.comment)
 			s := newCommentService(store)
-			err := s.Validate(tc.comment)
+			err := s.Validate(context.Background(), tc.comment)
 
 			assert.Equal(t, tc.expError, err)
 		})
 	}
 }
+
+func TestCommentService_Patch(t *testing.T) {
+	testcases := []struct {
+		name       string
+		mock       func(*gomock.Controller, *mock_store.MockStore)
+		patch      model.CommentPatch
+		expComment model.Comment
+		expError   error
+	}{
+		{
+			name: "comment is patched",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				cr := mock_store.NewMockCommentRepo(c)
+				updated := model.Comment{ID: 1, Text: "Edited", TaskID: 1}
+
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Comment{ID: 1, Text: "Comment", TaskID: 1}, nil)
+				cr.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+				s.EXPECT().Comments().Times(2).Return(cr)
+			},
+			patch:      model.CommentPatch{Text: strPtr("Edited")},
+			expComment: model.Comment{ID: 1, Text: "Edited", TaskID: 1},
+			expError:   nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			store := mock_store.NewMockStore(c)
+			tc.mock(c, store)
+			s := newCommentService(store)
+			comment, err := s.Patch(context.Background(), 1, tc.patch)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expComment, comment)
+		})
+	}
+}
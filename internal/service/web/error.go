@@ -1,6 +1,10 @@
 package web
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	// ErrNameIsRequired is thrown when name field is not provided.
@@ -19,20 +23,163 @@ var (
 	ErrLastColumn = errors.New("last column can't be deleted")
 	// ErrInvalidMove is thrown when model is moved to invalid position.
 	ErrInvalidMove = errors.New("move can't be performed")
+	// ErrWIPLimitIsNegative is thrown when WIP limit is negative.
+	ErrWIPLimitIsNegative = errors.New("wip limit can't be negative")
+	// ErrColumnTypeIsInvalid is thrown when column type is unknown.
+	ErrColumnTypeIsInvalid = errors.New("column type is invalid")
+	// ErrInvalidPeriod is thrown when a period doesn't end after it starts.
+	ErrInvalidPeriod = errors.New("period must end after it starts")
+	// ErrDependencyCycle is thrown when a task link would make a task block itself.
+	ErrDependencyCycle = errors.New("task can't block itself directly or through other tasks")
+	// ErrTaskIsBlocked is thrown when a blocked task is moved to the last column of
+	// a project with strict dependencies.
+	ErrTaskIsBlocked = errors.New("blocked task can't be moved to the last column")
+)
+
+// WIPLimitError is thrown when a task is added to a column which has reached its hard
+// WIP limit.
+type WIPLimitError struct {
+	ColumnID int
+	Limit    int
+}
+
+// Error returns WIP limit error message.
+func (e *WIPLimitError) Error() string {
+	return fmt.Sprintf("column %d has reached its wip limit of %d tasks", e.ColumnID, e.Limit)
+}
+
+// Validation error codes.
+const (
+	// CodeRequired is used when a required field is not provided.
+	CodeRequired = "required"
+	// CodeTooLong is used when a field exceeds its maximum length.
+	CodeTooLong = "too_long"
+	// CodeAlreadyExists is used when a field must be unique but isn't.
+	CodeAlreadyExists = "already_exists"
+	// CodeTooSmall is used when a field is less than its minimum value.
+	CodeTooSmall = "too_small"
+	// CodeInvalid is used when a field isn't one of its allowed values.
+	CodeInvalid = "invalid"
 )
 
+// ValidationError is a single field validation failure.
+type ValidationError struct {
+	Field      string `json:"field"`
+	Code       string `json:"code"`
+	Constraint string `json:"constraint,omitempty"`
+	Message    string `json:"message"`
+
+	err error
+}
+
+// Error returns validation error message.
+func (e ValidationError) Error() string {
+	if e.Constraint == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s (%s)", e.Message, e.Constraint)
+}
+
+// Unwrap returns the sentinel error this validation error was built from.
+func (e ValidationError) Unwrap() error { return e.err }
+
+// ValidationErrors is the list of all validation failures found for a model.
+type ValidationErrors []ValidationError
+
+// Error joins all validation error messages.
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of validation errors matches the target.
+func (es ValidationErrors) Is(target error) bool {
+	for _, e := range es {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// err returns validation errors as error or nil if there are none.
+func (es ValidationErrors) err() error {
+	if len(es) == 0 {
+		return nil
+	}
+
+	return es
+}
+
+// newRequiredError creates a validation error for a missing field.
+func newRequiredError(field string, err error) ValidationError {
+	return ValidationError{Field: field, Code: CodeRequired, Message: err.Error(), err: err}
+}
+
+// newTooLongError creates a validation error for a field longer than max characters.
+func newTooLongError(field string, max int, err error) ValidationError {
+	return ValidationError{
+		Field:      field,
+		Code:       CodeTooLong,
+		Constraint: fmt.Sprintf("max length is %d", max),
+		Message:    err.Error(),
+		err:        err,
+	}
+}
+
+// newTooSmallError creates a validation error for a field less than min.
+func newTooSmallError(field string, min int, err error) ValidationError {
+	return ValidationError{
+		Field:      field,
+		Code:       CodeTooSmall,
+		Constraint: fmt.Sprintf("min value is %d", min),
+		Message:    err.Error(),
+		err:        err,
+	}
+}
+
+// newInvalidError creates a validation error for a field which isn't one of the values.
+func newInvalidError(field string, values []string, err error) ValidationError {
+	return ValidationError{
+		Field:      field,
+		Code:       CodeInvalid,
+		Constraint: "must be one of " + strings.Join(values, ", "),
+		Message:    err.Error(),
+		err:        err,
+	}
+}
+
+// newAlreadyExistsError creates a validation error for a non unique field.
+func newAlreadyExistsError(field, scope string, err error) ValidationError {
+	return ValidationError{
+		Field:      field,
+		Code:       CodeAlreadyExists,
+		Constraint: fmt.Sprintf("must be unique within %s", scope),
+		Message:    err.Error(),
+		err:        err,
+	}
+}
+
 // IsValidationError checks whether error is validation related.
 func IsValidationError(err error) bool {
-	switch err {
-	case ErrNameIsRequired, ErrNameIsTooLong:
-		return true
-	case ErrDescriptionIsTooLong:
+	var es ValidationErrors
+	if errors.As(err, &es) {
 		return true
-	case ErrTextIsRequired, ErrTextIsTooLong:
-		return true
-	case ErrColumnAlreadyExists:
-		return true
-	default:
-		return false
 	}
+	var e ValidationError
+
+	return errors.As(err, &e)
+}
+
+// IsWIPLimitError checks whether error is caused by a hard WIP limit.
+func IsWIPLimitError(err error) bool {
+	var e *WIPLimitError
+
+	return errors.As(err, &e)
 }
@@ -0,0 +1,199 @@
+package web
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/inmem"
+	"github.com/imarrche/tasker/internal/store/pg/pgtest"
+	"github.com/imarrche/tasker/internal/store/sqlite"
+)
+
+func TestMain(m *testing.M) {
+	pgtest.Main(m)
+}
+
+// integrationStores are the real stores service scenarios are run against.
+var integrationStores = []struct {
+	name     string
+	newStore func(*testing.T) store.Store
+}{
+	{"inmem", func(t *testing.T) store.Store { return inmem.NewStore() }},
+	{"sqlite", func(t *testing.T) store.Store {
+		dir, err := ioutil.TempDir("", "tasker")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := sqlite.New(config.SQLite{Path: filepath.Join(dir, "tasker.db")})
+		if err := s.Open(); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			s.Close()
+			os.RemoveAll(dir)
+		})
+
+		return s
+	}},
+	{"pg", func(t *testing.T) store.Store { return pgtest.NewStore(t) }},
+}
+
+// names returns names of columns or tasks in order of their indices.
+func names(t *testing.T, models interface{}) []string {
+	var ns []string
+	switch ms := models.(type) {
+	case []model.Column:
+		for i, c := range ms {
+			require.Equal(t, i+1, c.Index, "column %q index", c.Name)
+			ns = append(ns, c.Name)
+		}
+	case []model.Task:
+		for i, task := range ms {
+			require.Equal(t, i+1, task.Index, "task %q index", task.Name)
+			ns = append(ns, task.Name)
+		}
+	}
+
+	return ns
+}
+
+func TestService_BoardScenario(t *testing.T) {
+	for _, st := range integrationStores {
+		t.Run(st.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewService(st.newStore(t))
+
+			// Project is created with default column.
+			p, err := s.Projects().Create(ctx, model.Project{Name: "Board"})
+			require.NoError(t, err)
+			cs, err := s.Columns().GetByProjectID(ctx, p.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"default"}, names(t, cs))
+			todo := cs[0]
+
+			inProgress, err := s.Columns().Create(
+				ctx, model.Column{Name: "In progress", ProjectID: p.ID, Type: model.ColumnTypeActive},
+			)
+			require.NoError(t, err)
+			done, err := s.Columns().Create(ctx, model.Column{Name: "Done", ProjectID: p.ID, Type: model.ColumnTypeDone})
+			require.NoError(t, err)
+			_, err = s.Columns().Create(ctx, model.Column{Name: "Done", ProjectID: p.ID})
+			assert.True(t, IsValidationError(err))
+
+			var tasks []model.Task
+			for _, name := range []string{"Task 1", "Task 2", "Task 3"} {
+				task, err := s.Tasks().Create(ctx, model.Task{Name: name, ColumnID: todo.ID})
+				require.NoError(t, err)
+				tasks = append(tasks, task)
+			}
+
+			// Reprioritizing and moving tasks keeps indices contiguous.
+			require.NoError(t, s.Tasks().MoveByID(ctx, tasks[2].ID, true))
+			_, err = s.Tasks().MoveToColumnByID(ctx, tasks[0].ID, false)
+			require.NoError(t, err)
+			_, err = s.Tasks().MoveToColumnByID(ctx, tasks[1].ID, true)
+			assert.Equal(t, ErrInvalidMove, err)
+			ts, err := s.Tasks().GetByColumnID(ctx, todo.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Task 3", "Task 2"}, names(t, ts))
+			ts, err = s.Tasks().GetByColumnID(ctx, inProgress.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Task 1"}, names(t, ts))
+			assert.NotNil(t, ts[0].StartedAt)
+			assert.Nil(t, ts[0].CompletedAt)
+
+			// Moving columns and deleting the first one moves its tasks to the next column.
+			require.NoError(t, s.Columns().MoveByID(ctx, done.ID, true))
+			require.NoError(t, s.Columns().DeleteByID(ctx, todo.ID))
+			cs, err = s.Columns().GetByProjectID(ctx, p.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Done", "In progress"}, names(t, cs))
+			ts, err = s.Tasks().GetByColumnID(ctx, done.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Task 3", "Task 2"}, names(t, ts))
+			for _, task := range ts {
+				assert.NotNil(t, task.StartedAt)
+				assert.NotNil(t, task.CompletedAt)
+			}
+
+			// Comments are listed newest first.
+			_, err = s.Comments().Create(ctx, model.Comment{Text: "First", TaskID: tasks[0].ID})
+			require.NoError(t, err)
+			_, err = s.Comments().Create(ctx, model.Comment{Text: "Second", TaskID: tasks[0].ID})
+			require.NoError(t, err)
+			comments, err := s.Comments().GetByTaskID(ctx, tasks[0].ID)
+			require.NoError(t, err)
+			require.Equal(t, 2, len(comments))
+			assert.Equal(t, "Second", comments[0].Text)
+
+			// Deleting the task shifts the tasks below it up.
+			require.NoError(t, s.Tasks().DeleteByID(ctx, tasks[2].ID))
+			ts, err = s.Tasks().GetByColumnID(ctx, done.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Task 2"}, names(t, ts))
+
+			// Last column can't be deleted, deleting the project deletes the board.
+			require.NoError(t, s.Columns().DeleteByID(ctx, inProgress.ID))
+			assert.Equal(t, ErrLastColumn, s.Columns().DeleteByID(ctx, done.ID))
+			require.NoError(t, s.Projects().DeleteByID(ctx, p.ID))
+			_, err = s.Tasks().GetByID(ctx, tasks[1].ID)
+			assert.Equal(t, store.ErrNotFound, err)
+		})
+	}
+}
+
+func TestService_ConcurrentLinks(t *testing.T) {
+	for _, st := range integrationStores {
+		t.Run(st.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewService(st.newStore(t))
+			p, err := s.Projects().Create(ctx, model.Project{Name: "Board"})
+			require.NoError(t, err)
+			cs, err := s.Columns().GetByProjectID(ctx, p.ID)
+			require.NoError(t, err)
+
+			for round := 0; round < 10; round++ {
+				var ids [4]int
+				for i := range ids {
+					task, err := s.Tasks().Create(ctx, model.Task{Name: "Task", ColumnID: cs[0].ID})
+					require.NoError(t, err)
+					ids[i] = task.ID
+				}
+				a, b, c, d := ids[0], ids[1], ids[2], ids[3]
+				_, err = s.Tasks().CreateLink(ctx, model.TaskLink{BlockerID: b, BlockedID: c})
+				require.NoError(t, err)
+				_, err = s.Tasks().CreateLink(ctx, model.TaskLink{BlockerID: d, BlockedID: a})
+				require.NoError(t, err)
+
+				// Each link closes the cycle a, b, c, d only together with the other one.
+				var wg sync.WaitGroup
+				errs := make([]error, 2)
+				for i, l := range []model.TaskLink{{BlockerID: a, BlockedID: b}, {BlockerID: c, BlockedID: d}} {
+					wg.Add(1)
+					go func(i int, l model.TaskLink) {
+						defer wg.Done()
+						_, errs[i] = s.Tasks().CreateLink(ctx, l)
+					}(i, l)
+				}
+				wg.Wait()
+
+				assert.False(t, errs[0] == nil && errs[1] == nil, "round %d: both links are created", round)
+				for _, err := range errs {
+					if err != nil && err != ErrDependencyCycle && err != store.ErrConflict {
+						t.Errorf("round %d: unexpected error: %v", round, err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package web
 
 import (
+	"context"
 	"sort"
+	"time"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
@@ -18,8 +20,8 @@ func newProjectService(s store.Store) *projectService {
 }
 
 // GetAll returns all projects sorted alphabetically by name.
-func (s *projectService) GetAll() ([]model.Project, error) {
-	ps, err := s.store.Projects().GetAll()
+func (s *projectService) GetAll(ctx context.Context) ([]model.Project, error) {
+	ps, err := s.store.Projects().GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -32,19 +34,22 @@ func (s *projectService) GetAll() ([]model.Project, error) {
 }
 
 // Create creates a new project.
-func (s *projectService) Create(p model.Project) (model.Project, error) {
-	if err := s.Validate(p); err != nil {
+func (s *projectService) Create(ctx context.Context, p model.Project) (model.Project, error) {
+	if err := s.Validate(ctx, p); err != nil {
 		return model.Project{}, err
 	}
 
-	p, err := s.store.Projects().Create(p)
-	if err != nil {
-		return model.Project{}, err
-	}
+	err := s.store.InTx(ctx, func(tx store.Tx) error {
+		var err error
+		if p, err = tx.Projects().Create(ctx, p); err != nil {
+			return err
+		}
 
-	_, err = s.store.Columns().Create(
-		model.Column{Name: "default", Index: 1, ProjectID: p.ID},
-	)
+		_, err = tx.Columns().Create(
+			ctx, model.Column{Name: "default", Index: 1, ProjectID: p.ID, Type: model.ColumnTypeBacklog},
+		)
+		return err
+	})
 	if err != nil {
 		return model.Project{}, err
 	}
@@ -53,42 +58,185 @@ func (s *projectService) Create(p model.Project) (model.Project, error) {
 }
 
 // GetByID returns the project with specific ID.
-func (s *projectService) GetByID(id int) (model.Project, error) {
-	return s.store.Projects().GetByID(id)
+func (s *projectService) GetByID(ctx context.Context, id int) (model.Project, error) {
+	return s.store.Projects().GetByID(ctx, id)
 }
 
 // Update updates a project.
-func (s *projectService) Update(p model.Project) (model.Project, error) {
-	project, err := s.store.Projects().GetByID(p.ID)
+func (s *projectService) Update(ctx context.Context, p model.Project) (model.Project, error) {
+	project, err := s.store.Projects().GetByID(ctx, p.ID)
 	if err != nil {
 		return model.Project{}, err
 	}
 
 	project.Name = p.Name
 	project.Description = p.Description
-	if err := s.Validate(project); err != nil {
+	project.SoftWIPLimits = p.SoftWIPLimits
+	project.StrictDependencies = p.StrictDependencies
+	if err := s.Validate(ctx, project); err != nil {
+		return model.Project{}, err
+	}
+
+	return s.store.Projects().Update(ctx, project)
+}
+
+// Patch applies the patch to the project with specific ID.
+func (s *projectService) Patch(ctx context.Context, id int, patch model.ProjectPatch) (model.Project, error) {
+	p, err := s.store.Projects().GetByID(ctx, id)
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	patch.Apply(&p)
+	if err := s.Validate(ctx, p); err != nil {
 		return model.Project{}, err
 	}
 
-	return s.store.Projects().Update(project)
+	return s.store.Projects().Update(ctx, p)
 }
 
 // DeleteByID deletes the project with specific ID.
-func (s *projectService) DeleteByID(id int) error {
-	return s.store.Projects().DeleteByID(id)
+func (s *projectService) DeleteByID(ctx context.Context, id int) error {
+	return s.store.Projects().DeleteByID(ctx, id)
+}
+
+// Analytics returns flow metrics of the project with specific ID over the period
+// from (inclusive) to (exclusive).
+func (s *projectService) Analytics(ctx context.Context, id int, from, to time.Time) (model.Analytics, error) {
+	if !to.After(from) {
+		return model.Analytics{}, ErrInvalidPeriod
+	}
+	if _, err := s.store.Projects().GetByID(ctx, id); err != nil {
+		return model.Analytics{}, err
+	}
+
+	return s.store.Transitions().Analytics(ctx, id, from.UTC(), to.UTC())
+}
+
+// TakeSnapshots saves the number of tasks in every column of every project on the UTC
+// day of at. Snapshots taken earlier that day are replaced, so the last ones show the
+// board at the end of the day.
+func (s *projectService) TakeSnapshots(ctx context.Context, at time.Time) error {
+	ps, err := s.store.Projects().GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	day := truncateToDay(at)
+	for _, p := range ps {
+		err := s.store.InTx(ctx, func(tx store.Tx) error {
+			cs, err := tx.Columns().GetByProjectID(ctx, p.ID)
+			if err != nil {
+				return err
+			}
+			for _, c := range cs {
+				ts, err := tx.Tasks().GetByColumnID(ctx, c.ID)
+				if err != nil {
+					return err
+				}
+				_, err = tx.ColumnSnapshots().Save(ctx, model.ColumnSnapshot{
+					ProjectID: p.ID, ColumnID: c.ID, ColumnName: c.Name, ColumnIndex: c.Index,
+					Day: day, Count: len(ts),
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		// The project may be deleted since it was listed.
+		if err != nil && err != store.ErrNotFound && err != store.ErrInvalidReference {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CFD returns cumulative flow diagram data of the project with specific ID from the UTC
+// day of from to the one of to inclusive. Columns are ordered by their current index,
+// columns deleted since the snapshots were taken follow them ordered by last index.
+func (s *projectService) CFD(ctx context.Context, id int, from, to time.Time) (model.CFD, error) {
+	from, to = truncateToDay(from), truncateToDay(to)
+	if to.Before(from) {
+		return model.CFD{}, ErrInvalidPeriod
+	}
+	cs, err := s.store.Columns().GetByProjectID(ctx, id)
+	if err != nil {
+		return model.CFD{}, err
+	}
+	ss, err := s.store.ColumnSnapshots().GetByProjectID(ctx, id, from, to)
+	if err != nil {
+		return model.CFD{}, err
+	}
+
+	current := map[int]model.Column{}
+	for _, c := range cs {
+		current[c.ID] = c
+	}
+	columns := map[int]model.CFDColumn{}
+	for _, snapshot := range ss {
+		if c, ok := current[snapshot.ColumnID]; ok {
+			columns[c.ID] = model.CFDColumn{ID: c.ID, Name: c.Name, Index: c.Index}
+		} else {
+			// Snapshots are ordered by day, so the last one is kept.
+			columns[snapshot.ColumnID] = model.CFDColumn{
+				ID: snapshot.ColumnID, Name: snapshot.ColumnName, Index: snapshot.ColumnIndex, Deleted: true,
+			}
+		}
+	}
+
+	cfd := model.CFD{
+		From: from.Format(model.DateLayout), To: to.Format(model.DateLayout),
+		Columns: []model.CFDColumn{}, Days: []model.CFDDay{},
+	}
+	for _, c := range columns {
+		cfd.Columns = append(cfd.Columns, c)
+	}
+	sort.Slice(cfd.Columns, func(i, j int) bool {
+		ci, cj := cfd.Columns[i], cfd.Columns[j]
+		if ci.Deleted != cj.Deleted {
+			return !ci.Deleted
+		}
+		if ci.Index != cj.Index {
+			return ci.Index < cj.Index
+		}
+		return ci.ID < cj.ID
+	})
+	positions := map[int]int{}
+	for i, c := range cfd.Columns {
+		positions[c.ID] = i
+	}
+	for _, snapshot := range ss {
+		date := snapshot.Day.UTC().Format(model.DateLayout)
+		if len(cfd.Days) == 0 || cfd.Days[len(cfd.Days)-1].Date != date {
+			cfd.Days = append(cfd.Days, model.CFDDay{Date: date, Counts: make([]int, len(cfd.Columns))})
+		}
+		cfd.Days[len(cfd.Days)-1].Counts[positions[snapshot.ColumnID]] = snapshot.Count
+	}
+
+	return cfd, nil
+}
+
+// truncateToDay returns the start of the UTC day of t.
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
 
 // Validate validates a project.
-func (s *projectService) Validate(p model.Project) error {
+func (s *projectService) Validate(ctx context.Context, p model.Project) error {
+	var es ValidationErrors
 	if len(p.Name) == 0 {
-		return ErrNameIsRequired
+		es = append(es, newRequiredError("name", ErrNameIsRequired))
 	} else if len(p.Name) > 500 {
-		return ErrNameIsTooLong
+		es = append(es, newTooLongError("name", 500, ErrNameIsTooLong))
 	}
 
 	if len(p.Description) > 1000 {
-		return ErrDescriptionIsTooLong
+		es = append(es, newTooLongError("description", 1000, ErrDescriptionIsTooLong))
 	}
 
-	return nil
+	return es.err()
 }
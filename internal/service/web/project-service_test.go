@@ -1,12 +1,15 @@
 package web
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
 	mock_store "github.com/imarrche/tasker/internal/store/mocks"
 )
 
@@ -23,7 +26,7 @@ func TestProjectService_GetAll(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, ps []model.Project) {
 				pr := mock_store.NewMockProjectRepo(c)
 
-				pr.EXPECT().GetAll().Return(ps, nil)
+				pr.EXPECT().GetAll(gomock.Any()).Return(ps, nil)
 				s.EXPECT().Projects().Return(pr)
 			},
 			projects: []model.Project{
@@ -44,7 +47,7 @@ func TestProjectService_GetAll(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.projects)
 			s := newProjectService(store)
-			ps, err := s.GetAll()
+			ps, err := s.GetAll(context.Background())
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expProjects, ps)
@@ -63,12 +66,13 @@ func TestProjectService_Create(t *testing.T) {
 		{
 			name: "project is created with default column",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, p model.Project) {
+				expectTx(s)
 				pr := mock_store.NewMockProjectRepo(c)
 				cr := mock_store.NewMockColumnRepo(c)
 
-				pr.EXPECT().Create(p).Return(p, nil)
-				column := model.Column{Name: "default", Index: 1, ProjectID: p.ID}
-				cr.EXPECT().Create(column).Return(
+				pr.EXPECT().Create(gomock.Any(), p).Return(p, nil)
+				column := model.Column{Name: "default", Index: 1, ProjectID: p.ID, Type: model.ColumnTypeBacklog}
+				cr.EXPECT().Create(gomock.Any(), column).Return(
 					model.Column{ID: 1, Name: column.Name, ProjectID: column.ProjectID},
 					nil,
 				)
@@ -89,7 +93,7 @@ func TestProjectService_Create(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.project)
 			s := newProjectService(store)
-			p, err := s.Create(tc.project)
+			p, err := s.Create(context.Background(), tc.project)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expProject, p)
@@ -110,7 +114,7 @@ func TestProjectService_GetByID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, p model.Project) {
 				pr := mock_store.NewMockProjectRepo(c)
 
-				pr.EXPECT().GetByID(p.ID).Return(p, nil)
+				pr.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
 				s.EXPECT().Projects().Return(pr)
 			},
 			project:    model.Project{ID: 1, Name: "Project 1"},
@@ -127,7 +131,7 @@ func TestProjectService_GetByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.project)
 			s := newProjectService(store)
-			p, err := s.GetByID(tc.project.ID)
+			p, err := s.GetByID(context.Background(), tc.project.ID)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expProject, p)
@@ -148,8 +152,8 @@ func TestProjectService_Update(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, p model.Project) {
 				pr := mock_store.NewMockProjectRepo(c)
 
-				pr.EXPECT().GetByID(p.ID).Return(p, nil)
-				pr.EXPECT().Update(p).Return(p, nil)
+				pr.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
+				pr.EXPECT().Update(gomock.Any(), p).Return(p, nil)
 				s.EXPECT().Projects().Times(2).Return(pr)
 			},
 			project:    model.Project{ID: 1, Name: "Project 1"},
@@ -166,7 +170,7 @@ func TestProjectService_Update(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.project)
 			s := newProjectService(store)
-			p, err := s.Update(tc.project)
+			p, err := s.Update(context.Background(), tc.project)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expProject, p)
@@ -186,7 +190,7 @@ func TestProjectService_DeleteByID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, p model.Project) {
 				pr := mock_store.NewMockProjectRepo(c)
 
-				pr.EXPECT().DeleteByID(p.ID).Return(nil)
+				pr.EXPECT().DeleteByID(gomock.Any(), p.ID).Return(nil)
 				s.EXPECT().Projects().Return(pr)
 			},
 			project:  model.Project{ID: 1, Name: "Project 1"},
@@ -202,13 +206,207 @@ func TestProjectService_DeleteByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.project)
 			s := newProjectService(store)
-			err := s.DeleteByID(tc.project.ID)
+			err := s.DeleteByID(context.Background(), tc.project.ID)
 
 			assert.Equal(t, tc.expError, err)
 		})
 	}
 }
 
+func TestProjectService_Analytics(t *testing.T) {
+	from, to := testNow.Add(-24*time.Hour), testNow
+	testcases := []struct {
+		name         string
+		mock         func(*gomock.Controller, *mock_store.MockStore)
+		from, to     time.Time
+		expAnalytics model.Analytics
+		expError     error
+	}{
+		{
+			name: "analytics are computed",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				pr := mock_store.NewMockProjectRepo(c)
+				trr := mock_store.NewMockTransitionRepo(c)
+
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Project{ID: 1, Name: "Project 1"}, nil)
+				s.EXPECT().Projects().Return(pr)
+				trr.EXPECT().Analytics(gomock.Any(), 1, from, to).Return(
+					model.Analytics{From: from, To: to, Columns: []model.ColumnDwell{}}, nil,
+				)
+				s.EXPECT().Transitions().Return(trr)
+			},
+			from:         from,
+			to:           to,
+			expAnalytics: model.Analytics{From: from, To: to, Columns: []model.ColumnDwell{}},
+			expError:     nil,
+		},
+		{
+			name: "project doesn't exist",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				pr := mock_store.NewMockProjectRepo(c)
+
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Project{}, store.ErrNotFound)
+				s.EXPECT().Projects().Return(pr)
+			},
+			from:         from,
+			to:           to,
+			expAnalytics: model.Analytics{},
+			expError:     store.ErrNotFound,
+		},
+		{
+			name:         "period doesn't end after it starts",
+			mock:         func(c *gomock.Controller, s *mock_store.MockStore) {},
+			from:         to,
+			to:           to,
+			expAnalytics: model.Analytics{},
+			expError:     ErrInvalidPeriod,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			store := mock_store.NewMockStore(c)
+			tc.mock(c, store)
+			s := newProjectService(store)
+			a, err := s.Analytics(context.Background(), 1, tc.from, tc.to)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expAnalytics, a)
+		})
+	}
+}
+
+func TestProjectService_TakeSnapshots(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s := mock_store.NewMockStore(c)
+	pr := mock_store.NewMockProjectRepo(c)
+	cr := mock_store.NewMockColumnRepo(c)
+	tr := mock_store.NewMockTaskRepo(c)
+	sr := mock_store.NewMockColumnSnapshotRepo(c)
+	day := time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	pr.EXPECT().GetAll(gomock.Any()).Return([]model.Project{{ID: 1}, {ID: 2}}, nil)
+	s.EXPECT().Projects().Return(pr)
+	expectTx(s)
+	cr.EXPECT().GetByProjectID(gomock.Any(), 1).Return(
+		[]model.Column{{ID: 1, Name: "To do", Index: 1, ProjectID: 1}, {ID: 2, Name: "Done", Index: 2, ProjectID: 1}},
+		nil,
+	)
+	tr.EXPECT().GetByColumnID(gomock.Any(), 1).Return([]model.Task{{ID: 1}, {ID: 2}}, nil)
+	tr.EXPECT().GetByColumnID(gomock.Any(), 2).Return([]model.Task{}, nil)
+	sr.EXPECT().Save(gomock.Any(), model.ColumnSnapshot{
+		ProjectID: 1, ColumnID: 1, ColumnName: "To do", ColumnIndex: 1, Day: day, Count: 2,
+	}).Return(model.ColumnSnapshot{ID: 1}, nil)
+	sr.EXPECT().Save(gomock.Any(), model.ColumnSnapshot{
+		ProjectID: 1, ColumnID: 2, ColumnName: "Done", ColumnIndex: 2, Day: day, Count: 0,
+	}).Return(model.ColumnSnapshot{ID: 2}, nil)
+	// The second project is deleted since it's listed.
+	expectTx(s)
+	cr.EXPECT().GetByProjectID(gomock.Any(), 2).Return(nil, store.ErrNotFound)
+	s.EXPECT().Columns().Times(2).Return(cr)
+	s.EXPECT().Tasks().Times(2).Return(tr)
+	s.EXPECT().ColumnSnapshots().Times(2).Return(sr)
+
+	err := newProjectService(s).TakeSnapshots(context.Background(), testNow)
+	assert.NoError(t, err)
+}
+
+func TestProjectService_CFD(t *testing.T) {
+	day := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		name     string
+		mock     func(*gomock.Controller, *mock_store.MockStore)
+		from, to time.Time
+		expCFD   model.CFD
+		expError error
+	}{
+		{
+			name: "columns renamed, moved and deleted are counted",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				cr := mock_store.NewMockColumnRepo(c)
+				sr := mock_store.NewMockColumnSnapshotRepo(c)
+
+				cr.EXPECT().GetByProjectID(gomock.Any(), 1).Return(
+					[]model.Column{
+						{ID: 1, Name: "Backlog", Index: 1, ProjectID: 1},
+						{ID: 3, Name: "Done", Index: 2, ProjectID: 1},
+					},
+					nil,
+				)
+				s.EXPECT().Columns().Return(cr)
+				sr.EXPECT().GetByProjectID(gomock.Any(), 1, day, day.AddDate(0, 0, 1)).Return(
+					[]model.ColumnSnapshot{
+						{ProjectID: 1, ColumnID: 1, ColumnName: "To do", ColumnIndex: 1, Day: day, Count: 2},
+						{ProjectID: 1, ColumnID: 2, ColumnName: "Doing", ColumnIndex: 2, Day: day, Count: 1},
+						{ProjectID: 1, ColumnID: 3, ColumnName: "Done", ColumnIndex: 3, Day: day, Count: 0},
+						{ProjectID: 1, ColumnID: 1, ColumnName: "Backlog", ColumnIndex: 1, Day: day.AddDate(0, 0, 1), Count: 1},
+						{ProjectID: 1, ColumnID: 3, ColumnName: "Done", ColumnIndex: 2, Day: day.AddDate(0, 0, 1), Count: 2},
+					},
+					nil,
+				)
+				s.EXPECT().ColumnSnapshots().Return(sr)
+			},
+			from: day.Add(time.Hour),
+			to:   day.AddDate(0, 0, 1).Add(time.Hour),
+			expCFD: model.CFD{
+				From: "2021-01-01",
+				To:   "2021-01-02",
+				Columns: []model.CFDColumn{
+					{ID: 1, Name: "Backlog", Index: 1},
+					{ID: 3, Name: "Done", Index: 2},
+					{ID: 2, Name: "Doing", Index: 2, Deleted: true},
+				},
+				Days: []model.CFDDay{
+					{Date: "2021-01-01", Counts: []int{2, 0, 1}},
+					{Date: "2021-01-02", Counts: []int{1, 2, 0}},
+				},
+			},
+			expError: nil,
+		},
+		{
+			name: "project doesn't exist",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				cr := mock_store.NewMockColumnRepo(c)
+
+				cr.EXPECT().GetByProjectID(gomock.Any(), 1).Return(nil, store.ErrNotFound)
+				s.EXPECT().Columns().Return(cr)
+			},
+			from:     day,
+			to:       day,
+			expCFD:   model.CFD{},
+			expError: store.ErrNotFound,
+		},
+		{
+			name:     "period ends before it starts",
+			mock:     func(c *gomock.Controller, s *mock_store.MockStore) {},
+			from:     day,
+			to:       day.Add(-time.Hour),
+			expCFD:   model.CFD{},
+			expError: ErrInvalidPeriod,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			store := mock_store.NewMockStore(c)
+			tc.mock(c, store)
+			s := newProjectService(store)
+			cfd, err := s.CFD(context.Background(), 1, tc.from, tc.to)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expCFD, cfd)
+		})
+	}
+}
+
 func TestProjectService_Validate(t *testing.T) {
 	testcases := []struct {
 		name     string
@@ -223,17 +421,25 @@ func TestProjectService_Validate(t *testing.T) {
 		{
 			name:     "project doesn't pass validation because of empty name",
 			project:  model.Project{},
-			expError: ErrNameIsRequired,
+			expError: ValidationErrors{newRequiredError("name", ErrNameIsRequired)},
 		},
 		{
 			name:     "project doesn't pass validation because of too long name",
 			project:  model.Project{Name: fixedLengthString(501)},
-			expError: ErrNameIsTooLong,
+			expError: ValidationErrors{newTooLongError("name", 500, ErrNameIsTooLong)},
 		},
 		{
 			name:     "project doesn't pass validation because of too long description",
 			project:  model.Project{Name: "Project", Description: fixedLengthString(1001)},
-			expError: ErrDescriptionIsTooLong,
+			expError: ValidationErrors{newTooLongError("description", 1000, ErrDescriptionIsTooLong)},
+		},
+		{
+			name:    "project doesn't pass validation because of empty name and too long description",
+			project: model.Project{Description: fixedLengthString(1001)},
+			expError: ValidationErrors{
+				newRequiredError("name", ErrNameIsRequired),
+				newTooLongError("description", 1000, ErrDescriptionIsTooLong),
+			},
 		},
 	}
 
@@ -241,7 +447,60 @@ func TestProjectService_Validate(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			s := newProjectService(nil)
 
-			assert.Equal(t, tc.expError, s.Validate(tc.project))
+			assert.Equal(t, tc.expError, s.Validate(context.Background(), tc.project))
+		})
+	}
+}
+
+func TestProjectService_Patch(t *testing.T) {
+	testcases := []struct {
+		name       string
+		mock       func(*gomock.Controller, *mock_store.MockStore)
+		patch      model.ProjectPatch
+		expProject model.Project
+		expError   error
+	}{
+		{
+			name: "only present fields are patched",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				pr := mock_store.NewMockProjectRepo(c)
+				p := model.Project{ID: 1, Name: "Project 1", Description: "Description"}
+				updated := model.Project{ID: 1, Name: "Renamed", Description: "Description"}
+
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(p, nil)
+				pr.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+				s.EXPECT().Projects().Times(2).Return(pr)
+			},
+			patch:      model.ProjectPatch{Name: strPtr("Renamed")},
+			expProject: model.Project{ID: 1, Name: "Renamed", Description: "Description"},
+			expError:   nil,
+		},
+		{
+			name: "patched project is invalid",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				pr := mock_store.NewMockProjectRepo(c)
+
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Project{ID: 1, Name: "Project 1"}, nil)
+				s.EXPECT().Projects().Return(pr)
+			},
+			patch:      model.ProjectPatch{Name: strPtr("")},
+			expProject: model.Project{},
+			expError:   ValidationErrors{newRequiredError("name", ErrNameIsRequired)},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			store := mock_store.NewMockStore(c)
+			tc.mock(c, store)
+			s := newProjectService(store)
+			p, err := s.Patch(context.Background(), 1, tc.patch)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expProject, p)
 		})
 	}
 }
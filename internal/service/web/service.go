@@ -1,6 +1,8 @@
 package web
 
 import (
+	"context"
+
 	"github.com/imarrche/tasker/internal/service"
 	"github.com/imarrche/tasker/internal/store"
 )
@@ -52,3 +54,64 @@ func (s *Service) Comments() service.CommentService {
 
 	return s.comments
 }
+
+// InTx runs fn with services reading and writing through a single store transaction,
+// so changes made by fn are committed if it returns nil and rolled back otherwise.
+func (s *Service) InTx(ctx context.Context, fn func(service.Service) error) error {
+	return s.store.InTx(ctx, func(tx store.Tx) error {
+		return fn(NewService(&txStore{root: s.store, tx: tx}))
+	})
+}
+
+// txStore is the store with repositories bound to a transaction. Transactions started
+// on it are part of that transaction. It doesn't embed the root store, so a repository
+// added to store.Store must be bound here too; only repositories store.Tx doesn't have
+// are taken from the root store.
+type txStore struct {
+	root store.Store
+	tx   store.Tx
+}
+
+var _ store.Store = (*txStore)(nil)
+
+// Open does nothing as the root store is open already.
+func (s *txStore) Open() error { return nil }
+
+// Ping checks the connection of the root store.
+func (s *txStore) Ping(ctx context.Context) error { return s.root.Ping(ctx) }
+
+// Projects returns the project repository of the transaction.
+func (s *txStore) Projects() store.ProjectRepo { return s.tx.Projects() }
+
+// Columns returns the column repository of the transaction.
+func (s *txStore) Columns() store.ColumnRepo { return s.tx.Columns() }
+
+// Tasks returns the task repository of the transaction.
+func (s *txStore) Tasks() store.TaskRepo { return s.tx.Tasks() }
+
+// Comments returns the comment repository of the transaction.
+func (s *txStore) Comments() store.CommentRepo { return s.tx.Comments() }
+
+// Transitions returns the transition repository of the transaction.
+func (s *txStore) Transitions() store.TransitionRepo { return s.tx.Transitions() }
+
+// ColumnSnapshots returns the column snapshot repository of the transaction.
+func (s *txStore) ColumnSnapshots() store.ColumnSnapshotRepo { return s.tx.ColumnSnapshots() }
+
+// TaskLinks returns the task link repository of the transaction.
+func (s *txStore) TaskLinks() store.TaskLinkRepo { return s.tx.TaskLinks() }
+
+// IdempotencyKeys returns the idempotency key repository of the root store, as keys
+// aren't part of transactions.
+func (s *txStore) IdempotencyKeys() store.IdempotencyKeyRepo { return s.root.IdempotencyKeys() }
+
+// InTx runs fn in the transaction.
+func (s *txStore) InTx(ctx context.Context, fn func(store.Tx) error) error { return fn(s.tx) }
+
+// InSerializableTx runs fn in the transaction, its isolation is set by the root store.
+func (s *txStore) InSerializableTx(ctx context.Context, fn func(store.Tx) error) error {
+	return fn(s.tx)
+}
+
+// Close does nothing as the root store is closed by its owner.
+func (s *txStore) Close() error { return nil }
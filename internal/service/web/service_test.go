@@ -1,14 +1,47 @@
 package web
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/service"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/inmem"
 	mock_store "github.com/imarrche/tasker/internal/store/mocks"
 )
 
+// expectTx expects a transaction running on the mock store itself.
+func expectTx(s *mock_store.MockStore) {
+	s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(store.Tx) error) error { return fn(s) },
+	)
+}
+
+// expectSerializableTx expects a serializable transaction running on the mock store itself.
+func expectSerializableTx(s *mock_store.MockStore) {
+	s.EXPECT().InSerializableTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(store.Tx) error) error { return fn(s) },
+	)
+}
+
+// expectTransition expects the transition to be recorded in the store.
+func expectTransition(c *gomock.Controller, s *mock_store.MockStore, t model.Transition) {
+	trr := mock_store.NewMockTransitionRepo(c)
+	trr.EXPECT().Create(gomock.Any(), t).Return(t, nil)
+	s.EXPECT().Transitions().Return(trr)
+}
+
+// strPtr returns a pointer to the string for patches.
+func strPtr(s string) *string { return &s }
+
+func columnTypePtr(t model.ColumnType) *model.ColumnType { return &t }
+
 func TestService_Projects(t *testing.T) {
 	c := gomock.NewController(t)
 	defer c.Finish()
@@ -24,7 +57,10 @@ func TestService_Columns(t *testing.T) {
 
 	store := mock_store.NewMockStore(c)
 
-	assert.Equal(t, newColumnService(store), NewService(store).Columns())
+	s, ok := NewService(store).Columns().(*columnService)
+	assert.True(t, ok)
+	assert.Equal(t, store, s.store)
+	assert.NotNil(t, s.now)
 }
 
 func TestService_Tasks(t *testing.T) {
@@ -33,7 +69,10 @@ func TestService_Tasks(t *testing.T) {
 
 	store := mock_store.NewMockStore(c)
 
-	assert.Equal(t, newTaskService(store), NewService(store).Tasks())
+	s, ok := NewService(store).Tasks().(*taskService)
+	assert.True(t, ok)
+	assert.Equal(t, store, s.store)
+	assert.NotNil(t, s.now)
 }
 
 func TestService_Comments(t *testing.T) {
@@ -44,3 +83,83 @@ func TestService_Comments(t *testing.T) {
 
 	assert.Equal(t, newCommentService(store), NewService(store).Comments())
 }
+
+func TestService_InTx(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	testcases := []struct {
+		name        string
+		fnErr       error
+		expProjects int
+	}{
+		{name: "changes are committed", fnErr: nil, expProjects: 2},
+		{name: "changes are rolled back", fnErr: errFailed, expProjects: 0},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewService(inmem.NewStore())
+			ctx := context.Background()
+
+			err := s.InTx(ctx, func(tx service.Service) error {
+				for _, name := range []string{"Project 1", "Project 2"} {
+					if _, err := tx.Projects().Create(ctx, model.Project{Name: name}); err != nil {
+						return err
+					}
+				}
+				ps, err := tx.Projects().GetAll(ctx)
+				assert.NoError(t, err)
+				assert.Len(t, ps, 2)
+
+				return tc.fnErr
+			})
+
+			assert.Equal(t, tc.fnErr, err)
+			ps, err := s.Projects().GetAll(ctx)
+			assert.NoError(t, err)
+			assert.Len(t, ps, tc.expProjects)
+		})
+	}
+}
+
+func TestService_InTxRepos(t *testing.T) {
+	s := inmem.TestStoreWithFixtures()
+	svc := NewService(s)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Services using repositories outside of their own transactions must get the ones
+	// of the enclosing transaction, otherwise in memory store deadlocks.
+	done := make(chan error, 1)
+	go func() {
+		done <- svc.InTx(ctx, func(tx service.Service) error {
+			l, err := tx.Tasks().CreateLink(ctx, model.TaskLink{BlockerID: 3, BlockedID: 1})
+			if err != nil {
+				return err
+			}
+			ls, err := tx.Tasks().GetLinksByID(ctx, 1)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, []model.TaskLink{l}, ls)
+			if err := tx.Tasks().DeleteLink(ctx, l); err != nil {
+				return err
+			}
+			if _, err := tx.Projects().Analytics(ctx, 1, now.Add(-time.Hour), now); err != nil {
+				return err
+			}
+			if _, err := tx.Projects().CFD(ctx, 1, now, now); err != nil {
+				return err
+			}
+
+			return tx.Projects().TakeSnapshots(ctx, now)
+		})
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("transaction is deadlocked")
+	}
+}
@@ -1,7 +1,9 @@
 package web
 
 import (
+	"context"
 	"sort"
+	"time"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
@@ -10,16 +12,17 @@ import (
 // taskService is the web task service.
 type taskService struct {
 	store store.Store
+	now   func() time.Time
 }
 
 // newTaskService creates and returns a new taskService instance.
 func newTaskService(s store.Store) *taskService {
-	return &taskService{store: s}
+	return &taskService{store: s, now: time.Now}
 }
 
 // GetByColumnID returns all tasks with specific column ID sorted by index.
-func (s *taskService) GetByColumnID(id int) ([]model.Task, error) {
-	ts, err := s.store.Tasks().GetByColumnID(id)
+func (s *taskService) GetByColumnID(ctx context.Context, id int) ([]model.Task, error) {
+	ts, err := s.store.Tasks().GetByColumnID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -31,151 +34,433 @@ func (s *taskService) GetByColumnID(id int) ([]model.Task, error) {
 	return ts, nil
 }
 
-// Create creates a new task.
-func (s *taskService) Create(t model.Task) (model.Task, error) {
-	if err := s.Validate(t); err != nil {
+// GetByColumnIDAndFilter returns tasks with specific column ID passing the filter sorted
+// by index.
+func (s *taskService) GetByColumnIDAndFilter(
+	ctx context.Context, id int, f model.TaskFilter,
+) ([]model.Task, error) {
+	ts, err := s.GetByColumnID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []model.Task{}
+	for _, t := range ts {
+		if f.Match(t) {
+			filtered = append(filtered, t)
+		}
+	}
+
+	return filtered, nil
+}
+
+// Create creates a new task. If the column's soft WIP limit is exceeded, the task is
+// returned with a warning.
+func (s *taskService) Create(ctx context.Context, t model.Task) (model.Task, error) {
+	if err := s.Validate(ctx, t); err != nil {
 		return model.Task{}, err
 	}
 
-	ts, err := s.store.Tasks().GetByColumnID(t.ColumnID)
+	var warnings []model.Warning
+	err := s.store.InTx(ctx, func(tx store.Tx) error {
+		c, err := tx.Columns().GetByID(ctx, t.ColumnID)
+		if err != nil {
+			return err
+		}
+		ts, err := tx.Tasks().GetByColumnID(ctx, t.ColumnID)
+		if err != nil {
+			return err
+		}
+		if warnings, err = checkWIPLimit(ctx, tx, c, len(ts)+1); err != nil {
+			return err
+		}
+		t.Index = len(ts) + 1
+		now := s.now().UTC()
+		trackProgress(&t, c, now)
+
+		if t, err = tx.Tasks().Create(ctx, t); err != nil {
+			return err
+		}
+		return recordTransition(ctx, tx, t, 0, c, now)
+	})
 	if err != nil {
 		return model.Task{}, err
 	}
-	t.Index = len(ts) + 1
+	t.Warnings = warnings
 
-	return s.store.Tasks().Create(t)
+	return t, nil
 }
 
 // GetByID returns the task with specific ID.
-func (s *taskService) GetByID(id int) (model.Task, error) {
-	return s.store.Tasks().GetByID(id)
+func (s *taskService) GetByID(ctx context.Context, id int) (model.Task, error) {
+	return s.store.Tasks().GetByID(ctx, id)
 }
 
 // Update updates a task.
-func (s *taskService) Update(t model.Task) (model.Task, error) {
-	task, err := s.store.Tasks().GetByID(t.ID)
+func (s *taskService) Update(ctx context.Context, t model.Task) (model.Task, error) {
+	task, err := s.store.Tasks().GetByID(ctx, t.ID)
 	if err != nil {
 		return model.Task{}, err
 	}
 
 	task.Name = t.Name
 	task.Description = t.Description
-	if err := s.Validate(task); err != nil {
+	if err := s.Validate(ctx, task); err != nil {
 		return model.Task{}, err
 	}
 
-	return s.store.Tasks().Update(task)
+	return s.store.Tasks().Update(ctx, task)
 }
 
-// MoveToColumnID moves the task with specific ID to the left/right column.
-func (s *taskService) MoveToColumnByID(id int, left bool) error {
-	t, err := s.store.Tasks().GetByID(id)
+// Patch applies the patch to the task with specific ID.
+func (s *taskService) Patch(ctx context.Context, id int, patch model.TaskPatch) (model.Task, error) {
+	t, err := s.store.Tasks().GetByID(ctx, id)
 	if err != nil {
-		return err
+		return model.Task{}, err
 	}
-	c, err := s.store.Columns().GetByID(t.ColumnID)
-	if err != nil {
+
+	patch.Apply(&t)
+	if err := s.Validate(ctx, t); err != nil {
+		return model.Task{}, err
+	}
+
+	return s.store.Tasks().Update(ctx, t)
+}
+
+// MoveToColumnID moves the task with specific ID to the left/right column and returns it.
+func (s *taskService) MoveToColumnByID(ctx context.Context, id int, left bool) (model.Task, error) {
+	var t model.Task
+	err := s.store.InTx(ctx, func(tx store.Tx) error {
+		var err error
+		if t, err = tx.Tasks().GetByID(ctx, id); err != nil {
+			return err
+		}
+		c, err := tx.Columns().GetByID(ctx, t.ColumnID)
+		if err != nil {
+			return err
+		}
+
+		nextIdx := c.Index + 1
+		if left {
+			nextIdx = c.Index - 1
+		}
+		nextColumn, err := tx.Columns().GetByIndexAndProjectID(ctx, nextIdx, c.ProjectID)
+		if err == store.ErrNotFound {
+			return ErrInvalidMove
+		} else if err != nil {
+			return err
+		}
+
+		t, err = moveToColumn(ctx, tx, t, nextColumn, s.now().UTC())
 		return err
+	})
+	if err != nil {
+		return model.Task{}, err
 	}
 
-	nextIdx := c.Index + 1
-	if left {
-		nextIdx = c.Index - 1
+	return t, nil
+}
+
+// MoveToColumn moves the task with specific ID to the end of the column with specific ID
+// of the same project and returns it. Moving the task to its own column doesn't change it.
+func (s *taskService) MoveToColumn(ctx context.Context, id, columnID int) (model.Task, error) {
+	var t model.Task
+	err := s.store.InTx(ctx, func(tx store.Tx) error {
+		var err error
+		if t, err = tx.Tasks().GetByID(ctx, id); err != nil {
+			return err
+		}
+		if t.ColumnID == columnID {
+			return nil
+		}
+		c, err := tx.Columns().GetByID(ctx, t.ColumnID)
+		if err != nil {
+			return err
+		}
+		nextColumn, err := tx.Columns().GetByID(ctx, columnID)
+		if err == store.ErrNotFound {
+			return ErrInvalidMove
+		} else if err != nil {
+			return err
+		}
+		if nextColumn.ProjectID != c.ProjectID {
+			return ErrInvalidMove
+		}
+
+		t, err = moveToColumn(ctx, tx, t, nextColumn, s.now().UTC())
+		return err
+	})
+	if err != nil {
+		return model.Task{}, err
 	}
-	nextColumn, err := s.store.Columns().GetByIndexAndProjectID(nextIdx, c.ProjectID)
-	if err == store.ErrNotFound {
-		return ErrInvalidMove
-	} else if err != nil {
+
+	return t, nil
+}
+
+// MoveByID moves the task with specific ID up/down.
+func (s *taskService) MoveByID(ctx context.Context, id int, up bool) error {
+	return s.store.InTx(ctx, func(tx store.Tx) error {
+		t, err := tx.Tasks().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		nextIdx := t.Index + 1
+		if up {
+			nextIdx = t.Index - 1
+		}
+		nextTask, err := tx.Tasks().GetByIndexAndColumnID(ctx, nextIdx, t.ColumnID)
+		if err == store.ErrNotFound {
+			return ErrInvalidMove
+		} else if err != nil {
+			return err
+		}
+
+		if up {
+			t.Index--
+			nextTask.Index++
+		} else {
+			t.Index++
+			nextTask.Index--
+		}
+		if _, err = tx.Tasks().Update(ctx, nextTask); err != nil {
+			return err
+		}
+
+		_, err = tx.Tasks().Update(ctx, t)
 		return err
+	})
+}
+
+// DeleteByID deletes the task with specific ID.
+func (s *taskService) DeleteByID(ctx context.Context, id int) error {
+	return s.store.InTx(ctx, func(tx store.Tx) error {
+		t, err := tx.Tasks().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		ts, err := tx.Tasks().GetByColumnID(ctx, t.ColumnID)
+		if err != nil {
+			return err
+		}
+		for _, task := range ts {
+			if task.Index > t.Index {
+				task.Index--
+				if _, err = tx.Tasks().Update(ctx, task); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Tasks().DeleteByID(ctx, id)
+	})
+}
+
+// GetLinksByID returns links the task with specific ID takes either side of.
+func (s *taskService) GetLinksByID(ctx context.Context, id int) ([]model.TaskLink, error) {
+	return s.store.TaskLinks().GetByTaskID(ctx, id)
+}
+
+// CreateLink makes the blocker task block the blocked one. Links making a task block
+// itself directly or through other tasks are refused.
+func (s *taskService) CreateLink(ctx context.Context, l model.TaskLink) (model.TaskLink, error) {
+	if l.BlockerID == l.BlockedID {
+		return model.TaskLink{}, ErrDependencyCycle
 	}
-	nextColumnTasks, err := s.store.Tasks().GetByColumnID(nextColumn.ID)
-	if err != nil {
+
+	// Concurrent links could close a cycle each of them doesn't see, so the check and
+	// the insert run in a serializable transaction.
+	err := s.store.InSerializableTx(ctx, func(tx store.Tx) error {
+		for _, id := range []int{l.BlockedID, l.BlockerID} {
+			if _, err := tx.Tasks().GetByID(ctx, id); err != nil {
+				return err
+			}
+		}
+		cycle, err := blocks(ctx, tx, l.BlockedID, l.BlockerID)
+		if err != nil {
+			return err
+		} else if cycle {
+			return ErrDependencyCycle
+		}
+
+		l, err = tx.TaskLinks().Create(ctx, l)
 		return err
+	})
+	if err != nil {
+		return model.TaskLink{}, err
 	}
 
-	tasks, err := s.store.Tasks().GetByColumnID(c.ID)
+	return l, nil
+}
+
+// DeleteLink deletes the task link.
+func (s *taskService) DeleteLink(ctx context.Context, l model.TaskLink) error {
+	return s.store.TaskLinks().Delete(ctx, l)
+}
+
+// Validate validates a task.
+func (s *taskService) Validate(ctx context.Context, t model.Task) error {
+	var es ValidationErrors
+	if len(t.Name) == 0 {
+		es = append(es, newRequiredError("name", ErrNameIsRequired))
+	} else if len(t.Name) > 500 {
+		es = append(es, newTooLongError("name", 500, ErrNameIsTooLong))
+	}
+
+	if len(t.Description) > 5000 {
+		es = append(es, newTooLongError("description", 5000, ErrDescriptionIsTooLong))
+	}
+
+	return es.err()
+}
+
+// moveToColumn moves the task to the end of the column closing the gap it leaves. If the
+// column's soft WIP limit is exceeded, the task is returned with a warning.
+func moveToColumn(
+	ctx context.Context, tx store.Tx, t model.Task, c model.Column, now time.Time,
+) (model.Task, error) {
+	if err := checkDependencies(ctx, tx, t, c, 0); err != nil {
+		return model.Task{}, err
+	}
+	ts, err := tx.Tasks().GetByColumnID(ctx, c.ID)
 	if err != nil {
-		return err
+		return model.Task{}, err
+	}
+	warnings, err := checkWIPLimit(ctx, tx, c, len(ts)+1)
+	if err != nil {
+		return model.Task{}, err
+	}
+	tasks, err := tx.Tasks().GetByColumnID(ctx, t.ColumnID)
+	if err != nil {
+		return model.Task{}, err
 	}
 	for _, task := range tasks {
 		if task.Index > t.Index {
 			task.Index--
-			if _, err = s.store.Tasks().Update(task); err != nil {
-				return err
+			if _, err = tx.Tasks().Update(ctx, task); err != nil {
+				return model.Task{}, err
 			}
 		}
 	}
 
-	t.ColumnID = nextColumn.ID
-	t.Index = len(nextColumnTasks) + 1
-	t, err = s.store.Tasks().Update(t)
-	return err
+	from := t.ColumnID
+	t.ColumnID = c.ID
+	t.Index = len(ts) + 1
+	trackProgress(&t, c, now)
+	if t, err = tx.Tasks().Update(ctx, t); err != nil {
+		return model.Task{}, err
+	}
+	if err = recordTransition(ctx, tx, t, from, c, now); err != nil {
+		return model.Task{}, err
+	}
+	t.Warnings = warnings
+
+	return t, nil
 }
 
-// MoveByID moves the task with specific ID up/down.
-func (s *taskService) MoveByID(id int, up bool) error {
-	t, err := s.store.Tasks().GetByID(id)
+// checkWIPLimit checks whether the column can have count tasks. If its WIP limit is
+// exceeded, it returns a warning when project's limits are soft and *WIPLimitError
+// otherwise.
+func checkWIPLimit(ctx context.Context, tx store.Tx, c model.Column, count int) ([]model.Warning, error) {
+	if c.WIPLimit == 0 || count <= c.WIPLimit {
+		return nil, nil
+	}
+	p, err := tx.Projects().GetByID(ctx, c.ProjectID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	nextIdx := t.Index + 1
-	if up {
-		nextIdx = t.Index - 1
-	}
-	nextTask, err := s.store.Tasks().GetByIndexAndColumnID(nextIdx, t.ColumnID)
-	if err == store.ErrNotFound {
-		return ErrInvalidMove
-	} else if err != nil {
-		return err
+	e := &WIPLimitError{ColumnID: c.ID, Limit: c.WIPLimit}
+	if !p.SoftWIPLimits {
+		return nil, e
 	}
 
-	if up {
-		t.Index--
-		nextTask.Index++
-	} else {
-		t.Index++
-		nextTask.Index--
+	return []model.Warning{{Code: model.WarningWIPLimitExceeded, Message: e.Error()}}, nil
+}
+
+// checkDependencies returns ErrTaskIsBlocked if the task is blocked and the column is
+// the last one of a project with strict dependencies. The column with ID skip, which is
+// being deleted, isn't counted.
+func checkDependencies(ctx context.Context, tx store.Tx, t model.Task, c model.Column, skip int) error {
+	if !t.Blocked {
+		return nil
 	}
-	if _, err = s.store.Tasks().Update(nextTask); err != nil {
+	p, err := tx.Projects().GetByID(ctx, c.ProjectID)
+	if err != nil {
 		return err
+	} else if !p.StrictDependencies {
+		return nil
 	}
-
-	_, err = s.store.Tasks().Update(t)
-	return err
-}
-
-// DeleteByID deletes the task with specific ID.
-func (s *taskService) DeleteByID(id int) error {
-	t, err := s.store.Tasks().GetByID(id)
+	cs, err := tx.Columns().GetByProjectID(ctx, c.ProjectID)
 	if err != nil {
 		return err
 	}
+	for _, column := range cs {
+		if column.ID != skip && column.Index > c.Index {
+			return nil
+		}
+	}
 
-	ts, err := s.store.Tasks().GetByColumnID(t.ColumnID)
-	for _, task := range ts {
-		if task.Index > t.Index {
-			task.Index--
-			if _, err = s.store.Tasks().Update(task); err != nil {
-				return err
+	return ErrTaskIsBlocked
+}
+
+// blocks checks whether the task with ID from blocks the task with ID to directly or
+// through other tasks.
+func blocks(ctx context.Context, tx store.Tx, from, to int) (bool, error) {
+	visited := map[int]bool{from: true}
+	queue := []int{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		ls, err := tx.TaskLinks().GetByTaskID(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		for _, l := range ls {
+			if l.BlockerID != id || visited[l.BlockedID] {
+				continue
+			}
+			if l.BlockedID == to {
+				return true, nil
 			}
+			visited[l.BlockedID] = true
+			queue = append(queue, l.BlockedID)
 		}
 	}
 
-	return s.store.Tasks().DeleteByID(id)
+	return false, nil
 }
 
-// Validate validates a task.
-func (s *taskService) Validate(t model.Task) error {
-	if len(t.Name) == 0 {
-		return ErrNameIsRequired
-	} else if len(t.Name) > 500 {
-		return ErrNameIsTooLong
-	}
+// recordTransition records the task entering the column from the column with ID from,
+// which is 0 for a new task.
+func recordTransition(ctx context.Context, tx store.Tx, t model.Task, from int, c model.Column, now time.Time) error {
+	_, err := tx.Transitions().Create(ctx, model.Transition{
+		TaskID: t.ID, ProjectID: c.ProjectID, FromColumnID: from, ToColumnID: c.ID, At: now,
+	})
 
-	if len(t.Description) > 5000 {
-		return ErrDescriptionIsTooLong
-	}
+	return err
+}
 
-	return nil
+// trackProgress records when the task entering the column is started and completed
+// according to the column type. Moving the task back to a backlog or active column
+// clears the times it no longer has.
+func trackProgress(t *model.Task, c model.Column, now time.Time) {
+	switch c.Type {
+	case model.ColumnTypeActive:
+		if t.StartedAt == nil {
+			t.StartedAt = &now
+		}
+		t.CompletedAt = nil
+	case model.ColumnTypeDone:
+		if t.StartedAt == nil {
+			t.StartedAt = &now
+		}
+		if t.CompletedAt == nil {
+			t.CompletedAt = &now
+		}
+	default:
+		t.StartedAt, t.CompletedAt = nil, nil
+	}
 }
@@ -1,15 +1,21 @@
 package web
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
 	mock_store "github.com/imarrche/tasker/internal/store/mocks"
 )
 
+// testNow is the current time of services in tests.
+var testNow = time.Date(2021, 1, 31, 8, 45, 12, 0, time.UTC)
+
 func TestTaskService_GetByColumnID(t *testing.T) {
 	testcases := []struct {
 		name     string
@@ -24,7 +30,7 @@ func TestTaskService_GetByColumnID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, id int, ts []model.Task) {
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByColumnID(id).Return(ts, nil)
+				tr.EXPECT().GetByColumnID(gomock.Any(), id).Return(ts, nil)
 				s.EXPECT().Tasks().Return(tr)
 			},
 			tasks: []model.Task{
@@ -49,7 +55,7 @@ func TestTaskService_GetByColumnID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.columnID, tc.tasks)
 			s := newTaskService(store)
-			ts, err := s.GetByColumnID(tc.columnID)
+			ts, err := s.GetByColumnID(context.Background(), tc.columnID)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expTasks, ts)
@@ -68,19 +74,100 @@ func TestTaskService_Create(t *testing.T) {
 		{
 			name: "task is created",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
+				expectTx(s)
+				cr := mock_store.NewMockColumnRepo(c)
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByColumnID(t.ColumnID).Return([]model.Task{}, nil)
-				tr.EXPECT().Create(t).Return(
+				cr.EXPECT().GetByID(gomock.Any(), t.ColumnID).Return(model.Column{ID: 1, ProjectID: 1}, nil)
+				s.EXPECT().Columns().Return(cr)
+				tr.EXPECT().GetByColumnID(gomock.Any(), t.ColumnID).Return([]model.Task{}, nil)
+				tr.EXPECT().Create(gomock.Any(), t).Return(
 					model.Task{ID: 1, Name: t.Name, Index: t.Index, ColumnID: t.ColumnID},
 					nil,
 				)
 				s.EXPECT().Tasks().Times(2).Return(tr)
+				expectTransition(c, s, model.Transition{TaskID: 1, ProjectID: 1, ToColumnID: 1, At: testNow})
 			},
 			task:     model.Task{Name: "Task 1", Index: 1, ColumnID: 1},
 			expTask:  model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
 			expError: nil,
 		},
+		{
+			name: "task created in active column is started",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
+				expectTx(s)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+				created := model.Task{Name: t.Name, Index: 1, ColumnID: t.ColumnID, StartedAt: &testNow}
+
+				cr.EXPECT().GetByID(gomock.Any(), t.ColumnID).Return(
+					model.Column{ID: 1, ProjectID: 1, Type: model.ColumnTypeActive}, nil,
+				)
+				s.EXPECT().Columns().Return(cr)
+				tr.EXPECT().GetByColumnID(gomock.Any(), t.ColumnID).Return([]model.Task{}, nil)
+				tr.EXPECT().Create(gomock.Any(), created).Return(
+					model.Task{ID: 1, Name: t.Name, Index: 1, ColumnID: t.ColumnID, StartedAt: &testNow}, nil,
+				)
+				s.EXPECT().Tasks().Times(2).Return(tr)
+				expectTransition(c, s, model.Transition{TaskID: 1, ProjectID: 1, ToColumnID: 1, At: testNow})
+			},
+			task:     model.Task{Name: "Task 1", ColumnID: 1},
+			expTask:  model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1, StartedAt: &testNow},
+			expError: nil,
+		},
+		{
+			name: "column has reached its hard wip limit",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
+				expectTx(s)
+				pr := mock_store.NewMockProjectRepo(c)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				cr.EXPECT().GetByID(gomock.Any(), t.ColumnID).Return(
+					model.Column{ID: 1, ProjectID: 1, WIPLimit: 1}, nil,
+				)
+				s.EXPECT().Columns().Return(cr)
+				tr.EXPECT().GetByColumnID(gomock.Any(), t.ColumnID).Return([]model.Task{{ID: 1}}, nil)
+				s.EXPECT().Tasks().Return(tr)
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Project{ID: 1}, nil)
+				s.EXPECT().Projects().Return(pr)
+			},
+			task:     model.Task{Name: "Task 2", ColumnID: 1},
+			expTask:  model.Task{},
+			expError: &WIPLimitError{ColumnID: 1, Limit: 1},
+		},
+		{
+			name: "column has exceeded its soft wip limit",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
+				expectTx(s)
+				pr := mock_store.NewMockProjectRepo(c)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				cr.EXPECT().GetByID(gomock.Any(), t.ColumnID).Return(
+					model.Column{ID: 1, ProjectID: 1, WIPLimit: 1}, nil,
+				)
+				s.EXPECT().Columns().Return(cr)
+				tr.EXPECT().GetByColumnID(gomock.Any(), t.ColumnID).Return([]model.Task{{ID: 1}}, nil)
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Project{ID: 1, SoftWIPLimits: true}, nil)
+				s.EXPECT().Projects().Return(pr)
+				tr.EXPECT().Create(gomock.Any(), model.Task{Name: t.Name, Index: 2, ColumnID: t.ColumnID}).Return(
+					model.Task{ID: 2, Name: t.Name, Index: 2, ColumnID: t.ColumnID},
+					nil,
+				)
+				s.EXPECT().Tasks().Times(2).Return(tr)
+				expectTransition(c, s, model.Transition{TaskID: 2, ProjectID: 1, ToColumnID: 1, At: testNow})
+			},
+			task: model.Task{Name: "Task 2", ColumnID: 1},
+			expTask: model.Task{
+				ID: 2, Name: "Task 2", Index: 2, ColumnID: 1,
+				Warnings: []model.Warning{{
+					Code:    model.WarningWIPLimitExceeded,
+					Message: "column 1 has reached its wip limit of 1 tasks",
+				}},
+			},
+			expError: nil,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -91,7 +178,8 @@ func TestTaskService_Create(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.task)
 			s := newTaskService(store)
-			task, err := s.Create(tc.task)
+			s.now = func() time.Time { return testNow }
+			task, err := s.Create(context.Background(), tc.task)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expTask, task)
@@ -112,7 +200,7 @@ func TestTaskService_GetByID(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByID(t.ID).Return(t, nil)
+				tr.EXPECT().GetByID(gomock.Any(), t.ID).Return(t, nil)
 				s.EXPECT().Tasks().Return(tr)
 			},
 			task:     model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
@@ -129,7 +217,7 @@ func TestTaskService_GetByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.task)
 			s := newTaskService(store)
-			task, err := s.GetByID(tc.task.ID)
+			task, err := s.GetByID(context.Background(), tc.task.ID)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expTask, task)
@@ -150,8 +238,8 @@ func TestTaskService_Update(t *testing.T) {
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByID(t.ID).Return(t, nil)
-				tr.EXPECT().Update(t).Return(t, nil)
+				tr.EXPECT().GetByID(gomock.Any(), t.ID).Return(t, nil)
+				tr.EXPECT().Update(gomock.Any(), t).Return(t, nil)
 				s.EXPECT().Tasks().Times(2).Return(tr)
 			},
 			task:     model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
@@ -168,7 +256,7 @@ func TestTaskService_Update(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.task)
 			s := newTaskService(store)
-			task, err := s.Update(tc.task)
+			task, err := s.Update(context.Background(), tc.task)
 
 			assert.Equal(t, tc.expTask, task)
 			assert.Equal(t, tc.expError, err)
@@ -187,37 +275,39 @@ func TestTaskService_MoveToColumnByID(t *testing.T) {
 		{
 			name: "task is moved left",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, task model.Task) {
+				expectTx(s)
 				cr := mock_store.NewMockColumnRepo(c)
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByID(task.ID).Return(task, nil)
-				cr.EXPECT().GetByID(task.ColumnID).Return(
+				tr.EXPECT().GetByID(gomock.Any(), task.ID).Return(task, nil)
+				cr.EXPECT().GetByID(gomock.Any(), task.ColumnID).Return(
 					model.Column{ID: task.ColumnID, Name: "Column 1", Index: 2, ProjectID: 1},
 					nil,
 				)
-				cr.EXPECT().GetByIndexAndProjectID(1, 1).Return(
+				cr.EXPECT().GetByIndexAndProjectID(gomock.Any(), 1, 1).Return(
 					model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
 					nil,
 				)
-				tr.EXPECT().GetByColumnID(1).Return(
+				tr.EXPECT().GetByColumnID(gomock.Any(), 1).Return(
 					[]model.Task{{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1}},
 					nil,
 				)
-				tr.EXPECT().GetByColumnID(task.ColumnID).Return(
+				tr.EXPECT().GetByColumnID(gomock.Any(), task.ColumnID).Return(
 					[]model.Task{
 						task, {ID: 3, Name: "Task 3", Index: 2, ColumnID: 2},
 					},
 					nil,
 				)
-				tr.EXPECT().Update(model.Task{ID: 3, Name: "Task 3", Index: 1, ColumnID: 2}).Return(
+				tr.EXPECT().Update(gomock.Any(), model.Task{ID: 3, Name: "Task 3", Index: 1, ColumnID: 2}).Return(
 					model.Task{ID: 3, Name: "Task 3", Index: 1, ColumnID: 2},
 					nil,
 				)
-				tr.EXPECT().Update(model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 1}).Return(
+				tr.EXPECT().Update(gomock.Any(), model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 1}).Return(
 					model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 1},
 					nil,
 				)
 				s.EXPECT().Tasks().Times(5).Return(tr)
+				expectTransition(c, s, model.Transition{TaskID: 2, ProjectID: 1, FromColumnID: 2, ToColumnID: 1, At: testNow})
 				s.EXPECT().Columns().Times(2).Return(cr)
 			},
 			task:     model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: 2},
@@ -227,37 +317,39 @@ func TestTaskService_MoveToColumnByID(t *testing.T) {
 		{
 			name: "task is moved right",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, task model.Task) {
+				expectTx(s)
 				cr := mock_store.NewMockColumnRepo(c)
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByID(task.ID).Return(task, nil)
-				cr.EXPECT().GetByID(task.ColumnID).Return(
+				tr.EXPECT().GetByID(gomock.Any(), task.ID).Return(task, nil)
+				cr.EXPECT().GetByID(gomock.Any(), task.ColumnID).Return(
 					model.Column{ID: task.ColumnID, Name: "Column 1", Index: 1, ProjectID: 1},
 					nil,
 				)
-				cr.EXPECT().GetByIndexAndProjectID(2, 1).Return(
+				cr.EXPECT().GetByIndexAndProjectID(gomock.Any(), 2, 1).Return(
 					model.Column{ID: 2, Name: "Column 2", Index: 2, ProjectID: 1},
 					nil,
 				)
-				tr.EXPECT().GetByColumnID(2).Return(
+				tr.EXPECT().GetByColumnID(gomock.Any(), 2).Return(
 					[]model.Task{{ID: 1, Name: "Task 1", Index: 1, ColumnID: 2}},
 					nil,
 				)
-				tr.EXPECT().GetByColumnID(task.ColumnID).Return(
+				tr.EXPECT().GetByColumnID(gomock.Any(), task.ColumnID).Return(
 					[]model.Task{
 						task, {ID: 3, Name: "Task 3", Index: 2, ColumnID: 1},
 					},
 					nil,
 				)
-				tr.EXPECT().Update(model.Task{ID: 3, Name: "Task 3", Index: 1, ColumnID: 1}).Return(
+				tr.EXPECT().Update(gomock.Any(), model.Task{ID: 3, Name: "Task 3", Index: 1, ColumnID: 1}).Return(
 					model.Task{ID: 3, Name: "Task 3", Index: 1, ColumnID: 1},
 					nil,
 				)
-				tr.EXPECT().Update(model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 2}).Return(
+				tr.EXPECT().Update(gomock.Any(), model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 2}).Return(
 					model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 2},
 					nil,
 				)
 				s.EXPECT().Tasks().Times(5).Return(tr)
+				expectTransition(c, s, model.Transition{TaskID: 2, ProjectID: 1, FromColumnID: 1, ToColumnID: 2, At: testNow})
 				s.EXPECT().Columns().Times(2).Return(cr)
 			},
 			task:     model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: 1},
@@ -274,7 +366,168 @@ func TestTaskService_MoveToColumnByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.task)
 			s := newTaskService(store)
-			err := s.MoveToColumnByID(tc.task.ID, tc.left)
+			s.now = func() time.Time { return testNow }
+			_, err := s.MoveToColumnByID(context.Background(), tc.task.ID, tc.left)
+
+			assert.Equal(t, tc.expError, err)
+		})
+	}
+}
+
+func TestTaskService_MoveToColumn(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*gomock.Controller, *mock_store.MockStore, model.Task)
+		task     model.Task
+		columnID int
+		expError error
+	}{
+		{
+			name: "task is moved to the column",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, task model.Task) {
+				expectTx(s)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				tr.EXPECT().GetByID(gomock.Any(), task.ID).Return(task, nil)
+				cr.EXPECT().GetByID(gomock.Any(), task.ColumnID).Return(
+					model.Column{ID: task.ColumnID, Name: "Column 1", Index: 1, ProjectID: 1},
+					nil,
+				)
+				cr.EXPECT().GetByID(gomock.Any(), 3).Return(
+					model.Column{ID: 3, Name: "Column 3", Index: 3, ProjectID: 1},
+					nil,
+				)
+				tr.EXPECT().GetByColumnID(gomock.Any(), 3).Return(
+					[]model.Task{{ID: 1, Name: "Task 1", Index: 1, ColumnID: 3}},
+					nil,
+				)
+				tr.EXPECT().GetByColumnID(gomock.Any(), task.ColumnID).Return(
+					[]model.Task{
+						task, {ID: 3, Name: "Task 3", Index: 2, ColumnID: 1},
+					},
+					nil,
+				)
+				tr.EXPECT().Update(gomock.Any(), model.Task{ID: 3, Name: "Task 3", Index: 1, ColumnID: 1}).Return(
+					model.Task{ID: 3, Name: "Task 3", Index: 1, ColumnID: 1},
+					nil,
+				)
+				tr.EXPECT().Update(gomock.Any(), model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 3}).Return(
+					model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: 3},
+					nil,
+				)
+				s.EXPECT().Tasks().Times(5).Return(tr)
+				expectTransition(c, s, model.Transition{TaskID: 2, ProjectID: 1, FromColumnID: 1, ToColumnID: 3, At: testNow})
+				s.EXPECT().Columns().Times(2).Return(cr)
+			},
+			task:     model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: 1},
+			columnID: 3,
+			expError: nil,
+		},
+		{
+			name: "task is in the column already",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, task model.Task) {
+				expectTx(s)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				tr.EXPECT().GetByID(gomock.Any(), task.ID).Return(task, nil)
+				s.EXPECT().Tasks().Return(tr)
+			},
+			task:     model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: 1},
+			columnID: 1,
+			expError: nil,
+		},
+		{
+			name: "column is in another project",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, task model.Task) {
+				expectTx(s)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				tr.EXPECT().GetByID(gomock.Any(), task.ID).Return(task, nil)
+				cr.EXPECT().GetByID(gomock.Any(), task.ColumnID).Return(
+					model.Column{ID: task.ColumnID, Name: "Column 1", Index: 1, ProjectID: 1},
+					nil,
+				)
+				cr.EXPECT().GetByID(gomock.Any(), 3).Return(
+					model.Column{ID: 3, Name: "Column 3", Index: 1, ProjectID: 2},
+					nil,
+				)
+				s.EXPECT().Tasks().Return(tr)
+				s.EXPECT().Columns().Times(2).Return(cr)
+			},
+			task:     model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: 1},
+			columnID: 3,
+			expError: ErrInvalidMove,
+		},
+		{
+			name: "column doesn't exist",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, task model.Task) {
+				expectTx(s)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				tr.EXPECT().GetByID(gomock.Any(), task.ID).Return(task, nil)
+				cr.EXPECT().GetByID(gomock.Any(), task.ColumnID).Return(
+					model.Column{ID: task.ColumnID, Name: "Column 1", Index: 1, ProjectID: 1},
+					nil,
+				)
+				cr.EXPECT().GetByID(gomock.Any(), 3).Return(model.Column{}, store.ErrNotFound)
+				s.EXPECT().Tasks().Return(tr)
+				s.EXPECT().Columns().Times(2).Return(cr)
+			},
+			task:     model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: 1},
+			columnID: 3,
+			expError: ErrInvalidMove,
+		},
+		{
+			name: "blocked task isn't moved to the last column",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, task model.Task) {
+				expectTx(s)
+				pr := mock_store.NewMockProjectRepo(c)
+				cr := mock_store.NewMockColumnRepo(c)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				tr.EXPECT().GetByID(gomock.Any(), task.ID).Return(task, nil)
+				cr.EXPECT().GetByID(gomock.Any(), task.ColumnID).Return(
+					model.Column{ID: task.ColumnID, Name: "Column 1", Index: 1, ProjectID: 1},
+					nil,
+				)
+				cr.EXPECT().GetByID(gomock.Any(), 3).Return(
+					model.Column{ID: 3, Name: "Column 3", Index: 2, ProjectID: 1},
+					nil,
+				)
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(
+					model.Project{ID: 1, Name: "Project 1", StrictDependencies: true},
+					nil,
+				)
+				cr.EXPECT().GetByProjectID(gomock.Any(), 1).Return(
+					[]model.Column{
+						{ID: task.ColumnID, Name: "Column 1", Index: 1, ProjectID: 1},
+						{ID: 3, Name: "Column 3", Index: 2, ProjectID: 1},
+					},
+					nil,
+				)
+				s.EXPECT().Tasks().Return(tr)
+				s.EXPECT().Columns().Times(3).Return(cr)
+				s.EXPECT().Projects().Return(pr)
+			},
+			task:     model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: 1, Blocked: true},
+			columnID: 3,
+			expError: ErrTaskIsBlocked,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			store := mock_store.NewMockStore(c)
+			tc.mock(c, store, tc.task)
+			s := newTaskService(store)
+			s.now = func() time.Time { return testNow }
+			_, err := s.MoveToColumn(context.Background(), tc.task.ID, tc.columnID)
 
 			assert.Equal(t, tc.expError, err)
 		})
@@ -292,20 +545,21 @@ func TestTaskService_MoveByID(t *testing.T) {
 		{
 			name: "task is moved up",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
+				expectTx(s)
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByID(t.ID).Return(t, nil)
-				tr.EXPECT().GetByIndexAndColumnID(t.Index-1, t.ColumnID).Return(
+				tr.EXPECT().GetByID(gomock.Any(), t.ID).Return(t, nil)
+				tr.EXPECT().GetByIndexAndColumnID(gomock.Any(), t.Index-1, t.ColumnID).Return(
 					model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: t.ColumnID},
 					nil,
 				)
-				tr.EXPECT().Update(
+				tr.EXPECT().Update(gomock.Any(),
 					model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: t.ColumnID},
 				).Return(
 					model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: t.ColumnID},
 					nil,
 				)
-				tr.EXPECT().Update(
+				tr.EXPECT().Update(gomock.Any(),
 					model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: t.ColumnID},
 				).Return(
 					model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: t.ColumnID},
@@ -320,20 +574,21 @@ func TestTaskService_MoveByID(t *testing.T) {
 		{
 			name: "task is moved down",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
+				expectTx(s)
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByID(t.ID).Return(t, nil)
-				tr.EXPECT().GetByIndexAndColumnID(t.Index+1, t.ColumnID).Return(
+				tr.EXPECT().GetByID(gomock.Any(), t.ID).Return(t, nil)
+				tr.EXPECT().GetByIndexAndColumnID(gomock.Any(), t.Index+1, t.ColumnID).Return(
 					model.Task{ID: 2, Name: "Task 2", Index: 2, ColumnID: t.ColumnID},
 					nil,
 				)
-				tr.EXPECT().Update(
+				tr.EXPECT().Update(gomock.Any(),
 					model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: t.ColumnID},
 				).Return(
 					model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: t.ColumnID},
 					nil,
 				)
-				tr.EXPECT().Update(
+				tr.EXPECT().Update(gomock.Any(),
 					model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: t.ColumnID},
 				).Return(
 					model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: t.ColumnID},
@@ -355,7 +610,7 @@ func TestTaskService_MoveByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.task)
 			s := newTaskService(store)
-			err := s.MoveByID(tc.task.ID, tc.up)
+			err := s.MoveByID(context.Background(), tc.task.ID, tc.up)
 
 			assert.Equal(t, tc.expError, err)
 		})
@@ -372,21 +627,22 @@ func TestTaskService_DeleteByID(t *testing.T) {
 		{
 			name: "task is deleted",
 			mock: func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {
+				expectTx(s)
 				tr := mock_store.NewMockTaskRepo(c)
 
-				tr.EXPECT().GetByID(t.ID).Return(t, nil)
-				tr.EXPECT().GetByColumnID(t.ColumnID).Return(
+				tr.EXPECT().GetByID(gomock.Any(), t.ID).Return(t, nil)
+				tr.EXPECT().GetByColumnID(gomock.Any(), t.ColumnID).Return(
 					[]model.Task{
 						t, {ID: 2, Name: "Task 2", Index: 2, ColumnID: t.ColumnID}},
 					nil,
 				)
-				tr.EXPECT().Update(
+				tr.EXPECT().Update(gomock.Any(),
 					model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: t.ColumnID},
 				).Return(
 					model.Task{ID: 2, Name: "Task 2", Index: 1, ColumnID: t.ColumnID},
 					nil,
 				)
-				tr.EXPECT().DeleteByID(t.ID).Return(nil)
+				tr.EXPECT().DeleteByID(gomock.Any(), t.ID).Return(nil)
 				s.EXPECT().Tasks().Times(4).Return(tr)
 			},
 			task:     model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
@@ -402,7 +658,88 @@ func TestTaskService_DeleteByID(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.task)
 			s := newTaskService(store)
-			err := s.DeleteByID(tc.task.ID)
+			err := s.DeleteByID(context.Background(), tc.task.ID)
+
+			assert.Equal(t, tc.expError, err)
+		})
+	}
+}
+
+func TestTaskService_CreateLink(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*gomock.Controller, *mock_store.MockStore, model.TaskLink)
+		link     model.TaskLink
+		expError error
+	}{
+		{
+			name: "task link is created",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, l model.TaskLink) {
+				expectSerializableTx(s)
+				tr := mock_store.NewMockTaskRepo(c)
+				lr := mock_store.NewMockTaskLinkRepo(c)
+
+				tr.EXPECT().GetByID(gomock.Any(), l.BlockedID).Return(model.Task{ID: l.BlockedID}, nil)
+				tr.EXPECT().GetByID(gomock.Any(), l.BlockerID).Return(model.Task{ID: l.BlockerID}, nil)
+				lr.EXPECT().GetByTaskID(gomock.Any(), 2).Return([]model.TaskLink{{BlockerID: 2, BlockedID: 3}}, nil)
+				lr.EXPECT().GetByTaskID(gomock.Any(), 3).Return([]model.TaskLink{{BlockerID: 2, BlockedID: 3}}, nil)
+				lr.EXPECT().Create(gomock.Any(), l).Return(l, nil)
+				s.EXPECT().Tasks().Times(2).Return(tr)
+				s.EXPECT().TaskLinks().Times(3).Return(lr)
+			},
+			link:     model.TaskLink{BlockerID: 1, BlockedID: 2},
+			expError: nil,
+		},
+		{
+			name: "task link makes a cycle",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, l model.TaskLink) {
+				expectSerializableTx(s)
+				tr := mock_store.NewMockTaskRepo(c)
+				lr := mock_store.NewMockTaskLinkRepo(c)
+
+				tr.EXPECT().GetByID(gomock.Any(), l.BlockedID).Return(model.Task{ID: l.BlockedID}, nil)
+				tr.EXPECT().GetByID(gomock.Any(), l.BlockerID).Return(model.Task{ID: l.BlockerID}, nil)
+				lr.EXPECT().GetByTaskID(gomock.Any(), 2).Return([]model.TaskLink{{BlockerID: 2, BlockedID: 3}}, nil)
+				lr.EXPECT().GetByTaskID(gomock.Any(), 3).Return(
+					[]model.TaskLink{{BlockerID: 2, BlockedID: 3}, {BlockerID: 3, BlockedID: 1}},
+					nil,
+				)
+				s.EXPECT().Tasks().Times(2).Return(tr)
+				s.EXPECT().TaskLinks().Times(2).Return(lr)
+			},
+			link:     model.TaskLink{BlockerID: 1, BlockedID: 2},
+			expError: ErrDependencyCycle,
+		},
+		{
+			name:     "task blocks itself",
+			mock:     func(*gomock.Controller, *mock_store.MockStore, model.TaskLink) {},
+			link:     model.TaskLink{BlockerID: 1, BlockedID: 1},
+			expError: ErrDependencyCycle,
+		},
+		{
+			name: "blocker task doesn't exist",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore, l model.TaskLink) {
+				expectSerializableTx(s)
+				tr := mock_store.NewMockTaskRepo(c)
+
+				tr.EXPECT().GetByID(gomock.Any(), l.BlockedID).Return(model.Task{ID: l.BlockedID}, nil)
+				tr.EXPECT().GetByID(gomock.Any(), l.BlockerID).Return(model.Task{}, store.ErrNotFound)
+				s.EXPECT().Tasks().Times(2).Return(tr)
+			},
+			link:     model.TaskLink{BlockerID: 1, BlockedID: 2},
+			expError: store.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			store := mock_store.NewMockStore(c)
+			tc.mock(c, store, tc.link)
+			s := newTaskService(store)
+			_, err := s.CreateLink(context.Background(), tc.link)
 
 			assert.Equal(t, tc.expError, err)
 		})
@@ -426,19 +763,19 @@ func TestTaskService_Validate(t *testing.T) {
 			name:     "task doesn't pass validation because of empty name",
 			mock:     func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {},
 			task:     model.Task{},
-			expError: ErrNameIsRequired,
+			expError: ValidationErrors{newRequiredError("name", ErrNameIsRequired)},
 		},
 		{
 			name:     "task doesn't pass validation because of too long name",
 			mock:     func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {},
 			task:     model.Task{Name: fixedLengthString(501)},
-			expError: ErrNameIsTooLong,
+			expError: ValidationErrors{newTooLongError("name", 500, ErrNameIsTooLong)},
 		},
 		{
 			name:     "task doesn't pass validation because of too long description",
 			mock:     func(c *gomock.Controller, s *mock_store.MockStore, t model.Task) {},
 			task:     model.Task{Name: "Task 1", Description: fixedLengthString(5001)},
-			expError: ErrDescriptionIsTooLong,
+			expError: ValidationErrors{newTooLongError("description", 5000, ErrDescriptionIsTooLong)},
 		},
 	}
 
@@ -450,9 +787,111 @@ func TestTaskService_Validate(t *testing.T) {
 			store := mock_store.NewMockStore(c)
 			tc.mock(c, store, tc.task)
 			s := newTaskService(store)
-			err := s.Validate(tc.task)
+			err := s.Validate(context.Background(), tc.task)
 
 			assert.Equal(t, tc.expError, err)
 		})
 	}
 }
+
+func TestTaskService_Patch(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*gomock.Controller, *mock_store.MockStore)
+		patch    model.TaskPatch
+		expTask  model.Task
+		expError error
+	}{
+		{
+			name: "only present fields are patched",
+			mock: func(c *gomock.Controller, s *mock_store.MockStore) {
+				tr := mock_store.NewMockTaskRepo(c)
+				task := model.Task{ID: 1, Name: "Task 1", Description: "Description", Index: 1, ColumnID: 1}
+				updated := model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1}
+
+				tr.EXPECT().GetByID(gomock.Any(), 1).Return(task, nil)
+				tr.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+				s.EXPECT().Tasks().Times(2).Return(tr)
+			},
+			patch:    model.TaskPatch{Description: strPtr("")},
+			expTask:  model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
+			expError: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			store := mock_store.NewMockStore(c)
+			tc.mock(c, store)
+			s := newTaskService(store)
+			task, err := s.Patch(context.Background(), 1, tc.patch)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expTask, task)
+		})
+	}
+}
+
+func TestTrackProgress(t *testing.T) {
+	earlier := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := earlier.Add(time.Hour)
+
+	testcases := []struct {
+		name           string
+		task           model.Task
+		columnType     model.ColumnType
+		expStartedAt   *time.Time
+		expCompletedAt *time.Time
+	}{
+		{
+			name:       "task entering backlog isn't started",
+			task:       model.Task{StartedAt: &earlier, CompletedAt: &earlier},
+			columnType: model.ColumnTypeBacklog,
+		},
+		{
+			name:         "task entering active column is started",
+			task:         model.Task{},
+			columnType:   model.ColumnTypeActive,
+			expStartedAt: &now,
+		},
+		{
+			name:         "reopened task keeps its start time",
+			task:         model.Task{StartedAt: &earlier, CompletedAt: &earlier},
+			columnType:   model.ColumnTypeActive,
+			expStartedAt: &earlier,
+		},
+		{
+			name:           "task entering done column is completed",
+			task:           model.Task{StartedAt: &earlier},
+			columnType:     model.ColumnTypeDone,
+			expStartedAt:   &earlier,
+			expCompletedAt: &now,
+		},
+		{
+			name:           "task skipping active column is started when completed",
+			task:           model.Task{},
+			columnType:     model.ColumnTypeDone,
+			expStartedAt:   &now,
+			expCompletedAt: &now,
+		},
+		{
+			name:           "completed task keeps its completion time",
+			task:           model.Task{StartedAt: &earlier, CompletedAt: &earlier},
+			columnType:     model.ColumnTypeDone,
+			expStartedAt:   &earlier,
+			expCompletedAt: &earlier,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			trackProgress(&tc.task, model.Column{Type: tc.columnType}, now)
+
+			assert.Equal(t, tc.expStartedAt, tc.task.StartedAt)
+			assert.Equal(t, tc.expCompletedAt, tc.task.CompletedAt)
+		})
+	}
+}
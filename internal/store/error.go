@@ -5,6 +5,10 @@ import "errors"
 var (
 	// ErrNotFound is thrown when specified record was not found in a store.
 	ErrNotFound = errors.New("not found")
+	// ErrInvalidReference is thrown when a record references a record that doesn't exist.
+	ErrInvalidReference = errors.New("referenced record doesn't exist")
+	// ErrConflict is thrown when a record violates uniqueness of names or indices.
+	ErrConflict = errors.New("conflicts with existing record")
 	// ErrDbQuery is thrown when store cannot perform a query.
 	ErrDbQuery = errors.New("couldn't perform query")
 )
@@ -1,7 +1,8 @@
 package inmem
 
 import (
-	"sync"
+	"context"
+	"sort"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
@@ -10,52 +11,70 @@ import (
 // columnRepo is the column repository for in memory store.
 type columnRepo struct {
 	db *inMemoryDb
-	m  sync.RWMutex
 }
 
 // newColumnRepo creates and returns a new columnRepo instance.
 func newColumnRepo(db *inMemoryDb) *columnRepo { return &columnRepo{db: db} }
 
 // GetByProjectID returns all columns with specific project ID.
-func (r *columnRepo) GetByProjectID(id int) ([]model.Column, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *columnRepo) GetByProjectID(ctx context.Context, id int) ([]model.Column, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
 
-	if _, ok := r.db.projects[id]; !ok {
+	if _, ok := r.db.project(id); !ok {
 		return nil, store.ErrNotFound
 	}
 
 	cs := []model.Column{}
-	for _, c := range r.db.columns {
+	r.db.eachColumn(func(c model.Column) {
 		if c.ProjectID == id {
 			cs = append(cs, c)
 		}
-	}
+	})
+	sort.Slice(cs, func(i, j int) bool { return cs[i].ID < cs[j].ID })
 
 	return cs, nil
 }
 
 // Create creates and returns a new column.
-func (r *columnRepo) Create(c model.Column) (model.Column, error) {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *columnRepo) Create(ctx context.Context, c model.Column) (model.Column, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Column{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
 
-	if _, ok := r.db.projects[c.ProjectID]; !ok {
-		return model.Column{}, store.ErrDbQuery
+	if _, ok := r.db.project(c.ProjectID); !ok {
+		return model.Column{}, store.ErrInvalidReference
+	}
+	if r.db.columnConflicts(c) {
+		return model.Column{}, store.ErrConflict
 	}
 
-	c.ID = len(r.db.columns) + 1
-	r.db.columns[c.ID] = c
+	c.ID = r.db.seq.Columns + 1
+	rec := record{Op: putOp, Entity: columnEntity, ID: c.ID, Column: &c}
+	if err := r.db.commit(rec); err != nil {
+		return model.Column{}, err
+	}
 
 	return c, nil
 }
 
 // GetByID returns the column with specific ID.
-func (r *columnRepo) GetByID(id int) (model.Column, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *columnRepo) GetByID(ctx context.Context, id int) (model.Column, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Column{}, err
+	}
 
-	if c, ok := r.db.columns[id]; ok {
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	if c, ok := r.db.column(id); ok {
 		return c, nil
 	}
 
@@ -63,56 +82,66 @@ func (r *columnRepo) GetByID(id int) (model.Column, error) {
 }
 
 // GetByIndexAndProjectID returns the column with specific index and project ID.
-func (r *columnRepo) GetByIndexAndProjectID(index, id int) (model.Column, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *columnRepo) GetByIndexAndProjectID(ctx context.Context, index, id int) (model.Column, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Column{}, err
+	}
 
-	for _, c := range r.db.columns {
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	column, found := model.Column{}, false
+	r.db.eachColumn(func(c model.Column) {
 		if c.Index == index && c.ProjectID == id {
-			return c, nil
+			column, found = c, true
 		}
+	})
+	if found {
+		return column, nil
 	}
 
 	return model.Column{}, store.ErrNotFound
 }
 
 // Update updates the column.
-func (r *columnRepo) Update(c model.Column) (model.Column, error) {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *columnRepo) Update(ctx context.Context, c model.Column) (model.Column, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Column{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
 
-	if _, ok := r.db.columns[c.ID]; !ok {
+	if _, ok := r.db.column(c.ID); !ok {
 		return model.Column{}, store.ErrNotFound
 	}
-	if _, ok := r.db.projects[c.ProjectID]; !ok {
-		return model.Column{}, store.ErrDbQuery
+	if _, ok := r.db.project(c.ProjectID); !ok {
+		return model.Column{}, store.ErrInvalidReference
+	}
+	if r.db.columnConflicts(c) {
+		return model.Column{}, store.ErrConflict
 	}
 
-	r.db.columns[c.ID] = c
+	rec := record{Op: putOp, Entity: columnEntity, ID: c.ID, Column: &c}
+	if err := r.db.commit(rec); err != nil {
+		return model.Column{}, err
+	}
 
 	return c, nil
 }
 
 // DeleteByID deletes the column with specific ID.
-func (r *columnRepo) DeleteByID(id int) error {
-	r.m.Lock()
-	defer r.m.Unlock()
-
-	if _, ok := r.db.columns[id]; !ok {
-		return store.ErrNotFound
+func (r *columnRepo) DeleteByID(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	for taskID, task := range r.db.tasks {
-		if task.ColumnID == id {
-			for commentID, comment := range r.db.comments {
-				if comment.TaskID == taskID {
-					delete(r.db.comments, commentID)
-				}
-			}
-			delete(r.db.tasks, taskID)
-		}
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.column(id); !ok {
+		return store.ErrNotFound
 	}
-	delete(r.db.columns, id)
 
-	return nil
+	return r.db.commit(record{Op: deleteOp, Entity: columnEntity, ID: id})
 }
@@ -1,6 +1,7 @@
 package inmem
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,7 +12,7 @@ import (
 func TestColumnRepo_GetByProjectID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	cs, err := s.Columns().GetByProjectID(1)
+	cs, err := s.Columns().GetByProjectID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(cs))
@@ -20,7 +21,7 @@ func TestColumnRepo_GetByProjectID(t *testing.T) {
 func TestColumnRepo_Create(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	c, err := s.Columns().Create(model.Column{Name: "Column 4", Index: 2, ProjectID: 2})
+	c, err := s.Columns().Create(context.Background(), model.Column{Name: "Column 4", Index: 2, ProjectID: 2})
 
 	assert.NoError(t, err)
 	assert.Equal(t, model.Column{ID: 4, Name: "Column 4", Index: 2, ProjectID: 2}, c)
@@ -29,7 +30,7 @@ func TestColumnRepo_Create(t *testing.T) {
 func TestColumnRepo_GetByID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	c, err := s.Columns().GetByID(1)
+	c, err := s.Columns().GetByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, c.ID)
@@ -38,7 +39,7 @@ func TestColumnRepo_GetByID(t *testing.T) {
 func TestColumnRepo_GetByIndexAndProjectID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	c, err := s.Columns().GetByIndexAndProjectID(1, 1)
+	c, err := s.Columns().GetByIndexAndProjectID(context.Background(), 1, 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, c.ID)
@@ -48,7 +49,7 @@ func TestColumnRepo_Update(t *testing.T) {
 	s := TestStoreWithFixtures()
 	column := model.Column{ID: 1, Name: "Updated column 1", Index: 1, ProjectID: 1}
 
-	c, err := s.Columns().Update(column)
+	c, err := s.Columns().Update(context.Background(), column)
 
 	assert.NoError(t, err)
 	assert.Equal(t, column, c)
@@ -57,7 +58,7 @@ func TestColumnRepo_Update(t *testing.T) {
 func TestColumnRepo_DeleteByID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	err := s.Columns().DeleteByID(1)
+	err := s.Columns().DeleteByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(s.db.columns))
@@ -0,0 +1,73 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// columnSnapshotRepo is the column snapshot repository for in memory store.
+type columnSnapshotRepo struct {
+	db *inMemoryDb
+}
+
+// newColumnSnapshotRepo creates and returns a new columnSnapshotRepo instance.
+func newColumnSnapshotRepo(db *inMemoryDb) *columnSnapshotRepo { return &columnSnapshotRepo{db: db} }
+
+// Save creates the snapshot or replaces the one of the same column and day.
+func (r *columnSnapshotRepo) Save(ctx context.Context, s model.ColumnSnapshot) (model.ColumnSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return model.ColumnSnapshot{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.project(s.ProjectID); !ok {
+		return model.ColumnSnapshot{}, store.ErrInvalidReference
+	}
+
+	s.ID = r.db.seq.ColumnSnapshots + 1
+	r.db.eachColumnSnapshot(func(snapshot model.ColumnSnapshot) {
+		if snapshot.ColumnID == s.ColumnID && snapshot.Day.Equal(s.Day) {
+			s.ID = snapshot.ID
+		}
+	})
+	rec := record{Op: putOp, Entity: columnSnapshotEntity, ID: s.ID, ColumnSnapshot: &s}
+	if err := r.db.commit(rec); err != nil {
+		return model.ColumnSnapshot{}, err
+	}
+
+	return s, nil
+}
+
+// GetByProjectID returns snapshots of the project with specific ID from day from to day
+// to inclusive ordered by day and column ID.
+func (r *columnSnapshotRepo) GetByProjectID(
+	ctx context.Context, id int, from, to time.Time,
+) ([]model.ColumnSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	ss := []model.ColumnSnapshot{}
+	r.db.eachColumnSnapshot(func(s model.ColumnSnapshot) {
+		if s.ProjectID == id && !s.Day.Before(from) && !s.Day.After(to) {
+			ss = append(ss, s)
+		}
+	})
+	sort.Slice(ss, func(i, j int) bool {
+		if !ss[i].Day.Equal(ss[j].Day) {
+			return ss[i].Day.Before(ss[j].Day)
+		}
+		return ss[i].ColumnID < ss[j].ColumnID
+	})
+
+	return ss, nil
+}
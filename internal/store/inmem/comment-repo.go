@@ -1,7 +1,8 @@
 package inmem
 
 import (
-	"sync"
+	"context"
+	"sort"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
@@ -10,52 +11,85 @@ import (
 // commentRepo is the comment repository for in memory store.
 type commentRepo struct {
 	db *inMemoryDb
-	m  sync.RWMutex
 }
 
 // newCommentRepo creates and returns a new commentRepo instance.
 func newCommentRepo(db *inMemoryDb) *commentRepo { return &commentRepo{db: db} }
 
+// GetAll returns all comments.
+func (r *commentRepo) GetAll(ctx context.Context) ([]model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	cs := []model.Comment{}
+	r.db.eachComment(func(c model.Comment) {
+		cs = append(cs, c)
+	})
+	sort.Slice(cs, func(i, j int) bool { return cs[i].ID < cs[j].ID })
+
+	return cs, nil
+}
+
 // GetByTaskID returns all comments with specific task ID.
-func (r *commentRepo) GetByTaskID(id int) ([]model.Comment, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *commentRepo) GetByTaskID(ctx context.Context, id int) ([]model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	if _, ok := r.db.tasks[id]; !ok {
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	if _, ok := r.db.task(id); !ok {
 		return nil, store.ErrNotFound
 	}
 
 	cs := []model.Comment{}
-	for _, c := range r.db.comments {
+	r.db.eachComment(func(c model.Comment) {
 		if c.TaskID == id {
 			cs = append(cs, c)
 		}
-	}
+	})
+	sort.Slice(cs, func(i, j int) bool { return cs[i].ID < cs[j].ID })
 
 	return cs, nil
 }
 
 // Create creates and returns a new comment.
-func (r *commentRepo) Create(c model.Comment) (model.Comment, error) {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *commentRepo) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Comment{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
 
-	if _, ok := r.db.tasks[c.TaskID]; !ok {
-		return model.Comment{}, store.ErrDbQuery
+	if _, ok := r.db.task(c.TaskID); !ok {
+		return model.Comment{}, store.ErrInvalidReference
 	}
 
-	c.ID = len(r.db.comments) + 1
-	r.db.comments[c.ID] = c
+	c.ID = r.db.seq.Comments + 1
+	rec := record{Op: putOp, Entity: commentEntity, ID: c.ID, Comment: &c}
+	if err := r.db.commit(rec); err != nil {
+		return model.Comment{}, err
+	}
 
 	return c, nil
 }
 
 // GetByID returns the comment with specific ID.
-func (r *commentRepo) GetByID(id int) (model.Comment, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *commentRepo) GetByID(ctx context.Context, id int) (model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Comment{}, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
 
-	if c, ok := r.db.comments[id]; ok {
+	if c, ok := r.db.comment(id); ok {
 		return c, nil
 	}
 
@@ -63,32 +97,41 @@ func (r *commentRepo) GetByID(id int) (model.Comment, error) {
 }
 
 // Update updates the comment.
-func (r *commentRepo) Update(c model.Comment) (model.Comment, error) {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *commentRepo) Update(ctx context.Context, c model.Comment) (model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Comment{}, err
+	}
 
-	if _, ok := r.db.comments[c.ID]; !ok {
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.comment(c.ID); !ok {
 		return model.Comment{}, store.ErrNotFound
 	}
-	if _, ok := r.db.tasks[c.TaskID]; !ok {
-		return model.Comment{}, store.ErrDbQuery
+	if _, ok := r.db.task(c.TaskID); !ok {
+		return model.Comment{}, store.ErrInvalidReference
 	}
 
-	r.db.comments[c.ID] = c
+	rec := record{Op: putOp, Entity: commentEntity, ID: c.ID, Comment: &c}
+	if err := r.db.commit(rec); err != nil {
+		return model.Comment{}, err
+	}
 
 	return c, nil
 }
 
 // DeleteByID deletes the comment with specific ID.
-func (r *commentRepo) DeleteByID(id int) error {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *commentRepo) DeleteByID(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-	if _, ok := r.db.comments[id]; !ok {
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.comment(id); !ok {
 		return store.ErrNotFound
 	}
 
-	delete(r.db.comments, id)
-
-	return nil
+	return r.db.commit(record{Op: deleteOp, Entity: commentEntity, ID: id})
 }
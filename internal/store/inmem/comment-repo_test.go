@@ -1,6 +1,7 @@
 package inmem
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,10 +9,19 @@ import (
 	"github.com/imarrche/tasker/internal/model"
 )
 
+func TestCommentRepo_GetAll(t *testing.T) {
+	s := TestStoreWithFixtures()
+
+	cs, err := s.Comments().GetAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(cs))
+}
+
 func TestCommentRepo_GetByTaskID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	cs, err := s.Comments().GetByTaskID(1)
+	cs, err := s.Comments().GetByTaskID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(cs))
@@ -20,7 +30,7 @@ func TestCommentRepo_GetByTaskID(t *testing.T) {
 func TestCommentRepo_Create(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	c, err := s.Comments().Create(model.Comment{Text: "Comment 4", TaskID: 2})
+	c, err := s.Comments().Create(context.Background(), model.Comment{Text: "Comment 4", TaskID: 2})
 
 	assert.NoError(t, err)
 	assert.Equal(t, model.Comment{ID: 4, Text: "Comment 4", TaskID: 2}, c)
@@ -29,7 +39,7 @@ func TestCommentRepo_Create(t *testing.T) {
 func TestCommentRepo_GetByID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	c, err := s.Comments().GetByID(1)
+	c, err := s.Comments().GetByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, c.ID)
@@ -39,7 +49,7 @@ func TestCommentRepo_Update(t *testing.T) {
 	s := TestStoreWithFixtures()
 	comment := model.Comment{ID: 1, Text: "Updated comment 1", TaskID: 1}
 
-	c, err := s.Comments().Update(comment)
+	c, err := s.Comments().Update(context.Background(), comment)
 
 	assert.NoError(t, err)
 	assert.Equal(t, comment, c)
@@ -48,7 +58,7 @@ func TestCommentRepo_Update(t *testing.T) {
 func TestCommentRepo_DeleteByID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	err := s.Comments().DeleteByID(1)
+	err := s.Comments().DeleteByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(s.db.comments))
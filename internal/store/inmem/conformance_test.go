@@ -0,0 +1,22 @@
+package inmem
+
+import (
+	"testing"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/storetest"
+)
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store { return NewStore() })
+}
+
+func TestStore_ConformancePersistent(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		s := openStore(t, config.InMemory{Dir: tempDir(t), Fsync: config.FsyncNever, SnapshotEvery: 5})
+		t.Cleanup(func() { s.Close() })
+
+		return s
+	})
+}
@@ -0,0 +1,562 @@
+package inmem
+
+import (
+	"sync"
+
+	"github.com/imarrche/tasker/internal/logger"
+	"github.com/imarrche/tasker/internal/model"
+)
+
+// Entities stored in inMemoryDb.
+const (
+	projectEntity = "project"
+	columnEntity  = "column"
+	taskEntity    = "task"
+	commentEntity = "comment"
+
+	transitionEntity     = "transition"
+	columnSnapshotEntity = "column_snapshot"
+	taskLinkEntity       = "task_link"
+
+	idempotencyKeyEntity = "idempotency_key"
+)
+
+// Record operations.
+const (
+	putOp    = "put"
+	deleteOp = "delete"
+	// batchOp applies records of a transaction together.
+	batchOp = "batch"
+)
+
+// record is a single mutation of inMemoryDb, it's what write-ahead log consists of.
+type record struct {
+	Op      string         `json:"op"`
+	Entity  string         `json:"entity"`
+	ID      int            `json:"id"`
+	Project *model.Project `json:"project,omitempty"`
+	Column  *model.Column  `json:"column,omitempty"`
+	Task    *model.Task    `json:"task,omitempty"`
+	Comment *model.Comment `json:"comment,omitempty"`
+
+	Transition     *model.Transition     `json:"transition,omitempty"`
+	ColumnSnapshot *model.ColumnSnapshot `json:"column_snapshot,omitempty"`
+	TaskLink       *model.TaskLink       `json:"task_link,omitempty"`
+
+	// Key identifies idempotency keys which have string IDs.
+	Key            string                `json:"key,omitempty"`
+	IdempotencyKey *model.IdempotencyKey `json:"idempotency_key,omitempty"`
+
+	// Batch are the records of a transaction, they are written to the log as a single
+	// record so a crash can't leave a part of the transaction.
+	Batch []record `json:"batch,omitempty"`
+}
+
+// sequences are the last IDs allocated for each entity. IDs are never reused even
+// after the entity is deleted.
+type sequences struct {
+	Projects int `json:"projects"`
+	Columns  int `json:"columns"`
+	Tasks    int `json:"tasks"`
+	Comments int `json:"comments"`
+
+	Transitions     int `json:"transitions"`
+	ColumnSnapshots int `json:"column_snapshots"`
+}
+
+// inMemoryDb is the data of in memory store. All repositories share m, reads hold it
+// for reading and mutations hold it for writing from checking constraints to applying
+// the change, so each repository method is atomic across the whole store.
+type inMemoryDb struct {
+	projects map[int]model.Project
+	columns  map[int]model.Column
+	tasks    map[int]model.Task
+	comments map[int]model.Comment
+	seq      sequences
+
+	transitions     map[int]model.Transition
+	columnSnapshots map[int]model.ColumnSnapshot
+	taskLinks       map[model.TaskLink]bool
+
+	idempotencyKeys map[string]model.IdempotencyKey
+
+	m   sync.RWMutex
+	log *wal
+	l   *logger.Logger
+
+	// parent is set for the overlay of the database used by a transaction. The overlay
+	// maps keep only records put by the transaction and deleted marks records of parent
+	// deleted by it, reads see parent's records through them. Records of the transaction
+	// are collected in pending and committed to parent at the end of transaction.
+	parent  *inMemoryDb
+	deleted map[deletedKey]bool
+	pending []record
+}
+
+// deletedKey identifies a record of any entity deleted by a transaction.
+type deletedKey struct {
+	entity string
+	id     int
+	key    string
+	link   model.TaskLink
+}
+
+func newInMemoryDb() *inMemoryDb {
+	return &inMemoryDb{
+		projects: map[int]model.Project{},
+		columns:  map[int]model.Column{},
+		tasks:    map[int]model.Task{},
+		comments: map[int]model.Comment{},
+
+		transitions:     map[int]model.Transition{},
+		columnSnapshots: map[int]model.ColumnSnapshot{},
+		taskLinks:       map[model.TaskLink]bool{},
+		idempotencyKeys: map[string]model.IdempotencyKey{},
+	}
+}
+
+// overlay returns the overlay of the database for a transaction, the caller must hold
+// db.m for writing until the transaction ends.
+func (db *inMemoryDb) overlay() *inMemoryDb {
+	o := newInMemoryDb()
+	o.seq = db.seq
+	o.parent = db
+	o.deleted = map[deletedKey]bool{}
+
+	return o
+}
+
+// commit writes the record to write-ahead log if it's enabled and applies it,
+// the caller must hold db.m for writing.
+func (db *inMemoryDb) commit(rec record) error {
+	if db.parent != nil {
+		db.pending = append(db.pending, rec)
+		db.apply(rec)
+		return nil
+	}
+	if db.log == nil {
+		db.apply(rec)
+		return nil
+	}
+
+	if err := db.log.append(rec); err != nil {
+		return err
+	}
+	db.apply(rec)
+	// The record is already committed, a failed snapshot is retried on the next commit.
+	if db.log.needsSnapshot() {
+		if err := db.snapshot(); err != nil {
+			db.l.WithField("error", err).Error("couldn't snapshot in memory store")
+		}
+	}
+
+	return nil
+}
+
+// commitAll commits records of a transaction as a single record, the caller must hold
+// db.m for writing.
+func (db *inMemoryDb) commitAll(recs []record) error {
+	switch len(recs) {
+	case 0:
+		return nil
+	case 1:
+		return db.commit(recs[0])
+	default:
+		return db.commit(record{Op: batchOp, Batch: recs})
+	}
+}
+
+// apply applies the record to the database. Applying a record more than once has
+// the same effect as applying it once, it's required for replaying the log.
+func (db *inMemoryDb) apply(rec record) {
+	switch {
+	case rec.Op == batchOp:
+		for _, r := range rec.Batch {
+			db.apply(r)
+		}
+	case rec.Op == putOp && rec.Project != nil:
+		db.projects[rec.ID] = *rec.Project
+		delete(db.deleted, deletedKey{entity: projectEntity, id: rec.ID})
+		db.seq.Projects = max(db.seq.Projects, rec.ID)
+	case rec.Op == putOp && rec.Column != nil:
+		db.columns[rec.ID] = *rec.Column
+		delete(db.deleted, deletedKey{entity: columnEntity, id: rec.ID})
+		db.seq.Columns = max(db.seq.Columns, rec.ID)
+	case rec.Op == putOp && rec.Task != nil:
+		db.tasks[rec.ID] = *rec.Task
+		delete(db.deleted, deletedKey{entity: taskEntity, id: rec.ID})
+		db.seq.Tasks = max(db.seq.Tasks, rec.ID)
+	case rec.Op == putOp && rec.Comment != nil:
+		db.comments[rec.ID] = *rec.Comment
+		delete(db.deleted, deletedKey{entity: commentEntity, id: rec.ID})
+		db.seq.Comments = max(db.seq.Comments, rec.ID)
+	case rec.Op == putOp && rec.Transition != nil:
+		db.transitions[rec.ID] = *rec.Transition
+		delete(db.deleted, deletedKey{entity: transitionEntity, id: rec.ID})
+		db.seq.Transitions = max(db.seq.Transitions, rec.ID)
+	case rec.Op == putOp && rec.ColumnSnapshot != nil:
+		db.columnSnapshots[rec.ID] = *rec.ColumnSnapshot
+		delete(db.deleted, deletedKey{entity: columnSnapshotEntity, id: rec.ID})
+		db.seq.ColumnSnapshots = max(db.seq.ColumnSnapshots, rec.ID)
+	case rec.Op == putOp && rec.TaskLink != nil:
+		db.taskLinks[*rec.TaskLink] = true
+		delete(db.deleted, deletedKey{entity: taskLinkEntity, link: *rec.TaskLink})
+	case rec.Op == putOp && rec.IdempotencyKey != nil:
+		db.idempotencyKeys[rec.Key] = *rec.IdempotencyKey
+		delete(db.deleted, deletedKey{entity: idempotencyKeyEntity, key: rec.Key})
+	case rec.Op == deleteOp && rec.Entity == projectEntity:
+		db.deleteProject(rec.ID)
+	case rec.Op == deleteOp && rec.Entity == columnEntity:
+		db.deleteColumn(rec.ID)
+	case rec.Op == deleteOp && rec.Entity == taskEntity:
+		db.deleteTask(rec.ID)
+	case rec.Op == deleteOp && rec.Entity == commentEntity:
+		delete(db.comments, rec.ID)
+		db.markDeleted(deletedKey{entity: commentEntity, id: rec.ID})
+	case rec.Op == deleteOp && rec.Entity == taskLinkEntity && rec.TaskLink != nil:
+		delete(db.taskLinks, *rec.TaskLink)
+		db.markDeleted(deletedKey{entity: taskLinkEntity, link: *rec.TaskLink})
+	case rec.Op == deleteOp && rec.Entity == idempotencyKeyEntity:
+		delete(db.idempotencyKeys, rec.Key)
+		db.markDeleted(deletedKey{entity: idempotencyKeyEntity, key: rec.Key})
+	}
+}
+
+// markDeleted marks the record of parent as deleted by the transaction.
+func (db *inMemoryDb) markDeleted(k deletedKey) {
+	if db.parent != nil {
+		db.deleted[k] = true
+	}
+}
+
+// deleteProject deletes the project with all its columns and column snapshots.
+func (db *inMemoryDb) deleteProject(id int) {
+	db.eachColumn(func(c model.Column) {
+		if c.ProjectID == id {
+			db.deleteColumn(c.ID)
+		}
+	})
+	db.eachColumnSnapshot(func(s model.ColumnSnapshot) {
+		if s.ProjectID == id {
+			delete(db.columnSnapshots, s.ID)
+			db.markDeleted(deletedKey{entity: columnSnapshotEntity, id: s.ID})
+		}
+	})
+	delete(db.projects, id)
+	db.markDeleted(deletedKey{entity: projectEntity, id: id})
+}
+
+// deleteColumn deletes the column with all its tasks.
+func (db *inMemoryDb) deleteColumn(id int) {
+	db.eachTask(func(t model.Task) {
+		if t.ColumnID == id {
+			db.deleteTask(t.ID)
+		}
+	})
+	delete(db.columns, id)
+	db.markDeleted(deletedKey{entity: columnEntity, id: id})
+}
+
+// deleteTask deletes the task with all its comments, transitions and links.
+func (db *inMemoryDb) deleteTask(id int) {
+	db.eachComment(func(c model.Comment) {
+		if c.TaskID == id {
+			delete(db.comments, c.ID)
+			db.markDeleted(deletedKey{entity: commentEntity, id: c.ID})
+		}
+	})
+	db.eachTransition(func(t model.Transition) {
+		if t.TaskID == id {
+			delete(db.transitions, t.ID)
+			db.markDeleted(deletedKey{entity: transitionEntity, id: t.ID})
+		}
+	})
+	db.eachTaskLink(func(l model.TaskLink) {
+		if l.BlockerID == id || l.BlockedID == id {
+			delete(db.taskLinks, l)
+			db.markDeleted(deletedKey{entity: taskLinkEntity, link: l})
+		}
+	})
+	delete(db.tasks, id)
+	db.markDeleted(deletedKey{entity: taskEntity, id: id})
+}
+
+// withBlocked returns the task with Blocked set if any task blocking it isn't completed.
+func (db *inMemoryDb) withBlocked(t model.Task) model.Task {
+	t.Blocked = false
+	db.eachTaskLink(func(l model.TaskLink) {
+		if blocker, _ := db.task(l.BlockerID); l.BlockedID == t.ID && blocker.CompletedAt == nil {
+			t.Blocked = true
+		}
+	})
+
+	return t
+}
+
+// columnConflicts checks whether another column of the project has the same name or,
+// unless checks are deferred to the end of transaction, the same index.
+func (db *inMemoryDb) columnConflicts(c model.Column) bool {
+	conflicts := false
+	db.eachColumn(func(column model.Column) {
+		if column.ID == c.ID || column.ProjectID != c.ProjectID {
+			return
+		}
+		if column.Name == c.Name || (db.parent == nil && column.Index == c.Index) {
+			conflicts = true
+		}
+	})
+
+	return conflicts
+}
+
+// taskConflicts checks whether another task of the column has the same index unless
+// checks are deferred to the end of transaction.
+func (db *inMemoryDb) taskConflicts(t model.Task) bool {
+	if db.parent != nil {
+		return false
+	}
+	conflicts := false
+	db.eachTask(func(task model.Task) {
+		if task.ID != t.ID && task.ColumnID == t.ColumnID && task.Index == t.Index {
+			conflicts = true
+		}
+	})
+
+	return conflicts
+}
+
+// duplicateIndices checks whether any columns of a project or tasks of a column share
+// an index.
+func (db *inMemoryDb) duplicateIndices() bool {
+	type key struct{ parent, index int }
+
+	duplicate := false
+	columns := map[key]bool{}
+	db.eachColumn(func(c model.Column) {
+		k := key{c.ProjectID, c.Index}
+		duplicate = duplicate || columns[k]
+		columns[k] = true
+	})
+	tasks := map[key]bool{}
+	db.eachTask(func(t model.Task) {
+		k := key{t.ColumnID, t.Index}
+		duplicate = duplicate || tasks[k]
+		tasks[k] = true
+	})
+
+	return duplicate
+}
+
+// project returns the project with specific ID.
+func (db *inMemoryDb) project(id int) (model.Project, bool) {
+	if p, ok := db.projects[id]; ok || db.parent == nil || db.deleted[deletedKey{entity: projectEntity, id: id}] {
+		return p, ok
+	}
+
+	return db.parent.project(id)
+}
+
+// column returns the column with specific ID.
+func (db *inMemoryDb) column(id int) (model.Column, bool) {
+	if c, ok := db.columns[id]; ok || db.parent == nil || db.deleted[deletedKey{entity: columnEntity, id: id}] {
+		return c, ok
+	}
+
+	return db.parent.column(id)
+}
+
+// task returns the task with specific ID.
+func (db *inMemoryDb) task(id int) (model.Task, bool) {
+	if t, ok := db.tasks[id]; ok || db.parent == nil || db.deleted[deletedKey{entity: taskEntity, id: id}] {
+		return t, ok
+	}
+
+	return db.parent.task(id)
+}
+
+// comment returns the comment with specific ID.
+func (db *inMemoryDb) comment(id int) (model.Comment, bool) {
+	if c, ok := db.comments[id]; ok || db.parent == nil || db.deleted[deletedKey{entity: commentEntity, id: id}] {
+		return c, ok
+	}
+
+	return db.parent.comment(id)
+}
+
+// hasTaskLink checks whether the task link exists.
+func (db *inMemoryDb) hasTaskLink(l model.TaskLink) bool {
+	if db.taskLinks[l] || db.parent == nil || db.deleted[deletedKey{entity: taskLinkEntity, link: l}] {
+		return db.taskLinks[l]
+	}
+
+	return db.parent.hasTaskLink(l)
+}
+
+// idempotencyKey returns the idempotency key.
+func (db *inMemoryDb) idempotencyKey(key string) (model.IdempotencyKey, bool) {
+	k, ok := db.idempotencyKeys[key]
+	if ok || db.parent == nil || db.deleted[deletedKey{entity: idempotencyKeyEntity, key: key}] {
+		return k, ok
+	}
+
+	return db.parent.idempotencyKey(key)
+}
+
+// visible checks whether the record of parent isn't replaced or deleted by the
+// transaction.
+func (db *inMemoryDb) visible(k deletedKey, replaced bool) bool {
+	return !replaced && !db.deleted[k]
+}
+
+// eachProject calls fn for every project.
+func (db *inMemoryDb) eachProject(fn func(model.Project)) {
+	for _, p := range db.projects {
+		fn(p)
+	}
+	if db.parent == nil {
+		return
+	}
+	db.parent.eachProject(func(p model.Project) {
+		if _, ok := db.projects[p.ID]; db.visible(deletedKey{entity: projectEntity, id: p.ID}, ok) {
+			fn(p)
+		}
+	})
+}
+
+// eachColumn calls fn for every column.
+func (db *inMemoryDb) eachColumn(fn func(model.Column)) {
+	for _, c := range db.columns {
+		fn(c)
+	}
+	if db.parent == nil {
+		return
+	}
+	db.parent.eachColumn(func(c model.Column) {
+		if _, ok := db.columns[c.ID]; db.visible(deletedKey{entity: columnEntity, id: c.ID}, ok) {
+			fn(c)
+		}
+	})
+}
+
+// eachTask calls fn for every task.
+func (db *inMemoryDb) eachTask(fn func(model.Task)) {
+	for _, t := range db.tasks {
+		fn(t)
+	}
+	if db.parent == nil {
+		return
+	}
+	db.parent.eachTask(func(t model.Task) {
+		if _, ok := db.tasks[t.ID]; db.visible(deletedKey{entity: taskEntity, id: t.ID}, ok) {
+			fn(t)
+		}
+	})
+}
+
+// eachComment calls fn for every comment.
+func (db *inMemoryDb) eachComment(fn func(model.Comment)) {
+	for _, c := range db.comments {
+		fn(c)
+	}
+	if db.parent == nil {
+		return
+	}
+	db.parent.eachComment(func(c model.Comment) {
+		if _, ok := db.comments[c.ID]; db.visible(deletedKey{entity: commentEntity, id: c.ID}, ok) {
+			fn(c)
+		}
+	})
+}
+
+// eachTransition calls fn for every transition.
+func (db *inMemoryDb) eachTransition(fn func(model.Transition)) {
+	for _, t := range db.transitions {
+		fn(t)
+	}
+	if db.parent == nil {
+		return
+	}
+	db.parent.eachTransition(func(t model.Transition) {
+		if _, ok := db.transitions[t.ID]; db.visible(deletedKey{entity: transitionEntity, id: t.ID}, ok) {
+			fn(t)
+		}
+	})
+}
+
+// eachColumnSnapshot calls fn for every column snapshot.
+func (db *inMemoryDb) eachColumnSnapshot(fn func(model.ColumnSnapshot)) {
+	for _, s := range db.columnSnapshots {
+		fn(s)
+	}
+	if db.parent == nil {
+		return
+	}
+	db.parent.eachColumnSnapshot(func(s model.ColumnSnapshot) {
+		if _, ok := db.columnSnapshots[s.ID]; db.visible(deletedKey{entity: columnSnapshotEntity, id: s.ID}, ok) {
+			fn(s)
+		}
+	})
+}
+
+// eachTaskLink calls fn for every task link.
+func (db *inMemoryDb) eachTaskLink(fn func(model.TaskLink)) {
+	for l := range db.taskLinks {
+		fn(l)
+	}
+	if db.parent == nil {
+		return
+	}
+	db.parent.eachTaskLink(func(l model.TaskLink) {
+		if db.visible(deletedKey{entity: taskLinkEntity, link: l}, db.taskLinks[l]) {
+			fn(l)
+		}
+	})
+}
+
+// eachIdempotencyKey calls fn for every idempotency key.
+func (db *inMemoryDb) eachIdempotencyKey(fn func(model.IdempotencyKey)) {
+	for _, k := range db.idempotencyKeys {
+		fn(k)
+	}
+	if db.parent == nil {
+		return
+	}
+	db.parent.eachIdempotencyKey(func(k model.IdempotencyKey) {
+		_, ok := db.idempotencyKeys[k.Key]
+		if db.visible(deletedKey{entity: idempotencyKeyEntity, key: k.Key}, ok) {
+			fn(k)
+		}
+	})
+}
+
+// resetSequences sets sequences to the maximum IDs stored.
+func (db *inMemoryDb) resetSequences() {
+	db.seq = sequences{}
+	for id := range db.projects {
+		db.seq.Projects = max(db.seq.Projects, id)
+	}
+	for id := range db.columns {
+		db.seq.Columns = max(db.seq.Columns, id)
+	}
+	for id := range db.tasks {
+		db.seq.Tasks = max(db.seq.Tasks, id)
+	}
+	for id := range db.comments {
+		db.seq.Comments = max(db.seq.Comments, id)
+	}
+	for id := range db.transitions {
+		db.seq.Transitions = max(db.seq.Transitions, id)
+	}
+	for id := range db.columnSnapshots {
+		db.seq.ColumnSnapshots = max(db.seq.ColumnSnapshots, id)
+	}
+}
+
+// max returns the larger of x and y.
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+
+	return y
+}
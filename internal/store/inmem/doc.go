@@ -1,2 +1,2 @@
-// Package inmem provides in memory store for testing.
+// Package inmem provides in memory store for testing and ephemeral deployments.
 package inmem
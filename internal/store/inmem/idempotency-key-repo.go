@@ -0,0 +1,117 @@
+package inmem
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// idempotencyKeyRepo is the idempotency key repository for in memory store.
+type idempotencyKeyRepo struct {
+	db *inMemoryDb
+}
+
+// newIdempotencyKeyRepo creates and returns a new idempotencyKeyRepo instance.
+func newIdempotencyKeyRepo(db *inMemoryDb) *idempotencyKeyRepo { return &idempotencyKeyRepo{db: db} }
+
+// Create creates and returns a new idempotency key.
+func (r *idempotencyKeyRepo) Create(ctx context.Context, k model.IdempotencyKey) (model.IdempotencyKey, error) {
+	if err := ctx.Err(); err != nil {
+		return model.IdempotencyKey{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.idempotencyKey(k.Key); ok {
+		return model.IdempotencyKey{}, store.ErrConflict
+	}
+
+	rec := record{Op: putOp, Entity: idempotencyKeyEntity, Key: k.Key, IdempotencyKey: &k}
+	if err := r.db.commit(rec); err != nil {
+		return model.IdempotencyKey{}, err
+	}
+
+	return k, nil
+}
+
+// GetByKey returns the idempotency key.
+func (r *idempotencyKeyRepo) GetByKey(ctx context.Context, key string) (model.IdempotencyKey, error) {
+	if err := ctx.Err(); err != nil {
+		return model.IdempotencyKey{}, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	if k, ok := r.db.idempotencyKey(key); ok {
+		return k, nil
+	}
+
+	return model.IdempotencyKey{}, store.ErrNotFound
+}
+
+// Update saves the response of the idempotency key.
+func (r *idempotencyKeyRepo) Update(ctx context.Context, k model.IdempotencyKey) (model.IdempotencyKey, error) {
+	if err := ctx.Err(); err != nil {
+		return model.IdempotencyKey{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if !r.owns(k) {
+		return model.IdempotencyKey{}, store.ErrNotFound
+	}
+
+	rec := record{Op: putOp, Entity: idempotencyKeyEntity, Key: k.Key, IdempotencyKey: &k}
+	if err := r.db.commit(rec); err != nil {
+		return model.IdempotencyKey{}, err
+	}
+
+	return k, nil
+}
+
+// Delete deletes the idempotency key.
+func (r *idempotencyKeyRepo) Delete(ctx context.Context, k model.IdempotencyKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if !r.owns(k) {
+		return store.ErrNotFound
+	}
+
+	return r.db.commit(record{Op: deleteOp, Entity: idempotencyKeyEntity, Key: k.Key})
+}
+
+// owns checks whether the stored key has the fingerprint and creation time of k.
+func (r *idempotencyKeyRepo) owns(k model.IdempotencyKey) bool {
+	stored, ok := r.db.idempotencyKey(k.Key)
+
+	return ok && stored.Fingerprint == k.Fingerprint && stored.CreatedAt.Equal(k.CreatedAt)
+}
+
+// DeleteCreatedBefore deletes idempotency keys created before t.
+func (r *idempotencyKeyRepo) DeleteCreatedBefore(ctx context.Context, t time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	var recs []record
+	r.db.eachIdempotencyKey(func(k model.IdempotencyKey) {
+		if k.CreatedAt.Before(t) {
+			recs = append(recs, record{Op: deleteOp, Entity: idempotencyKeyEntity, Key: k.Key})
+		}
+	})
+
+	return r.db.commitAll(recs)
+}
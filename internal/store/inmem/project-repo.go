@@ -1,7 +1,8 @@
 package inmem
 
 import (
-	"sync"
+	"context"
+	"sort"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
@@ -10,42 +11,87 @@ import (
 // projectRepo is the project repository for in memory store.
 type projectRepo struct {
 	db *inMemoryDb
-	m  sync.RWMutex
 }
 
 // newProjectRepo creates and returns a new projectRepo instance.
 func newProjectRepo(db *inMemoryDb) *projectRepo { return &projectRepo{db: db} }
 
 // GetAll returns all projects.
-func (r *projectRepo) GetAll() ([]model.Project, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *projectRepo) GetAll(ctx context.Context) ([]model.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
 
 	ps := []model.Project{}
-	for _, p := range r.db.projects {
+	r.db.eachProject(func(p model.Project) {
 		ps = append(ps, p)
-	}
+	})
+	sort.Slice(ps, func(i, j int) bool { return ps[i].ID < ps[j].ID })
 
 	return ps, nil
 }
 
+// GetCounts returns the number of columns and tasks of every project.
+func (r *projectRepo) GetCounts(ctx context.Context) ([]model.ProjectCounts, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	counts := map[int]*model.ProjectCounts{}
+	r.db.eachProject(func(p model.Project) {
+		counts[p.ID] = &model.ProjectCounts{ProjectID: p.ID}
+	})
+	r.db.eachColumn(func(c model.Column) {
+		counts[c.ProjectID].Columns++
+	})
+	r.db.eachTask(func(t model.Task) {
+		c, _ := r.db.column(t.ColumnID)
+		counts[c.ProjectID].Tasks++
+	})
+
+	pcs := []model.ProjectCounts{}
+	for _, pc := range counts {
+		pcs = append(pcs, *pc)
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i].ProjectID < pcs[j].ProjectID })
+
+	return pcs, nil
+}
+
 // Create creates and returns a new project.
-func (r *projectRepo) Create(p model.Project) (model.Project, error) {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *projectRepo) Create(ctx context.Context, p model.Project) (model.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Project{}, err
+	}
 
-	p.ID = len(r.db.projects) + 1
-	r.db.projects[p.ID] = p
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	p.ID = r.db.seq.Projects + 1
+	rec := record{Op: putOp, Entity: projectEntity, ID: p.ID, Project: &p}
+	if err := r.db.commit(rec); err != nil {
+		return model.Project{}, err
+	}
 
 	return p, nil
 }
 
 // GetByID returns the project with specific ID.
-func (r *projectRepo) GetByID(id int) (model.Project, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *projectRepo) GetByID(ctx context.Context, id int) (model.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Project{}, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
 
-	if p, ok := r.db.projects[id]; ok {
+	if p, ok := r.db.project(id); ok {
 		return p, nil
 	}
 
@@ -53,44 +99,38 @@ func (r *projectRepo) GetByID(id int) (model.Project, error) {
 }
 
 // Update updates the project.
-func (r *projectRepo) Update(p model.Project) (model.Project, error) {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *projectRepo) Update(ctx context.Context, p model.Project) (model.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Project{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
 
-	if _, ok := r.db.projects[p.ID]; !ok {
+	if _, ok := r.db.project(p.ID); !ok {
 		return model.Project{}, store.ErrNotFound
 	}
 
-	r.db.projects[p.ID] = p
+	rec := record{Op: putOp, Entity: projectEntity, ID: p.ID, Project: &p}
+	if err := r.db.commit(rec); err != nil {
+		return model.Project{}, err
+	}
 
 	return p, nil
 }
 
 // DeleteByID deletes the project with specific ID.
-func (r *projectRepo) DeleteByID(id int) error {
-	r.m.Lock()
-	defer r.m.Unlock()
-
-	if _, ok := r.db.projects[id]; !ok {
-		return store.ErrNotFound
+func (r *projectRepo) DeleteByID(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	for columnID, column := range r.db.columns {
-		if column.ProjectID == id {
-			for taskID, task := range r.db.tasks {
-				if task.ColumnID == columnID {
-					for commentID, comment := range r.db.comments {
-						if comment.TaskID == taskID {
-							delete(r.db.comments, commentID)
-						}
-					}
-					delete(r.db.tasks, taskID)
-				}
-			}
-			delete(r.db.columns, columnID)
-		}
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.project(id); !ok {
+		return store.ErrNotFound
 	}
-	delete(r.db.projects, id)
 
-	return nil
+	return r.db.commit(record{Op: deleteOp, Entity: projectEntity, ID: id})
 }
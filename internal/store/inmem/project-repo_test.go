@@ -1,6 +1,7 @@
 package inmem
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,7 +12,7 @@ import (
 func TestProjectRepo_GetAll(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	ps, err := s.Projects().GetAll()
+	ps, err := s.Projects().GetAll(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(ps))
@@ -20,7 +21,7 @@ func TestProjectRepo_GetAll(t *testing.T) {
 func TestProjectRepo_Create(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	p, err := s.Projects().Create(model.Project{Name: "Project 3"})
+	p, err := s.Projects().Create(context.Background(), model.Project{Name: "Project 3"})
 
 	assert.NoError(t, err)
 	assert.Equal(t, model.Project{ID: 3, Name: "Project 3"}, p)
@@ -29,7 +30,7 @@ func TestProjectRepo_Create(t *testing.T) {
 func TestProjectRepo_GetByID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	p, err := s.Projects().GetByID(1)
+	p, err := s.Projects().GetByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, p.ID)
@@ -39,7 +40,7 @@ func TestProjectRepo_Update(t *testing.T) {
 	s := TestStoreWithFixtures()
 	project := model.Project{ID: 1, Name: "Updated project 1"}
 
-	p, err := s.Projects().Update(project)
+	p, err := s.Projects().Update(context.Background(), project)
 
 	assert.NoError(t, err)
 	assert.Equal(t, project, p)
@@ -48,7 +49,7 @@ func TestProjectRepo_Update(t *testing.T) {
 func TestProjectRepo_DeleteByID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	err := s.Projects().DeleteByID(1)
+	err := s.Projects().DeleteByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(s.db.projects))
@@ -0,0 +1,170 @@
+package inmem
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+const snapshotFile = "snapshot.json"
+
+// snapshotData is the compacted state of inMemoryDb.
+type snapshotData struct {
+	Sequences sequences       `json:"sequences"`
+	Projects  []model.Project `json:"projects"`
+	Columns   []model.Column  `json:"columns"`
+	Tasks     []model.Task    `json:"tasks"`
+	Comments  []model.Comment `json:"comments"`
+
+	Transitions     []model.Transition     `json:"transitions"`
+	ColumnSnapshots []model.ColumnSnapshot `json:"column_snapshots"`
+	TaskLinks       []model.TaskLink       `json:"task_links"`
+	IdempotencyKeys []model.IdempotencyKey `json:"idempotency_keys"`
+}
+
+// snapshot atomically writes the database to snapshot file and truncates the log,
+// the caller must hold db.m.
+func (db *inMemoryDb) snapshot() error {
+	data := snapshotData{
+		Sequences: db.seq,
+		Projects:  []model.Project{},
+		Columns:   []model.Column{},
+		Tasks:     []model.Task{},
+		Comments:  []model.Comment{},
+
+		Transitions:     []model.Transition{},
+		ColumnSnapshots: []model.ColumnSnapshot{},
+		TaskLinks:       []model.TaskLink{},
+		IdempotencyKeys: []model.IdempotencyKey{},
+	}
+	for _, p := range db.projects {
+		data.Projects = append(data.Projects, p)
+	}
+	for _, c := range db.columns {
+		data.Columns = append(data.Columns, c)
+	}
+	for _, t := range db.tasks {
+		data.Tasks = append(data.Tasks, t)
+	}
+	for _, c := range db.comments {
+		data.Comments = append(data.Comments, c)
+	}
+	for _, t := range db.transitions {
+		data.Transitions = append(data.Transitions, t)
+	}
+	for _, s := range db.columnSnapshots {
+		data.ColumnSnapshots = append(data.ColumnSnapshots, s)
+	}
+	for l := range db.taskLinks {
+		data.TaskLinks = append(data.TaskLinks, l)
+	}
+	for _, k := range db.idempotencyKeys {
+		data.IdempotencyKeys = append(data.IdempotencyKeys, k)
+	}
+	sort.Slice(data.Projects, func(i, j int) bool { return data.Projects[i].ID < data.Projects[j].ID })
+	sort.Slice(data.Columns, func(i, j int) bool { return data.Columns[i].ID < data.Columns[j].ID })
+	sort.Slice(data.Tasks, func(i, j int) bool { return data.Tasks[i].ID < data.Tasks[j].ID })
+	sort.Slice(data.Comments, func(i, j int) bool { return data.Comments[i].ID < data.Comments[j].ID })
+	sort.Slice(data.Transitions, func(i, j int) bool { return data.Transitions[i].ID < data.Transitions[j].ID })
+	sort.Slice(data.ColumnSnapshots, func(i, j int) bool {
+		return data.ColumnSnapshots[i].ID < data.ColumnSnapshots[j].ID
+	})
+	sort.Slice(data.TaskLinks, func(i, j int) bool { return taskLinkLess(data.TaskLinks[i], data.TaskLinks[j]) })
+	sort.Slice(data.IdempotencyKeys, func(i, j int) bool {
+		return data.IdempotencyKeys[i].Key < data.IdempotencyKeys[j].Key
+	})
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(db.log.dir, snapshotFile)
+	if err := writeFileSync(path+".tmp", b); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	if err := syncDir(db.log.dir); err != nil {
+		return err
+	}
+
+	// Records are in the snapshot now. If the process crashes before truncating,
+	// they are replayed on top of the snapshot which is harmless.
+	return db.log.reset()
+}
+
+// loadSnapshot reads the database from snapshot file in the directory if it exists.
+func (db *inMemoryDb) loadSnapshot(dir string) error {
+	b, err := ioutil.ReadFile(filepath.Join(dir, snapshotFile))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var data snapshotData
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	for _, p := range data.Projects {
+		db.projects[p.ID] = p
+	}
+	for _, c := range data.Columns {
+		db.columns[c.ID] = c
+	}
+	for _, t := range data.Tasks {
+		db.tasks[t.ID] = t
+	}
+	for _, c := range data.Comments {
+		db.comments[c.ID] = c
+	}
+	for _, t := range data.Transitions {
+		db.transitions[t.ID] = t
+	}
+	for _, s := range data.ColumnSnapshots {
+		db.columnSnapshots[s.ID] = s
+	}
+	for _, l := range data.TaskLinks {
+		db.taskLinks[l] = true
+	}
+	for _, k := range data.IdempotencyKeys {
+		db.idempotencyKeys[k.Key] = k
+	}
+	db.seq = data.Sequences
+
+	return nil
+}
+
+// writeFileSync writes data to the file and flushes it to disk.
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// syncDir flushes directory entries to disk so renames survive a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
@@ -1,76 +1,164 @@
 package inmem
 
 import (
-	"github.com/imarrche/tasker/internal/model"
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
 	"github.com/imarrche/tasker/internal/store"
 )
 
-type inMemoryDb struct {
-	projects map[int]model.Project
-	columns  map[int]model.Column
-	tasks    map[int]model.Task
-	comments map[int]model.Comment
-}
-
-func newInMemoryDb() *inMemoryDb {
-	return &inMemoryDb{
-		projects: map[int]model.Project{},
-		columns:  map[int]model.Column{},
-		tasks:    map[int]model.Task{},
-		comments: map[int]model.Comment{},
-	}
-}
-
-// Store is the in memory store.
+// Store is the in memory store. Optionally, it persists mutations to write-ahead log
+// and snapshots in a directory so data survives restarts.
 type Store struct {
+	config      config.InMemory
 	db          *inMemoryDb
 	projectRepo *projectRepo
 	columnRepo  *columnRepo
 	taskRepo    *taskRepo
 	commentRepo *commentRepo
+
+	transitionRepo     *transitionRepo
+	columnSnapshotRepo *columnSnapshotRepo
+	taskLinkRepo       *taskLinkRepo
+	idempotencyKeyRepo *idempotencyKeyRepo
+
+	stop chan struct{}
+	done chan struct{}
 }
 
-// NewStore creates and returns a new Store instance.
-func NewStore() *Store { return &Store{db: newInMemoryDb()} }
+// NewStore creates and returns a new Store instance keeping data only in memory.
+func NewStore() *Store { return New(config.InMemory{}) }
 
-// Open opens the store.
-func (s *Store) Open() error { return nil }
+// New creates and returns a new Store instance.
+// Repositories are created up front as they are shared by concurrent callers.
+func New(c config.InMemory) *Store {
+	db := newInMemoryDb()
+	db.l = logger.New(ioutil.Discard, logger.ErrorLevel, logger.TextFormat)
 
-// Projects returns the project repository.
-func (s *Store) Projects() store.ProjectRepo {
-	if s.projectRepo == nil {
-		s.projectRepo = newProjectRepo(s.db)
-	}
+	return &Store{
+		config:      c,
+		db:          db,
+		projectRepo: newProjectRepo(db),
+		columnRepo:  newColumnRepo(db),
+		taskRepo:    newTaskRepo(db),
+		commentRepo: newCommentRepo(db),
 
-	return s.projectRepo
+		transitionRepo:     newTransitionRepo(db),
+		columnSnapshotRepo: newColumnSnapshotRepo(db),
+		taskLinkRepo:       newTaskLinkRepo(db),
+		idempotencyKeyRepo: newIdempotencyKeyRepo(db),
+	}
 }
 
-// Columns returns the column repository.
-func (s *Store) Columns() store.ColumnRepo {
-	if s.columnRepo == nil {
-		s.columnRepo = newColumnRepo(s.db)
+// Open opens the store restoring data from the last snapshot and write-ahead log
+// if persistence is enabled.
+func (s *Store) Open() error {
+	if s.config.Dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.config.Dir, 0700); err != nil {
+		return err
+	}
+	if err := s.db.loadSnapshot(s.config.Dir); err != nil {
+		return err
+	}
+	l, err := openWAL(s.config.Dir, s.config)
+	if err != nil {
+		return err
+	}
+	if err := l.replay(s.db.apply); err != nil {
+		l.close()
+		return err
+	}
+	s.db.log = l
+
+	if s.config.Fsync == config.FsyncInterval {
+		s.stop, s.done = make(chan struct{}), make(chan struct{})
+		go s.syncPeriodically()
 	}
 
-	return s.columnRepo
+	return nil
 }
 
-// Tasks returns the task repository.
-func (s *Store) Tasks() store.TaskRepo {
-	if s.taskRepo == nil {
-		s.taskRepo = newTaskRepo(s.db)
+// syncPeriodically flushes write-ahead log to disk until the store is closed.
+func (s *Store) syncPeriodically() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.FsyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.db.m.Lock()
+			// Failed sync is retried on the next tick, a broken disk fails next append.
+			s.db.log.sync()
+			s.db.m.Unlock()
+		case <-s.stop:
+			return
+		}
 	}
+}
+
+// SetLogger sets the logger reporting failures which don't fail the mutation,
+// such as a failed snapshot. Nothing is logged by default.
+func (s *Store) SetLogger(l *logger.Logger) {
+	s.db.m.Lock()
+	defer s.db.m.Unlock()
 
-	return s.taskRepo
+	s.db.l = l
 }
 
+// Ping checks whether the store is available.
+func (s *Store) Ping(ctx context.Context) error { return ctx.Err() }
+
+// Projects returns the project repository.
+func (s *Store) Projects() store.ProjectRepo { return s.projectRepo }
+
+// Columns returns the column repository.
+func (s *Store) Columns() store.ColumnRepo { return s.columnRepo }
+
+// Tasks returns the task repository.
+func (s *Store) Tasks() store.TaskRepo { return s.taskRepo }
+
 // Comments returns the comment repository.
-func (s *Store) Comments() store.CommentRepo {
-	if s.commentRepo == nil {
-		s.commentRepo = newCommentRepo(s.db)
+func (s *Store) Comments() store.CommentRepo { return s.commentRepo }
+
+// Transitions returns the transition repository.
+func (s *Store) Transitions() store.TransitionRepo { return s.transitionRepo }
+
+// ColumnSnapshots returns the column snapshot repository.
+func (s *Store) ColumnSnapshots() store.ColumnSnapshotRepo { return s.columnSnapshotRepo }
+
+// TaskLinks returns the task link repository.
+func (s *Store) TaskLinks() store.TaskLinkRepo { return s.taskLinkRepo }
+
+// IdempotencyKeys returns the idempotency key repository.
+func (s *Store) IdempotencyKeys() store.IdempotencyKeyRepo { return s.idempotencyKeyRepo }
+
+// Close closes the store compacting write-ahead log into a snapshot.
+func (s *Store) Close() error {
+	if s.db.log == nil {
+		return nil
 	}
 
-	return s.commentRepo
-}
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+	}
+
+	s.db.m.Lock()
+	defer s.db.m.Unlock()
 
-// Close closes the store.
-func (s *Store) Close() error { return nil }
+	err := s.db.snapshot()
+	if cerr := s.db.log.close(); err == nil {
+		err = cerr
+	}
+	s.db.log = nil
+
+	return err
+}
@@ -1,18 +1,165 @@
 package inmem
 
 import (
+	"context"
+	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
 )
 
 func TestStore_Open(t *testing.T) {
 	assert.NoError(t, NewStore().Open())
 }
 
+func TestStore_Ping(t *testing.T) {
+	s := NewStore()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	assert.NoError(t, s.Ping(ctx))
+	cancel()
+	assert.Equal(t, context.Canceled, s.Ping(ctx))
+}
+
 func TestStore_Close(t *testing.T) {
 	s := NewStore()
 	s.Open()
 
 	assert.NoError(t, s.Close())
 }
+
+func TestStore_CanceledContext(t *testing.T) {
+	s := TestStoreWithFixtures()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.Projects().GetAll(ctx)
+	assert.Equal(t, context.Canceled, err)
+	_, err = s.Columns().GetByProjectID(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	_, err = s.Tasks().GetByColumnID(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	err = s.Comments().DeleteByID(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 3, len(s.db.comments))
+}
+
+func TestStore_TxOverlay(t *testing.T) {
+	s := TestStoreWithFixtures()
+	ctx := context.Background()
+
+	err := s.InTx(ctx, func(tx store.Tx) error {
+		// Changes are seen by the transaction only.
+		task, err := tx.Tasks().GetByID(ctx, 1)
+		assert.NoError(t, err)
+		task.Name = "Renamed"
+		_, err = tx.Tasks().Update(ctx, task)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Columns().DeleteByID(ctx, 2))
+
+		ts, err := tx.Tasks().GetAll(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, ts, 2)
+		task, err = tx.Tasks().GetByID(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "Renamed", task.Name)
+		_, err = tx.Tasks().GetByID(ctx, 3)
+		assert.Equal(t, store.ErrNotFound, err)
+		cs, err := tx.Columns().GetByProjectID(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, cs, 1)
+		assert.Equal(t, "Task 1", s.db.tasks[1].Name)
+		assert.Contains(t, s.db.columns, 2)
+
+		return store.ErrNotFound
+	})
+
+	assert.Equal(t, store.ErrNotFound, err)
+	assert.Equal(t, "Task 1", s.db.tasks[1].Name)
+	assert.Len(t, s.db.columns, 3)
+	assert.Len(t, s.db.tasks, 3)
+}
+
+func TestStore_IDsAreNotReused(t *testing.T) {
+	s := TestStoreWithFixtures()
+	ctx := context.Background()
+
+	assert.NoError(t, s.Comments().DeleteByID(ctx, 3))
+	c, err := s.Comments().Create(ctx, model.Comment{Text: "Comment 4", TaskID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, c.ID)
+
+	assert.NoError(t, s.Projects().DeleteByID(ctx, 1))
+	p, err := s.Projects().Create(ctx, model.Project{Name: "Project 3"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, p.ID)
+	p, err = s.Projects().GetByID(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "Project 2", p.Name)
+}
+
+func TestStore_ConcurrentMutations(t *testing.T) {
+	s := TestStoreWithFixtures()
+	ctx := context.Background()
+	workers, iterations := 8, 200
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			id := func() int { return rnd.Intn(iterations) + 1 }
+
+			for i := 0; i < iterations; i++ {
+				switch rnd.Intn(8) {
+				case 0:
+					s.Projects().Create(ctx, model.Project{Name: "Project"})
+				case 1:
+					s.Columns().Create(ctx, model.Column{Name: "Column", ProjectID: id()})
+				case 2:
+					s.Tasks().Create(ctx, model.Task{Name: "Task", ColumnID: id()})
+				case 3:
+					s.Comments().Create(ctx, model.Comment{Text: "Comment", TaskID: id()})
+				case 4:
+					// Moving the task to another column.
+					if task, err := s.Tasks().GetByID(ctx, id()); err == nil {
+						task.ColumnID = id()
+						s.Tasks().Update(ctx, task)
+					}
+				case 5:
+					s.Columns().DeleteByID(ctx, id())
+				case 6:
+					s.Projects().DeleteByID(ctx, id())
+				case 7:
+					s.Projects().GetAll(ctx)
+					s.Columns().GetByProjectID(ctx, id())
+					s.Tasks().GetByColumnID(ctx, id())
+					s.Comments().GetByTaskID(ctx, id())
+				}
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+
+	// Nothing references deleted entities and IDs match their keys.
+	for id, c := range s.db.columns {
+		assert.Equal(t, id, c.ID)
+		assert.Contains(t, s.db.projects, c.ProjectID, "column %d", id)
+	}
+	for id, task := range s.db.tasks {
+		assert.Equal(t, id, task.ID)
+		assert.Contains(t, s.db.columns, task.ColumnID, "task %d", id)
+	}
+	for id, c := range s.db.comments {
+		assert.Equal(t, id, c.ID)
+		assert.Contains(t, s.db.tasks, c.TaskID, "comment %d", id)
+	}
+	for id := range s.db.projects {
+		assert.LessOrEqual(t, id, s.db.seq.Projects)
+	}
+}
@@ -0,0 +1,92 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// taskLinkRepo is the task link repository for in memory store.
+type taskLinkRepo struct {
+	db *inMemoryDb
+}
+
+// newTaskLinkRepo creates and returns a new taskLinkRepo instance.
+func newTaskLinkRepo(db *inMemoryDb) *taskLinkRepo { return &taskLinkRepo{db: db} }
+
+// GetByTaskID returns links the task with specific ID takes either side of.
+func (r *taskLinkRepo) GetByTaskID(ctx context.Context, id int) ([]model.TaskLink, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	if _, ok := r.db.task(id); !ok {
+		return nil, store.ErrNotFound
+	}
+
+	ls := []model.TaskLink{}
+	r.db.eachTaskLink(func(l model.TaskLink) {
+		if l.BlockerID == id || l.BlockedID == id {
+			ls = append(ls, l)
+		}
+	})
+	sort.Slice(ls, func(i, j int) bool { return taskLinkLess(ls[i], ls[j]) })
+
+	return ls, nil
+}
+
+// Create creates and returns a new task link.
+func (r *taskLinkRepo) Create(ctx context.Context, l model.TaskLink) (model.TaskLink, error) {
+	if err := ctx.Err(); err != nil {
+		return model.TaskLink{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.task(l.BlockerID); !ok {
+		return model.TaskLink{}, store.ErrInvalidReference
+	}
+	if _, ok := r.db.task(l.BlockedID); !ok {
+		return model.TaskLink{}, store.ErrInvalidReference
+	}
+	if r.db.hasTaskLink(l) {
+		return model.TaskLink{}, store.ErrConflict
+	}
+
+	if err := r.db.commit(record{Op: putOp, Entity: taskLinkEntity, TaskLink: &l}); err != nil {
+		return model.TaskLink{}, err
+	}
+
+	return l, nil
+}
+
+// Delete deletes the task link.
+func (r *taskLinkRepo) Delete(ctx context.Context, l model.TaskLink) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if !r.db.hasTaskLink(l) {
+		return store.ErrNotFound
+	}
+
+	return r.db.commit(record{Op: deleteOp, Entity: taskLinkEntity, TaskLink: &l})
+}
+
+// taskLinkLess orders links by blocker and blocked task IDs.
+func taskLinkLess(a, b model.TaskLink) bool {
+	if a.BlockerID != b.BlockerID {
+		return a.BlockerID < b.BlockerID
+	}
+
+	return a.BlockedID < b.BlockedID
+}
@@ -1,7 +1,8 @@
 package inmem
 
 import (
-	"sync"
+	"context"
+	"sort"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
@@ -10,104 +11,161 @@ import (
 // taskRepo is the task repository for in memory store.
 type taskRepo struct {
 	db *inMemoryDb
-	m  sync.RWMutex
 }
 
 // newTaskRepo creates and returns a new taskRepo instance.
 func newTaskRepo(db *inMemoryDb) *taskRepo { return &taskRepo{db: db} }
 
+// GetAll returns all tasks.
+func (r *taskRepo) GetAll(ctx context.Context) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	ts := []model.Task{}
+	r.db.eachTask(func(t model.Task) {
+		ts = append(ts, r.db.withBlocked(t))
+	})
+	sort.Slice(ts, func(i, j int) bool { return ts[i].ID < ts[j].ID })
+
+	return ts, nil
+}
+
 // GetByColumnID returns all tasks with specific column ID.
-func (r *taskRepo) GetByColumnID(id int) ([]model.Task, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *taskRepo) GetByColumnID(ctx context.Context, id int) ([]model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	if _, ok := r.db.columns[id]; !ok {
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	if _, ok := r.db.column(id); !ok {
 		return nil, store.ErrNotFound
 	}
 
 	ts := []model.Task{}
-	for _, t := range r.db.tasks {
+	r.db.eachTask(func(t model.Task) {
 		if t.ColumnID == id {
-			ts = append(ts, t)
+			ts = append(ts, r.db.withBlocked(t))
 		}
-	}
+	})
+	sort.Slice(ts, func(i, j int) bool { return ts[i].ID < ts[j].ID })
 
 	return ts, nil
 }
 
 // Create creates and returns a new task.
-func (r *taskRepo) Create(t model.Task) (model.Task, error) {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *taskRepo) Create(ctx context.Context, t model.Task) (model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Task{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
 
-	if _, ok := r.db.columns[t.ColumnID]; !ok {
-		return model.Task{}, store.ErrDbQuery
+	if _, ok := r.db.column(t.ColumnID); !ok {
+		return model.Task{}, store.ErrInvalidReference
+	}
+	if r.db.taskConflicts(t) {
+		return model.Task{}, store.ErrConflict
 	}
 
-	t.ID = len(r.db.tasks) + 1
-	r.db.tasks[t.ID] = t
+	t.ID = r.db.seq.Tasks + 1
+	// Blocked is computed when the task is read.
+	stored := t
+	stored.Blocked = false
+	rec := record{Op: putOp, Entity: taskEntity, ID: t.ID, Task: &stored}
+	if err := r.db.commit(rec); err != nil {
+		return model.Task{}, err
+	}
 
 	return t, nil
 }
 
 // GetByID returns the task with specific ID.
-func (r *taskRepo) GetByID(id int) (model.Task, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *taskRepo) GetByID(ctx context.Context, id int) (model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Task{}, err
+	}
 
-	if t, ok := r.db.tasks[id]; ok {
-		return t, nil
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	if t, ok := r.db.task(id); ok {
+		return r.db.withBlocked(t), nil
 	}
 
 	return model.Task{}, store.ErrNotFound
 }
 
 // GetByIndexAndColumnID returns the task with specific index and column ID.
-func (r *taskRepo) GetByIndexAndColumnID(index, id int) (model.Task, error) {
-	r.m.RLock()
-	defer r.m.RUnlock()
+func (r *taskRepo) GetByIndexAndColumnID(ctx context.Context, index, id int) (model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Task{}, err
+	}
 
-	for _, t := range r.db.tasks {
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	task, found := model.Task{}, false
+	r.db.eachTask(func(t model.Task) {
 		if t.Index == index && t.ColumnID == id {
-			return t, nil
+			task, found = t, true
 		}
+	})
+	if found {
+		return r.db.withBlocked(task), nil
 	}
 
 	return model.Task{}, store.ErrNotFound
 }
 
 // Update updates the task.
-func (r *taskRepo) Update(t model.Task) (model.Task, error) {
-	r.m.Lock()
-	defer r.m.Unlock()
+func (r *taskRepo) Update(ctx context.Context, t model.Task) (model.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Task{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
 
-	if _, ok := r.db.tasks[t.ID]; !ok {
+	if _, ok := r.db.task(t.ID); !ok {
 		return model.Task{}, store.ErrNotFound
 	}
-	if _, ok := r.db.columns[t.ColumnID]; !ok {
-		return model.Task{}, store.ErrDbQuery
+	if _, ok := r.db.column(t.ColumnID); !ok {
+		return model.Task{}, store.ErrInvalidReference
+	}
+	if r.db.taskConflicts(t) {
+		return model.Task{}, store.ErrConflict
 	}
 
-	r.db.tasks[t.ID] = t
+	// Blocked is computed when the task is read.
+	stored := t
+	stored.Blocked = false
+	rec := record{Op: putOp, Entity: taskEntity, ID: t.ID, Task: &stored}
+	if err := r.db.commit(rec); err != nil {
+		return model.Task{}, err
+	}
 
 	return t, nil
 }
 
 // DeleteByID deletes the task with specific ID.
-func (r *taskRepo) DeleteByID(id int) error {
-	r.m.Lock()
-	defer r.m.Unlock()
-
-	if _, ok := r.db.tasks[id]; !ok {
-		return store.ErrNotFound
+func (r *taskRepo) DeleteByID(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	for commentID, comment := range r.db.comments {
-		if comment.TaskID == id {
-			delete(r.db.comments, commentID)
-		}
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.task(id); !ok {
+		return store.ErrNotFound
 	}
-	delete(r.db.tasks, id)
 
-	return nil
+	return r.db.commit(record{Op: deleteOp, Entity: taskEntity, ID: id})
 }
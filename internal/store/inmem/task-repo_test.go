@@ -1,6 +1,7 @@
 package inmem
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,10 +9,19 @@ import (
 	"github.com/imarrche/tasker/internal/model"
 )
 
+func TestTaskRepo_GetAll(t *testing.T) {
+	s := TestStoreWithFixtures()
+
+	ts, err := s.Tasks().GetAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(ts))
+}
+
 func TestTaskRepo_GetByColumnID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	ts, err := s.Tasks().GetByColumnID(1)
+	ts, err := s.Tasks().GetByColumnID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(ts))
@@ -20,7 +30,7 @@ func TestTaskRepo_GetByColumnID(t *testing.T) {
 func TestTaskRepo_Create(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	task, err := s.Tasks().Create(model.Task{Name: "Task 4", Index: 2, ColumnID: 2})
+	task, err := s.Tasks().Create(context.Background(), model.Task{Name: "Task 4", Index: 2, ColumnID: 2})
 
 	assert.NoError(t, err)
 	assert.Equal(t, model.Task{ID: 4, Name: "Task 4", Index: 2, ColumnID: 2}, task)
@@ -29,7 +39,7 @@ func TestTaskRepo_Create(t *testing.T) {
 func TestTaskRepo_GetByID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	task, err := s.Tasks().GetByID(1)
+	task, err := s.Tasks().GetByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, task.ID)
@@ -38,7 +48,7 @@ func TestTaskRepo_GetByID(t *testing.T) {
 func TestTaskRepo_GetByIndexAndColumnID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	task, err := s.Tasks().GetByIndexAndColumnID(1, 1)
+	task, err := s.Tasks().GetByIndexAndColumnID(context.Background(), 1, 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, task.ID)
@@ -48,7 +58,7 @@ func TestTaskRepo_Update(t *testing.T) {
 	s := TestStoreWithFixtures()
 	task := model.Task{ID: 1, Name: "Updated task 1", Index: 1, ColumnID: 1}
 
-	task1, err := s.Tasks().Update(task)
+	task1, err := s.Tasks().Update(context.Background(), task)
 
 	assert.NoError(t, err)
 	assert.Equal(t, task, task1)
@@ -57,7 +67,7 @@ func TestTaskRepo_Update(t *testing.T) {
 func TestTaskRepository_DeleteByID(t *testing.T) {
 	s := TestStoreWithFixtures()
 
-	err := s.Tasks().DeleteByID(1)
+	err := s.Tasks().DeleteByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(s.db.tasks))
@@ -0,0 +1,131 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// transitionRepo is the transition repository for in memory store.
+type transitionRepo struct {
+	db *inMemoryDb
+}
+
+// newTransitionRepo creates and returns a new transitionRepo instance.
+func newTransitionRepo(db *inMemoryDb) *transitionRepo { return &transitionRepo{db: db} }
+
+// Create creates and returns a new transition.
+func (r *transitionRepo) Create(ctx context.Context, t model.Transition) (model.Transition, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Transition{}, err
+	}
+
+	r.db.m.Lock()
+	defer r.db.m.Unlock()
+
+	if _, ok := r.db.task(t.TaskID); !ok {
+		return model.Transition{}, store.ErrInvalidReference
+	}
+	if _, ok := r.db.project(t.ProjectID); !ok {
+		return model.Transition{}, store.ErrInvalidReference
+	}
+
+	t.ID = r.db.seq.Transitions + 1
+	rec := record{Op: putOp, Entity: transitionEntity, ID: t.ID, Transition: &t}
+	if err := r.db.commit(rec); err != nil {
+		return model.Transition{}, err
+	}
+
+	return t, nil
+}
+
+// GetByTaskID returns all transitions of the task with specific ID in order.
+func (r *transitionRepo) GetByTaskID(ctx context.Context, id int) ([]model.Transition, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	if _, ok := r.db.task(id); !ok {
+		return nil, store.ErrNotFound
+	}
+
+	if ts, ok := r.db.taskTransitions()[id]; ok {
+		return ts, nil
+	}
+
+	return []model.Transition{}, nil
+}
+
+// Analytics computes metrics of the project with specific ID over the period the same
+// way SQL stores do.
+func (r *transitionRepo) Analytics(ctx context.Context, id int, from, to time.Time) (model.Analytics, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Analytics{}, err
+	}
+
+	r.db.m.RLock()
+	defer r.db.m.RUnlock()
+
+	a := model.Analytics{From: from, To: to, Columns: []model.ColumnDwell{}}
+	within := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+	transitions := r.db.taskTransitions()
+
+	// A stay in a column lasts until the next transition of the task.
+	dwell, seconds := map[int]int{}, map[int]float64{}
+	for _, ts := range transitions {
+		for i := 0; i+1 < len(ts); i++ {
+			if ts[i].ProjectID != id || !within(ts[i+1].At) {
+				continue
+			}
+			dwell[ts[i].ToColumnID]++
+			seconds[ts[i].ToColumnID] += ts[i+1].At.Sub(ts[i].At).Seconds()
+		}
+	}
+	for columnID, count := range dwell {
+		a.Columns = append(a.Columns, model.ColumnDwell{
+			ColumnID: columnID, Count: count, AverageSeconds: seconds[columnID] / float64(count),
+		})
+	}
+	sort.Slice(a.Columns, func(i, j int) bool { return a.Columns[i].ColumnID < a.Columns[j].ColumnID })
+
+	var lead, cycle []float64
+	r.db.eachTask(func(t model.Task) {
+		if c, _ := r.db.column(t.ColumnID); c.ProjectID != id || t.CompletedAt == nil || !within(*t.CompletedAt) {
+			return
+		}
+		if ts := transitions[t.ID]; len(ts) > 0 {
+			lead = append(lead, t.CompletedAt.Sub(ts[0].At).Seconds())
+		}
+		if t.StartedAt != nil {
+			cycle = append(cycle, t.CompletedAt.Sub(*t.StartedAt).Seconds())
+		}
+	})
+	a.LeadTime, a.CycleTime = model.NewPercentiles(lead), model.NewPercentiles(cycle)
+
+	return a, nil
+}
+
+// taskTransitions returns transitions grouped by task ID in order, the caller must hold
+// db.m.
+func (db *inMemoryDb) taskTransitions() map[int][]model.Transition {
+	byTask := map[int][]model.Transition{}
+	db.eachTransition(func(t model.Transition) {
+		byTask[t.TaskID] = append(byTask[t.TaskID], t)
+	})
+	for _, ts := range byTask {
+		sort.Slice(ts, func(i, j int) bool {
+			if !ts[i].At.Equal(ts[j].At) {
+				return ts[i].At.Before(ts[j].At)
+			}
+			return ts[i].ID < ts[j].ID
+		})
+	}
+
+	return byTask
+}
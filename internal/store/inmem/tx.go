@@ -0,0 +1,77 @@
+package inmem
+
+import (
+	"context"
+
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// txRepos are the repositories bound to a transaction.
+type txRepos struct {
+	projectRepo        *projectRepo
+	columnRepo         *columnRepo
+	taskRepo           *taskRepo
+	commentRepo        *commentRepo
+	transitionRepo     *transitionRepo
+	columnSnapshotRepo *columnSnapshotRepo
+	taskLinkRepo       *taskLinkRepo
+}
+
+// InTx runs fn in a transaction. The store is locked for the whole transaction while fn
+// works on an overlay of the data keeping only its changes. On commit the changes are
+// written to the log as a single record and applied to the store, on rollback the
+// overlay is discarded. Uniqueness of indices is checked on commit.
+func (s *Store) InTx(ctx context.Context, fn func(store.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.db.m.Lock()
+	defer s.db.m.Unlock()
+
+	db := s.db.overlay()
+	err := fn(&txRepos{
+		projectRepo:        newProjectRepo(db),
+		columnRepo:         newColumnRepo(db),
+		taskRepo:           newTaskRepo(db),
+		commentRepo:        newCommentRepo(db),
+		transitionRepo:     newTransitionRepo(db),
+		columnSnapshotRepo: newColumnSnapshotRepo(db),
+		taskLinkRepo:       newTaskLinkRepo(db),
+	})
+	if err != nil {
+		return err
+	}
+	if db.duplicateIndices() {
+		return store.ErrConflict
+	}
+
+	return s.db.commitAll(db.pending)
+}
+
+// InSerializableTx runs fn in a transaction like InTx. Transactions are serializable as
+// the store is locked for the whole transaction.
+func (s *Store) InSerializableTx(ctx context.Context, fn func(store.Tx) error) error {
+	return s.InTx(ctx, fn)
+}
+
+// Projects returns the project repository.
+func (r *txRepos) Projects() store.ProjectRepo { return r.projectRepo }
+
+// Columns returns the column repository.
+func (r *txRepos) Columns() store.ColumnRepo { return r.columnRepo }
+
+// Tasks returns the task repository.
+func (r *txRepos) Tasks() store.TaskRepo { return r.taskRepo }
+
+// Comments returns the comment repository.
+func (r *txRepos) Comments() store.CommentRepo { return r.commentRepo }
+
+// Transitions returns the transition repository.
+func (r *txRepos) Transitions() store.TransitionRepo { return r.transitionRepo }
+
+// ColumnSnapshots returns the column snapshot repository.
+func (r *txRepos) ColumnSnapshots() store.ColumnSnapshotRepo { return r.columnSnapshotRepo }
+
+// TaskLinks returns the task link repository.
+func (r *txRepos) TaskLinks() store.TaskLinkRepo { return r.taskLinkRepo }
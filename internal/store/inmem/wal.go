@@ -0,0 +1,203 @@
+package inmem
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/imarrche/tasker/internal/config"
+)
+
+const (
+	walFile = "wal.log"
+	// recordHeaderSize is the size of record length and checksum preceding each record.
+	recordHeaderSize = 8
+	// maxRecordSize protects from allocating huge buffers for corrupted lengths.
+	maxRecordSize = 16 << 20
+)
+
+// errCorruptRecord is returned when a record can't be read completely or its
+// checksum doesn't match.
+var errCorruptRecord = errors.New("corrupt record")
+
+// errRecordTooLarge is returned when a record can't be written as replaying the log
+// would take it for a corrupted one.
+var errRecordTooLarge = errors.New("record is too large")
+
+// wal is the write-ahead log of inMemoryDb mutations. Each record is JSON prefixed with
+// its length and CRC-32 checksum, both are 4 byte big-endian integers.
+type wal struct {
+	f      *os.File
+	dir    string
+	config config.InMemory
+	// records is the number of records written since the last snapshot.
+	records int
+	// unsynced is set when records were written but not flushed to disk.
+	unsynced bool
+	// broken is set when a failed write couldn't be removed from the log, records
+	// appended after it would be lost on replay so no more records are written.
+	broken error
+}
+
+// openWAL opens the write-ahead log in the directory creating it if it doesn't exist.
+func openWAL(dir string, c config.InMemory) (*wal, error) {
+	f, err := os.OpenFile(filepath.Join(dir, walFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wal{f: f, dir: dir, config: c}, nil
+}
+
+// replay applies all records of the log. Incomplete or corrupted record at the end of
+// the log is left by a crash in the middle of write, the log is truncated before it.
+func (l *wal) replay(apply func(record)) error {
+	if _, err := l.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var offset int64
+	for {
+		rec, n, err := readRecord(l.f)
+		if err == io.EOF {
+			break
+		} else if err == errCorruptRecord {
+			if err := l.f.Truncate(offset); err != nil {
+				return err
+			}
+			break
+		} else if err != nil {
+			return fmt.Errorf("replaying %s at offset %d: %w", walFile, offset, err)
+		}
+
+		apply(rec)
+		offset += n
+		l.records++
+	}
+
+	_, err := l.f.Seek(offset, io.SeekStart)
+	return err
+}
+
+// readRecord reads the next record returning its size in the log.
+func readRecord(r io.Reader) (record, int64, error) {
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err == io.EOF {
+		return record{}, 0, io.EOF
+	} else if err != nil {
+		return record{}, 0, errCorruptRecord
+	}
+
+	size := binary.BigEndian.Uint32(header[:4])
+	if size > maxRecordSize {
+		return record{}, 0, errCorruptRecord
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return record{}, 0, errCorruptRecord
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:]) {
+		return record{}, 0, errCorruptRecord
+	}
+
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return record{}, 0, err
+	}
+
+	return rec, int64(recordHeaderSize + size), nil
+}
+
+// append writes the record to the end of the log syncing it according to fsync policy.
+// A record which failed to be written is removed from the log.
+func (l *wal) append(rec record) error {
+	if l.broken != nil {
+		return l.broken
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxRecordSize {
+		return errRecordTooLarge
+	}
+
+	buf := make([]byte, recordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(data)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(data))
+	copy(buf[recordHeaderSize:], data)
+	offset, err := l.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := l.f.Write(buf); err != nil {
+		return l.discard(offset, err)
+	}
+	if l.config.Fsync == config.FsyncAlways {
+		if err := l.f.Sync(); err != nil {
+			return l.discard(offset, err)
+		}
+	} else {
+		l.unsynced = true
+	}
+	l.records++
+
+	return nil
+}
+
+// discard truncates the log to the offset removing a partly written record, so records
+// appended later aren't cut off with it on replay. The log is broken if it fails.
+func (l *wal) discard(offset int64, err error) error {
+	if terr := l.f.Truncate(offset); terr != nil {
+		l.broken = fmt.Errorf("write-ahead log is broken after failed write: %w", terr)
+		return err
+	}
+	if _, serr := l.f.Seek(offset, io.SeekStart); serr != nil {
+		l.broken = fmt.Errorf("write-ahead log is broken after failed write: %w", serr)
+	}
+
+	return err
+}
+
+// sync flushes written records to disk.
+func (l *wal) sync() error {
+	if !l.unsynced {
+		return nil
+	}
+	l.unsynced = false
+
+	return l.f.Sync()
+}
+
+// needsSnapshot checks whether enough records were written to compact the log.
+func (l *wal) needsSnapshot() bool {
+	return l.config.SnapshotEvery > 0 && l.records >= l.config.SnapshotEvery
+}
+
+// reset truncates the log after its records were saved to snapshot.
+func (l *wal) reset() error {
+	if err := l.f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := l.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	l.records, l.unsynced = 0, false
+
+	return l.f.Sync()
+}
+
+// close flushes and closes the log.
+func (l *wal) close() error {
+	if err := l.sync(); err != nil {
+		l.f.Close()
+		return err
+	}
+
+	return l.f.Close()
+}
@@ -0,0 +1,331 @@
+package inmem
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/logger"
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// tempDir creates a directory removed after the test.
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "inmem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return dir
+}
+
+// openStore opens a new persistent store in the directory.
+func openStore(t *testing.T, c config.InMemory) *Store {
+	s := New(c)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// populate creates a board and returns its comment.
+func populate(t *testing.T, s *Store) model.Comment {
+	ctx := context.Background()
+	p, err := s.Projects().Create(ctx, model.Project{Name: "Project"})
+	assert.NoError(t, err)
+	c, err := s.Columns().Create(ctx, model.Column{Name: "Column", Index: 1, ProjectID: p.ID})
+	assert.NoError(t, err)
+	task, err := s.Tasks().Create(ctx, model.Task{Name: "Task", Index: 1, ColumnID: c.ID})
+	assert.NoError(t, err)
+	task.Description = "Description"
+	_, err = s.Tasks().Update(ctx, task)
+	assert.NoError(t, err)
+	comment := model.Comment{
+		Text: "Comment", CreatedAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), TaskID: task.ID,
+	}
+	comment, err = s.Comments().Create(ctx, comment)
+	assert.NoError(t, err)
+
+	return comment
+}
+
+// populateKey creates an idempotency key with the stored response and returns it.
+func populateKey(t *testing.T, s *Store, key string) model.IdempotencyKey {
+	ctx := context.Background()
+	k := model.IdempotencyKey{
+		Key: key, Fingerprint: "f", CreatedAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	k, err := s.IdempotencyKeys().Create(ctx, k)
+	assert.NoError(t, err)
+	k.Status, k.ContentType, k.Body = 201, "application/json", []byte(`{"id": 1}`)
+	k.Headers = map[string][]string{"Location": {"/api/v1/projects/1"}}
+	k, err = s.IdempotencyKeys().Update(ctx, k)
+	assert.NoError(t, err)
+
+	return k
+}
+
+func TestStore_Persistence(t *testing.T) {
+	testcases := []struct {
+		name   string
+		config config.InMemory
+		close  bool
+	}{
+		{
+			name:   "data is restored from snapshot",
+			config: config.InMemory{Fsync: config.FsyncAlways},
+			close:  true,
+		},
+		{
+			name:   "data is restored from log after crash",
+			config: config.InMemory{Fsync: config.FsyncAlways},
+		},
+		{
+			name:   "data is restored from snapshot and log after crash",
+			config: config.InMemory{Fsync: config.FsyncNever, SnapshotEvery: 3},
+		},
+		{
+			name:   "data is restored with periodic fsync",
+			config: config.InMemory{Fsync: config.FsyncInterval, FsyncInterval: time.Millisecond},
+			close:  true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.Dir = tempDir(t)
+			s := openStore(t, tc.config)
+			comment := populate(t, s)
+			deleted := populateKey(t, s, "deleted")
+			assert.NoError(t, s.Projects().DeleteByID(context.Background(), 1))
+			assert.NoError(t, s.IdempotencyKeys().Delete(context.Background(), deleted))
+			comment2 := populate(t, s)
+			populateKey(t, s, "kept")
+			if tc.close {
+				assert.NoError(t, s.Close())
+			}
+
+			reopened := openStore(t, tc.config)
+			defer reopened.Close()
+
+			assert.Equal(t, s.db.projects, reopened.db.projects)
+			assert.Equal(t, s.db.columns, reopened.db.columns)
+			assert.Equal(t, s.db.tasks, reopened.db.tasks)
+			assert.Equal(t, s.db.comments, reopened.db.comments)
+			assert.Equal(t, s.db.idempotencyKeys, reopened.db.idempotencyKeys)
+			assert.Equal(t, s.db.seq, reopened.db.seq)
+			_, err := reopened.Comments().GetByID(context.Background(), comment.ID)
+			assert.Equal(t, store.ErrNotFound, err)
+			c, err := reopened.Comments().GetByID(context.Background(), comment2.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, comment2, c)
+		})
+	}
+}
+
+func TestStore_TruncatedLog(t *testing.T) {
+	dir := tempDir(t)
+	c := config.InMemory{Dir: dir, Fsync: config.FsyncAlways}
+	s := openStore(t, c)
+	populate(t, s)
+	path := filepath.Join(dir, walFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, _ := ioutil.ReadFile(path)
+
+	// Crash in the middle of writing another record at every possible byte.
+	p := model.Project{Name: "Lost project"}
+	_, err = s.Projects().Create(context.Background(), p)
+	assert.NoError(t, err)
+	withLast, _ := ioutil.ReadFile(path)
+	for size := info.Size(); size < int64(len(withLast)); size++ {
+		if err := ioutil.WriteFile(path, withLast[:size], 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		reopened := openStore(t, c)
+
+		assert.Equal(t, 1, len(reopened.db.projects), "size %d", size)
+		assert.Equal(t, 1, len(reopened.db.comments), "size %d", size)
+		data, _ := ioutil.ReadFile(path)
+		assert.Equal(t, full, data, "log is truncated to the last complete record")
+		reopened.db.log.close()
+	}
+
+	// Appending after recovery.
+	reopened := openStore(t, c)
+	_, err = reopened.Projects().Create(context.Background(), p)
+	assert.NoError(t, err)
+	reopened.db.log.close()
+	reopened = openStore(t, c)
+	assert.Equal(t, 2, len(reopened.db.projects))
+	assert.NoError(t, reopened.Close())
+}
+
+func TestStore_CorruptRecord(t *testing.T) {
+	dir := tempDir(t)
+	c := config.InMemory{Dir: dir, Fsync: config.FsyncAlways}
+	s := openStore(t, c)
+	populate(t, s)
+	s.db.log.close()
+
+	// Flipping a byte of the last record payload.
+	path := filepath.Join(dir, walFile)
+	data, _ := ioutil.ReadFile(path)
+	data[len(data)-2] ^= 0xff
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openStore(t, c)
+	defer reopened.Close()
+
+	assert.Equal(t, 1, len(reopened.db.tasks))
+	assert.Equal(t, 0, len(reopened.db.comments), "record with wrong checksum is dropped")
+}
+
+func TestStore_RecordTooLarge(t *testing.T) {
+	dir := tempDir(t)
+	c := config.InMemory{Dir: dir, Fsync: config.FsyncAlways}
+	s := openStore(t, c)
+	populate(t, s)
+
+	p := model.Project{Name: "Project", Description: strings.Repeat("d", maxRecordSize)}
+	_, err := s.Projects().Create(context.Background(), p)
+
+	assert.Equal(t, errRecordTooLarge, err)
+	assert.Equal(t, 1, len(s.db.projects), "record isn't applied")
+	s.db.log.close()
+	reopened := openStore(t, c)
+	assert.Equal(t, 1, len(reopened.db.projects))
+	assert.NoError(t, reopened.Close())
+}
+
+func TestStore_TruncatedTransaction(t *testing.T) {
+	dir := tempDir(t)
+	c := config.InMemory{Dir: dir, Fsync: config.FsyncAlways}
+	s := openStore(t, c)
+	comment := populate(t, s)
+	path := filepath.Join(dir, walFile)
+	full, _ := ioutil.ReadFile(path)
+	records := s.db.log.records
+
+	err := s.InTx(context.Background(), func(tx store.Tx) error {
+		p, err := tx.Projects().Create(context.Background(), model.Project{Name: "Lost project"})
+		if err != nil {
+			return err
+		}
+		col, err := tx.Columns().Create(context.Background(), model.Column{Name: "Column", Index: 1, ProjectID: p.ID})
+		if err != nil {
+			return err
+		}
+		_, err = tx.Tasks().Create(context.Background(), model.Task{Name: "Task", Index: 1, ColumnID: col.ID})
+		if err != nil {
+			return err
+		}
+
+		return tx.Comments().DeleteByID(context.Background(), comment.ID)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, records+1, s.db.log.records, "transaction is written as a single record")
+	s.db.log.close()
+
+	// Crash in the middle of writing the transaction drops all of its changes.
+	withTx, _ := ioutil.ReadFile(path)
+	if err := ioutil.WriteFile(path, withTx[:len(withTx)-1], 0600); err != nil {
+		t.Fatal(err)
+	}
+	reopened := openStore(t, c)
+	assert.Equal(t, 1, len(reopened.db.projects))
+	assert.Equal(t, 1, len(reopened.db.tasks))
+	assert.Equal(t, 1, len(reopened.db.comments))
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, full, data)
+	reopened.db.log.close()
+
+	// The complete transaction is replayed.
+	if err := ioutil.WriteFile(path, withTx, 0600); err != nil {
+		t.Fatal(err)
+	}
+	reopened = openStore(t, c)
+	assert.Equal(t, 2, len(reopened.db.projects))
+	assert.Equal(t, 2, len(reopened.db.tasks))
+	assert.Equal(t, 0, len(reopened.db.comments))
+	assert.NoError(t, reopened.Close())
+}
+
+func TestStore_FailedWrite(t *testing.T) {
+	dir := tempDir(t)
+	c := config.InMemory{Dir: dir, Fsync: config.FsyncAlways}
+	s := openStore(t, c)
+	populate(t, s)
+
+	// Write failed after a part of the record.
+	offset, err := s.db.log.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.db.log.f.Write([]byte{0, 0, 1})
+	writeErr := errors.New("write failed")
+	assert.Equal(t, writeErr, s.db.log.discard(offset, writeErr))
+	_, err = s.Projects().Create(context.Background(), model.Project{Name: "Project 2"})
+	assert.NoError(t, err)
+	s.db.log.close()
+
+	reopened := openStore(t, c)
+	assert.Equal(t, 2, len(reopened.db.projects), "records after the failed write are kept")
+	reopened.db.log.close()
+
+	// Log which can't be truncated isn't written anymore.
+	reopened = openStore(t, c)
+	f := reopened.db.log.f
+	reopened.db.log.f, err = os.Open(filepath.Join(dir, walFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = reopened.Projects().Create(context.Background(), model.Project{Name: "Project 3"})
+	assert.Error(t, err)
+	_, err = reopened.Projects().Create(context.Background(), model.Project{Name: "Project 3"})
+	assert.Equal(t, reopened.db.log.broken, err)
+	assert.Equal(t, 2, len(reopened.db.projects))
+	reopened.db.log.f.Close()
+	f.Close()
+}
+
+func TestStore_FailedSnapshot(t *testing.T) {
+	dir := tempDir(t)
+	c := config.InMemory{Dir: dir, Fsync: config.FsyncAlways, SnapshotEvery: 1}
+	s := openStore(t, c)
+	var out bytes.Buffer
+	s.SetLogger(logger.New(&out, logger.ErrorLevel, logger.TextFormat))
+	// Snapshot can't replace a directory.
+	if err := os.MkdirAll(filepath.Join(dir, snapshotFile, "dir"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := s.Projects().Create(context.Background(), model.Project{Name: "Project"})
+
+	assert.NoError(t, err, "committed mutation doesn't fail")
+	assert.Contains(t, s.db.projects, p.ID)
+	assert.Contains(t, out.String(), "couldn't snapshot in memory store")
+	s.db.log.close()
+	os.RemoveAll(filepath.Join(dir, snapshotFile))
+	reopened := openStore(t, c)
+	assert.Equal(t, 1, len(reopened.db.projects))
+	reopened.db.log.close()
+}
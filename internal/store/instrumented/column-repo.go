@@ -0,0 +1,69 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// columnRepo is the instrumented column repository.
+type columnRepo struct {
+	repo store.ColumnRepo
+	s    *Store
+}
+
+// GetByProjectID implements store.ColumnRepo.
+func (r *columnRepo) GetByProjectID(ctx context.Context, id int) ([]model.Column, error) {
+	start := time.Now()
+	res, err := r.repo.GetByProjectID(ctx, id)
+	r.s.observe("column", "GetByProjectID", start, err)
+
+	return res, err
+}
+
+// Create implements store.ColumnRepo.
+func (r *columnRepo) Create(ctx context.Context, c model.Column) (model.Column, error) {
+	start := time.Now()
+	res, err := r.repo.Create(ctx, c)
+	r.s.observe("column", "Create", start, err)
+
+	return res, err
+}
+
+// GetByID implements store.ColumnRepo.
+func (r *columnRepo) GetByID(ctx context.Context, id int) (model.Column, error) {
+	start := time.Now()
+	res, err := r.repo.GetByID(ctx, id)
+	r.s.observe("column", "GetByID", start, err)
+
+	return res, err
+}
+
+// GetByIndexAndProjectID implements store.ColumnRepo.
+func (r *columnRepo) GetByIndexAndProjectID(ctx context.Context, index, id int) (model.Column, error) {
+	start := time.Now()
+	res, err := r.repo.GetByIndexAndProjectID(ctx, index, id)
+	r.s.observe("column", "GetByIndexAndProjectID", start, err)
+
+	return res, err
+}
+
+// Update implements store.ColumnRepo.
+func (r *columnRepo) Update(ctx context.Context, c model.Column) (model.Column, error) {
+	start := time.Now()
+	res, err := r.repo.Update(ctx, c)
+	r.s.observe("column", "Update", start, err)
+
+	return res, err
+}
+
+// DeleteByID implements store.ColumnRepo.
+func (r *columnRepo) DeleteByID(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.repo.DeleteByID(ctx, id)
+	r.s.observe("column", "DeleteByID", start, err)
+
+	return err
+}
@@ -0,0 +1,35 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// columnSnapshotRepo is the instrumented column snapshot repository.
+type columnSnapshotRepo struct {
+	repo store.ColumnSnapshotRepo
+	s    *Store
+}
+
+// Save implements store.ColumnSnapshotRepo.
+func (r *columnSnapshotRepo) Save(ctx context.Context, cs model.ColumnSnapshot) (model.ColumnSnapshot, error) {
+	start := time.Now()
+	res, err := r.repo.Save(ctx, cs)
+	r.s.observe("columnSnapshot", "Save", start, err)
+
+	return res, err
+}
+
+// GetByProjectID implements store.ColumnSnapshotRepo.
+func (r *columnSnapshotRepo) GetByProjectID(
+	ctx context.Context, id int, from, to time.Time,
+) ([]model.ColumnSnapshot, error) {
+	start := time.Now()
+	res, err := r.repo.GetByProjectID(ctx, id, from, to)
+	r.s.observe("columnSnapshot", "GetByProjectID", start, err)
+
+	return res, err
+}
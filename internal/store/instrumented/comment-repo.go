@@ -0,0 +1,69 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// commentRepo is the instrumented comment repository.
+type commentRepo struct {
+	repo store.CommentRepo
+	s    *Store
+}
+
+// GetAll implements store.CommentRepo.
+func (r *commentRepo) GetAll(ctx context.Context) ([]model.Comment, error) {
+	start := time.Now()
+	res, err := r.repo.GetAll(ctx)
+	r.s.observe("comment", "GetAll", start, err)
+
+	return res, err
+}
+
+// GetByTaskID implements store.CommentRepo.
+func (r *commentRepo) GetByTaskID(ctx context.Context, id int) ([]model.Comment, error) {
+	start := time.Now()
+	res, err := r.repo.GetByTaskID(ctx, id)
+	r.s.observe("comment", "GetByTaskID", start, err)
+
+	return res, err
+}
+
+// Create implements store.CommentRepo.
+func (r *commentRepo) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
+	start := time.Now()
+	res, err := r.repo.Create(ctx, c)
+	r.s.observe("comment", "Create", start, err)
+
+	return res, err
+}
+
+// GetByID implements store.CommentRepo.
+func (r *commentRepo) GetByID(ctx context.Context, id int) (model.Comment, error) {
+	start := time.Now()
+	res, err := r.repo.GetByID(ctx, id)
+	r.s.observe("comment", "GetByID", start, err)
+
+	return res, err
+}
+
+// Update implements store.CommentRepo.
+func (r *commentRepo) Update(ctx context.Context, c model.Comment) (model.Comment, error) {
+	start := time.Now()
+	res, err := r.repo.Update(ctx, c)
+	r.s.observe("comment", "Update", start, err)
+
+	return res, err
+}
+
+// DeleteByID implements store.CommentRepo.
+func (r *commentRepo) DeleteByID(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.repo.DeleteByID(ctx, id)
+	r.s.observe("comment", "DeleteByID", start, err)
+
+	return err
+}
@@ -0,0 +1,15 @@
+package instrumented
+
+import (
+	"testing"
+
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/inmem"
+	"github.com/imarrche/tasker/internal/store/storetest"
+)
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		return New(inmem.NewStore(), NewLatencyHistogram())
+	})
+}
@@ -0,0 +1,2 @@
+// Package instrumented provides store decorator recording latency of store operations.
+package instrumented
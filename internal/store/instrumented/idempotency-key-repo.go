@@ -0,0 +1,60 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// idempotencyKeyRepo is the instrumented idempotency key repository.
+type idempotencyKeyRepo struct {
+	repo store.IdempotencyKeyRepo
+	s    *Store
+}
+
+// Create implements store.IdempotencyKeyRepo.
+func (r *idempotencyKeyRepo) Create(ctx context.Context, i model.IdempotencyKey) (model.IdempotencyKey, error) {
+	start := time.Now()
+	res, err := r.repo.Create(ctx, i)
+	r.s.observe("idempotencyKey", "Create", start, err)
+
+	return res, err
+}
+
+// GetByKey implements store.IdempotencyKeyRepo.
+func (r *idempotencyKeyRepo) GetByKey(ctx context.Context, key string) (model.IdempotencyKey, error) {
+	start := time.Now()
+	res, err := r.repo.GetByKey(ctx, key)
+	r.s.observe("idempotencyKey", "GetByKey", start, err)
+
+	return res, err
+}
+
+// Update implements store.IdempotencyKeyRepo.
+func (r *idempotencyKeyRepo) Update(ctx context.Context, i model.IdempotencyKey) (model.IdempotencyKey, error) {
+	start := time.Now()
+	res, err := r.repo.Update(ctx, i)
+	r.s.observe("idempotencyKey", "Update", start, err)
+
+	return res, err
+}
+
+// Delete implements store.IdempotencyKeyRepo.
+func (r *idempotencyKeyRepo) Delete(ctx context.Context, i model.IdempotencyKey) error {
+	start := time.Now()
+	err := r.repo.Delete(ctx, i)
+	r.s.observe("idempotencyKey", "Delete", start, err)
+
+	return err
+}
+
+// DeleteCreatedBefore implements store.IdempotencyKeyRepo.
+func (r *idempotencyKeyRepo) DeleteCreatedBefore(ctx context.Context, t time.Time) error {
+	start := time.Now()
+	err := r.repo.DeleteCreatedBefore(ctx, t)
+	r.s.observe("idempotencyKey", "DeleteCreatedBefore", start, err)
+
+	return err
+}
@@ -0,0 +1,69 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// projectRepo is the instrumented project repository.
+type projectRepo struct {
+	repo store.ProjectRepo
+	s    *Store
+}
+
+// GetAll implements store.ProjectRepo.
+func (r *projectRepo) GetAll(ctx context.Context) ([]model.Project, error) {
+	start := time.Now()
+	res, err := r.repo.GetAll(ctx)
+	r.s.observe("project", "GetAll", start, err)
+
+	return res, err
+}
+
+// GetCounts implements store.ProjectRepo.
+func (r *projectRepo) GetCounts(ctx context.Context) ([]model.ProjectCounts, error) {
+	start := time.Now()
+	res, err := r.repo.GetCounts(ctx)
+	r.s.observe("project", "GetCounts", start, err)
+
+	return res, err
+}
+
+// Create implements store.ProjectRepo.
+func (r *projectRepo) Create(ctx context.Context, p model.Project) (model.Project, error) {
+	start := time.Now()
+	res, err := r.repo.Create(ctx, p)
+	r.s.observe("project", "Create", start, err)
+
+	return res, err
+}
+
+// GetByID implements store.ProjectRepo.
+func (r *projectRepo) GetByID(ctx context.Context, id int) (model.Project, error) {
+	start := time.Now()
+	res, err := r.repo.GetByID(ctx, id)
+	r.s.observe("project", "GetByID", start, err)
+
+	return res, err
+}
+
+// Update implements store.ProjectRepo.
+func (r *projectRepo) Update(ctx context.Context, p model.Project) (model.Project, error) {
+	start := time.Now()
+	res, err := r.repo.Update(ctx, p)
+	r.s.observe("project", "Update", start, err)
+
+	return res, err
+}
+
+// DeleteByID implements store.ProjectRepo.
+func (r *projectRepo) DeleteByID(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.repo.DeleteByID(ctx, id)
+	r.s.observe("project", "DeleteByID", start, err)
+
+	return err
+}
@@ -0,0 +1,177 @@
+package instrumented
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/imarrche/tasker/internal/metrics"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// Store is the store decorator recording latency of every repository method.
+type Store struct {
+	store.Store
+	latency     *metrics.HistogramVec
+	once        sync.Once
+	projectRepo *projectRepo
+	columnRepo  *columnRepo
+	taskRepo    *taskRepo
+	commentRepo *commentRepo
+
+	transitionRepo     *transitionRepo
+	columnSnapshotRepo *columnSnapshotRepo
+	taskLinkRepo       *taskLinkRepo
+	idempotencyKeyRepo *idempotencyKeyRepo
+}
+
+// txRepos are the instrumented repositories bound to a transaction.
+type txRepos struct {
+	projectRepo        *projectRepo
+	columnRepo         *columnRepo
+	taskRepo           *taskRepo
+	commentRepo        *commentRepo
+	transitionRepo     *transitionRepo
+	columnSnapshotRepo *columnSnapshotRepo
+	taskLinkRepo       *taskLinkRepo
+}
+
+// NewLatencyHistogram creates the histogram New expects.
+func NewLatencyHistogram() *metrics.HistogramVec {
+	return metrics.NewHistogramVec(
+		"tasker_store_operation_duration_seconds",
+		"Latency of store operations by repository method.",
+		nil,
+		"repo", "method", "status",
+	)
+}
+
+// New creates and returns a new Store instance decorating s.
+func New(s store.Store, latency *metrics.HistogramVec) *Store {
+	return &Store{Store: s, latency: latency}
+}
+
+// repos creates instrumented repositories on first use, as decorated store creates its
+// repositories when it's opened.
+func (s *Store) repos() {
+	s.once.Do(func() {
+		s.projectRepo = &projectRepo{repo: s.Store.Projects(), s: s}
+		s.columnRepo = &columnRepo{repo: s.Store.Columns(), s: s}
+		s.taskRepo = &taskRepo{repo: s.Store.Tasks(), s: s}
+		s.commentRepo = &commentRepo{repo: s.Store.Comments(), s: s}
+		s.transitionRepo = &transitionRepo{repo: s.Store.Transitions(), s: s}
+		s.columnSnapshotRepo = &columnSnapshotRepo{repo: s.Store.ColumnSnapshots(), s: s}
+		s.taskLinkRepo = &taskLinkRepo{repo: s.Store.TaskLinks(), s: s}
+		s.idempotencyKeyRepo = &idempotencyKeyRepo{repo: s.Store.IdempotencyKeys(), s: s}
+	})
+}
+
+// Projects returns the instrumented project repository.
+func (s *Store) Projects() store.ProjectRepo {
+	s.repos()
+	return s.projectRepo
+}
+
+// Columns returns the instrumented column repository.
+func (s *Store) Columns() store.ColumnRepo {
+	s.repos()
+	return s.columnRepo
+}
+
+// Tasks returns the instrumented task repository.
+func (s *Store) Tasks() store.TaskRepo {
+	s.repos()
+	return s.taskRepo
+}
+
+// Comments returns the instrumented comment repository.
+func (s *Store) Comments() store.CommentRepo {
+	s.repos()
+	return s.commentRepo
+}
+
+// Transitions returns the instrumented transition repository.
+func (s *Store) Transitions() store.TransitionRepo {
+	s.repos()
+	return s.transitionRepo
+}
+
+// ColumnSnapshots returns the instrumented column snapshot repository.
+func (s *Store) ColumnSnapshots() store.ColumnSnapshotRepo {
+	s.repos()
+	return s.columnSnapshotRepo
+}
+
+// TaskLinks returns the instrumented task link repository.
+func (s *Store) TaskLinks() store.TaskLinkRepo {
+	s.repos()
+	return s.taskLinkRepo
+}
+
+// IdempotencyKeys returns the instrumented idempotency key repository.
+func (s *Store) IdempotencyKeys() store.IdempotencyKeyRepo {
+	s.repos()
+	return s.idempotencyKeyRepo
+}
+
+// InTx runs fn in a transaction of the decorated store with instrumented repositories.
+func (s *Store) InTx(ctx context.Context, fn func(store.Tx) error) error {
+	return s.Store.InTx(ctx, s.instrumentTx(fn))
+}
+
+// InSerializableTx runs fn in a serializable transaction of the decorated store with
+// instrumented repositories.
+func (s *Store) InSerializableTx(ctx context.Context, fn func(store.Tx) error) error {
+	return s.Store.InSerializableTx(ctx, s.instrumentTx(fn))
+}
+
+// instrumentTx returns the function calling fn with instrumented repositories of the
+// transaction.
+func (s *Store) instrumentTx(fn func(store.Tx) error) func(store.Tx) error {
+	return func(tx store.Tx) error {
+		return fn(&txRepos{
+			projectRepo:        &projectRepo{repo: tx.Projects(), s: s},
+			columnRepo:         &columnRepo{repo: tx.Columns(), s: s},
+			taskRepo:           &taskRepo{repo: tx.Tasks(), s: s},
+			commentRepo:        &commentRepo{repo: tx.Comments(), s: s},
+			transitionRepo:     &transitionRepo{repo: tx.Transitions(), s: s},
+			columnSnapshotRepo: &columnSnapshotRepo{repo: tx.ColumnSnapshots(), s: s},
+			taskLinkRepo:       &taskLinkRepo{repo: tx.TaskLinks(), s: s},
+		})
+	}
+}
+
+// Projects returns the instrumented project repository.
+func (r *txRepos) Projects() store.ProjectRepo { return r.projectRepo }
+
+// Columns returns the instrumented column repository.
+func (r *txRepos) Columns() store.ColumnRepo { return r.columnRepo }
+
+// Tasks returns the instrumented task repository.
+func (r *txRepos) Tasks() store.TaskRepo { return r.taskRepo }
+
+// Comments returns the instrumented comment repository.
+func (r *txRepos) Comments() store.CommentRepo { return r.commentRepo }
+
+// Transitions returns the instrumented transition repository.
+func (r *txRepos) Transitions() store.TransitionRepo { return r.transitionRepo }
+
+// ColumnSnapshots returns the instrumented column snapshot repository.
+func (r *txRepos) ColumnSnapshots() store.ColumnSnapshotRepo { return r.columnSnapshotRepo }
+
+// TaskLinks returns the instrumented task link repository.
+func (r *txRepos) TaskLinks() store.TaskLinkRepo { return r.taskLinkRepo }
+
+// observe records the latency of repository method started at start.
+func (s *Store) observe(repo, method string, start time.Time, err error) {
+	status := "ok"
+	if err == store.ErrNotFound {
+		status = "not_found"
+	} else if err == store.ErrConflict {
+		status = "conflict"
+	} else if err != nil {
+		status = "error"
+	}
+
+	s.latency.Observe(time.Since(start).Seconds(), repo, method, status)
+}
@@ -0,0 +1,33 @@
+package instrumented
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/inmem"
+)
+
+func TestStore_Observe(t *testing.T) {
+	latency := NewLatencyHistogram()
+	s := New(inmem.TestStoreWithFixtures(), latency)
+	ctx := context.Background()
+
+	p, err := s.Projects().GetByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, p.ID)
+	_, err = s.Projects().GetByID(ctx, 100)
+	assert.Equal(t, store.ErrNotFound, err)
+	_, err = s.Tasks().Create(ctx, model.Task{Name: "Task", ColumnID: 100})
+	assert.Error(t, err)
+	_, err = s.Comments().GetByTaskID(ctx, 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(1), latency.Count("project", "GetByID", "ok"))
+	assert.Equal(t, uint64(1), latency.Count("project", "GetByID", "not_found"))
+	assert.Equal(t, uint64(1), latency.Count("task", "Create", "error"))
+	assert.Equal(t, uint64(1), latency.Count("comment", "GetByTaskID", "ok"))
+}
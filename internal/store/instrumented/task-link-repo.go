@@ -0,0 +1,42 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// taskLinkRepo is the instrumented task link repository.
+type taskLinkRepo struct {
+	repo store.TaskLinkRepo
+	s    *Store
+}
+
+// GetByTaskID implements store.TaskLinkRepo.
+func (r *taskLinkRepo) GetByTaskID(ctx context.Context, id int) ([]model.TaskLink, error) {
+	start := time.Now()
+	res, err := r.repo.GetByTaskID(ctx, id)
+	r.s.observe("taskLink", "GetByTaskID", start, err)
+
+	return res, err
+}
+
+// Create implements store.TaskLinkRepo.
+func (r *taskLinkRepo) Create(ctx context.Context, l model.TaskLink) (model.TaskLink, error) {
+	start := time.Now()
+	res, err := r.repo.Create(ctx, l)
+	r.s.observe("taskLink", "Create", start, err)
+
+	return res, err
+}
+
+// Delete implements store.TaskLinkRepo.
+func (r *taskLinkRepo) Delete(ctx context.Context, l model.TaskLink) error {
+	start := time.Now()
+	err := r.repo.Delete(ctx, l)
+	r.s.observe("taskLink", "Delete", start, err)
+
+	return err
+}
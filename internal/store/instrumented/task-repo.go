@@ -0,0 +1,78 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// taskRepo is the instrumented task repository.
+type taskRepo struct {
+	repo store.TaskRepo
+	s    *Store
+}
+
+// GetAll implements store.TaskRepo.
+func (r *taskRepo) GetAll(ctx context.Context) ([]model.Task, error) {
+	start := time.Now()
+	res, err := r.repo.GetAll(ctx)
+	r.s.observe("task", "GetAll", start, err)
+
+	return res, err
+}
+
+// GetByColumnID implements store.TaskRepo.
+func (r *taskRepo) GetByColumnID(ctx context.Context, id int) ([]model.Task, error) {
+	start := time.Now()
+	res, err := r.repo.GetByColumnID(ctx, id)
+	r.s.observe("task", "GetByColumnID", start, err)
+
+	return res, err
+}
+
+// Create implements store.TaskRepo.
+func (r *taskRepo) Create(ctx context.Context, t model.Task) (model.Task, error) {
+	start := time.Now()
+	res, err := r.repo.Create(ctx, t)
+	r.s.observe("task", "Create", start, err)
+
+	return res, err
+}
+
+// GetByID implements store.TaskRepo.
+func (r *taskRepo) GetByID(ctx context.Context, id int) (model.Task, error) {
+	start := time.Now()
+	res, err := r.repo.GetByID(ctx, id)
+	r.s.observe("task", "GetByID", start, err)
+
+	return res, err
+}
+
+// GetByIndexAndColumnID implements store.TaskRepo.
+func (r *taskRepo) GetByIndexAndColumnID(ctx context.Context, index, id int) (model.Task, error) {
+	start := time.Now()
+	res, err := r.repo.GetByIndexAndColumnID(ctx, index, id)
+	r.s.observe("task", "GetByIndexAndColumnID", start, err)
+
+	return res, err
+}
+
+// Update implements store.TaskRepo.
+func (r *taskRepo) Update(ctx context.Context, t model.Task) (model.Task, error) {
+	start := time.Now()
+	res, err := r.repo.Update(ctx, t)
+	r.s.observe("task", "Update", start, err)
+
+	return res, err
+}
+
+// DeleteByID implements store.TaskRepo.
+func (r *taskRepo) DeleteByID(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.repo.DeleteByID(ctx, id)
+	r.s.observe("task", "DeleteByID", start, err)
+
+	return err
+}
@@ -0,0 +1,42 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// transitionRepo is the instrumented transition repository.
+type transitionRepo struct {
+	repo store.TransitionRepo
+	s    *Store
+}
+
+// Create implements store.TransitionRepo.
+func (r *transitionRepo) Create(ctx context.Context, t model.Transition) (model.Transition, error) {
+	start := time.Now()
+	res, err := r.repo.Create(ctx, t)
+	r.s.observe("transition", "Create", start, err)
+
+	return res, err
+}
+
+// GetByTaskID implements store.TransitionRepo.
+func (r *transitionRepo) GetByTaskID(ctx context.Context, id int) ([]model.Transition, error) {
+	start := time.Now()
+	res, err := r.repo.GetByTaskID(ctx, id)
+	r.s.observe("transition", "GetByTaskID", start, err)
+
+	return res, err
+}
+
+// Analytics implements store.TransitionRepo.
+func (r *transitionRepo) Analytics(ctx context.Context, id int, from, to time.Time) (model.Analytics, error) {
+	start := time.Now()
+	res, err := r.repo.Analytics(ctx, id, from, to)
+	r.s.observe("transition", "Analytics", start, err)
+
+	return res, err
+}
@@ -1,53 +1,127 @@
 package store
 
-import "github.com/imarrche/tasker/internal/model"
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+)
 
 //go:generate mockgen -source=interface.go -destination=mocks/mock.go
 
 // Store is the interface all stores must implement.
 type Store interface {
 	Open() error
+	Ping(context.Context) error
 	Projects() ProjectRepo
 	Columns() ColumnRepo
 	Tasks() TaskRepo
 	Comments() CommentRepo
+	Transitions() TransitionRepo
+	ColumnSnapshots() ColumnSnapshotRepo
+	TaskLinks() TaskLinkRepo
+	IdempotencyKeys() IdempotencyKeyRepo
+	InTx(context.Context, func(Tx) error) error
+	InSerializableTx(context.Context, func(Tx) error) error
 	Close() error
 }
 
+// Tx is the set of repositories bound to a store transaction. The transaction passed to
+// Store.InTx is committed if the function returns nil and rolled back otherwise.
+type Tx interface {
+	Projects() ProjectRepo
+	Columns() ColumnRepo
+	Tasks() TaskRepo
+	Comments() CommentRepo
+	Transitions() TransitionRepo
+	ColumnSnapshots() ColumnSnapshotRepo
+	TaskLinks() TaskLinkRepo
+}
+
 // ProjectRepo is the interface all project repositories must implement.
 type ProjectRepo interface {
-	GetAll() ([]model.Project, error)
-	Create(model.Project) (model.Project, error)
-	GetByID(int) (model.Project, error)
-	Update(model.Project) (model.Project, error)
-	DeleteByID(int) error
+	GetAll(context.Context) ([]model.Project, error)
+	GetCounts(context.Context) ([]model.ProjectCounts, error)
+	Create(context.Context, model.Project) (model.Project, error)
+	GetByID(context.Context, int) (model.Project, error)
+	Update(context.Context, model.Project) (model.Project, error)
+	DeleteByID(context.Context, int) error
 }
 
 // ColumnRepo is the interface all column repositories must implement.
 type ColumnRepo interface {
-	GetByProjectID(int) ([]model.Column, error)
-	Create(model.Column) (model.Column, error)
-	GetByID(int) (model.Column, error)
-	GetByIndexAndProjectID(int, int) (model.Column, error)
-	Update(model.Column) (model.Column, error)
-	DeleteByID(int) error
+	GetByProjectID(context.Context, int) ([]model.Column, error)
+	Create(context.Context, model.Column) (model.Column, error)
+	GetByID(context.Context, int) (model.Column, error)
+	GetByIndexAndProjectID(context.Context, int, int) (model.Column, error)
+	Update(context.Context, model.Column) (model.Column, error)
+	DeleteByID(context.Context, int) error
 }
 
 // TaskRepo is the interface all task repositories must implement.
 type TaskRepo interface {
-	GetByColumnID(int) ([]model.Task, error)
-	Create(model.Task) (model.Task, error)
-	GetByID(int) (model.Task, error)
-	GetByIndexAndColumnID(int, int) (model.Task, error)
-	Update(model.Task) (model.Task, error)
-	DeleteByID(int) error
+	GetAll(context.Context) ([]model.Task, error)
+	GetByColumnID(context.Context, int) ([]model.Task, error)
+	Create(context.Context, model.Task) (model.Task, error)
+	GetByID(context.Context, int) (model.Task, error)
+	GetByIndexAndColumnID(context.Context, int, int) (model.Task, error)
+	Update(context.Context, model.Task) (model.Task, error)
+	DeleteByID(context.Context, int) error
 }
 
 // CommentRepo is the interface all comment repositories must implement.
 type CommentRepo interface {
-	GetByTaskID(int) ([]model.Comment, error)
-	Create(model.Comment) (model.Comment, error)
-	GetByID(int) (model.Comment, error)
-	Update(model.Comment) (model.Comment, error)
-	DeleteByID(int) error
+	GetAll(context.Context) ([]model.Comment, error)
+	GetByTaskID(context.Context, int) ([]model.Comment, error)
+	Create(context.Context, model.Comment) (model.Comment, error)
+	GetByID(context.Context, int) (model.Comment, error)
+	Update(context.Context, model.Comment) (model.Comment, error)
+	DeleteByID(context.Context, int) error
+}
+
+// TransitionRepo is the interface all transition repositories must implement.
+// Transitions are deleted with their task or project.
+type TransitionRepo interface {
+	Create(context.Context, model.Transition) (model.Transition, error)
+	GetByTaskID(context.Context, int) ([]model.Transition, error)
+	// Analytics computes metrics of the project with specific ID over the period from
+	// the first time inclusive to the second one exclusive. Only tasks which aren't
+	// deleted are counted, and lead time only of those with transitions.
+	Analytics(context.Context, int, time.Time, time.Time) (model.Analytics, error)
+}
+
+// ColumnSnapshotRepo is the interface all column snapshot repositories must implement.
+// Snapshots are deleted with their project but not with their column.
+type ColumnSnapshotRepo interface {
+	// Save creates the snapshot or replaces the one of the same column and day.
+	Save(context.Context, model.ColumnSnapshot) (model.ColumnSnapshot, error)
+	// GetByProjectID returns snapshots of the project with specific ID from the first
+	// day to the second one inclusive ordered by day and column ID.
+	GetByProjectID(context.Context, int, time.Time, time.Time) ([]model.ColumnSnapshot, error)
+}
+
+// TaskLinkRepo is the interface all task link repositories must implement.
+// Links are deleted with either of their tasks.
+type TaskLinkRepo interface {
+	// GetByTaskID returns links the task with specific ID takes either side of, ordered
+	// by blocker and blocked task IDs.
+	GetByTaskID(context.Context, int) ([]model.TaskLink, error)
+	// Create returns ErrConflict if the link already exists and ErrInvalidReference if
+	// either of its tasks doesn't.
+	Create(context.Context, model.TaskLink) (model.TaskLink, error)
+	Delete(context.Context, model.TaskLink) error
+}
+
+// IdempotencyKeyRepo is the interface all idempotency key repositories must implement.
+type IdempotencyKeyRepo interface {
+	// Create returns ErrConflict if the key already exists. The returned key has the
+	// creation time as it's stored.
+	Create(context.Context, model.IdempotencyKey) (model.IdempotencyKey, error)
+	GetByKey(context.Context, string) (model.IdempotencyKey, error)
+	// Update saves the response of the key and Delete deletes it only if the stored key
+	// has the same fingerprint and creation time, so a request can't change the key
+	// after another one took it over. Both return ErrNotFound otherwise.
+	Update(context.Context, model.IdempotencyKey) (model.IdempotencyKey, error)
+	Delete(context.Context, model.IdempotencyKey) error
+	DeleteCreatedBefore(context.Context, time.Time) error
 }
@@ -5,10 +5,12 @@
 package mock_store
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/imarrche/tasker/internal/model"
 	store "github.com/imarrche/tasker/internal/store"
 	reflect "reflect"
+	time "time"
 )
 
 // MockStore is a mock of Store interface
@@ -48,6 +50,20 @@ func (mr *MockStoreMockRecorder) Open() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockStore)(nil).Open))
 }
 
+// Ping mocks base method
+func (m *MockStore) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping
+func (mr *MockStoreMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStore)(nil).Ping), arg0)
+}
+
 // Projects mocks base method
 func (m *MockStore) Projects() store.ProjectRepo {
 	m.ctrl.T.Helper()
@@ -104,6 +120,90 @@ func (mr *MockStoreMockRecorder) Comments() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Comments", reflect.TypeOf((*MockStore)(nil).Comments))
 }
 
+// Transitions mocks base method
+func (m *MockStore) Transitions() store.TransitionRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transitions")
+	ret0, _ := ret[0].(store.TransitionRepo)
+	return ret0
+}
+
+// Transitions indicates an expected call of Transitions
+func (mr *MockStoreMockRecorder) Transitions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transitions", reflect.TypeOf((*MockStore)(nil).Transitions))
+}
+
+// ColumnSnapshots mocks base method
+func (m *MockStore) ColumnSnapshots() store.ColumnSnapshotRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ColumnSnapshots")
+	ret0, _ := ret[0].(store.ColumnSnapshotRepo)
+	return ret0
+}
+
+// ColumnSnapshots indicates an expected call of ColumnSnapshots
+func (mr *MockStoreMockRecorder) ColumnSnapshots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColumnSnapshots", reflect.TypeOf((*MockStore)(nil).ColumnSnapshots))
+}
+
+// TaskLinks mocks base method
+func (m *MockStore) TaskLinks() store.TaskLinkRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TaskLinks")
+	ret0, _ := ret[0].(store.TaskLinkRepo)
+	return ret0
+}
+
+// TaskLinks indicates an expected call of TaskLinks
+func (mr *MockStoreMockRecorder) TaskLinks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TaskLinks", reflect.TypeOf((*MockStore)(nil).TaskLinks))
+}
+
+// IdempotencyKeys mocks base method
+func (m *MockStore) IdempotencyKeys() store.IdempotencyKeyRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IdempotencyKeys")
+	ret0, _ := ret[0].(store.IdempotencyKeyRepo)
+	return ret0
+}
+
+// IdempotencyKeys indicates an expected call of IdempotencyKeys
+func (mr *MockStoreMockRecorder) IdempotencyKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IdempotencyKeys", reflect.TypeOf((*MockStore)(nil).IdempotencyKeys))
+}
+
+// InTx mocks base method
+func (m *MockStore) InTx(arg0 context.Context, arg1 func(store.Tx) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx
+func (mr *MockStoreMockRecorder) InTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockStore)(nil).InTx), arg0, arg1)
+}
+
+// InSerializableTx mocks base method
+func (m *MockStore) InSerializableTx(arg0 context.Context, arg1 func(store.Tx) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InSerializableTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InSerializableTx indicates an expected call of InSerializableTx
+func (mr *MockStoreMockRecorder) InSerializableTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InSerializableTx", reflect.TypeOf((*MockStore)(nil).InSerializableTx), arg0, arg1)
+}
+
 // Close mocks base method
 func (m *MockStore) Close() error {
 	m.ctrl.T.Helper()
@@ -118,6 +218,127 @@ func (mr *MockStoreMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStore)(nil).Close))
 }
 
+// MockTx is a mock of Tx interface
+type MockTx struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxMockRecorder
+}
+
+// MockTxMockRecorder is the mock recorder for MockTx
+type MockTxMockRecorder struct {
+	mock *MockTx
+}
+
+// NewMockTx creates a new mock instance
+func NewMockTx(ctrl *gomock.Controller) *MockTx {
+	mock := &MockTx{ctrl: ctrl}
+	mock.recorder = &MockTxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTx) EXPECT() *MockTxMockRecorder {
+	return m.recorder
+}
+
+// Projects mocks base method
+func (m *MockTx) Projects() store.ProjectRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Projects")
+	ret0, _ := ret[0].(store.ProjectRepo)
+	return ret0
+}
+
+// Projects indicates an expected call of Projects
+func (mr *MockTxMockRecorder) Projects() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Projects", reflect.TypeOf((*MockTx)(nil).Projects))
+}
+
+// Columns mocks base method
+func (m *MockTx) Columns() store.ColumnRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Columns")
+	ret0, _ := ret[0].(store.ColumnRepo)
+	return ret0
+}
+
+// Columns indicates an expected call of Columns
+func (mr *MockTxMockRecorder) Columns() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Columns", reflect.TypeOf((*MockTx)(nil).Columns))
+}
+
+// Tasks mocks base method
+func (m *MockTx) Tasks() store.TaskRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tasks")
+	ret0, _ := ret[0].(store.TaskRepo)
+	return ret0
+}
+
+// Tasks indicates an expected call of Tasks
+func (mr *MockTxMockRecorder) Tasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tasks", reflect.TypeOf((*MockTx)(nil).Tasks))
+}
+
+// Comments mocks base method
+func (m *MockTx) Comments() store.CommentRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Comments")
+	ret0, _ := ret[0].(store.CommentRepo)
+	return ret0
+}
+
+// Comments indicates an expected call of Comments
+func (mr *MockTxMockRecorder) Comments() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Comments", reflect.TypeOf((*MockTx)(nil).Comments))
+}
+
+// Transitions mocks base method
+func (m *MockTx) Transitions() store.TransitionRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transitions")
+	ret0, _ := ret[0].(store.TransitionRepo)
+	return ret0
+}
+
+// Transitions indicates an expected call of Transitions
+func (mr *MockTxMockRecorder) Transitions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transitions", reflect.TypeOf((*MockTx)(nil).Transitions))
+}
+
+// ColumnSnapshots mocks base method
+func (m *MockTx) ColumnSnapshots() store.ColumnSnapshotRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ColumnSnapshots")
+	ret0, _ := ret[0].(store.ColumnSnapshotRepo)
+	return ret0
+}
+
+// ColumnSnapshots indicates an expected call of ColumnSnapshots
+func (mr *MockTxMockRecorder) ColumnSnapshots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ColumnSnapshots", reflect.TypeOf((*MockTx)(nil).ColumnSnapshots))
+}
+
+// TaskLinks mocks base method
+func (m *MockTx) TaskLinks() store.TaskLinkRepo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TaskLinks")
+	ret0, _ := ret[0].(store.TaskLinkRepo)
+	return ret0
+}
+
+// TaskLinks indicates an expected call of TaskLinks
+func (mr *MockTxMockRecorder) TaskLinks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TaskLinks", reflect.TypeOf((*MockTx)(nil).TaskLinks))
+}
+
 // MockProjectRepo is a mock of ProjectRepo interface
 type MockProjectRepo struct {
 	ctrl     *gomock.Controller
@@ -142,77 +363,92 @@ func (m *MockProjectRepo) EXPECT() *MockProjectRepoMockRecorder {
 }
 
 // GetAll mocks base method
-func (m *MockProjectRepo) GetAll() ([]model.Project, error) {
+func (m *MockProjectRepo) GetAll(arg0 context.Context) ([]model.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", arg0)
 	ret0, _ := ret[0].([]model.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll
-func (mr *MockProjectRepoMockRecorder) GetAll() *gomock.Call {
+func (mr *MockProjectRepoMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProjectRepo)(nil).GetAll), arg0)
+}
+
+// GetCounts mocks base method
+func (m *MockProjectRepo) GetCounts(arg0 context.Context) ([]model.ProjectCounts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCounts", arg0)
+	ret0, _ := ret[0].([]model.ProjectCounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCounts indicates an expected call of GetCounts
+func (mr *MockProjectRepoMockRecorder) GetCounts(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProjectRepo)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCounts", reflect.TypeOf((*MockProjectRepo)(nil).GetCounts), arg0)
 }
 
 // Create mocks base method
-func (m *MockProjectRepo) Create(arg0 model.Project) (model.Project, error) {
+func (m *MockProjectRepo) Create(arg0 context.Context, arg1 model.Project) (model.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockProjectRepoMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProjectRepo)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProjectRepo)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockProjectRepo) GetByID(arg0 int) (model.Project, error) {
+func (m *MockProjectRepo) GetByID(arg0 context.Context, arg1 int) (model.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockProjectRepoMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectRepoMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProjectRepo)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProjectRepo)(nil).GetByID), arg0, arg1)
 }
 
 // Update mocks base method
-func (m *MockProjectRepo) Update(arg0 model.Project) (model.Project, error) {
+func (m *MockProjectRepo) Update(arg0 context.Context, arg1 model.Project) (model.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Project)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockProjectRepoMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProjectRepo)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProjectRepo)(nil).Update), arg0, arg1)
 }
 
 // DeleteByID mocks base method
-func (m *MockProjectRepo) DeleteByID(arg0 int) error {
+func (m *MockProjectRepo) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockProjectRepoMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockProjectRepoMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockProjectRepo)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockProjectRepo)(nil).DeleteByID), arg0, arg1)
 }
 
 // MockColumnRepo is a mock of ColumnRepo interface
@@ -239,92 +475,92 @@ func (m *MockColumnRepo) EXPECT() *MockColumnRepoMockRecorder {
 }
 
 // GetByProjectID mocks base method
-func (m *MockColumnRepo) GetByProjectID(arg0 int) ([]model.Column, error) {
+func (m *MockColumnRepo) GetByProjectID(arg0 context.Context, arg1 int) ([]model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByProjectID", arg0)
+	ret := m.ctrl.Call(m, "GetByProjectID", arg0, arg1)
 	ret0, _ := ret[0].([]model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByProjectID indicates an expected call of GetByProjectID
-func (mr *MockColumnRepoMockRecorder) GetByProjectID(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnRepoMockRecorder) GetByProjectID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProjectID", reflect.TypeOf((*MockColumnRepo)(nil).GetByProjectID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProjectID", reflect.TypeOf((*MockColumnRepo)(nil).GetByProjectID), arg0, arg1)
 }
 
 // Create mocks base method
-func (m *MockColumnRepo) Create(arg0 model.Column) (model.Column, error) {
+func (m *MockColumnRepo) Create(arg0 context.Context, arg1 model.Column) (model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockColumnRepoMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockColumnRepo)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockColumnRepo)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockColumnRepo) GetByID(arg0 int) (model.Column, error) {
+func (m *MockColumnRepo) GetByID(arg0 context.Context, arg1 int) (model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockColumnRepoMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnRepoMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockColumnRepo)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockColumnRepo)(nil).GetByID), arg0, arg1)
 }
 
 // GetByIndexAndProjectID mocks base method
-func (m *MockColumnRepo) GetByIndexAndProjectID(arg0, arg1 int) (model.Column, error) {
+func (m *MockColumnRepo) GetByIndexAndProjectID(arg0 context.Context, arg1, arg2 int) (model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIndexAndProjectID", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetByIndexAndProjectID", arg0, arg1, arg2)
 	ret0, _ := ret[0].(model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByIndexAndProjectID indicates an expected call of GetByIndexAndProjectID
-func (mr *MockColumnRepoMockRecorder) GetByIndexAndProjectID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockColumnRepoMockRecorder) GetByIndexAndProjectID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIndexAndProjectID", reflect.TypeOf((*MockColumnRepo)(nil).GetByIndexAndProjectID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIndexAndProjectID", reflect.TypeOf((*MockColumnRepo)(nil).GetByIndexAndProjectID), arg0, arg1, arg2)
 }
 
 // Update mocks base method
-func (m *MockColumnRepo) Update(arg0 model.Column) (model.Column, error) {
+func (m *MockColumnRepo) Update(arg0 context.Context, arg1 model.Column) (model.Column, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Column)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockColumnRepoMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockColumnRepo)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockColumnRepo)(nil).Update), arg0, arg1)
 }
 
 // DeleteByID mocks base method
-func (m *MockColumnRepo) DeleteByID(arg0 int) error {
+func (m *MockColumnRepo) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockColumnRepoMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockColumnRepoMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockColumnRepo)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockColumnRepo)(nil).DeleteByID), arg0, arg1)
 }
 
 // MockTaskRepo is a mock of TaskRepo interface
@@ -350,93 +586,108 @@ func (m *MockTaskRepo) EXPECT() *MockTaskRepoMockRecorder {
 	return m.recorder
 }
 
+// GetAll mocks base method
+func (m *MockTaskRepo) GetAll(arg0 context.Context) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", arg0)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockTaskRepoMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTaskRepo)(nil).GetAll), arg0)
+}
+
 // GetByColumnID mocks base method
-func (m *MockTaskRepo) GetByColumnID(arg0 int) ([]model.Task, error) {
+func (m *MockTaskRepo) GetByColumnID(arg0 context.Context, arg1 int) ([]model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByColumnID", arg0)
+	ret := m.ctrl.Call(m, "GetByColumnID", arg0, arg1)
 	ret0, _ := ret[0].([]model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByColumnID indicates an expected call of GetByColumnID
-func (mr *MockTaskRepoMockRecorder) GetByColumnID(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskRepoMockRecorder) GetByColumnID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByColumnID", reflect.TypeOf((*MockTaskRepo)(nil).GetByColumnID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByColumnID", reflect.TypeOf((*MockTaskRepo)(nil).GetByColumnID), arg0, arg1)
 }
 
 // Create mocks base method
-func (m *MockTaskRepo) Create(arg0 model.Task) (model.Task, error) {
+func (m *MockTaskRepo) Create(arg0 context.Context, arg1 model.Task) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockTaskRepoMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskRepo)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskRepo)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockTaskRepo) GetByID(arg0 int) (model.Task, error) {
+func (m *MockTaskRepo) GetByID(arg0 context.Context, arg1 int) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockTaskRepoMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskRepoMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTaskRepo)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTaskRepo)(nil).GetByID), arg0, arg1)
 }
 
 // GetByIndexAndColumnID mocks base method
-func (m *MockTaskRepo) GetByIndexAndColumnID(arg0, arg1 int) (model.Task, error) {
+func (m *MockTaskRepo) GetByIndexAndColumnID(arg0 context.Context, arg1, arg2 int) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIndexAndColumnID", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetByIndexAndColumnID", arg0, arg1, arg2)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByIndexAndColumnID indicates an expected call of GetByIndexAndColumnID
-func (mr *MockTaskRepoMockRecorder) GetByIndexAndColumnID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockTaskRepoMockRecorder) GetByIndexAndColumnID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIndexAndColumnID", reflect.TypeOf((*MockTaskRepo)(nil).GetByIndexAndColumnID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIndexAndColumnID", reflect.TypeOf((*MockTaskRepo)(nil).GetByIndexAndColumnID), arg0, arg1, arg2)
 }
 
 // Update mocks base method
-func (m *MockTaskRepo) Update(arg0 model.Task) (model.Task, error) {
+func (m *MockTaskRepo) Update(arg0 context.Context, arg1 model.Task) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockTaskRepoMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskRepo)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskRepo)(nil).Update), arg0, arg1)
 }
 
 // DeleteByID mocks base method
-func (m *MockTaskRepo) DeleteByID(arg0 int) error {
+func (m *MockTaskRepo) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockTaskRepoMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockTaskRepoMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockTaskRepo)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockTaskRepo)(nil).DeleteByID), arg0, arg1)
 }
 
 // MockCommentRepo is a mock of CommentRepo interface
@@ -462,76 +713,375 @@ func (m *MockCommentRepo) EXPECT() *MockCommentRepoMockRecorder {
 	return m.recorder
 }
 
+// GetAll mocks base method
+func (m *MockCommentRepo) GetAll(arg0 context.Context) ([]model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", arg0)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockCommentRepoMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCommentRepo)(nil).GetAll), arg0)
+}
+
 // GetByTaskID mocks base method
-func (m *MockCommentRepo) GetByTaskID(arg0 int) ([]model.Comment, error) {
+func (m *MockCommentRepo) GetByTaskID(arg0 context.Context, arg1 int) ([]model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByTaskID", arg0)
+	ret := m.ctrl.Call(m, "GetByTaskID", arg0, arg1)
 	ret0, _ := ret[0].([]model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByTaskID indicates an expected call of GetByTaskID
-func (mr *MockCommentRepoMockRecorder) GetByTaskID(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentRepoMockRecorder) GetByTaskID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTaskID", reflect.TypeOf((*MockCommentRepo)(nil).GetByTaskID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTaskID", reflect.TypeOf((*MockCommentRepo)(nil).GetByTaskID), arg0, arg1)
 }
 
 // Create mocks base method
-func (m *MockCommentRepo) Create(arg0 model.Comment) (model.Comment, error) {
+func (m *MockCommentRepo) Create(arg0 context.Context, arg1 model.Comment) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockCommentRepoMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentRepo)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentRepo)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockCommentRepo) GetByID(arg0 int) (model.Comment, error) {
+func (m *MockCommentRepo) GetByID(arg0 context.Context, arg1 int) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockCommentRepoMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentRepoMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCommentRepo)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCommentRepo)(nil).GetByID), arg0, arg1)
 }
 
 // Update mocks base method
-func (m *MockCommentRepo) Update(arg0 model.Comment) (model.Comment, error) {
+func (m *MockCommentRepo) Update(arg0 context.Context, arg1 model.Comment) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockCommentRepoMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentRepo)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentRepo)(nil).Update), arg0, arg1)
 }
 
 // DeleteByID mocks base method
-func (m *MockCommentRepo) DeleteByID(arg0 int) error {
+func (m *MockCommentRepo) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockCommentRepoMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockCommentRepoMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockCommentRepo)(nil).DeleteByID), arg0, arg1)
+}
+
+// MockTransitionRepo is a mock of TransitionRepo interface
+type MockTransitionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransitionRepoMockRecorder
+}
+
+// MockTransitionRepoMockRecorder is the mock recorder for MockTransitionRepo
+type MockTransitionRepoMockRecorder struct {
+	mock *MockTransitionRepo
+}
+
+// NewMockTransitionRepo creates a new mock instance
+func NewMockTransitionRepo(ctrl *gomock.Controller) *MockTransitionRepo {
+	mock := &MockTransitionRepo{ctrl: ctrl}
+	mock.recorder = &MockTransitionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTransitionRepo) EXPECT() *MockTransitionRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockTransitionRepo) Create(arg0 context.Context, arg1 model.Transition) (model.Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(model.Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockTransitionRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransitionRepo)(nil).Create), arg0, arg1)
+}
+
+// GetByTaskID mocks base method
+func (m *MockTransitionRepo) GetByTaskID(arg0 context.Context, arg1 int) ([]model.Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTaskID", arg0, arg1)
+	ret0, _ := ret[0].([]model.Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTaskID indicates an expected call of GetByTaskID
+func (mr *MockTransitionRepoMockRecorder) GetByTaskID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTaskID", reflect.TypeOf((*MockTransitionRepo)(nil).GetByTaskID), arg0, arg1)
+}
+
+// Analytics mocks base method
+func (m *MockTransitionRepo) Analytics(arg0 context.Context, arg1 int, arg2, arg3 time.Time) (model.Analytics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Analytics", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(model.Analytics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Analytics indicates an expected call of Analytics
+func (mr *MockTransitionRepoMockRecorder) Analytics(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Analytics", reflect.TypeOf((*MockTransitionRepo)(nil).Analytics), arg0, arg1, arg2, arg3)
+}
+
+// MockColumnSnapshotRepo is a mock of ColumnSnapshotRepo interface
+type MockColumnSnapshotRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockColumnSnapshotRepoMockRecorder
+}
+
+// MockColumnSnapshotRepoMockRecorder is the mock recorder for MockColumnSnapshotRepo
+type MockColumnSnapshotRepoMockRecorder struct {
+	mock *MockColumnSnapshotRepo
+}
+
+// NewMockColumnSnapshotRepo creates a new mock instance
+func NewMockColumnSnapshotRepo(ctrl *gomock.Controller) *MockColumnSnapshotRepo {
+	mock := &MockColumnSnapshotRepo{ctrl: ctrl}
+	mock.recorder = &MockColumnSnapshotRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockColumnSnapshotRepo) EXPECT() *MockColumnSnapshotRepoMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method
+func (m *MockColumnSnapshotRepo) Save(arg0 context.Context, arg1 model.ColumnSnapshot) (model.ColumnSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(model.ColumnSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save
+func (mr *MockColumnSnapshotRepoMockRecorder) Save(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockColumnSnapshotRepo)(nil).Save), arg0, arg1)
+}
+
+// GetByProjectID mocks base method
+func (m *MockColumnSnapshotRepo) GetByProjectID(arg0 context.Context, arg1 int, arg2, arg3 time.Time) ([]model.ColumnSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProjectID", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.ColumnSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProjectID indicates an expected call of GetByProjectID
+func (mr *MockColumnSnapshotRepoMockRecorder) GetByProjectID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProjectID", reflect.TypeOf((*MockColumnSnapshotRepo)(nil).GetByProjectID), arg0, arg1, arg2, arg3)
+}
+
+// MockTaskLinkRepo is a mock of TaskLinkRepo interface
+type MockTaskLinkRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskLinkRepoMockRecorder
+}
+
+// MockTaskLinkRepoMockRecorder is the mock recorder for MockTaskLinkRepo
+type MockTaskLinkRepoMockRecorder struct {
+	mock *MockTaskLinkRepo
+}
+
+// NewMockTaskLinkRepo creates a new mock instance
+func NewMockTaskLinkRepo(ctrl *gomock.Controller) *MockTaskLinkRepo {
+	mock := &MockTaskLinkRepo{ctrl: ctrl}
+	mock.recorder = &MockTaskLinkRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTaskLinkRepo) EXPECT() *MockTaskLinkRepoMockRecorder {
+	return m.recorder
+}
+
+// GetByTaskID mocks base method
+func (m *MockTaskLinkRepo) GetByTaskID(arg0 context.Context, arg1 int) ([]model.TaskLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTaskID", arg0, arg1)
+	ret0, _ := ret[0].([]model.TaskLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTaskID indicates an expected call of GetByTaskID
+func (mr *MockTaskLinkRepoMockRecorder) GetByTaskID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTaskID", reflect.TypeOf((*MockTaskLinkRepo)(nil).GetByTaskID), arg0, arg1)
+}
+
+// Create mocks base method
+func (m *MockTaskLinkRepo) Create(arg0 context.Context, arg1 model.TaskLink) (model.TaskLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(model.TaskLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockTaskLinkRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskLinkRepo)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockTaskLinkRepo) Delete(arg0 context.Context, arg1 model.TaskLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockTaskLinkRepoMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTaskLinkRepo)(nil).Delete), arg0, arg1)
+}
+
+// MockIdempotencyKeyRepo is a mock of IdempotencyKeyRepo interface
+type MockIdempotencyKeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyKeyRepoMockRecorder
+}
+
+// MockIdempotencyKeyRepoMockRecorder is the mock recorder for MockIdempotencyKeyRepo
+type MockIdempotencyKeyRepoMockRecorder struct {
+	mock *MockIdempotencyKeyRepo
+}
+
+// NewMockIdempotencyKeyRepo creates a new mock instance
+func NewMockIdempotencyKeyRepo(ctrl *gomock.Controller) *MockIdempotencyKeyRepo {
+	mock := &MockIdempotencyKeyRepo{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyKeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIdempotencyKeyRepo) EXPECT() *MockIdempotencyKeyRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockIdempotencyKeyRepo) Create(arg0 context.Context, arg1 model.IdempotencyKey) (model.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(model.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockIdempotencyKeyRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdempotencyKeyRepo)(nil).Create), arg0, arg1)
+}
+
+// GetByKey mocks base method
+func (m *MockIdempotencyKeyRepo) GetByKey(arg0 context.Context, arg1 string) (model.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByKey", arg0, arg1)
+	ret0, _ := ret[0].(model.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByKey indicates an expected call of GetByKey
+func (mr *MockIdempotencyKeyRepoMockRecorder) GetByKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByKey", reflect.TypeOf((*MockIdempotencyKeyRepo)(nil).GetByKey), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockIdempotencyKeyRepo) Update(arg0 context.Context, arg1 model.IdempotencyKey) (model.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(model.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockIdempotencyKeyRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIdempotencyKeyRepo)(nil).Update), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockIdempotencyKeyRepo) Delete(arg0 context.Context, arg1 model.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockIdempotencyKeyRepoMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIdempotencyKeyRepo)(nil).Delete), arg0, arg1)
+}
+
+// DeleteCreatedBefore mocks base method
+func (m *MockIdempotencyKeyRepo) DeleteCreatedBefore(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCreatedBefore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCreatedBefore indicates an expected call of DeleteCreatedBefore
+func (mr *MockIdempotencyKeyRepoMockRecorder) DeleteCreatedBefore(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockCommentRepo)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCreatedBefore", reflect.TypeOf((*MockIdempotencyKeyRepo)(nil).DeleteCreatedBefore), arg0, arg1)
 }
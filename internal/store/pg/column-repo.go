@@ -1,38 +1,49 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
 )
 
+// columnColumns are the columns table columns mapped by scanColumn.
+const columnColumns = "id, name, index, project_id, type, wip_limit"
+
+// scanColumn maps the row selected with columnColumns to a column.
+func scanColumn(row scanner) (model.Column, error) {
+	var c model.Column
+	err := row.Scan(&c.ID, &c.Name, &c.Index, &c.ProjectID, &c.Type, &c.WIPLimit)
+
+	return c, err
+}
+
 // columnRepo is the column repository for PostgreSQL store.
 type columnRepo struct {
-	db *sql.DB
+	stmts *statements
 }
 
 // newColumnRepo creates and returns a new columnRepo instance.
-func newColumnRepo(db *sql.DB) *columnRepo { return &columnRepo{db: db} }
+func newColumnRepo(stmts *statements) *columnRepo { return &columnRepo{stmts: stmts} }
 
 // GetByProjectID returns all columns with specific project ID.
-func (r *columnRepo) GetByProjectID(id int) ([]model.Column, error) {
-	rows, err := r.db.Query("SELECT * FROM projects WHERE id = $1;", id)
-	if err != nil {
+func (r *columnRepo) GetByProjectID(ctx context.Context, id int) ([]model.Column, error) {
+	if err := r.stmts.exists(ctx, "projects", id); err != nil {
 		return nil, err
-	} else if !rows.Next() {
-		return nil, store.ErrNotFound
 	}
 
-	rows, err = r.db.Query("SELECT * FROM columns WHERE project_id = $1;", id)
+	query := "SELECT " + columnColumns + " FROM columns WHERE project_id = $1 ORDER BY id;"
+	rows, err := r.stmts.query(ctx, query, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	cs, c := []model.Column{}, model.Column{}
+	cs := []model.Column{}
 	for rows.Next() {
-		if err = rows.Scan(&c.ID, &c.Name, &c.Index, &c.ProjectID); err != nil {
+		c, err := scanColumn(rows)
+		if err != nil {
 			return nil, err
 		}
 		cs = append(cs, c)
@@ -45,13 +56,14 @@ func (r *columnRepo) GetByProjectID(id int) ([]model.Column, error) {
 }
 
 // Create creates and returns a new column.
-func (r *columnRepo) Create(c model.Column) (model.Column, error) {
-	query := "INSERT INTO columns (name, index, project_id) VALUES ($1, $2, $3) RETURNING id;"
-	row := r.db.QueryRow(query, c.Name, c.Index, c.ProjectID)
+func (r *columnRepo) Create(ctx context.Context, c model.Column) (model.Column, error) {
+	query := `INSERT INTO columns (name, index, project_id, type, wip_limit) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;`
+	row := r.stmts.queryRow(ctx, query, c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit)
 
 	var id int
 	if err := row.Scan(&id); err != nil {
-		return model.Column{}, err
+		return model.Column{}, storeError(err)
 	}
 	c.ID = id
 
@@ -59,11 +71,9 @@ func (r *columnRepo) Create(c model.Column) (model.Column, error) {
 }
 
 // GetByID returns the column with specifc ID.
-func (r *columnRepo) GetByID(id int) (model.Column, error) {
-	row := r.db.QueryRow("SELECT * FROM columns WHERE id = $1;", id)
-
-	var c model.Column
-	err := row.Scan(&c.ID, &c.Name, &c.Index, &c.ProjectID)
+func (r *columnRepo) GetByID(ctx context.Context, id int) (model.Column, error) {
+	query := "SELECT " + columnColumns + " FROM columns WHERE id = $1;"
+	c, err := scanColumn(r.stmts.queryRow(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return model.Column{}, store.ErrNotFound
 	} else if err != nil {
@@ -74,12 +84,9 @@ func (r *columnRepo) GetByID(id int) (model.Column, error) {
 }
 
 // GetByIndexAndProjectID returns the column with specific index and project ID.
-func (r *columnRepo) GetByIndexAndProjectID(index, id int) (model.Column, error) {
-	query := "SELECT * FROM columns WHERE index = $1 AND project_id = $2;"
-	row := r.db.QueryRow(query, index, id)
-
-	var c model.Column
-	err := row.Scan(&c.ID, &c.Name, &c.Index, &c.ProjectID)
+func (r *columnRepo) GetByIndexAndProjectID(ctx context.Context, index, id int) (model.Column, error) {
+	query := "SELECT " + columnColumns + " FROM columns WHERE index = $1 AND project_id = $2;"
+	c, err := scanColumn(r.stmts.queryRow(ctx, query, index, id))
 	if err == sql.ErrNoRows {
 		return model.Column{}, store.ErrNotFound
 	} else if err != nil {
@@ -90,12 +97,13 @@ func (r *columnRepo) GetByIndexAndProjectID(index, id int) (model.Column, error)
 }
 
 // Update updates the column.
-func (r *columnRepo) Update(c model.Column) (model.Column, error) {
-	query := "UPDATE columns SET name = $1, index = $2, project_id = $3 WHERE id = $4;"
-	res, err := r.db.Exec(query, c.Name, c.Index, c.ProjectID, c.ID)
+func (r *columnRepo) Update(ctx context.Context, c model.Column) (model.Column, error) {
+	query := `UPDATE columns SET name = $1, index = $2, project_id = $3, type = $4, wip_limit = $5
+		WHERE id = $6;`
+	res, err := r.stmts.exec(ctx, query, c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit, c.ID)
 
 	if err != nil {
-		return model.Column{}, err
+		return model.Column{}, storeError(err)
 	}
 	rowsCount, err := res.RowsAffected()
 	if err != nil {
@@ -108,8 +116,8 @@ func (r *columnRepo) Update(c model.Column) (model.Column, error) {
 }
 
 // DeleteByID deletes the column with specific ID.
-func (r *columnRepo) DeleteByID(id int) error {
-	res, err := r.db.Exec("DELETE FROM columns WHERE id = $1;", id)
+func (r *columnRepo) DeleteByID(ctx context.Context, id int) error {
+	res, err := r.stmts.exec(ctx, "DELETE FROM columns WHERE id = $1;", id)
 
 	if err != nil {
 		return err
@@ -1,12 +1,15 @@
 package pg
 
 import (
+	"context"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
 )
 
 func TestColumnRepo_GetByProjectID(t *testing.T) {
@@ -15,7 +18,6 @@ func TestColumnRepo_GetByProjectID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newColumnRepo(db)
 
 	testcases := []struct {
 		name       string
@@ -27,16 +29,14 @@ func TestColumnRepo_GetByProjectID(t *testing.T) {
 		{
 			name: "columns are retrieved",
 			mock: func(cs []model.Column) {
-				rows := sqlmock.NewRows([]string{"id", "name", "description"}).AddRow(
-					1, "Project 1", "",
-				)
-				mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = (.+);").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectPrepare("SELECT (.+) FROM projects WHERE id = (.+);").ExpectQuery().WillReturnRows(rows)
 
-				rows = sqlmock.NewRows([]string{"id", "name", "index", "project_id"})
+				rows = sqlmock.NewRows([]string{"id", "name", "index", "project_id", "type", "wip_limit"})
 				for _, c := range cs {
-					rows = rows.AddRow(c.ID, c.Name, c.Index, c.ProjectID)
+					rows = rows.AddRow(c.ID, c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit)
 				}
-				mock.ExpectQuery("SELECT (.+) FROM columns WHERE project_id = (.+);").WillReturnRows(rows)
+				mock.ExpectPrepare("SELECT (.+) FROM columns WHERE project_id = (.+);").ExpectQuery().WillReturnRows(rows)
 			},
 			projectID: 1,
 			expColumns: []model.Column{
@@ -48,9 +48,10 @@ func TestColumnRepo_GetByProjectID(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newColumnRepo(newStatements(db))
 		tc.mock(tc.expColumns)
 
-		cs, err := r.GetByProjectID(tc.projectID)
+		cs, err := r.GetByProjectID(context.Background(), tc.projectID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expColumns, cs)
@@ -63,7 +64,6 @@ func TestColumnRepo_Create(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newColumnRepo(db)
 
 	testcases := []struct {
 		name      string
@@ -76,20 +76,43 @@ func TestColumnRepo_Create(t *testing.T) {
 			name: "column is created",
 			mock: func(c model.Column) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-				mock.ExpectQuery("INSERT INTO columns (.+) VALUES (.+);").WithArgs(
-					c.Name, c.Index, c.ProjectID,
+				mock.ExpectPrepare("INSERT INTO columns (.+) VALUES (.+);").ExpectQuery().WithArgs(
+					c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit,
 				).WillReturnRows(rows)
 			},
 			column:    model.Column{Name: "Column 1", Index: 1, ProjectID: 1},
 			expColumn: model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
 			expError:  nil,
 		},
+		{
+			name: "project doesn't exist",
+			mock: func(c model.Column) {
+				mock.ExpectPrepare("INSERT INTO columns (.+) VALUES (.+);").ExpectQuery().WithArgs(
+					c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit,
+				).WillReturnError(&pq.Error{Code: foreignKeyViolation})
+			},
+			column:    model.Column{Name: "Column 1", Index: 1, ProjectID: 10},
+			expColumn: model.Column{},
+			expError:  store.ErrInvalidReference,
+		},
+		{
+			name: "column with the same name exists",
+			mock: func(c model.Column) {
+				mock.ExpectPrepare("INSERT INTO columns (.+) VALUES (.+);").ExpectQuery().WithArgs(
+					c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit,
+				).WillReturnError(&pq.Error{Code: uniqueViolation})
+			},
+			column:    model.Column{Name: "Column 1", Index: 2, ProjectID: 1},
+			expColumn: model.Column{},
+			expError:  store.ErrConflict,
+		},
 	}
 
 	for _, tc := range testcases {
+		r := newColumnRepo(newStatements(db))
 		tc.mock(tc.column)
 
-		c, err := r.Create(tc.column)
+		c, err := r.Create(context.Background(), tc.column)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expColumn, c)
@@ -102,7 +125,6 @@ func TestColumnRepo_GetByID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newColumnRepo(db)
 
 	testcases := []struct {
 		name      string
@@ -114,23 +136,24 @@ func TestColumnRepo_GetByID(t *testing.T) {
 		{
 			name: "column is retrieved",
 			mock: func(c model.Column) {
-				rows := sqlmock.NewRows([]string{"id", "name", "index", "project_id"}).AddRow(
-					c.ID, c.Name, c.Index, c.ProjectID,
+				rows := sqlmock.NewRows([]string{"id", "name", "index", "project_id", "type", "wip_limit"}).AddRow(
+					c.ID, c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit,
 				)
-				mock.ExpectQuery("SELECT (.+) FROM columns WHERE (.+);").WithArgs(
+				mock.ExpectPrepare("SELECT (.+) FROM columns WHERE (.+);").ExpectQuery().WithArgs(
 					c.ID,
 				).WillReturnRows(rows)
 			},
-			column:    model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
-			expColumn: model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
+			column:    model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, WIPLimit: 3},
+			expColumn: model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1, WIPLimit: 3},
 			expError:  nil,
 		},
 	}
 
 	for _, tc := range testcases {
+		r := newColumnRepo(newStatements(db))
 		tc.mock(tc.column)
 
-		c, err := r.GetByID(tc.column.ID)
+		c, err := r.GetByID(context.Background(), tc.column.ID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expColumn, c)
@@ -143,7 +166,6 @@ func TestColumnRepo_GetByIndexAndProjectID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newColumnRepo(db)
 
 	testcases := []struct {
 		name      string
@@ -155,10 +177,10 @@ func TestColumnRepo_GetByIndexAndProjectID(t *testing.T) {
 		{
 			name: "column is retrieved by index and project ID",
 			mock: func(c model.Column) {
-				rows := sqlmock.NewRows([]string{"id", "name", "index", "project_id"}).AddRow(
-					c.ID, c.Name, c.Index, c.ProjectID,
+				rows := sqlmock.NewRows([]string{"id", "name", "index", "project_id", "type", "wip_limit"}).AddRow(
+					c.ID, c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit,
 				)
-				mock.ExpectQuery("SELECT (.+) FROM columns WHERE index = (.+) AND project_id = (.+);").WithArgs(
+				mock.ExpectPrepare("SELECT (.+) FROM columns WHERE index = (.+) AND project_id = (.+);").ExpectQuery().WithArgs(
 					c.Index, c.ProjectID,
 				).WillReturnRows(rows)
 			},
@@ -169,9 +191,10 @@ func TestColumnRepo_GetByIndexAndProjectID(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newColumnRepo(newStatements(db))
 		tc.mock(tc.column)
 
-		c, err := r.GetByIndexAndProjectID(tc.column.Index, tc.column.ProjectID)
+		c, err := r.GetByIndexAndProjectID(context.Background(), tc.column.Index, tc.column.ProjectID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expColumn, c)
@@ -184,7 +207,6 @@ func TestColumnRepo_Update(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newColumnRepo(db)
 
 	testcases := []struct {
 		name      string
@@ -196,8 +218,8 @@ func TestColumnRepo_Update(t *testing.T) {
 		{
 			name: "column is updated",
 			mock: func(c model.Column) {
-				mock.ExpectExec("UPDATE columns SET (.+) WHERE id = (.+);").WithArgs(
-					c.Name, c.Index, c.ProjectID, c.ID,
+				mock.ExpectPrepare("UPDATE columns SET (.+) WHERE id = (.+);").ExpectExec().WithArgs(
+					c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit, c.ID,
 				).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			column:    model.Column{ID: 1, Name: "Column 1", Index: 1, ProjectID: 1},
@@ -207,9 +229,10 @@ func TestColumnRepo_Update(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newColumnRepo(newStatements(db))
 		tc.mock(tc.column)
 
-		c, err := r.Update(tc.column)
+		c, err := r.Update(context.Background(), tc.column)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expColumn, c)
@@ -222,7 +245,6 @@ func TestColumnRepo_Delete(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newColumnRepo(db)
 
 	testcases := []struct {
 		name     string
@@ -233,7 +255,7 @@ func TestColumnRepo_Delete(t *testing.T) {
 		{
 			name: "column is deleted",
 			mock: func(c model.Column) {
-				mock.ExpectExec("DELETE FROM columns WHERE id = (.+);").WithArgs(
+				mock.ExpectPrepare("DELETE FROM columns WHERE id = (.+);").ExpectExec().WithArgs(
 					c.ID,
 				).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -243,9 +265,10 @@ func TestColumnRepo_Delete(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newColumnRepo(newStatements(db))
 		tc.mock(tc.column)
 
-		err := r.DeleteByID(tc.column.ID)
+		err := r.DeleteByID(context.Background(), tc.column.ID)
 
 		assert.Equal(t, tc.expError, err)
 	}
@@ -0,0 +1,73 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+// columnSnapshotColumns are the column_snapshots table columns mapped by scanColumnSnapshot.
+const columnSnapshotColumns = "id, project_id, column_id, column_name, column_index, day, count"
+
+// scanColumnSnapshot maps the row selected with columnSnapshotColumns to a snapshot.
+func scanColumnSnapshot(row scanner) (model.ColumnSnapshot, error) {
+	var s model.ColumnSnapshot
+	err := row.Scan(&s.ID, &s.ProjectID, &s.ColumnID, &s.ColumnName, &s.ColumnIndex, &s.Day, &s.Count)
+
+	return s, err
+}
+
+// columnSnapshotRepo is the column snapshot repository for PostgreSQL store.
+type columnSnapshotRepo struct {
+	stmts *statements
+}
+
+// newColumnSnapshotRepo creates and returns a new columnSnapshotRepo instance.
+func newColumnSnapshotRepo(stmts *statements) *columnSnapshotRepo {
+	return &columnSnapshotRepo{stmts: stmts}
+}
+
+// Save creates the snapshot or replaces the one of the same column and day.
+func (r *columnSnapshotRepo) Save(ctx context.Context, s model.ColumnSnapshot) (model.ColumnSnapshot, error) {
+	query := `INSERT INTO column_snapshots (project_id, column_id, column_name, column_index, day, count)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (column_id, day) DO UPDATE SET project_id = EXCLUDED.project_id,
+			column_name = EXCLUDED.column_name, column_index = EXCLUDED.column_index, count = EXCLUDED.count
+		RETURNING id;`
+	row := r.stmts.queryRow(ctx, query, s.ProjectID, s.ColumnID, s.ColumnName, s.ColumnIndex, s.Day, s.Count)
+
+	if err := row.Scan(&s.ID); err != nil {
+		return model.ColumnSnapshot{}, storeError(err)
+	}
+
+	return s, nil
+}
+
+// GetByProjectID returns snapshots of the project with specific ID from day from to day
+// to inclusive ordered by day and column ID.
+func (r *columnSnapshotRepo) GetByProjectID(
+	ctx context.Context, id int, from, to time.Time,
+) ([]model.ColumnSnapshot, error) {
+	query := "SELECT " + columnSnapshotColumns + ` FROM column_snapshots
+		WHERE project_id = $1 AND day >= $2 AND day <= $3 ORDER BY day, column_id;`
+	rows, err := r.stmts.query(ctx, query, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ss := []model.ColumnSnapshot{}
+	for rows.Next() {
+		s, err := scanColumnSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
@@ -0,0 +1,95 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+func TestColumnSnapshotRepo_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testcases := []struct {
+		name        string
+		mock        func(model.ColumnSnapshot)
+		snapshot    model.ColumnSnapshot
+		expSnapshot model.ColumnSnapshot
+		expError    error
+	}{
+		{
+			name: "snapshot is saved",
+			mock: func(s model.ColumnSnapshot) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectPrepare("INSERT INTO column_snapshots (.+) ON CONFLICT (.+) RETURNING id;").ExpectQuery().
+					WithArgs(s.ProjectID, s.ColumnID, s.ColumnName, s.ColumnIndex, s.Day, s.Count).WillReturnRows(rows)
+			},
+			snapshot:    model.ColumnSnapshot{ProjectID: 1, ColumnID: 1, ColumnName: "Column", ColumnIndex: 1, Count: 2},
+			expSnapshot: model.ColumnSnapshot{ID: 1, ProjectID: 1, ColumnID: 1, ColumnName: "Column", ColumnIndex: 1, Count: 2},
+			expError:    nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newColumnSnapshotRepo(newStatements(db))
+		tc.mock(tc.snapshot)
+
+		s, err := r.Save(context.Background(), tc.snapshot)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expSnapshot, s)
+	}
+}
+
+func TestColumnSnapshotRepo_GetByProjectID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	day := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		name         string
+		mock         func([]model.ColumnSnapshot)
+		expSnapshots []model.ColumnSnapshot
+		expError     error
+	}{
+		{
+			name: "snapshots are retrieved",
+			mock: func(ss []model.ColumnSnapshot) {
+				rows := sqlmock.NewRows(
+					[]string{"id", "project_id", "column_id", "column_name", "column_index", "day", "count"},
+				)
+				for _, s := range ss {
+					rows = rows.AddRow(s.ID, s.ProjectID, s.ColumnID, s.ColumnName, s.ColumnIndex, s.Day, s.Count)
+				}
+				mock.ExpectPrepare("SELECT (.+) FROM column_snapshots WHERE project_id = (.+) ORDER BY day, column_id;").
+					ExpectQuery().WithArgs(1, day, day.AddDate(0, 0, 1)).WillReturnRows(rows)
+			},
+			expSnapshots: []model.ColumnSnapshot{
+				{ID: 1, ProjectID: 1, ColumnID: 1, ColumnName: "Column 1", ColumnIndex: 1, Day: day, Count: 2},
+				{ID: 2, ProjectID: 1, ColumnID: 1, ColumnName: "Column 1", ColumnIndex: 1, Day: day.AddDate(0, 0, 1)},
+			},
+			expError: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newColumnSnapshotRepo(newStatements(db))
+		tc.mock(tc.expSnapshots)
+
+		ss, err := r.GetByProjectID(context.Background(), 1, day, day.AddDate(0, 0, 1))
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expSnapshots, ss)
+	}
+}
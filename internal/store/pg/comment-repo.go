@@ -1,38 +1,72 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
 )
 
+// commentColumns are the comments table columns mapped by scanComment.
+const commentColumns = "id, text, created_at, task_id"
+
+// scanComment maps the row selected with commentColumns to a comment.
+func scanComment(row scanner) (model.Comment, error) {
+	var c model.Comment
+	err := row.Scan(&c.ID, &c.Text, &c.CreatedAt, &c.TaskID)
+
+	return c, err
+}
+
 // commentRepo is the comment repository for PostgreSQL store.
 type commentRepo struct {
-	db *sql.DB
+	stmts *statements
 }
 
 // newCommentRepo creates and returns a new commentRepo instance.
-func newCommentRepo(db *sql.DB) *commentRepo { return &commentRepo{db: db} }
+func newCommentRepo(stmts *statements) *commentRepo { return &commentRepo{stmts: stmts} }
 
-// GetByTaskID returns all comments with specific task ID.
-func (r *commentRepo) GetByTaskID(id int) ([]model.Comment, error) {
-	rows, err := r.db.Query("SELECT * FROM tasks WHERE id = $1;", id)
+// GetAll returns all comments.
+func (r *commentRepo) GetAll(ctx context.Context) ([]model.Comment, error) {
+	rows, err := r.stmts.query(ctx, "SELECT "+commentColumns+" FROM comments ORDER BY id;")
 	if err != nil {
 		return nil, err
-	} else if !rows.Next() {
-		return nil, store.ErrNotFound
 	}
+	defer rows.Close()
+
+	cs := []model.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
 
-	rows, err = r.db.Query("SELECT * FROM comments WHERE task_id = $1;", id)
+// GetByTaskID returns all comments with specific task ID.
+func (r *commentRepo) GetByTaskID(ctx context.Context, id int) ([]model.Comment, error) {
+	if err := r.stmts.exists(ctx, "tasks", id); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + commentColumns + " FROM comments WHERE task_id = $1 ORDER BY id;"
+	rows, err := r.stmts.query(ctx, query, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	cs, c := []model.Comment{}, model.Comment{}
+	cs := []model.Comment{}
 	for rows.Next() {
-		if err := rows.Scan(&c.ID, &c.Text, &c.CreatedAt, &c.TaskID); err != nil {
+		c, err := scanComment(rows)
+		if err != nil {
 			return nil, err
 		}
 		cs = append(cs, c)
@@ -45,13 +79,13 @@ func (r *commentRepo) GetByTaskID(id int) ([]model.Comment, error) {
 }
 
 // Create creates and returns a new comment.
-func (r *commentRepo) Create(c model.Comment) (model.Comment, error) {
+func (r *commentRepo) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
 	query := "INSERT INTO comments (text, created_at, task_id) VALUES ($1, $2, $3) RETURNING id;"
-	row := r.db.QueryRow(query, c.Text, c.CreatedAt, c.TaskID)
+	row := r.stmts.queryRow(ctx, query, c.Text, c.CreatedAt, c.TaskID)
 
 	var id int
 	if err := row.Scan(&id); err != nil {
-		return model.Comment{}, err
+		return model.Comment{}, storeError(err)
 	}
 	c.ID = id
 
@@ -59,11 +93,9 @@ func (r *commentRepo) Create(c model.Comment) (model.Comment, error) {
 }
 
 // GetByID returns the comment with specific ID.
-func (r *commentRepo) GetByID(id int) (model.Comment, error) {
-	row := r.db.QueryRow("SELECT * FROM comments WHERE id = $1;", id)
-
-	var c model.Comment
-	err := row.Scan(&c.ID, &c.Text, &c.CreatedAt, &c.TaskID)
+func (r *commentRepo) GetByID(ctx context.Context, id int) (model.Comment, error) {
+	query := "SELECT " + commentColumns + " FROM comments WHERE id = $1;"
+	c, err := scanComment(r.stmts.queryRow(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return model.Comment{}, store.ErrNotFound
 	} else if err != nil {
@@ -74,12 +106,12 @@ func (r *commentRepo) GetByID(id int) (model.Comment, error) {
 }
 
 // Update updates the comment.
-func (r *commentRepo) Update(c model.Comment) (model.Comment, error) {
+func (r *commentRepo) Update(ctx context.Context, c model.Comment) (model.Comment, error) {
 	query := "UPDATE comments SET text = $1, created_at = $2, task_id = $3 WHERE id = $4;"
-	res, err := r.db.Exec(query, c.Text, c.CreatedAt, c.TaskID, c.ID)
+	res, err := r.stmts.exec(ctx, query, c.Text, c.CreatedAt, c.TaskID, c.ID)
 
 	if err != nil {
-		return model.Comment{}, err
+		return model.Comment{}, storeError(err)
 	}
 	rowsCount, err := res.RowsAffected()
 	if err != nil {
@@ -92,8 +124,8 @@ func (r *commentRepo) Update(c model.Comment) (model.Comment, error) {
 }
 
 // DeleteByID deletes the comment with specific ID.
-func (r *commentRepo) DeleteByID(id int) error {
-	res, err := r.db.Exec("DELETE FROM comments WHERE id = $1;", id)
+func (r *commentRepo) DeleteByID(ctx context.Context, id int) error {
+	res, err := r.stmts.exec(ctx, "DELETE FROM comments WHERE id = $1;", id)
 
 	if err != nil {
 		return err
@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -10,13 +11,53 @@ import (
 	"github.com/imarrche/tasker/internal/model"
 )
 
+func TestCommentRepo_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testcases := []struct {
+		name        string
+		mock        func([]model.Comment)
+		expComments []model.Comment
+		expError    error
+	}{
+		{
+			name: "comments are retrieved",
+			mock: func(cs []model.Comment) {
+				rows := sqlmock.NewRows([]string{"id", "text", "created_at", "task_id"})
+				for _, c := range cs {
+					rows = rows.AddRow(c.ID, c.Text, c.CreatedAt, c.TaskID)
+				}
+				mock.ExpectPrepare("SELECT (.+) FROM comments ORDER BY id;").ExpectQuery().WillReturnRows(rows)
+			},
+			expComments: []model.Comment{
+				{ID: 1, Text: "Comment.", CreatedAt: time.Time{}, TaskID: 1},
+				{ID: 2, Text: "Comment.", CreatedAt: time.Time{}, TaskID: 2},
+			},
+			expError: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newCommentRepo(newStatements(db))
+		tc.mock(tc.expComments)
+
+		cs, err := r.GetAll(context.Background())
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expComments, cs)
+	}
+}
+
 func TestCommentRepo_GetByTaskID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newCommentRepo(db)
 
 	testcases := []struct {
 		name        string
@@ -28,16 +69,14 @@ func TestCommentRepo_GetByTaskID(t *testing.T) {
 		{
 			name: "comments are retrieved",
 			mock: func(cs []model.Comment) {
-				rows := sqlmock.NewRows([]string{"id", "name", "description", "index", "column_id"}).AddRow(
-					1, "Task 1", "", 1, 1,
-				)
-				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = (.+);").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectPrepare("SELECT (.+) FROM tasks WHERE id = (.+);").ExpectQuery().WillReturnRows(rows)
 
 				rows = sqlmock.NewRows([]string{"id", "text", "created_at", "task_id"})
 				for _, c := range cs {
 					rows = rows.AddRow(c.ID, c.Text, c.CreatedAt, c.TaskID)
 				}
-				mock.ExpectQuery("SELECT (.+) FROM comments WHERE task_id = (.+);").WillReturnRows(rows)
+				mock.ExpectPrepare("SELECT (.+) FROM comments WHERE task_id = (.+);").ExpectQuery().WillReturnRows(rows)
 			},
 			taskID: 1,
 			expComments: []model.Comment{
@@ -49,9 +88,10 @@ func TestCommentRepo_GetByTaskID(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newCommentRepo(newStatements(db))
 		tc.mock(tc.expComments)
 
-		cs, err := r.GetByTaskID(tc.taskID)
+		cs, err := r.GetByTaskID(context.Background(), tc.taskID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expComments, cs)
@@ -64,7 +104,6 @@ func TestCommentRepo_Create(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newCommentRepo(db)
 
 	testcases := []struct {
 		name       string
@@ -77,7 +116,7 @@ func TestCommentRepo_Create(t *testing.T) {
 			name: "comment is created",
 			mock: func(c model.Comment) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-				mock.ExpectQuery("INSERT INTO comments (.+) VALUES (.+);").WithArgs(
+				mock.ExpectPrepare("INSERT INTO comments (.+) VALUES (.+);").ExpectQuery().WithArgs(
 					c.Text, c.CreatedAt, c.TaskID,
 				).WillReturnRows(rows)
 			},
@@ -88,9 +127,10 @@ func TestCommentRepo_Create(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newCommentRepo(newStatements(db))
 		tc.mock(tc.comment)
 
-		c, err := r.Create(tc.comment)
+		c, err := r.Create(context.Background(), tc.comment)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expComment, c)
@@ -103,7 +143,6 @@ func TestCommentRepo_GetByID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newCommentRepo(db)
 
 	testcases := []struct {
 		name       string
@@ -118,7 +157,7 @@ func TestCommentRepo_GetByID(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id", "text", "created_at", "task_id"}).AddRow(
 					c.ID, c.Text, c.CreatedAt, c.TaskID,
 				)
-				mock.ExpectQuery("SELECT (.+) FROM comments WHERE id = (.+);").WithArgs(
+				mock.ExpectPrepare("SELECT (.+) FROM comments WHERE id = (.+);").ExpectQuery().WithArgs(
 					c.ID,
 				).WillReturnRows(rows)
 			},
@@ -129,9 +168,10 @@ func TestCommentRepo_GetByID(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newCommentRepo(newStatements(db))
 		tc.mock(tc.comment)
 
-		c, err := r.GetByID(tc.comment.ID)
+		c, err := r.GetByID(context.Background(), tc.comment.ID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expComment, c)
@@ -144,7 +184,6 @@ func TestCommentRepo_Update(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newCommentRepo(db)
 
 	testcases := []struct {
 		name       string
@@ -156,7 +195,7 @@ func TestCommentRepo_Update(t *testing.T) {
 		{
 			name: "comment is updated",
 			mock: func(c model.Comment) {
-				mock.ExpectExec("UPDATE comments SET (.+) WHERE id = (.+);").WithArgs(
+				mock.ExpectPrepare("UPDATE comments SET (.+) WHERE id = (.+);").ExpectExec().WithArgs(
 					c.Text, c.CreatedAt, c.TaskID, c.ID,
 				).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -167,9 +206,10 @@ func TestCommentRepo_Update(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newCommentRepo(newStatements(db))
 		tc.mock(tc.comment)
 
-		c, err := r.Update(tc.comment)
+		c, err := r.Update(context.Background(), tc.comment)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expComment, c)
@@ -182,7 +222,6 @@ func TestCommentRepo_DeleteByID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newCommentRepo(db)
 
 	testcases := []struct {
 		name     string
@@ -193,7 +232,7 @@ func TestCommentRepo_DeleteByID(t *testing.T) {
 		{
 			name: "comment is deleted",
 			mock: func(c model.Comment) {
-				mock.ExpectExec("DELETE FROM comments WHERE id = (.+);").WithArgs(
+				mock.ExpectPrepare("DELETE FROM comments WHERE id = (.+);").ExpectExec().WithArgs(
 					c.ID,
 				).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -203,9 +242,10 @@ func TestCommentRepo_DeleteByID(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newCommentRepo(newStatements(db))
 		tc.mock(tc.comment)
 
-		err := r.DeleteByID(tc.comment.ID)
+		err := r.DeleteByID(context.Background(), tc.comment.ID)
 
 		assert.Equal(t, tc.expError, err)
 	}
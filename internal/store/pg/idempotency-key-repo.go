@@ -0,0 +1,131 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// idempotencyKeyColumns are the idempotency_keys table columns mapped by
+// scanIdempotencyKey.
+const idempotencyKeyColumns = "key, fingerprint, status, content_type, headers, body, created_at"
+
+// scanIdempotencyKey maps the row selected with idempotencyKeyColumns to an idempotency
+// key.
+func scanIdempotencyKey(row scanner) (model.IdempotencyKey, error) {
+	var k model.IdempotencyKey
+	var headers []byte
+	if err := row.Scan(&k.Key, &k.Fingerprint, &k.Status, &k.ContentType, &headers, &k.Body, &k.CreatedAt); err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	err := json.Unmarshal(headers, &k.Headers)
+
+	return k, err
+}
+
+// encodeHeaders returns the headers of the key as JSON, which is passed as a string as
+// byte slices are sent as bytea.
+func encodeHeaders(k model.IdempotencyKey) (string, error) {
+	if k.Headers == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(k.Headers)
+
+	return string(b), err
+}
+
+// idempotencyKeyRepo is the idempotency key repository for PostgreSQL store.
+type idempotencyKeyRepo struct {
+	stmts *statements
+}
+
+// newIdempotencyKeyRepo creates and returns a new idempotencyKeyRepo instance.
+func newIdempotencyKeyRepo(stmts *statements) *idempotencyKeyRepo {
+	return &idempotencyKeyRepo{stmts: stmts}
+}
+
+// Create creates and returns a new idempotency key. Its creation time is truncated to
+// microseconds PostgreSQL stores.
+func (r *idempotencyKeyRepo) Create(ctx context.Context, k model.IdempotencyKey) (model.IdempotencyKey, error) {
+	query := "INSERT INTO idempotency_keys (" + idempotencyKeyColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7);"
+	if k.Body == nil {
+		k.Body = []byte{}
+	}
+	headers, err := encodeHeaders(k)
+	if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	k.CreatedAt = k.CreatedAt.Truncate(time.Microsecond)
+	_, err = r.stmts.exec(ctx, query, k.Key, k.Fingerprint, k.Status, k.ContentType, headers, k.Body, k.CreatedAt)
+	if err != nil {
+		return model.IdempotencyKey{}, storeError(err)
+	}
+
+	return k, nil
+}
+
+// GetByKey returns the idempotency key.
+func (r *idempotencyKeyRepo) GetByKey(ctx context.Context, key string) (model.IdempotencyKey, error) {
+	query := "SELECT " + idempotencyKeyColumns + " FROM idempotency_keys WHERE key = $1;"
+	k, err := scanIdempotencyKey(r.stmts.queryRow(ctx, query, key))
+	if err == sql.ErrNoRows {
+		return model.IdempotencyKey{}, store.ErrNotFound
+	} else if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+
+	return k, nil
+}
+
+// Update saves the response of the idempotency key.
+func (r *idempotencyKeyRepo) Update(ctx context.Context, k model.IdempotencyKey) (model.IdempotencyKey, error) {
+	query := `UPDATE idempotency_keys SET status = $1, content_type = $2, headers = $3, body = $4
+		WHERE key = $5 AND fingerprint = $6 AND created_at = $7;`
+	if k.Body == nil {
+		k.Body = []byte{}
+	}
+	headers, err := encodeHeaders(k)
+	if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	res, err := r.stmts.exec(ctx, query, k.Status, k.ContentType, headers, k.Body, k.Key, k.Fingerprint, k.CreatedAt)
+	if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return model.IdempotencyKey{}, err
+	} else if rowsCount == 0 {
+		return model.IdempotencyKey{}, store.ErrNotFound
+	}
+
+	return k, nil
+}
+
+// Delete deletes the idempotency key.
+func (r *idempotencyKeyRepo) Delete(ctx context.Context, k model.IdempotencyKey) error {
+	query := "DELETE FROM idempotency_keys WHERE key = $1 AND fingerprint = $2 AND created_at = $3;"
+	res, err := r.stmts.exec(ctx, query, k.Key, k.Fingerprint, k.CreatedAt)
+	if err != nil {
+		return err
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	} else if rowsCount == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteCreatedBefore deletes idempotency keys created before t.
+func (r *idempotencyKeyRepo) DeleteCreatedBefore(ctx context.Context, t time.Time) error {
+	_, err := r.stmts.exec(ctx, "DELETE FROM idempotency_keys WHERE created_at < $1;", t)
+
+	return err
+}
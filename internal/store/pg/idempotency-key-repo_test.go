@@ -0,0 +1,175 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+func TestIdempotencyKeyRepo_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	k := model.IdempotencyKey{
+		Key: "key", Fingerprint: "f", Body: []byte{}, CreatedAt: time.Now().Truncate(time.Microsecond),
+	}
+	testcases := []struct {
+		name     string
+		mock     func()
+		expKey   model.IdempotencyKey
+		expError error
+	}{
+		{
+			name: "idempotency key is created",
+			mock: func() {
+				mock.ExpectPrepare("INSERT INTO idempotency_keys (.+) VALUES (.+);").ExpectExec().WithArgs(
+					k.Key, k.Fingerprint, k.Status, k.ContentType, "{}", k.Body, k.CreatedAt,
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expKey:   k,
+			expError: nil,
+		},
+		{
+			name: "idempotency key already exists",
+			mock: func() {
+				mock.ExpectPrepare("INSERT INTO idempotency_keys (.+) VALUES (.+);").ExpectExec().WillReturnError(
+					&pq.Error{Code: uniqueViolation},
+				)
+			},
+			expKey:   model.IdempotencyKey{},
+			expError: store.ErrConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newIdempotencyKeyRepo(newStatements(db))
+		tc.mock()
+
+		key, err := r.Create(context.Background(), k)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expKey, key)
+	}
+}
+
+func TestIdempotencyKeyRepo_GetByKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	k := model.IdempotencyKey{
+		Key: "key", Fingerprint: "f", Status: 201, ContentType: "application/json",
+		Headers: map[string][]string{"Location": {"/api/v1/projects/1"}}, Body: []byte(`{"id": 1}`),
+		CreatedAt: time.Now(),
+	}
+	testcases := []struct {
+		name     string
+		mock     func()
+		expKey   model.IdempotencyKey
+		expError error
+	}{
+		{
+			name: "idempotency key is retrieved",
+			mock: func() {
+				rows := sqlmock.NewRows(
+					[]string{"key", "fingerprint", "status", "content_type", "headers", "body", "created_at"},
+				).AddRow(
+					k.Key, k.Fingerprint, k.Status, k.ContentType, []byte(`{"Location": ["/api/v1/projects/1"]}`),
+					k.Body, k.CreatedAt,
+				)
+				mock.ExpectPrepare("SELECT (.+) FROM idempotency_keys WHERE key = (.+);").ExpectQuery().WithArgs(
+					k.Key,
+				).WillReturnRows(rows)
+			},
+			expKey:   k,
+			expError: nil,
+		},
+		{
+			name: "idempotency key isn't found",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"key"})
+				mock.ExpectPrepare("SELECT (.+) FROM idempotency_keys WHERE key = (.+);").ExpectQuery().WillReturnRows(rows)
+			},
+			expKey:   model.IdempotencyKey{},
+			expError: store.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newIdempotencyKeyRepo(newStatements(db))
+		tc.mock()
+
+		key, err := r.GetByKey(context.Background(), k.Key)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expKey, key)
+	}
+}
+
+func TestIdempotencyKeyRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	r := newIdempotencyKeyRepo(newStatements(db))
+	k := model.IdempotencyKey{Key: "key", Fingerprint: "f", Status: 204, Body: []byte{}, CreatedAt: time.Now()}
+
+	mock.ExpectPrepare(
+		"UPDATE idempotency_keys SET (.+) WHERE key = (.+) AND fingerprint = (.+) AND created_at = (.+);",
+	).ExpectExec().WithArgs(
+		k.Status, k.ContentType, "{}", k.Body, k.Key, k.Fingerprint, k.CreatedAt,
+	).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	key, err := r.Update(context.Background(), k)
+
+	assert.NoError(t, err)
+	assert.Equal(t, k, key)
+}
+
+func TestIdempotencyKeyRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	r := newIdempotencyKeyRepo(newStatements(db))
+	k := model.IdempotencyKey{Key: "key", Fingerprint: "f", CreatedAt: time.Now()}
+
+	mock.ExpectPrepare(
+		"DELETE FROM idempotency_keys WHERE key = (.+) AND fingerprint = (.+) AND created_at = (.+);",
+	).ExpectExec().WithArgs(
+		k.Key, k.Fingerprint, k.CreatedAt,
+	).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.Equal(t, store.ErrNotFound, r.Delete(context.Background(), k))
+}
+
+func TestIdempotencyKeyRepo_DeleteCreatedBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	r := newIdempotencyKeyRepo(newStatements(db))
+	before := time.Now()
+
+	mock.ExpectPrepare("DELETE FROM idempotency_keys WHERE created_at < (.+);").ExpectExec().WithArgs(
+		before,
+	).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, r.DeleteCreatedBefore(context.Background(), before))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,68 @@
+package pg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/pg/pgtest"
+	"github.com/imarrche/tasker/internal/store/storetest"
+	"github.com/imarrche/tasker/schema"
+)
+
+func TestMain(m *testing.M) {
+	pgtest.Main(m)
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store { return pgtest.NewStore(t) })
+}
+
+func TestStore_MigrationVersion(t *testing.T) {
+	s := pgtest.NewStore(t)
+
+	assert.NoError(t, s.Ping(context.Background()))
+	version, dirty, err := s.MigrationVersion(context.Background())
+	assert.NoError(t, err)
+	versions, err := schema.Versions(schema.PostgreSQL, ".")
+	require.NoError(t, err)
+	assert.Equal(t, versions[len(versions)-1], version)
+	assert.False(t, dirty)
+}
+
+func TestStore_MigrateViolatingConstraints(t *testing.T) {
+	s := pgtest.NewStore(t)
+	ctx := context.Background()
+
+	// Board created before unique constraints.
+	m, err := s.Migrator()
+	require.NoError(t, err)
+	require.NoError(t, m.Migrate(20201227073627))
+	for _, query := range []string{
+		"INSERT INTO projects (id, name, description) VALUES (1, 'Project', '');",
+		"INSERT INTO columns (id, name, index, project_id) VALUES (1, 'To do', 2, 1);",
+		"INSERT INTO columns (id, name, index, project_id) VALUES (2, 'To do', 2, 1);",
+		"INSERT INTO tasks (id, name, description, index, column_id) VALUES (1, 'Task 1', '', 3, 1);",
+		"INSERT INTO tasks (id, name, description, index, column_id) VALUES (2, 'Task 2', '', 1, 1);",
+		"INSERT INTO tasks (id, name, description, index, column_id) VALUES (3, 'Task 3', '', 1, 1);",
+	} {
+		_, err = s.DB().Exec(query)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, m.Up())
+
+	cs, err := s.Columns().GetByProjectID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Column{
+		{ID: 1, Name: "To do", Index: 1, ProjectID: 1, Type: model.ColumnTypeBacklog},
+		{ID: 2, Name: "To do (2)", Index: 2, ProjectID: 1, Type: model.ColumnTypeBacklog},
+	}, cs)
+	ts, err := s.Tasks().GetByColumnID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1, 2}, []int{ts[0].Index, ts[1].Index, ts[2].Index})
+}
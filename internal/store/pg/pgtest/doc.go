@@ -0,0 +1,5 @@
+// Package pgtest runs tests against a real PostgreSQL database. The server is taken from
+// TASKER_TEST_POSTGRES connection string or started from initdb and pg_ctl binaries found
+// in TASKER_TEST_PG_BIN directory or on PATH. Tests are skipped when neither is available,
+// unless CI environment variable is set.
+package pgtest
@@ -0,0 +1,208 @@
+package pgtest
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/store/pg"
+)
+
+var (
+	// current is the server started by Main.
+	current *server
+	// skipReason explains why PostgreSQL isn't available.
+	skipReason = "pgtest.Main isn't called from TestMain"
+	// databases is the number of databases created.
+	databases int32
+)
+
+// Main starts PostgreSQL for the package tests, runs them and stops the server, it's
+// meant to be called from TestMain. When PostgreSQL isn't available the reason is
+// reported and the tests are skipped, or the package fails if CI variable is set.
+func Main(m *testing.M) {
+	var err error
+	if current, err = start(); err != nil {
+		skipReason = "PostgreSQL isn't available: " + err.Error()
+		if os.Getenv("CI") != "" {
+			fmt.Fprintf(os.Stderr, "pgtest: %s\n", skipReason)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "pgtest: %s, PostgreSQL tests are skipped\n", skipReason)
+	}
+	code := m.Run()
+	if current != nil {
+		current.stop()
+	}
+
+	os.Exit(code)
+}
+
+// NewStore opens the store on a new empty database dropped after the test. The test is
+// skipped if PostgreSQL isn't available.
+func NewStore(t *testing.T) *pg.Store {
+	if current == nil {
+		t.Skip(skipReason)
+	}
+
+	c := current.config
+	c.DbName = fmt.Sprintf("tasker_test_%d_%d", os.Getpid(), atomic.AddInt32(&databases, 1))
+	if _, err := current.admin.Exec("CREATE DATABASE " + c.DbName + ";"); err != nil {
+		t.Fatal(err)
+	}
+	s := pg.New(c)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		if _, err := current.admin.Exec("DROP DATABASE " + c.DbName + ";"); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return s
+}
+
+// server is the PostgreSQL server tests are run against.
+type server struct {
+	config config.PostgreSQL
+	admin  *sql.DB
+	// dir is the data directory of the server started with pg_ctl, empty for
+	// external servers.
+	dir   string
+	pgCtl string
+}
+
+// start connects to the server from environment or starts a local one.
+func start() (*server, error) {
+	s := &server{}
+	if dsn := os.Getenv("TASKER_TEST_POSTGRES"); dsn != "" {
+		s.config = parseDSN(dsn)
+	} else if err := s.startLocal(); err != nil {
+		return nil, err
+	}
+
+	admin := s.config
+	admin.DbName = "postgres"
+	db, err := sql.Open("postgres", admin.DSN())
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		s.stop()
+		return nil, err
+	}
+	s.admin = db
+
+	return s, nil
+}
+
+// startLocal initializes a temporary cluster and starts the server on a free port.
+func (s *server) startLocal() error {
+	initdb, err := lookPath("initdb")
+	if err != nil {
+		return err
+	}
+	if s.pgCtl, err = lookPath("pg_ctl"); err != nil {
+		return err
+	}
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+	if s.dir, err = ioutil.TempDir("", "tasker-pg"); err != nil {
+		return err
+	}
+
+	data := filepath.Join(s.dir, "data")
+	cmd := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		s.stop()
+		return fmt.Errorf("initdb: %v: %s", err, out)
+	}
+	opts := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -F", port, s.dir)
+	cmd = exec.Command(
+		s.pgCtl, "start", "-w", "-t", "30", "-D", data, "-o", opts, "-l", filepath.Join(s.dir, "log"),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		s.stop()
+		return fmt.Errorf("pg_ctl start: %v: %s", err, out)
+	}
+
+	s.config = config.PostgreSQL{
+		Host: "127.0.0.1", Port: strconv.Itoa(port), User: "postgres", SSLMode: "disable",
+	}
+
+	return nil
+}
+
+// stop stops the local server and removes its data.
+func (s *server) stop() {
+	if s.admin != nil {
+		s.admin.Close()
+	}
+	if s.dir == "" {
+		return
+	}
+
+	data := filepath.Join(s.dir, "data")
+	if _, err := os.Stat(filepath.Join(data, "postmaster.pid")); err == nil {
+		exec.Command(s.pgCtl, "stop", "-w", "-m", "immediate", "-D", data).Run()
+	}
+	os.RemoveAll(s.dir)
+}
+
+// lookPath finds PostgreSQL binary in TASKER_TEST_PG_BIN directory or on PATH.
+func lookPath(name string) (string, error) {
+	if dir := os.Getenv("TASKER_TEST_PG_BIN"); dir != "" {
+		return exec.LookPath(filepath.Join(dir, name))
+	}
+
+	return exec.LookPath(name)
+}
+
+// freePort returns a TCP port that isn't used at the moment.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// parseDSN parses "key=value" connection string without quoted values.
+func parseDSN(dsn string) config.PostgreSQL {
+	c := config.PostgreSQL{Host: "localhost", Port: "5432", User: "postgres", SSLMode: "disable"}
+	for _, opt := range strings.Fields(dsn) {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "host":
+			c.Host = kv[1]
+		case "port":
+			c.Port = kv[1]
+		case "user":
+			c.User = kv[1]
+		case "password":
+			c.Password = kv[1]
+		case "sslmode":
+			c.SSLMode = kv[1]
+		}
+	}
+
+	return c
+}
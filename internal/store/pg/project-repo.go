@@ -1,31 +1,44 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
 )
 
+// projectColumns are the projects table columns mapped by scanProject.
+const projectColumns = "id, name, description, soft_wip_limits, strict_dependencies"
+
+// scanProject maps the row selected with projectColumns to a project.
+func scanProject(row scanner) (model.Project, error) {
+	var p model.Project
+	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.SoftWIPLimits, &p.StrictDependencies)
+
+	return p, err
+}
+
 // projectRepo is the project repository for PostgreSQL store.
 type projectRepo struct {
-	db *sql.DB
+	stmts *statements
 }
 
 // newProjectRepo creates and returns a new projectRepo instance.
-func newProjectRepo(db *sql.DB) *projectRepo { return &projectRepo{db: db} }
+func newProjectRepo(stmts *statements) *projectRepo { return &projectRepo{stmts: stmts} }
 
 // GetAll returns all projects.
-func (r *projectRepo) GetAll() ([]model.Project, error) {
-	rows, err := r.db.Query("SELECT * FROM projects;")
+func (r *projectRepo) GetAll(ctx context.Context) ([]model.Project, error) {
+	rows, err := r.stmts.query(ctx, "SELECT "+projectColumns+" FROM projects ORDER BY id;")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	ps, p := []model.Project{}, model.Project{}
+	ps := []model.Project{}
 	for rows.Next() {
-		if err = rows.Scan(&p.ID, &p.Name, &p.Description); err != nil {
+		p, err := scanProject(rows)
+		if err != nil {
 			return nil, err
 		}
 		ps = append(ps, p)
@@ -37,10 +50,36 @@ func (r *projectRepo) GetAll() ([]model.Project, error) {
 	return ps, nil
 }
 
+// GetCounts returns the number of columns and tasks of every project.
+func (r *projectRepo) GetCounts(ctx context.Context) ([]model.ProjectCounts, error) {
+	query := `SELECT p.id, COUNT(DISTINCT c.id), COUNT(t.id) FROM projects p
+		LEFT JOIN columns c ON c.project_id = p.id LEFT JOIN tasks t ON t.column_id = c.id
+		GROUP BY p.id ORDER BY p.id;`
+	rows, err := r.stmts.query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pcs, pc := []model.ProjectCounts{}, model.ProjectCounts{}
+	for rows.Next() {
+		if err = rows.Scan(&pc.ProjectID, &pc.Columns, &pc.Tasks); err != nil {
+			return nil, err
+		}
+		pcs = append(pcs, pc)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pcs, nil
+}
+
 // Create creates and returns a new project.
-func (r *projectRepo) Create(p model.Project) (model.Project, error) {
-	query := "INSERT INTO projects (name, description) VALUES ($1, $2) RETURNING id;"
-	row := r.db.QueryRow(query, p.Name, p.Description)
+func (r *projectRepo) Create(ctx context.Context, p model.Project) (model.Project, error) {
+	query := `INSERT INTO projects (name, description, soft_wip_limits, strict_dependencies)
+		VALUES ($1, $2, $3, $4) RETURNING id;`
+	row := r.stmts.queryRow(ctx, query, p.Name, p.Description, p.SoftWIPLimits, p.StrictDependencies)
 
 	var id int
 	if err := row.Scan(&id); err != nil {
@@ -52,11 +91,9 @@ func (r *projectRepo) Create(p model.Project) (model.Project, error) {
 }
 
 // GetByID returns the project with specific ID.
-func (r *projectRepo) GetByID(id int) (model.Project, error) {
-	row := r.db.QueryRow("SELECT * FROM projects WHERE id = $1;", id)
-
-	var p model.Project
-	err := row.Scan(&p.ID, &p.Name, &p.Description)
+func (r *projectRepo) GetByID(ctx context.Context, id int) (model.Project, error) {
+	query := "SELECT " + projectColumns + " FROM projects WHERE id = $1;"
+	p, err := scanProject(r.stmts.queryRow(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return model.Project{}, store.ErrNotFound
 	} else if err != nil {
@@ -67,9 +104,10 @@ func (r *projectRepo) GetByID(id int) (model.Project, error) {
 }
 
 // Update updates the project.
-func (r *projectRepo) Update(p model.Project) (model.Project, error) {
-	query := "UPDATE projects SET name = $1, description = $2 WHERE id = $3;"
-	res, err := r.db.Exec(query, p.Name, p.Description, p.ID)
+func (r *projectRepo) Update(ctx context.Context, p model.Project) (model.Project, error) {
+	query := `UPDATE projects SET name = $1, description = $2, soft_wip_limits = $3, strict_dependencies = $4
+		WHERE id = $5;`
+	res, err := r.stmts.exec(ctx, query, p.Name, p.Description, p.SoftWIPLimits, p.StrictDependencies, p.ID)
 
 	if err != nil {
 		return model.Project{}, err
@@ -85,8 +123,8 @@ func (r *projectRepo) Update(p model.Project) (model.Project, error) {
 }
 
 // DeleteByID deletes the project with specific ID.
-func (r *projectRepo) DeleteByID(id int) error {
-	res, err := r.db.Exec("DELETE FROM projects WHERE id = $1;", id)
+func (r *projectRepo) DeleteByID(ctx context.Context, id int) error {
+	res, err := r.stmts.exec(ctx, "DELETE FROM projects WHERE id = $1;", id)
 
 	if err != nil {
 		return err
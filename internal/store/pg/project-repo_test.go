@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"context"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -15,47 +16,111 @@ func TestProjectRepo_GetAll(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newProjectRepo(db)
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
 
 	testcases := []struct {
 		name        string
 		mock        func([]model.Project)
+		ctx         context.Context
 		expProjects []model.Project
 		expError    error
 	}{
 		{
 			name: "projects are retrieved",
 			mock: func(ps []model.Project) {
-				rows := sqlmock.NewRows([]string{"id", "name", "description"})
+				rows := sqlmock.NewRows([]string{"id", "name", "description", "soft_wip_limits", "strict_dependencies"})
 				for _, p := range ps {
-					rows = rows.AddRow(p.ID, p.Name, p.Description)
+					rows = rows.AddRow(p.ID, p.Name, p.Description, p.SoftWIPLimits, p.StrictDependencies)
 				}
-				mock.ExpectQuery("SELECT (.+) FROM projects;").WillReturnRows(rows)
+				mock.ExpectPrepare("SELECT (.+) FROM projects ORDER BY id;").ExpectQuery().WillReturnRows(rows)
 			},
+			ctx: context.Background(),
 			expProjects: []model.Project{
 				{ID: 1, Name: "Project 1"}, {ID: 2, Name: "Project 2"},
 			},
 			expError: nil,
 		},
+		{
+			name:        "projects aren't retrieved because context is canceled",
+			mock:        func(ps []model.Project) {},
+			ctx:         canceledCtx,
+			expProjects: nil,
+			expError:    context.Canceled,
+		},
 	}
 
 	for _, tc := range testcases {
+		r := newProjectRepo(newStatements(db))
 		tc.mock(tc.expProjects)
 
-		ps, err := r.GetAll()
+		ps, err := r.GetAll(tc.ctx)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expProjects, ps)
 	}
 }
 
+func TestProjectRepo_GetCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testcases := []struct {
+		name      string
+		mock      func([]model.ProjectCounts)
+		ctx       context.Context
+		expCounts []model.ProjectCounts
+		expError  error
+	}{
+		{
+			name: "counts are retrieved",
+			mock: func(pcs []model.ProjectCounts) {
+				rows := sqlmock.NewRows([]string{"id", "columns", "tasks"})
+				for _, pc := range pcs {
+					rows = rows.AddRow(pc.ProjectID, pc.Columns, pc.Tasks)
+				}
+				mock.ExpectPrepare("SELECT (.+) FROM projects p (.+) GROUP BY p.id ORDER BY p.id;").
+					ExpectQuery().WillReturnRows(rows)
+			},
+			ctx: context.Background(),
+			expCounts: []model.ProjectCounts{
+				{ProjectID: 1, Columns: 2, Tasks: 3}, {ProjectID: 2, Columns: 0, Tasks: 0},
+			},
+			expError: nil,
+		},
+		{
+			name:      "counts aren't retrieved because context is canceled",
+			mock:      func(pcs []model.ProjectCounts) {},
+			ctx:       canceledCtx,
+			expCounts: nil,
+			expError:  context.Canceled,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newProjectRepo(newStatements(db))
+		tc.mock(tc.expCounts)
+
+		pcs, err := r.GetCounts(tc.ctx)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expCounts, pcs)
+	}
+}
+
 func TestProjectRepo_Create(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newProjectRepo(db)
 
 	testcases := []struct {
 		name       string
@@ -68,8 +133,8 @@ func TestProjectRepo_Create(t *testing.T) {
 			name: "project is created",
 			mock: func(p model.Project) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-				mock.ExpectQuery("INSERT INTO projects (.+) VALUES (.+);").WithArgs(
-					p.Name, p.Description,
+				mock.ExpectPrepare("INSERT INTO projects (.+) VALUES (.+);").ExpectQuery().WithArgs(
+					p.Name, p.Description, p.SoftWIPLimits, p.StrictDependencies,
 				).WillReturnRows(rows)
 			},
 			project:    model.Project{Name: "Project 1", Description: "Description."},
@@ -79,9 +144,10 @@ func TestProjectRepo_Create(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newProjectRepo(newStatements(db))
 		tc.mock(tc.project)
 
-		p, err := r.Create(tc.project)
+		p, err := r.Create(context.Background(), tc.project)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expProject, p)
@@ -94,7 +160,6 @@ func TestProjectRepo_GetByID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newProjectRepo(db)
 
 	testcases := []struct {
 		name       string
@@ -106,23 +171,24 @@ func TestProjectRepo_GetByID(t *testing.T) {
 		{
 			name: "project is retrieved",
 			mock: func(p model.Project) {
-				rows := sqlmock.NewRows([]string{"id", "name", "description"}).AddRow(
-					p.ID, p.Name, p.Description,
+				rows := sqlmock.NewRows([]string{"id", "name", "description", "soft_wip_limits", "strict_dependencies"}).AddRow(
+					p.ID, p.Name, p.Description, p.SoftWIPLimits, p.StrictDependencies,
 				)
-				mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = (.+);").WithArgs(
+				mock.ExpectPrepare("SELECT (.+) FROM projects WHERE id = (.+);").ExpectQuery().WithArgs(
 					p.ID,
 				).WillReturnRows(rows)
 			},
-			project:    model.Project{ID: 1, Name: "Project 1"},
-			expProject: model.Project{ID: 1, Name: "Project 1"},
+			project:    model.Project{ID: 1, Name: "Project 1", SoftWIPLimits: true},
+			expProject: model.Project{ID: 1, Name: "Project 1", SoftWIPLimits: true},
 			expError:   nil,
 		},
 	}
 
 	for _, tc := range testcases {
+		r := newProjectRepo(newStatements(db))
 		tc.mock(tc.project)
 
-		p, err := r.GetByID(tc.project.ID)
+		p, err := r.GetByID(context.Background(), tc.project.ID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expProject, p)
@@ -135,7 +201,6 @@ func TestProjectRepo_Update(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newProjectRepo(db)
 
 	testcases := []struct {
 		name       string
@@ -147,8 +212,8 @@ func TestProjectRepo_Update(t *testing.T) {
 		{
 			name: "project is updated",
 			mock: func(p model.Project) {
-				mock.ExpectExec("UPDATE projects SET (.+) WHERE id = (.+);").WithArgs(
-					p.Name, p.Description, p.ID,
+				mock.ExpectPrepare("UPDATE projects SET (.+) WHERE id = (.+);").ExpectExec().WithArgs(
+					p.Name, p.Description, p.SoftWIPLimits, p.StrictDependencies, p.ID,
 				).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			project:    model.Project{ID: 1, Name: "Project 1"},
@@ -158,9 +223,10 @@ func TestProjectRepo_Update(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newProjectRepo(newStatements(db))
 		tc.mock(tc.project)
 
-		p, err := r.Update(tc.project)
+		p, err := r.Update(context.Background(), tc.project)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expProject, p)
@@ -173,7 +239,6 @@ func TestProjectRepo_DeleteByID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newProjectRepo(db)
 
 	testcases := []struct {
 		name     string
@@ -184,7 +249,7 @@ func TestProjectRepo_DeleteByID(t *testing.T) {
 		{
 			name: "project is deleted",
 			mock: func(p model.Project) {
-				mock.ExpectExec("DELETE FROM projects WHERE id = (.+);").WithArgs(
+				mock.ExpectPrepare("DELETE FROM projects WHERE id = (.+);").ExpectExec().WithArgs(
 					p.ID,
 				).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -194,9 +259,10 @@ func TestProjectRepo_DeleteByID(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newProjectRepo(newStatements(db))
 		tc.mock(tc.project)
 
-		err := r.DeleteByID(tc.project.ID)
+		err := r.DeleteByID(context.Background(), tc.project.ID)
 
 		assert.Equal(t, tc.expError, err)
 	}
@@ -0,0 +1,130 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// errRow is the row which can't be scanned because the query failed to be prepared.
+type errRow struct {
+	err error
+}
+
+// Scan returns the error preparing the query.
+func (r errRow) Scan(dest ...interface{}) error { return r.err }
+
+// statements is the cache of prepared statements shared by repositories. Statements
+// bound to a transaction use statements cached by the parent.
+type statements struct {
+	db     *sql.DB
+	m      sync.Mutex
+	cache  map[string]*sql.Stmt
+	tx     *sql.Tx
+	parent *statements
+}
+
+// newStatements creates and returns a new statements instance.
+func newStatements(db *sql.DB) *statements {
+	return &statements{db: db, cache: make(map[string]*sql.Stmt)}
+}
+
+// inTx returns statements bound to the transaction.
+func (s *statements) inTx(tx *sql.Tx) *statements {
+	return &statements{db: s.db, tx: tx, parent: s}
+}
+
+// prepare returns the prepared statement for the query, the query is prepared on first
+// use.
+func (s *statements) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	if s.parent != nil {
+		// Preparing a statement for the cache would take another connection from the pool
+		// while the transaction holds one, so missing statements are prepared only for
+		// the transaction.
+		s.parent.m.Lock()
+		stmt, ok := s.parent.cache[query]
+		s.parent.m.Unlock()
+		if ok {
+			return s.tx.StmtContext(ctx, stmt), nil
+		}
+		return s.tx.PrepareContext(ctx, query)
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if stmt, ok := s.cache[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.cache[query] = stmt
+
+	return stmt, nil
+}
+
+// query executes the query returning rows.
+func (s *statements) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := s.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt.QueryContext(ctx, args...)
+}
+
+// queryRow executes the query returning at most one row.
+func (s *statements) queryRow(ctx context.Context, query string, args ...interface{}) scanner {
+	stmt, err := s.prepare(ctx, query)
+	if err != nil {
+		return errRow{err: err}
+	}
+
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// exec executes the query without returning any rows.
+func (s *statements) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := s.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt.ExecContext(ctx, args...)
+}
+
+// exists checks whether the row with specific ID exists in the table.
+func (s *statements) exists(ctx context.Context, table string, id int) error {
+	var found int
+	err := s.queryRow(ctx, "SELECT id FROM "+table+" WHERE id = $1;", id).Scan(&found)
+	if err == sql.ErrNoRows {
+		return store.ErrNotFound
+	}
+
+	return err
+}
+
+// close closes all prepared statements.
+func (s *statements) close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var err error
+	for query, stmt := range s.cache {
+		if e := stmt.Close(); e != nil && err == nil {
+			err = e
+		}
+		delete(s.cache, query)
+	}
+
+	return err
+}
@@ -0,0 +1,37 @@
+package pg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+func TestStatements_Prepare(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	r := newProjectRepo(newStatements(db))
+
+	query := "SELECT id, name, description, soft_wip_limits, strict_dependencies FROM projects WHERE id = $1;"
+	prep := mock.ExpectPrepare(query)
+	for id := 1; id <= 2; id++ {
+		rows := sqlmock.NewRows([]string{"id", "name", "description", "soft_wip_limits", "strict_dependencies"}).AddRow(
+			id, "Project", "", false, false,
+		)
+		prep.ExpectQuery().WithArgs(id).WillReturnRows(rows)
+	}
+
+	for id := 1; id <= 2; id++ {
+		p, err := r.GetByID(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.Project{ID: id, Name: "Project"}, p)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,26 +1,34 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"net/http"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file" //
-	_ "github.com/lib/pq"                                // PostgreSQL driver.
+	"github.com/golang-migrate/migrate/v4/source/httpfs"
+	"github.com/lib/pq"
 
 	"github.com/imarrche/tasker/internal/config"
 	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/schema"
 )
 
 // Store is PostgreSQL store.
 type Store struct {
 	config      config.PostgreSQL
 	db          *sql.DB
+	stmts       *statements
 	projectRepo *projectRepo
 	columnRepo  *columnRepo
 	taskRepo    *taskRepo
 	commentRepo *commentRepo
+
+	transitionRepo     *transitionRepo
+	columnSnapshotRepo *columnSnapshotRepo
+	taskLinkRepo       *taskLinkRepo
+	idempotencyKeyRepo *idempotencyKeyRepo
 }
 
 // New creates new Store instance.
@@ -28,74 +36,163 @@ func New(config config.PostgreSQL) *Store {
 	return &Store{config: config}
 }
 
-// Open open a connection with PostgreSQL.
+// Open opens a connection with PostgreSQL and migrates schema to the latest version.
 func (s *Store) Open() error {
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		s.config.Host, s.config.Port, s.config.User,
-		s.config.Password, s.config.DbName, s.config.SSLMode,
-	)
-	db, err := sql.Open("postgres", connStr)
-	if err != nil {
+	if err := s.Connect(); err != nil {
 		return err
 	}
-	err = db.Ping()
+
+	m, err := s.Migrator()
 	if err != nil {
 		return err
 	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
 
-	s.db = db
+	return nil
+}
 
-	// Migrating.
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
-	m, err := migrate.NewWithDatabaseInstance("file://schema", "postgres", driver)
+// Connect opens a connection with PostgreSQL without migrating schema.
+func (s *Store) Connect() error {
+	db, err := sql.Open("postgres", s.config.DSN())
 	if err != nil {
 		return err
 	}
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+	db.SetMaxOpenConns(s.config.MaxOpenConns)
+	db.SetMaxIdleConns(s.config.MaxIdleConns)
+	db.SetConnMaxLifetime(s.config.ConnMaxLifetime)
+	err = db.Ping()
+	if err != nil {
 		return err
 	}
 
+	s.db = db
+	s.stmts = newStatements(db)
+	s.projectRepo = newProjectRepo(s.stmts)
+	s.columnRepo = newColumnRepo(s.stmts)
+	s.taskRepo = newTaskRepo(s.stmts)
+	s.commentRepo = newCommentRepo(s.stmts)
+	s.transitionRepo = newTransitionRepo(s.stmts)
+	s.columnSnapshotRepo = newColumnSnapshotRepo(s.stmts)
+	s.taskLinkRepo = newTaskLinkRepo(s.stmts)
+	s.idempotencyKeyRepo = newIdempotencyKeyRepo(s.stmts)
+
 	return nil
 }
 
-// Projects returns the project repository.
-func (s *Store) Projects() store.ProjectRepo {
-	if s.projectRepo == nil {
-		s.projectRepo = newProjectRepo(s.db)
+// Migrator returns the schema migrator for the connected database using migrations
+// embedded into the binary.
+func (s *Store) Migrator() (*migrate.Migrate, error) {
+	src, err := httpfs.New(http.FS(schema.PostgreSQL), "/")
+	if err != nil {
+		return nil, err
+	}
+	driver, err := postgres.WithInstance(s.db, &postgres.Config{})
+	if err != nil {
+		return nil, err
 	}
 
+	return migrate.NewWithInstance("httpfs", src, "postgres", driver)
+}
+
+// Ping checks whether PostgreSQL is reachable.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// MigrationVersion returns the current schema version and whether the last migration
+// failed leaving schema dirty.
+func (s *Store) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1;")
+
+	var version uint
+	var dirty bool
+	if err := row.Scan(&version, &dirty); err != nil {
+		return 0, false, err
+	}
+
+	return version, dirty, nil
+}
+
+// Projects returns the project repository.
+func (s *Store) Projects() store.ProjectRepo {
 	return s.projectRepo
 }
 
 // Columns returns the column repository.
 func (s *Store) Columns() store.ColumnRepo {
-	if s.columnRepo == nil {
-		s.columnRepo = newColumnRepo(s.db)
-	}
-
 	return s.columnRepo
 }
 
 // Tasks returns the task repository.
 func (s *Store) Tasks() store.TaskRepo {
-	if s.taskRepo == nil {
-		s.taskRepo = newTaskRepo(s.db)
-	}
-
 	return s.taskRepo
 }
 
 // Comments returns the comment repository.
 func (s *Store) Comments() store.CommentRepo {
-	if s.commentRepo == nil {
-		s.commentRepo = newCommentRepo(s.db)
-	}
-
 	return s.commentRepo
 }
 
-// Close closes a connection with PostgreSQL.
+// Transitions returns the transition repository.
+func (s *Store) Transitions() store.TransitionRepo {
+	return s.transitionRepo
+}
+
+// ColumnSnapshots returns the column snapshot repository.
+func (s *Store) ColumnSnapshots() store.ColumnSnapshotRepo {
+	return s.columnSnapshotRepo
+}
+
+// TaskLinks returns the task link repository.
+func (s *Store) TaskLinks() store.TaskLinkRepo { return s.taskLinkRepo }
+
+// IdempotencyKeys returns the idempotency key repository.
+func (s *Store) IdempotencyKeys() store.IdempotencyKeyRepo {
+	return s.idempotencyKeyRepo
+}
+
+// Stats returns the database connection pool stats.
+func (s *Store) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// DB returns the database connection pool for tools working with any schema version.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes prepared statements and a connection with PostgreSQL.
 func (s *Store) Close() error {
+	if s.stmts != nil {
+		s.stmts.close()
+	}
+
 	return s.db.Close()
 }
+
+// PostgreSQL error codes of constraint violations.
+const (
+	uniqueViolation      = "23505"
+	foreignKeyViolation  = "23503"
+	serializationFailure = "40001"
+)
+
+// storeError converts PostgreSQL constraint violations and serialization failures to
+// store errors.
+func storeError(err error) error {
+	e, ok := err.(*pq.Error)
+	if !ok {
+		return err
+	}
+
+	switch e.Code {
+	case uniqueViolation, serializationFailure:
+		return store.ErrConflict
+	case foreignKeyViolation:
+		return store.ErrInvalidReference
+	default:
+		return err
+	}
+}
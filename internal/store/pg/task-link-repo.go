@@ -0,0 +1,73 @@
+package pg
+
+import (
+	"context"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// taskLinkRepo is the task link repository for PostgreSQL store.
+type taskLinkRepo struct {
+	stmts *statements
+}
+
+// newTaskLinkRepo creates and returns a new taskLinkRepo instance.
+func newTaskLinkRepo(stmts *statements) *taskLinkRepo { return &taskLinkRepo{stmts: stmts} }
+
+// GetByTaskID returns links the task with specific ID takes either side of.
+func (r *taskLinkRepo) GetByTaskID(ctx context.Context, id int) ([]model.TaskLink, error) {
+	if err := r.stmts.exists(ctx, "tasks", id); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT blocker_id, blocked_id FROM task_links WHERE blocker_id = $1 OR blocked_id = $1
+		ORDER BY blocker_id, blocked_id;`
+	rows, err := r.stmts.query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ls := []model.TaskLink{}
+	for rows.Next() {
+		var l model.TaskLink
+		if err := rows.Scan(&l.BlockerID, &l.BlockedID); err != nil {
+			return nil, err
+		}
+		ls = append(ls, l)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ls, nil
+}
+
+// Create creates and returns a new task link.
+func (r *taskLinkRepo) Create(ctx context.Context, l model.TaskLink) (model.TaskLink, error) {
+	query := "INSERT INTO task_links (blocker_id, blocked_id) VALUES ($1, $2);"
+	if _, err := r.stmts.exec(ctx, query, l.BlockerID, l.BlockedID); err != nil {
+		return model.TaskLink{}, storeError(err)
+	}
+
+	return l, nil
+}
+
+// Delete deletes the task link.
+func (r *taskLinkRepo) Delete(ctx context.Context, l model.TaskLink) error {
+	query := "DELETE FROM task_links WHERE blocker_id = $1 AND blocked_id = $2;"
+	res, err := r.stmts.exec(ctx, query, l.BlockerID, l.BlockedID)
+
+	if err != nil {
+		return err
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	} else if rowsCount == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package pg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+func TestTaskLinkRepo_GetByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testcases := []struct {
+		name     string
+		mock     func([]model.TaskLink)
+		taskID   int
+		expLinks []model.TaskLink
+		expError error
+	}{
+		{
+			name: "task links are retrieved",
+			mock: func(ls []model.TaskLink) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(2)
+				mock.ExpectPrepare("SELECT (.+) FROM tasks WHERE id = (.+);").ExpectQuery().WillReturnRows(rows)
+
+				rows = sqlmock.NewRows([]string{"blocker_id", "blocked_id"})
+				for _, l := range ls {
+					rows = rows.AddRow(l.BlockerID, l.BlockedID)
+				}
+				mock.ExpectPrepare("SELECT (.+) FROM task_links WHERE blocker_id = (.+) OR blocked_id = (.+);").
+					ExpectQuery().WithArgs(2).WillReturnRows(rows)
+			},
+			taskID:   2,
+			expLinks: []model.TaskLink{{BlockerID: 1, BlockedID: 2}, {BlockerID: 2, BlockedID: 3}},
+			expError: nil,
+		},
+		{
+			name: "task doesn't exist",
+			mock: func([]model.TaskLink) {
+				rows := sqlmock.NewRows([]string{"id"})
+				mock.ExpectPrepare("SELECT (.+) FROM tasks WHERE id = (.+);").ExpectQuery().WillReturnRows(rows)
+			},
+			taskID:   4,
+			expLinks: nil,
+			expError: store.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newTaskLinkRepo(newStatements(db))
+		tc.mock(tc.expLinks)
+
+		ls, err := r.GetByTaskID(context.Background(), tc.taskID)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expLinks, ls)
+	}
+}
+
+func TestTaskLinkRepo_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testcases := []struct {
+		name     string
+		mock     func(model.TaskLink)
+		link     model.TaskLink
+		expLink  model.TaskLink
+		expError error
+	}{
+		{
+			name: "task link is created",
+			mock: func(l model.TaskLink) {
+				mock.ExpectPrepare("INSERT INTO task_links (.+) VALUES (.+);").ExpectExec().WithArgs(
+					l.BlockerID, l.BlockedID,
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			link:     model.TaskLink{BlockerID: 1, BlockedID: 2},
+			expLink:  model.TaskLink{BlockerID: 1, BlockedID: 2},
+			expError: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newTaskLinkRepo(newStatements(db))
+		tc.mock(tc.link)
+
+		l, err := r.Create(context.Background(), tc.link)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expLink, l)
+	}
+}
+
+func TestTaskLinkRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testcases := []struct {
+		name     string
+		mock     func(model.TaskLink)
+		link     model.TaskLink
+		expError error
+	}{
+		{
+			name: "task link is deleted",
+			mock: func(l model.TaskLink) {
+				mock.ExpectPrepare("DELETE FROM task_links WHERE (.+);").ExpectExec().WithArgs(
+					l.BlockerID, l.BlockedID,
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			link:     model.TaskLink{BlockerID: 1, BlockedID: 2},
+			expError: nil,
+		},
+		{
+			name: "task link doesn't exist",
+			mock: func(l model.TaskLink) {
+				mock.ExpectPrepare("DELETE FROM task_links WHERE (.+);").ExpectExec().WithArgs(
+					l.BlockerID, l.BlockedID,
+				).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			link:     model.TaskLink{BlockerID: 2, BlockedID: 1},
+			expError: store.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newTaskLinkRepo(newStatements(db))
+		tc.mock(tc.link)
+
+		err := r.Delete(context.Background(), tc.link)
+
+		assert.Equal(t, tc.expError, err)
+	}
+}
@@ -1,38 +1,75 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/imarrche/tasker/internal/model"
 	"github.com/imarrche/tasker/internal/store"
 )
 
+// taskColumns are the tasks table columns mapped by scanTask followed by whether the task
+// is blocked by a task which isn't completed.
+const taskColumns = `id, name, description, index, column_id, started_at, completed_at,
+	EXISTS (SELECT 1 FROM task_links JOIN tasks blockers ON blockers.id = task_links.blocker_id
+		WHERE task_links.blocked_id = tasks.id AND blockers.completed_at IS NULL)`
+
+// scanTask maps the row selected with taskColumns to a task.
+func scanTask(row scanner) (model.Task, error) {
+	var t model.Task
+	err := row.Scan(&t.ID, &t.Name, &t.Description, &t.Index, &t.ColumnID, &t.StartedAt, &t.CompletedAt, &t.Blocked)
+
+	return t, err
+}
+
 // taskRepo is the task repository for PostgreSQL store.
 type taskRepo struct {
-	db *sql.DB
+	stmts *statements
 }
 
 // newTaskRepo creates and returns a new taskRepo instance.
-func newTaskRepo(db *sql.DB) *taskRepo { return &taskRepo{db: db} }
+func newTaskRepo(stmts *statements) *taskRepo { return &taskRepo{stmts: stmts} }
 
-// GetByColumnID returns all tasks with specific column ID.
-func (r *taskRepo) GetByColumnID(id int) ([]model.Task, error) {
-	rows, err := r.db.Query("SELECT * FROM columns WHERE id = $1;", id)
+// GetAll returns all tasks.
+func (r *taskRepo) GetAll(ctx context.Context) ([]model.Task, error) {
+	rows, err := r.stmts.query(ctx, "SELECT "+taskColumns+" FROM tasks ORDER BY id;")
 	if err != nil {
 		return nil, err
-	} else if !rows.Next() {
-		return nil, store.ErrNotFound
 	}
+	defer rows.Close()
 
-	rows, err = r.db.Query("SELECT * FROM tasks WHERE column_id = $1;", id)
+	ts := []model.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// GetByColumnID returns all tasks with specific column ID.
+func (r *taskRepo) GetByColumnID(ctx context.Context, id int) ([]model.Task, error) {
+	if err := r.stmts.exists(ctx, "columns", id); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + taskColumns + " FROM tasks WHERE column_id = $1 ORDER BY id;"
+	rows, err := r.stmts.query(ctx, query, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	ts, t := []model.Task{}, model.Task{}
+	ts := []model.Task{}
 	for rows.Next() {
-		if err = rows.Scan(&t.ID, &t.Name, &t.Description, &t.Index, &t.ColumnID); err != nil {
+		t, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
 		ts = append(ts, t)
@@ -45,13 +82,16 @@ func (r *taskRepo) GetByColumnID(id int) ([]model.Task, error) {
 }
 
 // Create creates and returns a new task.
-func (r *taskRepo) Create(t model.Task) (model.Task, error) {
-	query := "INSERT INTO tasks (name, description, index, column_id) VALUES ($1, $2, $3, $4) RETURNING id;"
-	row := r.db.QueryRow(query, t.Name, t.Description, t.Index, t.ColumnID)
+func (r *taskRepo) Create(ctx context.Context, t model.Task) (model.Task, error) {
+	query := `INSERT INTO tasks (name, description, index, column_id, started_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`
+	row := r.stmts.queryRow(
+		ctx, query, t.Name, t.Description, t.Index, t.ColumnID, t.StartedAt, t.CompletedAt,
+	)
 
 	var id int
 	if err := row.Scan(&id); err != nil {
-		return model.Task{}, err
+		return model.Task{}, storeError(err)
 	}
 	t.ID = id
 
@@ -59,11 +99,9 @@ func (r *taskRepo) Create(t model.Task) (model.Task, error) {
 }
 
 // GetByID returns the task with specifc ID.
-func (r *taskRepo) GetByID(id int) (model.Task, error) {
-	row := r.db.QueryRow("SELECT * FROM tasks WHERE id = $1;", id)
-
-	var t model.Task
-	err := row.Scan(&t.ID, &t.Name, &t.Description, &t.Index, &t.ColumnID)
+func (r *taskRepo) GetByID(ctx context.Context, id int) (model.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks WHERE id = $1;"
+	t, err := scanTask(r.stmts.queryRow(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return model.Task{}, store.ErrNotFound
 	} else if err != nil {
@@ -74,11 +112,9 @@ func (r *taskRepo) GetByID(id int) (model.Task, error) {
 }
 
 // GetByIndexAndColumnID returns the task with specific index and column ID.
-func (r *taskRepo) GetByIndexAndColumnID(index, id int) (model.Task, error) {
-	row := r.db.QueryRow("SELECT * FROM tasks WHERE index = $1 AND column_id = $2;", index, id)
-
-	var t model.Task
-	err := row.Scan(&t.ID, &t.Name, &t.Description, &t.Index, &t.ColumnID)
+func (r *taskRepo) GetByIndexAndColumnID(ctx context.Context, index, id int) (model.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks WHERE index = $1 AND column_id = $2;"
+	t, err := scanTask(r.stmts.queryRow(ctx, query, index, id))
 	if err == sql.ErrNoRows {
 		return model.Task{}, store.ErrNotFound
 	} else if err != nil {
@@ -89,12 +125,15 @@ func (r *taskRepo) GetByIndexAndColumnID(index, id int) (model.Task, error) {
 }
 
 // Update updates the tasks.
-func (r *taskRepo) Update(t model.Task) (model.Task, error) {
-	query := "UPDATE tasks SET name = $1, description = $2, index = $3, column_id = $4 WHERE id = $5;"
-	res, err := r.db.Exec(query, t.Name, t.Description, t.Index, t.ColumnID, t.ID)
+func (r *taskRepo) Update(ctx context.Context, t model.Task) (model.Task, error) {
+	query := `UPDATE tasks SET name = $1, description = $2, index = $3, column_id = $4, started_at = $5,
+		completed_at = $6 WHERE id = $7;`
+	res, err := r.stmts.exec(
+		ctx, query, t.Name, t.Description, t.Index, t.ColumnID, t.StartedAt, t.CompletedAt, t.ID,
+	)
 
 	if err != nil {
-		return model.Task{}, err
+		return model.Task{}, storeError(err)
 	}
 	rowsCount, err := res.RowsAffected()
 	if err != nil {
@@ -107,8 +146,8 @@ func (r *taskRepo) Update(t model.Task) (model.Task, error) {
 }
 
 // DeleteByID deletes the task with specific ID.
-func (r *taskRepo) DeleteByID(id int) error {
-	res, err := r.db.Exec("DELETE FROM tasks WHERE id = $1;", id)
+func (r *taskRepo) DeleteByID(ctx context.Context, id int) error {
+	res, err := r.stmts.exec(ctx, "DELETE FROM tasks WHERE id = $1;", id)
 
 	if err != nil {
 		return err
@@ -1,7 +1,10 @@
 package pg
 
 import (
+	"context"
+	"database/sql/driver"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -9,13 +12,70 @@ import (
 	"github.com/imarrche/tasker/internal/model"
 )
 
+// taskRowColumns are the columns of rows returned by tasks queries.
+var taskRowColumns = []string{
+	"id", "name", "description", "index", "column_id", "started_at", "completed_at", "blocked",
+}
+
+// timeValue converts the optional time to the value of a returned row.
+func timeValue(t *time.Time) driver.Value {
+	if t == nil {
+		return nil
+	}
+
+	return *t
+}
+
+func TestTaskRepo_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testcases := []struct {
+		name     string
+		mock     func([]model.Task)
+		expTasks []model.Task
+		expError error
+	}{
+		{
+			name: "tasks are retrieved",
+			mock: func(ts []model.Task) {
+				rows := sqlmock.NewRows(taskRowColumns)
+				for _, task := range ts {
+					rows = rows.AddRow(
+						task.ID, task.Name, task.Description, task.Index, task.ColumnID,
+						timeValue(task.StartedAt), timeValue(task.CompletedAt), task.Blocked,
+					)
+				}
+				mock.ExpectPrepare("SELECT (.+) FROM tasks ORDER BY id;").ExpectQuery().WillReturnRows(rows)
+			},
+			expTasks: []model.Task{
+				{ID: 1, Name: "Task 1", Description: "", Index: 1, ColumnID: 1},
+				{ID: 2, Name: "Task 2", Description: "", Index: 1, ColumnID: 2},
+			},
+			expError: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newTaskRepo(newStatements(db))
+		tc.mock(tc.expTasks)
+
+		ts, err := r.GetAll(context.Background())
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expTasks, ts)
+	}
+}
+
 func TestTaskRepo_GetByColumnID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newTaskRepo(db)
 
 	testcases := []struct {
 		name     string
@@ -27,16 +87,17 @@ func TestTaskRepo_GetByColumnID(t *testing.T) {
 		{
 			name: "tasks are retrieved",
 			mock: func(ts []model.Task) {
-				rows := sqlmock.NewRows([]string{"id", "name", "index", "project_id"}).AddRow(
-					1, "Column 1", 1, 1,
-				)
-				mock.ExpectQuery("SELECT (.+) FROM columns WHERE id = (.+);").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectPrepare("SELECT (.+) FROM columns WHERE id = (.+);").ExpectQuery().WillReturnRows(rows)
 
-				rows = sqlmock.NewRows([]string{"id", "name", "description", "index", "column_id"})
+				rows = sqlmock.NewRows(taskRowColumns)
 				for _, task := range ts {
-					rows = rows.AddRow(task.ID, task.Name, task.Description, task.Index, task.ColumnID)
+					rows = rows.AddRow(
+						task.ID, task.Name, task.Description, task.Index, task.ColumnID,
+						timeValue(task.StartedAt), timeValue(task.CompletedAt), task.Blocked,
+					)
 				}
-				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE column_id = (.+);").WillReturnRows(rows)
+				mock.ExpectPrepare("SELECT (.+) FROM tasks WHERE column_id = (.+);").ExpectQuery().WillReturnRows(rows)
 			},
 			columnID: 1,
 			expTasks: []model.Task{
@@ -48,9 +109,10 @@ func TestTaskRepo_GetByColumnID(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newTaskRepo(newStatements(db))
 		tc.mock(tc.expTasks)
 
-		ts, err := r.GetByColumnID(tc.columnID)
+		ts, err := r.GetByColumnID(context.Background(), tc.columnID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expTasks, ts)
@@ -63,7 +125,6 @@ func TestTaskRepo_Create(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newTaskRepo(db)
 
 	testcases := []struct {
 		name     string
@@ -76,8 +137,8 @@ func TestTaskRepo_Create(t *testing.T) {
 			name: "task is created",
 			mock: func(task model.Task) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-				mock.ExpectQuery("INSERT INTO tasks (.+) VALUES (.+);").WithArgs(
-					task.Name, task.Description, task.Index, task.ColumnID,
+				mock.ExpectPrepare("INSERT INTO tasks (.+) VALUES (.+);").ExpectQuery().WithArgs(
+					task.Name, task.Description, task.Index, task.ColumnID, task.StartedAt, task.CompletedAt,
 				).WillReturnRows(rows)
 			},
 			task:     model.Task{Name: "Task 1", Index: 1, ColumnID: 1},
@@ -87,9 +148,10 @@ func TestTaskRepo_Create(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newTaskRepo(newStatements(db))
 		tc.mock(tc.task)
 
-		task, err := r.Create(tc.task)
+		task, err := r.Create(context.Background(), tc.task)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expTask, task)
@@ -102,7 +164,7 @@ func TestTaskRepo_GetByID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newTaskRepo(db)
+	startedAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
 
 	testcases := []struct {
 		name     string
@@ -114,23 +176,25 @@ func TestTaskRepo_GetByID(t *testing.T) {
 		{
 			name: "task is retrieved",
 			mock: func(task model.Task) {
-				rows := sqlmock.NewRows([]string{"id", "name", "description", "index", "column_id"}).AddRow(
+				rows := sqlmock.NewRows(taskRowColumns).AddRow(
 					task.ID, task.Name, task.Description, task.Index, task.ColumnID,
+					timeValue(task.StartedAt), timeValue(task.CompletedAt), task.Blocked,
 				)
-				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = (.+);").WithArgs(
+				mock.ExpectPrepare("SELECT (.+) FROM tasks WHERE id = (.+);").ExpectQuery().WithArgs(
 					task.ID,
 				).WillReturnRows(rows)
 			},
-			task:     model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
-			expTask:  model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
+			task:     model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1, StartedAt: &startedAt, Blocked: true},
+			expTask:  model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1, StartedAt: &startedAt, Blocked: true},
 			expError: nil,
 		},
 	}
 
 	for _, tc := range testcases {
+		r := newTaskRepo(newStatements(db))
 		tc.mock(tc.task)
 
-		task, err := r.GetByID(tc.task.ID)
+		task, err := r.GetByID(context.Background(), tc.task.ID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expTask, task)
@@ -143,7 +207,6 @@ func TestTaskRepo_GetByIndexAndColumnID(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newTaskRepo(db)
 
 	testcases := []struct {
 		name     string
@@ -155,10 +218,11 @@ func TestTaskRepo_GetByIndexAndColumnID(t *testing.T) {
 		{
 			name: "task is retrieved by index and column ID",
 			mock: func(task model.Task) {
-				rows := sqlmock.NewRows([]string{"id", "name", "description", "index", "column_id"}).AddRow(
+				rows := sqlmock.NewRows(taskRowColumns).AddRow(
 					task.ID, task.Name, task.Description, task.Index, task.ColumnID,
+					timeValue(task.StartedAt), timeValue(task.CompletedAt), task.Blocked,
 				)
-				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE index = (.+) AND column_id = (.+);").WithArgs(
+				mock.ExpectPrepare("SELECT (.+) FROM tasks WHERE index = (.+) AND column_id = (.+);").ExpectQuery().WithArgs(
 					task.Index, task.ColumnID,
 				).WillReturnRows(rows)
 			},
@@ -169,9 +233,10 @@ func TestTaskRepo_GetByIndexAndColumnID(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newTaskRepo(newStatements(db))
 		tc.mock(tc.task)
 
-		task, err := r.GetByIndexAndColumnID(tc.task.Index, tc.task.ColumnID)
+		task, err := r.GetByIndexAndColumnID(context.Background(), tc.task.Index, tc.task.ColumnID)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expTask, task)
@@ -184,7 +249,6 @@ func TestTaskRepo_Update(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newTaskRepo(db)
 
 	testcases := []struct {
 		name     string
@@ -196,8 +260,8 @@ func TestTaskRepo_Update(t *testing.T) {
 		{
 			name: "task is updated",
 			mock: func(task model.Task) {
-				mock.ExpectExec("UPDATE tasks SET (.+) WHERE id = (.+);").WithArgs(
-					task.Name, task.Description, task.Index, task.ColumnID, task.ID,
+				mock.ExpectPrepare("UPDATE tasks SET (.+) WHERE id = (.+);").ExpectExec().WithArgs(
+					task.Name, task.Description, task.Index, task.ColumnID, task.StartedAt, task.CompletedAt, task.ID,
 				).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			task:     model.Task{ID: 1, Name: "Task 1", Index: 1, ColumnID: 1},
@@ -207,9 +271,10 @@ func TestTaskRepo_Update(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newTaskRepo(newStatements(db))
 		tc.mock(tc.task)
 
-		task, err := r.Update(tc.task)
+		task, err := r.Update(context.Background(), tc.task)
 
 		assert.Equal(t, tc.expError, err)
 		assert.Equal(t, tc.expTask, task)
@@ -222,7 +287,6 @@ func TestTaskRepo_Delete(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer db.Close()
-	r := newTaskRepo(db)
 
 	testcases := []struct {
 		name     string
@@ -233,7 +297,7 @@ func TestTaskRepo_Delete(t *testing.T) {
 		{
 			name: "task is deleted",
 			mock: func(task model.Task) {
-				mock.ExpectExec("DELETE FROM tasks WHERE id = (.+);").WithArgs(
+				mock.ExpectPrepare("DELETE FROM tasks WHERE id = (.+);").ExpectExec().WithArgs(
 					task.ID,
 				).WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -243,9 +307,10 @@ func TestTaskRepo_Delete(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		r := newTaskRepo(newStatements(db))
 		tc.mock(tc.task)
 
-		err := r.DeleteByID(tc.task.ID)
+		err := r.DeleteByID(context.Background(), tc.task.ID)
 
 		assert.Equal(t, tc.expError, err)
 	}
@@ -0,0 +1,129 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+// transitionColumns are the transitions table columns mapped by scanTransition.
+const transitionColumns = "id, task_id, project_id, from_column_id, to_column_id, at"
+
+// scanTransition maps the row selected with transitionColumns to a transition.
+func scanTransition(row scanner) (model.Transition, error) {
+	var t model.Transition
+	err := row.Scan(&t.ID, &t.TaskID, &t.ProjectID, &t.FromColumnID, &t.ToColumnID, &t.At)
+
+	return t, err
+}
+
+// transitionRepo is the transition repository for PostgreSQL store.
+type transitionRepo struct {
+	stmts *statements
+}
+
+// newTransitionRepo creates and returns a new transitionRepo instance.
+func newTransitionRepo(stmts *statements) *transitionRepo { return &transitionRepo{stmts: stmts} }
+
+// Create creates and returns a new transition.
+func (r *transitionRepo) Create(ctx context.Context, t model.Transition) (model.Transition, error) {
+	query := `INSERT INTO transitions (task_id, project_id, from_column_id, to_column_id, at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+	row := r.stmts.queryRow(ctx, query, t.TaskID, t.ProjectID, t.FromColumnID, t.ToColumnID, t.At)
+
+	if err := row.Scan(&t.ID); err != nil {
+		return model.Transition{}, storeError(err)
+	}
+
+	return t, nil
+}
+
+// GetByTaskID returns all transitions of the task with specific ID in order.
+func (r *transitionRepo) GetByTaskID(ctx context.Context, id int) ([]model.Transition, error) {
+	if err := r.stmts.exists(ctx, "tasks", id); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + transitionColumns + " FROM transitions WHERE task_id = $1 ORDER BY at, id;"
+	rows, err := r.stmts.query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ts := []model.Transition{}
+	for rows.Next() {
+		t, err := scanTransition(rows)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// Analytics computes metrics of the project with specific ID over the period. A stay in
+// a column lasts until the next transition of the task, which is found with a window
+// function, as is the first transition when the task was created.
+func (r *transitionRepo) Analytics(ctx context.Context, id int, from, to time.Time) (model.Analytics, error) {
+	a := model.Analytics{From: from, To: to, Columns: []model.ColumnDwell{}}
+
+	query := `SELECT column_id, COUNT(*), AVG(EXTRACT(EPOCH FROM left_at - entered_at)) FROM (
+			SELECT to_column_id AS column_id, at AS entered_at,
+				LEAD(at) OVER (PARTITION BY task_id ORDER BY at, id) AS left_at
+			FROM transitions WHERE project_id = $1
+		) AS stays
+		WHERE left_at >= $2 AND left_at < $3
+		GROUP BY column_id ORDER BY column_id;`
+	rows, err := r.stmts.query(ctx, query, id, from, to)
+	if err != nil {
+		return model.Analytics{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d model.ColumnDwell
+		if err := rows.Scan(&d.ColumnID, &d.Count, &d.AverageSeconds); err != nil {
+			return model.Analytics{}, err
+		}
+		a.Columns = append(a.Columns, d)
+	}
+	if err = rows.Err(); err != nil {
+		return model.Analytics{}, err
+	}
+
+	query = `SELECT
+			COUNT(created_at),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - created_at)),
+			percentile_cont(0.85) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - created_at)),
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - created_at)),
+			COUNT(started_at),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - started_at)),
+			percentile_cont(0.85) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - started_at)),
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - started_at))
+		FROM (
+			SELECT DISTINCT t.id, t.started_at, t.completed_at,
+				FIRST_VALUE(tr.at) OVER (PARTITION BY t.id ORDER BY tr.at, tr.id) AS created_at
+			FROM tasks t
+			JOIN columns c ON c.id = t.column_id
+			LEFT JOIN transitions tr ON tr.task_id = t.id
+			WHERE c.project_id = $1 AND t.completed_at >= $2 AND t.completed_at < $3
+		) AS completed;`
+	var lead, cycle [3]sql.NullFloat64
+	err = r.stmts.queryRow(ctx, query, id, from, to).Scan(
+		&a.LeadTime.Count, &lead[0], &lead[1], &lead[2],
+		&a.CycleTime.Count, &cycle[0], &cycle[1], &cycle[2],
+	)
+	if err != nil {
+		return model.Analytics{}, err
+	}
+	a.LeadTime.P50, a.LeadTime.P85, a.LeadTime.P95 = lead[0].Float64, lead[1].Float64, lead[2].Float64
+	a.CycleTime.P50, a.CycleTime.P85, a.CycleTime.P95 = cycle[0].Float64, cycle[1].Float64, cycle[2].Float64
+
+	return a, nil
+}
@@ -0,0 +1,146 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+func TestTransitionRepo_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testcases := []struct {
+		name          string
+		mock          func(model.Transition)
+		transition    model.Transition
+		expTransition model.Transition
+		expError      error
+	}{
+		{
+			name: "transition is created",
+			mock: func(tr model.Transition) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectPrepare("INSERT INTO transitions (.+) VALUES (.+) RETURNING id;").ExpectQuery().WithArgs(
+					tr.TaskID, tr.ProjectID, tr.FromColumnID, tr.ToColumnID, tr.At,
+				).WillReturnRows(rows)
+			},
+			transition:    model.Transition{TaskID: 1, ProjectID: 1, FromColumnID: 1, ToColumnID: 2, At: time.Time{}},
+			expTransition: model.Transition{ID: 1, TaskID: 1, ProjectID: 1, FromColumnID: 1, ToColumnID: 2, At: time.Time{}},
+			expError:      nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newTransitionRepo(newStatements(db))
+		tc.mock(tc.transition)
+
+		tr, err := r.Create(context.Background(), tc.transition)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expTransition, tr)
+	}
+}
+
+func TestTransitionRepo_GetByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testcases := []struct {
+		name           string
+		mock           func([]model.Transition)
+		taskID         int
+		expTransitions []model.Transition
+		expError       error
+	}{
+		{
+			name: "transitions are retrieved",
+			mock: func(ts []model.Transition) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectPrepare("SELECT (.+) FROM tasks WHERE id = (.+);").ExpectQuery().WillReturnRows(rows)
+
+				rows = sqlmock.NewRows([]string{"id", "task_id", "project_id", "from_column_id", "to_column_id", "at"})
+				for _, tr := range ts {
+					rows = rows.AddRow(tr.ID, tr.TaskID, tr.ProjectID, tr.FromColumnID, tr.ToColumnID, tr.At)
+				}
+				mock.ExpectPrepare("SELECT (.+) FROM transitions WHERE task_id = (.+) ORDER BY at, id;").
+					ExpectQuery().WithArgs(1).WillReturnRows(rows)
+			},
+			taskID: 1,
+			expTransitions: []model.Transition{
+				{ID: 1, TaskID: 1, ProjectID: 1, ToColumnID: 1, At: time.Time{}},
+				{ID: 2, TaskID: 1, ProjectID: 1, FromColumnID: 1, ToColumnID: 2, At: time.Time{}},
+			},
+			expError: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newTransitionRepo(newStatements(db))
+		tc.mock(tc.expTransitions)
+
+		ts, err := r.GetByTaskID(context.Background(), tc.taskID)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expTransitions, ts)
+	}
+}
+
+func TestTransitionRepo_Analytics(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	from, to := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		name         string
+		mock         func()
+		expAnalytics model.Analytics
+		expError     error
+	}{
+		{
+			name: "analytics are computed",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"column_id", "count", "avg"}).AddRow(1, 2, 5400.0)
+				mock.ExpectPrepare("SELECT column_id, (.+) LEAD(.+) GROUP BY column_id").
+					ExpectQuery().WithArgs(1, from, to).WillReturnRows(rows)
+
+				rows = sqlmock.NewRows([]string{"lead", "p50", "p85", "p95", "cycle", "p50", "p85", "p95"}).
+					AddRow(2, 3600.0, 6660.0, 7020.0, 0, nil, nil, nil)
+				mock.ExpectPrepare("SELECT (.+) percentile_cont(.+) FIRST_VALUE(.+)").
+					ExpectQuery().WithArgs(1, from, to).WillReturnRows(rows)
+			},
+			expAnalytics: model.Analytics{
+				From:      from,
+				To:        to,
+				Columns:   []model.ColumnDwell{{ColumnID: 1, Count: 2, AverageSeconds: 5400}},
+				LeadTime:  model.Percentiles{Count: 2, P50: 3600, P85: 6660, P95: 7020},
+				CycleTime: model.Percentiles{},
+			},
+			expError: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		r := newTransitionRepo(newStatements(db))
+		tc.mock()
+
+		a, err := r.Analytics(context.Background(), 1, from, to)
+
+		assert.Equal(t, tc.expError, err)
+		assert.Equal(t, tc.expAnalytics, a)
+	}
+}
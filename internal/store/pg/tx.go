@@ -0,0 +1,77 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// txRepos are the repositories bound to a transaction.
+type txRepos struct {
+	projectRepo        *projectRepo
+	columnRepo         *columnRepo
+	taskRepo           *taskRepo
+	commentRepo        *commentRepo
+	transitionRepo     *transitionRepo
+	columnSnapshotRepo *columnSnapshotRepo
+	taskLinkRepo       *taskLinkRepo
+}
+
+// InTx runs fn in a transaction. Uniqueness of indices is checked on commit, so indices
+// can be swapped within the transaction.
+func (s *Store) InTx(ctx context.Context, fn func(store.Tx) error) error {
+	return s.inTx(ctx, nil, fn)
+}
+
+// InSerializableTx runs fn in a serializable transaction. A transaction which can't be
+// serialized with concurrent ones fails with store.ErrConflict and can be retried.
+func (s *Store) InSerializableTx(ctx context.Context, fn func(store.Tx) error) error {
+	return s.inTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, fn)
+}
+
+// inTx runs fn in a transaction with the options.
+func (s *Store) inTx(ctx context.Context, opts *sql.TxOptions, fn func(store.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmts := s.stmts.inTx(tx)
+	err = fn(&txRepos{
+		projectRepo:        newProjectRepo(stmts),
+		columnRepo:         newColumnRepo(stmts),
+		taskRepo:           newTaskRepo(stmts),
+		commentRepo:        newCommentRepo(stmts),
+		transitionRepo:     newTransitionRepo(stmts),
+		columnSnapshotRepo: newColumnSnapshotRepo(stmts),
+		taskLinkRepo:       newTaskLinkRepo(stmts),
+	})
+	if err != nil {
+		return storeError(err)
+	}
+
+	return storeError(tx.Commit())
+}
+
+// Projects returns the project repository.
+func (r *txRepos) Projects() store.ProjectRepo { return r.projectRepo }
+
+// Columns returns the column repository.
+func (r *txRepos) Columns() store.ColumnRepo { return r.columnRepo }
+
+// Tasks returns the task repository.
+func (r *txRepos) Tasks() store.TaskRepo { return r.taskRepo }
+
+// Comments returns the comment repository.
+func (r *txRepos) Comments() store.CommentRepo { return r.commentRepo }
+
+// Transitions returns the transition repository.
+func (r *txRepos) Transitions() store.TransitionRepo { return r.transitionRepo }
+
+// ColumnSnapshots returns the column snapshot repository.
+func (r *txRepos) ColumnSnapshots() store.ColumnSnapshotRepo { return r.columnSnapshotRepo }
+
+// TaskLinks returns the task link repository.
+func (r *txRepos) TaskLinks() store.TaskLinkRepo { return r.taskLinkRepo }
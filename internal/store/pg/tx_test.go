@@ -0,0 +1,77 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+func TestStore_InTx(t *testing.T) {
+	errRollback := errors.New("rollback")
+
+	testcases := []struct {
+		name     string
+		mock     func(sqlmock.Sqlmock)
+		fn       func(store.Tx) error
+		expError error
+	}{
+		{
+			name: "transaction is committed",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectPrepare("UPDATE tasks SET (.+) WHERE id = (.+);").ExpectExec().WithArgs(
+					"Task 1", "", 2, 1, nil, nil, 1,
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(tx store.Tx) error {
+				task := model.Task{ID: 1, Name: "Task 1", Index: 2, ColumnID: 1}
+				_, err := tx.Tasks().Update(context.Background(), task)
+				return err
+			},
+			expError: nil,
+		},
+		{
+			name: "transaction is rolled back",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn:       func(tx store.Tx) error { return errRollback },
+			expError: errRollback,
+		},
+		{
+			name: "duplicate index is detected on commit",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().WillReturnError(&pq.Error{Code: uniqueViolation})
+			},
+			fn:       func(tx store.Tx) error { return nil },
+			expError: store.ErrConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			s := &Store{db: db, stmts: newStatements(db)}
+			tc.mock(mock)
+
+			err = s.InTx(context.Background(), tc.fn)
+
+			assert.Equal(t, tc.expError, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// columnRepo is the column repository for SQLite store.
+type columnRepo struct {
+	db querier
+}
+
+// newColumnRepo creates and returns a new columnRepo instance.
+func newColumnRepo(db querier) *columnRepo { return &columnRepo{db: db} }
+
+// GetByProjectID returns all columns with specific project ID.
+func (r *columnRepo) GetByProjectID(ctx context.Context, id int) ([]model.Column, error) {
+	if err := exists(ctx, r.db, "projects", id); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, name, "index", project_id, type, wip_limit FROM columns
+		WHERE project_id = ? ORDER BY id;`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cs, c := []model.Column{}, model.Column{}
+	for rows.Next() {
+		if err = rows.Scan(&c.ID, &c.Name, &c.Index, &c.ProjectID, &c.Type, &c.WIPLimit); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+// Create creates and returns a new column.
+func (r *columnRepo) Create(ctx context.Context, c model.Column) (model.Column, error) {
+	query := `INSERT INTO columns (name, "index", project_id, type, wip_limit) VALUES (?, ?, ?, ?, ?);`
+	res, err := r.db.ExecContext(ctx, query, c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit)
+	if err != nil {
+		return model.Column{}, storeError(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return model.Column{}, err
+	}
+	c.ID = int(id)
+
+	return c, nil
+}
+
+// GetByID returns the column with specifc ID.
+func (r *columnRepo) GetByID(ctx context.Context, id int) (model.Column, error) {
+	query := `SELECT id, name, "index", project_id, type, wip_limit FROM columns WHERE id = ?;`
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var c model.Column
+	err := row.Scan(&c.ID, &c.Name, &c.Index, &c.ProjectID, &c.Type, &c.WIPLimit)
+	if err == sql.ErrNoRows {
+		return model.Column{}, store.ErrNotFound
+	} else if err != nil {
+		return model.Column{}, err
+	}
+
+	return c, nil
+}
+
+// GetByIndexAndProjectID returns the column with specific index and project ID.
+func (r *columnRepo) GetByIndexAndProjectID(ctx context.Context, index, id int) (model.Column, error) {
+	query := `SELECT id, name, "index", project_id, type, wip_limit FROM columns
+		WHERE "index" = ? AND project_id = ?;`
+	row := r.db.QueryRowContext(ctx, query, index, id)
+
+	var c model.Column
+	err := row.Scan(&c.ID, &c.Name, &c.Index, &c.ProjectID, &c.Type, &c.WIPLimit)
+	if err == sql.ErrNoRows {
+		return model.Column{}, store.ErrNotFound
+	} else if err != nil {
+		return model.Column{}, err
+	}
+
+	return c, nil
+}
+
+// Update updates the column.
+func (r *columnRepo) Update(ctx context.Context, c model.Column) (model.Column, error) {
+	query := `UPDATE columns SET name = ?, "index" = ?, project_id = ?, type = ?, wip_limit = ?
+		WHERE id = ?;`
+	res, err := r.db.ExecContext(ctx, query, c.Name, c.Index, c.ProjectID, c.Type, c.WIPLimit, c.ID)
+	if err != nil {
+		return model.Column{}, storeError(err)
+	}
+	if err := affected(res); err != nil {
+		return model.Column{}, err
+	}
+
+	return c, nil
+}
+
+// DeleteByID deletes the column with specific ID.
+func (r *columnRepo) DeleteByID(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM columns WHERE id = ?;", id)
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+func TestColumnRepo(t *testing.T) {
+	ctx := context.Background()
+	s := testStore(t)
+	r := s.Columns()
+
+	_, err := r.GetByProjectID(ctx, 1)
+	assert.Equal(t, store.ErrNotFound, err)
+
+	p, _ := s.Projects().Create(ctx, model.Project{Name: "Project"})
+	c1, err := r.Create(ctx, model.Column{Name: "To do", Index: 1, ProjectID: p.ID})
+	assert.NoError(t, err)
+	c2, err := r.Create(ctx, model.Column{Name: "Done", Index: 2, ProjectID: p.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, model.Column{ID: 2, Name: "Done", Index: 2, ProjectID: p.ID}, c2)
+
+	cs, err := r.GetByProjectID(ctx, p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Column{c1, c2}, cs)
+	c, err := r.GetByIndexAndProjectID(ctx, 2, p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, c2, c)
+
+	c1.Index, c2.Index = 2, 1
+	_, err = r.Update(ctx, c1)
+	assert.NoError(t, err)
+	_, err = r.Update(ctx, c2)
+	assert.NoError(t, err)
+	c, err = r.GetByID(ctx, c2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, c2, c)
+
+	assert.NoError(t, r.DeleteByID(ctx, c1.ID))
+	_, err = r.GetByID(ctx, c1.ID)
+	assert.Equal(t, store.ErrNotFound, err)
+
+	// Columns are deleted with their project.
+	assert.NoError(t, s.Projects().DeleteByID(ctx, p.ID))
+	_, err = r.GetByID(ctx, c2.ID)
+	assert.Equal(t, store.ErrNotFound, err)
+}
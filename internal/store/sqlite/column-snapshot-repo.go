@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+// columnSnapshotRepo is the column snapshot repository for SQLite store.
+type columnSnapshotRepo struct {
+	db querier
+}
+
+// newColumnSnapshotRepo creates and returns a new columnSnapshotRepo instance.
+func newColumnSnapshotRepo(db querier) *columnSnapshotRepo { return &columnSnapshotRepo{db: db} }
+
+// Save creates the snapshot or replaces the one of the same column and day.
+func (r *columnSnapshotRepo) Save(ctx context.Context, s model.ColumnSnapshot) (model.ColumnSnapshot, error) {
+	query := `INSERT INTO column_snapshots (project_id, column_id, column_name, column_index, day, count)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (column_id, day) DO UPDATE SET project_id = excluded.project_id,
+			column_name = excluded.column_name, column_index = excluded.column_index, count = excluded.count;`
+	_, err := r.db.ExecContext(
+		ctx, query, s.ProjectID, s.ColumnID, s.ColumnName, s.ColumnIndex, utc(&s.Day), s.Count,
+	)
+	if err != nil {
+		return model.ColumnSnapshot{}, storeError(err)
+	}
+	// LastInsertId isn't set when the snapshot is replaced.
+	query = "SELECT id FROM column_snapshots WHERE column_id = ? AND day = ?;"
+	if err := r.db.QueryRowContext(ctx, query, s.ColumnID, utc(&s.Day)).Scan(&s.ID); err != nil {
+		return model.ColumnSnapshot{}, err
+	}
+
+	return s, nil
+}
+
+// GetByProjectID returns snapshots of the project with specific ID from day from to day
+// to inclusive ordered by day and column ID.
+func (r *columnSnapshotRepo) GetByProjectID(
+	ctx context.Context, id int, from, to time.Time,
+) ([]model.ColumnSnapshot, error) {
+	query := `SELECT id, project_id, column_id, column_name, column_index, day, count FROM column_snapshots
+		WHERE project_id = ? AND day >= ? AND day <= ? ORDER BY day, column_id;`
+	rows, err := r.db.QueryContext(ctx, query, id, utc(&from), utc(&to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ss, s := []model.ColumnSnapshot{}, model.ColumnSnapshot{}
+	for rows.Next() {
+		err := rows.Scan(&s.ID, &s.ProjectID, &s.ColumnID, &s.ColumnName, &s.ColumnIndex, &s.Day, &s.Count)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// commentRepo is the comment repository for SQLite store.
+type commentRepo struct {
+	db querier
+}
+
+// newCommentRepo creates and returns a new commentRepo instance.
+func newCommentRepo(db querier) *commentRepo { return &commentRepo{db: db} }
+
+// GetAll returns all comments.
+func (r *commentRepo) GetAll(ctx context.Context) ([]model.Comment, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, text, created_at, task_id FROM comments ORDER BY id;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cs, c := []model.Comment{}, model.Comment{}
+	for rows.Next() {
+		if err = rows.Scan(&c.ID, &c.Text, &c.CreatedAt, &c.TaskID); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+// GetByTaskID returns all comments with specific task ID.
+func (r *commentRepo) GetByTaskID(ctx context.Context, id int) ([]model.Comment, error) {
+	if err := exists(ctx, r.db, "tasks", id); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT id, text, created_at, task_id FROM comments WHERE task_id = ? ORDER BY id;"
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cs, c := []model.Comment{}, model.Comment{}
+	for rows.Next() {
+		if err := rows.Scan(&c.ID, &c.Text, &c.CreatedAt, &c.TaskID); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+// Create creates and returns a new comment.
+func (r *commentRepo) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
+	query := "INSERT INTO comments (text, created_at, task_id) VALUES (?, ?, ?);"
+	res, err := r.db.ExecContext(ctx, query, c.Text, c.CreatedAt, c.TaskID)
+	if err != nil {
+		return model.Comment{}, storeError(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return model.Comment{}, err
+	}
+	c.ID = int(id)
+
+	return c, nil
+}
+
+// GetByID returns the comment with specific ID.
+func (r *commentRepo) GetByID(ctx context.Context, id int) (model.Comment, error) {
+	query := "SELECT id, text, created_at, task_id FROM comments WHERE id = ?;"
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var c model.Comment
+	err := row.Scan(&c.ID, &c.Text, &c.CreatedAt, &c.TaskID)
+	if err == sql.ErrNoRows {
+		return model.Comment{}, store.ErrNotFound
+	} else if err != nil {
+		return model.Comment{}, err
+	}
+
+	return c, nil
+}
+
+// Update updates the comment.
+func (r *commentRepo) Update(ctx context.Context, c model.Comment) (model.Comment, error) {
+	query := "UPDATE comments SET text = ?, created_at = ?, task_id = ? WHERE id = ?;"
+	res, err := r.db.ExecContext(ctx, query, c.Text, c.CreatedAt, c.TaskID, c.ID)
+	if err != nil {
+		return model.Comment{}, storeError(err)
+	}
+	if err := affected(res); err != nil {
+		return model.Comment{}, err
+	}
+
+	return c, nil
+}
+
+// DeleteByID deletes the comment with specific ID.
+func (r *commentRepo) DeleteByID(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM comments WHERE id = ?;", id)
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
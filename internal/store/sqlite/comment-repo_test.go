@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+func TestCommentRepo(t *testing.T) {
+	ctx := context.Background()
+	s := testStore(t)
+	r := s.Comments()
+
+	_, err := r.GetByTaskID(ctx, 1)
+	assert.Equal(t, store.ErrNotFound, err)
+
+	p, _ := s.Projects().Create(ctx, model.Project{Name: "Project"})
+	col, _ := s.Columns().Create(ctx, model.Column{Name: "To do", Index: 1, ProjectID: p.ID})
+	task, _ := s.Tasks().Create(ctx, model.Task{Name: "Task", Index: 1, ColumnID: col.ID})
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	c, err := r.Create(ctx, model.Comment{Text: "Comment", CreatedAt: createdAt, TaskID: task.ID})
+	assert.NoError(t, err)
+
+	cs, err := r.GetByTaskID(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Comment{c}, cs)
+
+	c.Text = "Updated"
+	_, err = r.Update(ctx, c)
+	assert.NoError(t, err)
+	got, err := r.GetByID(ctx, c.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, c, got)
+
+	assert.NoError(t, r.DeleteByID(ctx, c.ID))
+	_, err = r.GetByID(ctx, c.ID)
+	assert.Equal(t, store.ErrNotFound, err)
+}
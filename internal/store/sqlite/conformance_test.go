@@ -0,0 +1,12 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/internal/store/storetest"
+)
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store { return testStore(t) })
+}
@@ -0,0 +1,2 @@
+// Package sqlite provides SQLite store for running Tasker with a local file database.
+package sqlite
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// idempotencyKeyRepo is the idempotency key repository for SQLite store. Times are
+// stored in UTC as SQLite compares them as strings, headers are stored as JSON.
+type idempotencyKeyRepo struct {
+	db querier
+}
+
+// newIdempotencyKeyRepo creates and returns a new idempotencyKeyRepo instance.
+func newIdempotencyKeyRepo(db querier) *idempotencyKeyRepo { return &idempotencyKeyRepo{db: db} }
+
+// Create creates and returns a new idempotency key.
+func (r *idempotencyKeyRepo) Create(ctx context.Context, k model.IdempotencyKey) (model.IdempotencyKey, error) {
+	query := `INSERT INTO idempotency_keys (key, fingerprint, status, content_type, headers, body, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
+	if k.Body == nil {
+		k.Body = []byte{}
+	}
+	headers, err := encodeHeaders(k)
+	if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	_, err = r.db.ExecContext(
+		ctx, query, k.Key, k.Fingerprint, k.Status, k.ContentType, headers, k.Body, k.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return model.IdempotencyKey{}, storeError(err)
+	}
+
+	return k, nil
+}
+
+// GetByKey returns the idempotency key.
+func (r *idempotencyKeyRepo) GetByKey(ctx context.Context, key string) (model.IdempotencyKey, error) {
+	query := `SELECT key, fingerprint, status, content_type, headers, body, created_at FROM idempotency_keys
+		WHERE key = ?;`
+	row := r.db.QueryRowContext(ctx, query, key)
+
+	var k model.IdempotencyKey
+	var headers string
+	err := row.Scan(&k.Key, &k.Fingerprint, &k.Status, &k.ContentType, &headers, &k.Body, &k.CreatedAt)
+	if err == sql.ErrNoRows {
+		return model.IdempotencyKey{}, store.ErrNotFound
+	} else if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	if err := json.Unmarshal([]byte(headers), &k.Headers); err != nil {
+		return model.IdempotencyKey{}, err
+	}
+
+	return k, nil
+}
+
+// Update saves the response of the idempotency key.
+func (r *idempotencyKeyRepo) Update(ctx context.Context, k model.IdempotencyKey) (model.IdempotencyKey, error) {
+	query := `UPDATE idempotency_keys SET status = ?, content_type = ?, headers = ?, body = ?
+		WHERE key = ? AND fingerprint = ? AND created_at = ?;`
+	if k.Body == nil {
+		k.Body = []byte{}
+	}
+	headers, err := encodeHeaders(k)
+	if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	res, err := r.db.ExecContext(
+		ctx, query, k.Status, k.ContentType, headers, k.Body, k.Key, k.Fingerprint, k.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return model.IdempotencyKey{}, err
+	}
+	if err := affected(res); err != nil {
+		return model.IdempotencyKey{}, err
+	}
+
+	return k, nil
+}
+
+// Delete deletes the idempotency key.
+func (r *idempotencyKeyRepo) Delete(ctx context.Context, k model.IdempotencyKey) error {
+	query := "DELETE FROM idempotency_keys WHERE key = ? AND fingerprint = ? AND created_at = ?;"
+	res, err := r.db.ExecContext(ctx, query, k.Key, k.Fingerprint, k.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
+
+// DeleteCreatedBefore deletes idempotency keys created before t.
+func (r *idempotencyKeyRepo) DeleteCreatedBefore(ctx context.Context, t time.Time) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE created_at < ?;", t.UTC())
+
+	return err
+}
+
+// encodeHeaders returns the headers of the key as JSON.
+func encodeHeaders(k model.IdempotencyKey) (string, error) {
+	if k.Headers == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(k.Headers)
+
+	return string(b), err
+}
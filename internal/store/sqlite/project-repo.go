@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// projectRepo is the project repository for SQLite store.
+type projectRepo struct {
+	db querier
+}
+
+// newProjectRepo creates and returns a new projectRepo instance.
+func newProjectRepo(db querier) *projectRepo { return &projectRepo{db: db} }
+
+// GetAll returns all projects.
+func (r *projectRepo) GetAll(ctx context.Context) ([]model.Project, error) {
+	query := "SELECT id, name, description, soft_wip_limits, strict_dependencies FROM projects ORDER BY id;"
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ps, p := []model.Project{}, model.Project{}
+	for rows.Next() {
+		if err = rows.Scan(&p.ID, &p.Name, &p.Description, &p.SoftWIPLimits, &p.StrictDependencies); err != nil {
+			return nil, err
+		}
+		ps = append(ps, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+// GetCounts returns the number of columns and tasks of every project.
+func (r *projectRepo) GetCounts(ctx context.Context) ([]model.ProjectCounts, error) {
+	query := `SELECT p.id, COUNT(DISTINCT c.id), COUNT(t.id) FROM projects p
+		LEFT JOIN columns c ON c.project_id = p.id LEFT JOIN tasks t ON t.column_id = c.id
+		GROUP BY p.id ORDER BY p.id;`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pcs, pc := []model.ProjectCounts{}, model.ProjectCounts{}
+	for rows.Next() {
+		if err = rows.Scan(&pc.ProjectID, &pc.Columns, &pc.Tasks); err != nil {
+			return nil, err
+		}
+		pcs = append(pcs, pc)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pcs, nil
+}
+
+// Create creates and returns a new project.
+func (r *projectRepo) Create(ctx context.Context, p model.Project) (model.Project, error) {
+	query := "INSERT INTO projects (name, description, soft_wip_limits, strict_dependencies) VALUES (?, ?, ?, ?);"
+	res, err := r.db.ExecContext(ctx, query, p.Name, p.Description, p.SoftWIPLimits, p.StrictDependencies)
+	if err != nil {
+		return model.Project{}, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return model.Project{}, err
+	}
+	p.ID = int(id)
+
+	return p, nil
+}
+
+// GetByID returns the project with specific ID.
+func (r *projectRepo) GetByID(ctx context.Context, id int) (model.Project, error) {
+	query := "SELECT id, name, description, soft_wip_limits, strict_dependencies FROM projects WHERE id = ?;"
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var p model.Project
+	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.SoftWIPLimits, &p.StrictDependencies)
+	if err == sql.ErrNoRows {
+		return model.Project{}, store.ErrNotFound
+	} else if err != nil {
+		return model.Project{}, err
+	}
+
+	return p, nil
+}
+
+// Update updates the project.
+func (r *projectRepo) Update(ctx context.Context, p model.Project) (model.Project, error) {
+	query := "UPDATE projects SET name = ?, description = ?, soft_wip_limits = ?, strict_dependencies = ? WHERE id = ?;"
+	res, err := r.db.ExecContext(ctx, query, p.Name, p.Description, p.SoftWIPLimits, p.StrictDependencies, p.ID)
+	if err != nil {
+		return model.Project{}, err
+	}
+	if err := affected(res); err != nil {
+		return model.Project{}, err
+	}
+
+	return p, nil
+}
+
+// DeleteByID deletes the project with specific ID.
+func (r *projectRepo) DeleteByID(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM projects WHERE id = ?;", id)
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
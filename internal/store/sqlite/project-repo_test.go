@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+func TestProjectRepo(t *testing.T) {
+	ctx := context.Background()
+	r := testStore(t).Projects()
+
+	ps, err := r.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Project{}, ps)
+
+	p, err := r.Create(ctx, model.Project{Name: "Project", Description: "Description"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.Project{ID: 1, Name: "Project", Description: "Description"}, p)
+
+	p.Name = "Updated project"
+	_, err = r.Update(ctx, p)
+	assert.NoError(t, err)
+	got, err := r.GetByID(ctx, p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, p, got)
+	ps, err = r.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Project{p}, ps)
+
+	assert.NoError(t, r.DeleteByID(ctx, p.ID))
+	_, err = r.GetByID(ctx, p.ID)
+	assert.Equal(t, store.ErrNotFound, err)
+	_, err = r.Update(ctx, p)
+	assert.Equal(t, store.ErrNotFound, err)
+	assert.Equal(t, store.ErrNotFound, r.DeleteByID(ctx, p.ID))
+}
@@ -0,0 +1,220 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/httpfs"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/store"
+	"github.com/imarrche/tasker/schema"
+)
+
+// Store is SQLite store.
+type Store struct {
+	config      config.SQLite
+	db          *sql.DB
+	projectRepo *projectRepo
+	columnRepo  *columnRepo
+	taskRepo    *taskRepo
+	commentRepo *commentRepo
+
+	transitionRepo     *transitionRepo
+	columnSnapshotRepo *columnSnapshotRepo
+	taskLinkRepo       *taskLinkRepo
+	idempotencyKeyRepo *idempotencyKeyRepo
+}
+
+// New creates new Store instance.
+func New(config config.SQLite) *Store {
+	return &Store{config: config}
+}
+
+// Open opens SQLite database file creating it if it doesn't exist and migrates schema to
+// the latest version.
+func (s *Store) Open() error {
+	if err := s.Connect(); err != nil {
+		return err
+	}
+
+	m, err := s.Migrator()
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// Connect opens SQLite database file creating it if it doesn't exist without migrating
+// schema.
+func (s *Store) Connect() error {
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL", s.config.Path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return err
+	}
+	// SQLite allows only one writer at a time, a single connection serializes access
+	// instead of failing with "database is locked".
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		return err
+	}
+
+	s.db = db
+	s.projectRepo = newProjectRepo(db)
+	s.columnRepo = newColumnRepo(db)
+	s.taskRepo = newTaskRepo(db)
+	s.commentRepo = newCommentRepo(db)
+	s.transitionRepo = newTransitionRepo(db)
+	s.columnSnapshotRepo = newColumnSnapshotRepo(db)
+	s.taskLinkRepo = newTaskLinkRepo(db)
+	s.idempotencyKeyRepo = newIdempotencyKeyRepo(db)
+
+	return nil
+}
+
+// Migrator returns the schema migrator for the opened database using migrations embedded
+// into the binary.
+func (s *Store) Migrator() (*migrate.Migrate, error) {
+	src, err := httpfs.New(http.FS(schema.SQLite), "/sqlite")
+	if err != nil {
+		return nil, err
+	}
+	driver, err := migratesqlite.WithInstance(s.db, &migratesqlite.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("httpfs", src, "sqlite3", driver)
+}
+
+// Ping checks whether SQLite database is available.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// MigrationVersion returns the current schema version and whether the last migration
+// failed leaving schema dirty.
+func (s *Store) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1;")
+
+	var version uint
+	var dirty bool
+	if err := row.Scan(&version, &dirty); err != nil {
+		return 0, false, err
+	}
+
+	return version, dirty, nil
+}
+
+// Projects returns the project repository.
+func (s *Store) Projects() store.ProjectRepo {
+	return s.projectRepo
+}
+
+// Columns returns the column repository.
+func (s *Store) Columns() store.ColumnRepo {
+	return s.columnRepo
+}
+
+// Tasks returns the task repository.
+func (s *Store) Tasks() store.TaskRepo {
+	return s.taskRepo
+}
+
+// Comments returns the comment repository.
+func (s *Store) Comments() store.CommentRepo {
+	return s.commentRepo
+}
+
+// Transitions returns the transition repository.
+func (s *Store) Transitions() store.TransitionRepo {
+	return s.transitionRepo
+}
+
+// ColumnSnapshots returns the column snapshot repository.
+func (s *Store) ColumnSnapshots() store.ColumnSnapshotRepo {
+	return s.columnSnapshotRepo
+}
+
+// TaskLinks returns the task link repository.
+func (s *Store) TaskLinks() store.TaskLinkRepo { return s.taskLinkRepo }
+
+// IdempotencyKeys returns the idempotency key repository.
+func (s *Store) IdempotencyKeys() store.IdempotencyKeyRepo {
+	return s.idempotencyKeyRepo
+}
+
+// Stats returns the database connection pool stats.
+func (s *Store) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// DB returns the database connection pool for tools working with any schema version.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes SQLite database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// exists checks whether the row with specific ID exists in the table.
+func exists(ctx context.Context, db querier, table string, id int) error {
+	var found int
+	err := db.QueryRowContext(ctx, "SELECT id FROM "+table+" WHERE id = ?;", id).Scan(&found)
+	if err == sql.ErrNoRows {
+		return store.ErrNotFound
+	}
+
+	return err
+}
+
+// affected returns store.ErrNotFound if no rows were affected.
+func affected(res sql.Result) error {
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return err
+	} else if rowsCount == 0 {
+		return store.ErrNotFound
+	}
+
+	return nil
+}
+
+// utc converts the time to UTC keeping nil as NULL.
+func utc(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+
+	return t.UTC()
+}
+
+// storeError converts SQLite constraint violations to store errors.
+func storeError(err error) error {
+	e, ok := err.(sqlite3.Error)
+	if !ok {
+		return err
+	}
+
+	switch e.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return store.ErrConflict
+	case sqlite3.ErrConstraintForeignKey:
+		return store.ErrInvalidReference
+	default:
+		return err
+	}
+}
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imarrche/tasker/internal/config"
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/schema"
+)
+
+// testStore opens a new store with empty database file removed after the test.
+func testStore(t *testing.T) *Store {
+	dir, err := ioutil.TempDir("", "tasker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(config.SQLite{Path: filepath.Join(dir, "tasker.db")})
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		os.RemoveAll(dir)
+	})
+
+	return s
+}
+
+func TestStore_Open(t *testing.T) {
+	s := testStore(t)
+
+	assert.NoError(t, s.Ping(context.Background()))
+	version, dirty, err := s.MigrationVersion(context.Background())
+	assert.NoError(t, err)
+	versions, err := schema.Versions(schema.SQLite, "sqlite")
+	require.NoError(t, err)
+	assert.Equal(t, versions[len(versions)-1], version)
+	assert.False(t, dirty)
+
+	// Reopening already migrated database.
+	reopened := New(s.config)
+	assert.NoError(t, reopened.Open())
+	assert.NoError(t, reopened.Close())
+}
+
+func TestStore_OpenViolatingConstraints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tasker")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	s := New(config.SQLite{Path: filepath.Join(dir, "tasker.db")})
+	require.NoError(t, s.Connect())
+
+	// Board created before unique constraints.
+	m, err := s.Migrator()
+	require.NoError(t, err)
+	require.NoError(t, m.Migrate(20201227073627))
+	for _, query := range []string{
+		`INSERT INTO projects (id, name, description) VALUES (1, 'Project', '');`,
+		`INSERT INTO columns (id, name, "index", project_id) VALUES (1, 'To do', 2, 1);`,
+		`INSERT INTO columns (id, name, "index", project_id) VALUES (2, 'To do', 2, 1);`,
+		`INSERT INTO tasks (id, name, description, "index", column_id) VALUES (1, 'Task 1', '', 3, 1);`,
+		`INSERT INTO tasks (id, name, description, "index", column_id) VALUES (2, 'Task 2', '', 1, 1);`,
+		`INSERT INTO tasks (id, name, description, "index", column_id) VALUES (3, 'Task 3', '', 1, 1);`,
+	} {
+		_, err = s.db.Exec(query)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, s.Close())
+
+	s = New(s.config)
+	require.NoError(t, s.Open())
+	defer s.Close()
+
+	ctx := context.Background()
+	cs, err := s.Columns().GetByProjectID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Column{
+		{ID: 1, Name: "To do", Index: 1, ProjectID: 1, Type: model.ColumnTypeBacklog},
+		{ID: 2, Name: "To do (2)", Index: 2, ProjectID: 1, Type: model.ColumnTypeBacklog},
+	}, cs)
+	ts, err := s.Tasks().GetByColumnID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1, 2}, []int{ts[0].Index, ts[1].Index, ts[2].Index})
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+// taskLinkRepo is the task link repository for SQLite store.
+type taskLinkRepo struct {
+	db querier
+}
+
+// newTaskLinkRepo creates and returns a new taskLinkRepo instance.
+func newTaskLinkRepo(db querier) *taskLinkRepo { return &taskLinkRepo{db: db} }
+
+// GetByTaskID returns links the task with specific ID takes either side of.
+func (r *taskLinkRepo) GetByTaskID(ctx context.Context, id int) ([]model.TaskLink, error) {
+	if err := exists(ctx, r.db, "tasks", id); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT blocker_id, blocked_id FROM task_links WHERE blocker_id = ? OR blocked_id = ?
+		ORDER BY blocker_id, blocked_id;`
+	rows, err := r.db.QueryContext(ctx, query, id, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ls, l := []model.TaskLink{}, model.TaskLink{}
+	for rows.Next() {
+		if err = rows.Scan(&l.BlockerID, &l.BlockedID); err != nil {
+			return nil, err
+		}
+		ls = append(ls, l)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ls, nil
+}
+
+// Create creates and returns a new task link.
+func (r *taskLinkRepo) Create(ctx context.Context, l model.TaskLink) (model.TaskLink, error) {
+	query := "INSERT INTO task_links (blocker_id, blocked_id) VALUES (?, ?);"
+	if _, err := r.db.ExecContext(ctx, query, l.BlockerID, l.BlockedID); err != nil {
+		return model.TaskLink{}, storeError(err)
+	}
+
+	return l, nil
+}
+
+// Delete deletes the task link.
+func (r *taskLinkRepo) Delete(ctx context.Context, l model.TaskLink) error {
+	query := "DELETE FROM task_links WHERE blocker_id = ? AND blocked_id = ?;"
+	res, err := r.db.ExecContext(ctx, query, l.BlockerID, l.BlockedID)
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
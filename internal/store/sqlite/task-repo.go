@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// taskColumns are the tasks table columns followed by whether the task is blocked by
+// a task which isn't completed.
+const taskColumns = `id, name, description, "index", column_id, started_at, completed_at,
+	EXISTS (SELECT 1 FROM task_links JOIN tasks blockers ON blockers.id = task_links.blocker_id
+		WHERE task_links.blocked_id = tasks.id AND blockers.completed_at IS NULL)`
+
+// taskRepo is the task repository for SQLite store. Times are stored in UTC as SQLite
+// compares them as strings.
+type taskRepo struct {
+	db querier
+}
+
+// newTaskRepo creates and returns a new taskRepo instance.
+func newTaskRepo(db querier) *taskRepo { return &taskRepo{db: db} }
+
+// GetAll returns all tasks.
+func (r *taskRepo) GetAll(ctx context.Context) ([]model.Task, error) {
+	query := "SELECT " + taskColumns + ` FROM tasks
+		ORDER BY id;`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ts, t := []model.Task{}, model.Task{}
+	for rows.Next() {
+		err = rows.Scan(&t.ID, &t.Name, &t.Description, &t.Index, &t.ColumnID, &t.StartedAt, &t.CompletedAt, &t.Blocked)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// GetByColumnID returns all tasks with specific column ID.
+func (r *taskRepo) GetByColumnID(ctx context.Context, id int) ([]model.Task, error) {
+	if err := exists(ctx, r.db, "columns", id); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + taskColumns + ` FROM tasks
+		WHERE column_id = ? ORDER BY id;`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ts, t := []model.Task{}, model.Task{}
+	for rows.Next() {
+		err = rows.Scan(&t.ID, &t.Name, &t.Description, &t.Index, &t.ColumnID, &t.StartedAt, &t.CompletedAt, &t.Blocked)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// Create creates and returns a new task.
+func (r *taskRepo) Create(ctx context.Context, t model.Task) (model.Task, error) {
+	query := `INSERT INTO tasks (name, description, "index", column_id, started_at, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?);`
+	res, err := r.db.ExecContext(
+		ctx, query, t.Name, t.Description, t.Index, t.ColumnID, utc(t.StartedAt), utc(t.CompletedAt),
+	)
+	if err != nil {
+		return model.Task{}, storeError(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return model.Task{}, err
+	}
+	t.ID = int(id)
+
+	return t, nil
+}
+
+// GetByID returns the task with specifc ID.
+func (r *taskRepo) GetByID(ctx context.Context, id int) (model.Task, error) {
+	query := "SELECT " + taskColumns + ` FROM tasks
+		WHERE id = ?;`
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var t model.Task
+	err := row.Scan(&t.ID, &t.Name, &t.Description, &t.Index, &t.ColumnID, &t.StartedAt, &t.CompletedAt, &t.Blocked)
+	if err == sql.ErrNoRows {
+		return model.Task{}, store.ErrNotFound
+	} else if err != nil {
+		return model.Task{}, err
+	}
+
+	return t, nil
+}
+
+// GetByIndexAndColumnID returns the task with specific index and column ID.
+func (r *taskRepo) GetByIndexAndColumnID(ctx context.Context, index, id int) (model.Task, error) {
+	query := "SELECT " + taskColumns + ` FROM tasks
+		WHERE "index" = ? AND column_id = ?;`
+	row := r.db.QueryRowContext(ctx, query, index, id)
+
+	var t model.Task
+	err := row.Scan(&t.ID, &t.Name, &t.Description, &t.Index, &t.ColumnID, &t.StartedAt, &t.CompletedAt, &t.Blocked)
+	if err == sql.ErrNoRows {
+		return model.Task{}, store.ErrNotFound
+	} else if err != nil {
+		return model.Task{}, err
+	}
+
+	return t, nil
+}
+
+// Update updates the tasks.
+func (r *taskRepo) Update(ctx context.Context, t model.Task) (model.Task, error) {
+	query := `UPDATE tasks SET name = ?, description = ?, "index" = ?, column_id = ?, started_at = ?,
+		completed_at = ? WHERE id = ?;`
+	res, err := r.db.ExecContext(
+		ctx, query, t.Name, t.Description, t.Index, t.ColumnID, utc(t.StartedAt), utc(t.CompletedAt), t.ID,
+	)
+	if err != nil {
+		return model.Task{}, storeError(err)
+	}
+	if err := affected(res); err != nil {
+		return model.Task{}, err
+	}
+
+	return t, nil
+}
+
+// DeleteByID deletes the task with specific ID.
+func (r *taskRepo) DeleteByID(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?;", id)
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+func TestTaskRepo(t *testing.T) {
+	ctx := context.Background()
+	s := testStore(t)
+	r := s.Tasks()
+
+	_, err := r.GetByColumnID(ctx, 1)
+	assert.Equal(t, store.ErrNotFound, err)
+
+	p, _ := s.Projects().Create(ctx, model.Project{Name: "Project"})
+	c, _ := s.Columns().Create(ctx, model.Column{Name: "To do", Index: 1, ProjectID: p.ID})
+	t1, err := r.Create(ctx, model.Task{Name: "Task 1", Index: 1, ColumnID: c.ID})
+	assert.NoError(t, err)
+	t2, err := r.Create(ctx, model.Task{Name: "Task 2", Description: "Text", Index: 2, ColumnID: c.ID})
+	assert.NoError(t, err)
+
+	ts, err := r.GetByColumnID(ctx, c.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Task{t1, t2}, ts)
+	task, err := r.GetByIndexAndColumnID(ctx, 2, c.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, t2, task)
+
+	t1.Description = "Updated"
+	_, err = r.Update(ctx, t1)
+	assert.NoError(t, err)
+	task, err = r.GetByID(ctx, t1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, t1, task)
+
+	assert.NoError(t, r.DeleteByID(ctx, t1.ID))
+	assert.Equal(t, store.ErrNotFound, r.DeleteByID(ctx, t1.ID))
+
+	// Tasks are deleted with their column.
+	assert.NoError(t, s.Columns().DeleteByID(ctx, c.ID))
+	_, err = r.GetByID(ctx, t2.ID)
+	assert.Equal(t, store.ErrNotFound, err)
+}
@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/imarrche/tasker/internal/model"
+)
+
+// transitionRepo is the transition repository for SQLite store.
+type transitionRepo struct {
+	db querier
+}
+
+// newTransitionRepo creates and returns a new transitionRepo instance.
+func newTransitionRepo(db querier) *transitionRepo { return &transitionRepo{db: db} }
+
+// Create creates and returns a new transition.
+func (r *transitionRepo) Create(ctx context.Context, t model.Transition) (model.Transition, error) {
+	query := `INSERT INTO transitions (task_id, project_id, from_column_id, to_column_id, at)
+		VALUES (?, ?, ?, ?, ?);`
+	res, err := r.db.ExecContext(ctx, query, t.TaskID, t.ProjectID, t.FromColumnID, t.ToColumnID, utc(&t.At))
+	if err != nil {
+		return model.Transition{}, storeError(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return model.Transition{}, err
+	}
+	t.ID = int(id)
+
+	return t, nil
+}
+
+// GetByTaskID returns all transitions of the task with specific ID in order.
+func (r *transitionRepo) GetByTaskID(ctx context.Context, id int) ([]model.Transition, error) {
+	if err := exists(ctx, r.db, "tasks", id); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, task_id, project_id, from_column_id, to_column_id, at FROM transitions
+		WHERE task_id = ? ORDER BY at, id;`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ts, t := []model.Transition{}, model.Transition{}
+	for rows.Next() {
+		if err := rows.Scan(&t.ID, &t.TaskID, &t.ProjectID, &t.FromColumnID, &t.ToColumnID, &t.At); err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// Analytics computes metrics of the project with specific ID over the period with the
+// same window functions as PostgreSQL store. SQLite has no percentile_cont, so lead and
+// cycle times are selected and their percentiles are computed afterwards.
+func (r *transitionRepo) Analytics(ctx context.Context, id int, from, to time.Time) (model.Analytics, error) {
+	a := model.Analytics{From: from, To: to, Columns: []model.ColumnDwell{}}
+
+	query := `SELECT column_id, COUNT(*), AVG((julianday(left_at) - julianday(entered_at)) * 86400) FROM (
+			SELECT to_column_id AS column_id, at AS entered_at,
+				LEAD(at) OVER (PARTITION BY task_id ORDER BY at, id) AS left_at
+			FROM transitions WHERE project_id = ?
+		) AS stays
+		WHERE julianday(left_at) >= julianday(?) AND julianday(left_at) < julianday(?)
+		GROUP BY column_id ORDER BY column_id;`
+	rows, err := r.db.QueryContext(ctx, query, id, utc(&from), utc(&to))
+	if err != nil {
+		return model.Analytics{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d model.ColumnDwell
+		if err := rows.Scan(&d.ColumnID, &d.Count, &d.AverageSeconds); err != nil {
+			return model.Analytics{}, err
+		}
+		a.Columns = append(a.Columns, d)
+	}
+	if err = rows.Err(); err != nil {
+		return model.Analytics{}, err
+	}
+
+	query = `SELECT (julianday(completed_at) - julianday(created_at)) * 86400,
+			(julianday(completed_at) - julianday(started_at)) * 86400
+		FROM (
+			SELECT DISTINCT t.id, t.started_at, t.completed_at,
+				FIRST_VALUE(tr.at) OVER (PARTITION BY t.id ORDER BY tr.at, tr.id) AS created_at
+			FROM tasks t
+			JOIN columns c ON c.id = t.column_id
+			LEFT JOIN transitions tr ON tr.task_id = t.id
+			WHERE c.project_id = ? AND julianday(t.completed_at) >= julianday(?)
+				AND julianday(t.completed_at) < julianday(?)
+		) AS completed;`
+	rows, err = r.db.QueryContext(ctx, query, id, utc(&from), utc(&to))
+	if err != nil {
+		return model.Analytics{}, err
+	}
+	defer rows.Close()
+	var lead, cycle []float64
+	for rows.Next() {
+		var l, c sql.NullFloat64
+		if err := rows.Scan(&l, &c); err != nil {
+			return model.Analytics{}, err
+		}
+		if l.Valid {
+			lead = append(lead, l.Float64)
+		}
+		if c.Valid {
+			cycle = append(cycle, c.Float64)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return model.Analytics{}, err
+	}
+	a.LeadTime, a.CycleTime = model.NewPercentiles(lead), model.NewPercentiles(cycle)
+
+	return a, nil
+}
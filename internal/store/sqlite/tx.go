@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// querier is implemented by *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// duplicateIndices selects a row if any columns of a project or tasks of a column share
+// an index.
+const duplicateIndices = `SELECT 1 FROM columns GROUP BY project_id, "index" HAVING COUNT(*) > 1
+UNION ALL
+SELECT 1 FROM tasks GROUP BY column_id, "index" HAVING COUNT(*) > 1
+LIMIT 1;`
+
+// txRepos are the repositories bound to a transaction.
+type txRepos struct {
+	projectRepo        *projectRepo
+	columnRepo         *columnRepo
+	taskRepo           *taskRepo
+	commentRepo        *commentRepo
+	transitionRepo     *transitionRepo
+	columnSnapshotRepo *columnSnapshotRepo
+	taskLinkRepo       *taskLinkRepo
+}
+
+// InTx runs fn in a transaction. SQLite can't defer unique constraints, so uniqueness of
+// indices is checked before commit and indices can be swapped within the transaction.
+func (s *Store) InTx(ctx context.Context, fn func(store.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(&txRepos{
+		projectRepo:        newProjectRepo(tx),
+		columnRepo:         newColumnRepo(tx),
+		taskRepo:           newTaskRepo(tx),
+		commentRepo:        newCommentRepo(tx),
+		transitionRepo:     newTransitionRepo(tx),
+		columnSnapshotRepo: newColumnSnapshotRepo(tx),
+		taskLinkRepo:       newTaskLinkRepo(tx),
+	})
+	if err != nil {
+		return err
+	}
+
+	var found int
+	err = tx.QueryRowContext(ctx, duplicateIndices).Scan(&found)
+	if err == nil {
+		return store.ErrConflict
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// InSerializableTx runs fn in a transaction like InTx. Transactions are serializable as
+// the store has a single connection.
+func (s *Store) InSerializableTx(ctx context.Context, fn func(store.Tx) error) error {
+	return s.InTx(ctx, fn)
+}
+
+// Projects returns the project repository.
+func (r *txRepos) Projects() store.ProjectRepo { return r.projectRepo }
+
+// Columns returns the column repository.
+func (r *txRepos) Columns() store.ColumnRepo { return r.columnRepo }
+
+// Tasks returns the task repository.
+func (r *txRepos) Tasks() store.TaskRepo { return r.taskRepo }
+
+// Comments returns the comment repository.
+func (r *txRepos) Comments() store.CommentRepo { return r.commentRepo }
+
+// Transitions returns the transition repository.
+func (r *txRepos) Transitions() store.TransitionRepo { return r.transitionRepo }
+
+// ColumnSnapshots returns the column snapshot repository.
+func (r *txRepos) ColumnSnapshots() store.ColumnSnapshotRepo { return r.columnSnapshotRepo }
+
+// TaskLinks returns the task link repository.
+func (r *txRepos) TaskLinks() store.TaskLinkRepo { return r.taskLinkRepo }
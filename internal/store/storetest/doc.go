@@ -0,0 +1,3 @@
+// Package storetest provides the conformance suite all store.Store implementations must
+// pass, so the behaviour of stores doesn't diverge.
+package storetest
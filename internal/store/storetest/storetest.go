@@ -0,0 +1,691 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imarrche/tasker/internal/model"
+	"github.com/imarrche/tasker/internal/store"
+)
+
+// NewStore returns an opened empty store, it's called for every scenario.
+type NewStore func(t *testing.T) store.Store
+
+// Run runs all conformance scenarios against stores created with newStore.
+func Run(t *testing.T, newStore NewStore) {
+	scenarios := []struct {
+		name string
+		run  func(*testing.T, store.Store)
+	}{
+		{"CRUD", testCRUD},
+		{"NotFound", testNotFound},
+		{"InvalidReference", testInvalidReference},
+		{"CascadeDelete", testCascadeDelete},
+		{"Ordering", testOrdering},
+		{"Counts", testCounts},
+		{"IDsAreNotReused", testIDsAreNotReused},
+		{"CanceledContext", testCanceledContext},
+		{"Conflict", testConflict},
+		{"Tx", testTx},
+		{"IdempotencyKeys", testIdempotencyKeys},
+		{"Transitions", testTransitions},
+		{"ColumnSnapshots", testColumnSnapshots},
+		{"TaskLinks", testTaskLinks},
+	}
+
+	for _, sc := range scenarios {
+		sc := sc
+		t.Run(sc.name, func(t *testing.T) { sc.run(t, newStore(t)) })
+	}
+}
+
+// board is the project with its columns, tasks and comments.
+type board struct {
+	project  model.Project
+	columns  []model.Column
+	tasks    []model.Task
+	comments []model.Comment
+}
+
+// createBoard creates a project with two columns, two tasks in the first column and
+// a comment for each task.
+func createBoard(t *testing.T, s store.Store, name string) board {
+	ctx := context.Background()
+	var b board
+	var err error
+
+	b.project, err = s.Projects().Create(ctx, model.Project{Name: name, Description: "Description"})
+	require.NoError(t, err)
+	for i := 1; i <= 2; i++ {
+		c := model.Column{Name: fmt.Sprintf("%s %d", name, i), Index: i, ProjectID: b.project.ID}
+		c, err := s.Columns().Create(ctx, c)
+		require.NoError(t, err)
+		b.columns = append(b.columns, c)
+	}
+	for i := 1; i <= 2; i++ {
+		task := model.Task{Name: name, Index: i, ColumnID: b.columns[0].ID}
+		task, err = s.Tasks().Create(ctx, task)
+		require.NoError(t, err)
+		b.tasks = append(b.tasks, task)
+
+		c := model.Comment{Text: name, CreatedAt: createdAt(), TaskID: task.ID}
+		c, err = s.Comments().Create(ctx, c)
+		require.NoError(t, err)
+		b.comments = append(b.comments, c)
+	}
+
+	return b
+}
+
+// createdAt returns comment creation time all stores can keep exactly.
+func createdAt() time.Time {
+	return time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+}
+
+// normalize makes comments read from different stores comparable.
+func normalize(cs ...model.Comment) []model.Comment {
+	for i := range cs {
+		cs[i].CreatedAt = cs[i].CreatedAt.UTC()
+	}
+
+	return cs
+}
+
+// normalizeTasks makes tasks read from different stores comparable.
+func normalizeTasks(ts ...model.Task) []model.Task {
+	for i := range ts {
+		if ts[i].StartedAt != nil {
+			startedAt := ts[i].StartedAt.UTC()
+			ts[i].StartedAt = &startedAt
+		}
+		if ts[i].CompletedAt != nil {
+			completedAt := ts[i].CompletedAt.UTC()
+			ts[i].CompletedAt = &completedAt
+		}
+	}
+
+	return ts
+}
+
+func testCRUD(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b := createBoard(t, s, "Board")
+
+	p, err := s.Projects().GetByID(ctx, b.project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, b.project, p)
+	b.project.Name, b.project.SoftWIPLimits = "Renamed", true
+	_, err = s.Projects().Update(ctx, b.project)
+	assert.NoError(t, err)
+	ps, err := s.Projects().GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Project{b.project}, ps)
+
+	c, err := s.Columns().GetByIndexAndProjectID(ctx, 2, b.project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, b.columns[1], c)
+	b.columns[1].Name, b.columns[1].Type, b.columns[1].WIPLimit = "Renamed", model.ColumnTypeDone, 3
+	_, err = s.Columns().Update(ctx, b.columns[1])
+	assert.NoError(t, err)
+	c, err = s.Columns().GetByID(ctx, b.columns[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, b.columns[1], c)
+
+	// Moving the task to another column.
+	startedAt, completedAt := createdAt(), createdAt().Add(time.Hour)
+	b.tasks[1].ColumnID, b.tasks[1].Index = b.columns[1].ID, 1
+	b.tasks[1].StartedAt, b.tasks[1].CompletedAt = &startedAt, &completedAt
+	_, err = s.Tasks().Update(ctx, b.tasks[1])
+	assert.NoError(t, err)
+	task, err := s.Tasks().GetByIndexAndColumnID(ctx, 1, b.columns[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, normalizeTasks(b.tasks[1]), normalizeTasks(task))
+	ts, err := s.Tasks().GetByColumnID(ctx, b.columns[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Task{b.tasks[0]}, ts)
+
+	b.comments[0].Text = "Edited"
+	_, err = s.Comments().Update(ctx, b.comments[0])
+	assert.NoError(t, err)
+	comment, err := s.Comments().GetByID(ctx, b.comments[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, normalize(b.comments[0]), normalize(comment))
+
+	assert.NoError(t, s.Comments().DeleteByID(ctx, b.comments[0].ID))
+	cs, err := s.Comments().GetByTaskID(ctx, b.tasks[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Comment{}, cs)
+}
+
+func testNotFound(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b := createBoard(t, s, "Board")
+	missing := 1000
+
+	_, err := s.Projects().GetByID(ctx, missing)
+	assert.Equal(t, store.ErrNotFound, err, "project GetByID")
+	_, err = s.Projects().Update(ctx, model.Project{ID: missing, Name: "Project"})
+	assert.Equal(t, store.ErrNotFound, err, "project Update")
+	assert.Equal(t, store.ErrNotFound, s.Projects().DeleteByID(ctx, missing), "project DeleteByID")
+
+	_, err = s.Columns().GetByProjectID(ctx, missing)
+	assert.Equal(t, store.ErrNotFound, err, "column GetByProjectID")
+	_, err = s.Columns().GetByID(ctx, missing)
+	assert.Equal(t, store.ErrNotFound, err, "column GetByID")
+	_, err = s.Columns().GetByIndexAndProjectID(ctx, 3, b.project.ID)
+	assert.Equal(t, store.ErrNotFound, err, "column GetByIndexAndProjectID")
+	_, err = s.Columns().Update(ctx, model.Column{ID: missing, Name: "Column", ProjectID: b.project.ID})
+	assert.Equal(t, store.ErrNotFound, err, "column Update")
+	assert.Equal(t, store.ErrNotFound, s.Columns().DeleteByID(ctx, missing), "column DeleteByID")
+
+	_, err = s.Tasks().GetByColumnID(ctx, missing)
+	assert.Equal(t, store.ErrNotFound, err, "task GetByColumnID")
+	_, err = s.Tasks().GetByID(ctx, missing)
+	assert.Equal(t, store.ErrNotFound, err, "task GetByID")
+	_, err = s.Tasks().GetByIndexAndColumnID(ctx, 3, b.columns[0].ID)
+	assert.Equal(t, store.ErrNotFound, err, "task GetByIndexAndColumnID")
+	_, err = s.Tasks().Update(ctx, model.Task{ID: missing, Name: "Task", ColumnID: b.columns[0].ID})
+	assert.Equal(t, store.ErrNotFound, err, "task Update")
+	assert.Equal(t, store.ErrNotFound, s.Tasks().DeleteByID(ctx, missing), "task DeleteByID")
+
+	_, err = s.Comments().GetByTaskID(ctx, missing)
+	assert.Equal(t, store.ErrNotFound, err, "comment GetByTaskID")
+	_, err = s.Comments().GetByID(ctx, missing)
+	assert.Equal(t, store.ErrNotFound, err, "comment GetByID")
+	comment := model.Comment{ID: missing, Text: "Comment", CreatedAt: createdAt(), TaskID: b.tasks[0].ID}
+	_, err = s.Comments().Update(ctx, comment)
+	assert.Equal(t, store.ErrNotFound, err, "comment Update")
+	assert.Equal(t, store.ErrNotFound, s.Comments().DeleteByID(ctx, missing), "comment DeleteByID")
+}
+
+func testInvalidReference(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b := createBoard(t, s, "Board")
+	missing := 1000
+
+	_, err := s.Columns().Create(ctx, model.Column{Name: "Column", Index: 3, ProjectID: missing})
+	assert.Equal(t, store.ErrInvalidReference, err, "column Create")
+	b.columns[0].ProjectID = missing
+	_, err = s.Columns().Update(ctx, b.columns[0])
+	assert.Equal(t, store.ErrInvalidReference, err, "column Update")
+
+	_, err = s.Tasks().Create(ctx, model.Task{Name: "Task", Index: 3, ColumnID: missing})
+	assert.Equal(t, store.ErrInvalidReference, err, "task Create")
+	b.tasks[0].ColumnID = missing
+	_, err = s.Tasks().Update(ctx, b.tasks[0])
+	assert.Equal(t, store.ErrInvalidReference, err, "task Update")
+
+	comment := model.Comment{Text: "Comment", CreatedAt: createdAt(), TaskID: missing}
+	_, err = s.Comments().Create(ctx, comment)
+	assert.Equal(t, store.ErrInvalidReference, err, "comment Create")
+	b.comments[0].TaskID = missing
+	_, err = s.Comments().Update(ctx, b.comments[0])
+	assert.Equal(t, store.ErrInvalidReference, err, "comment Update")
+
+	// Failed mutations don't change anything.
+	cs, err := s.Columns().GetByProjectID(ctx, b.project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(cs))
+	ts, err := s.Tasks().GetByColumnID(ctx, cs[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(ts))
+}
+
+func testCascadeDelete(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	deleted, kept := createBoard(t, s, "Deleted"), createBoard(t, s, "Kept")
+
+	// Deleting a task deletes its comments.
+	assert.NoError(t, s.Tasks().DeleteByID(ctx, deleted.tasks[1].ID))
+	_, err := s.Comments().GetByID(ctx, deleted.comments[1].ID)
+	assert.Equal(t, store.ErrNotFound, err)
+
+	// Deleting a column deletes its tasks and their comments.
+	assert.NoError(t, s.Columns().DeleteByID(ctx, kept.columns[0].ID))
+	for _, task := range kept.tasks {
+		_, err := s.Tasks().GetByID(ctx, task.ID)
+		assert.Equal(t, store.ErrNotFound, err)
+	}
+	for _, c := range kept.comments {
+		_, err := s.Comments().GetByID(ctx, c.ID)
+		assert.Equal(t, store.ErrNotFound, err)
+	}
+
+	// Deleting a project deletes everything on its board.
+	assert.NoError(t, s.Projects().DeleteByID(ctx, deleted.project.ID))
+	for _, c := range deleted.columns {
+		_, err := s.Columns().GetByID(ctx, c.ID)
+		assert.Equal(t, store.ErrNotFound, err)
+	}
+	_, err = s.Tasks().GetByID(ctx, deleted.tasks[0].ID)
+	assert.Equal(t, store.ErrNotFound, err)
+	_, err = s.Comments().GetByID(ctx, deleted.comments[0].ID)
+	assert.Equal(t, store.ErrNotFound, err)
+
+	// Other boards are intact.
+	ps, err := s.Projects().GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Project{kept.project}, ps)
+	cs, err := s.Columns().GetByProjectID(ctx, kept.project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, kept.columns[1:], cs)
+}
+
+func testOrdering(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b1, b2, b3 := createBoard(t, s, "Board 1"), createBoard(t, s, "Board 2"), createBoard(t, s, "Board 3")
+
+	// Updated rows may be physically moved, lists must still be ordered by ID.
+	_, err := s.Projects().Update(ctx, b1.project)
+	assert.NoError(t, err)
+	_, err = s.Columns().Update(ctx, b2.columns[0])
+	assert.NoError(t, err)
+	_, err = s.Tasks().Update(ctx, b2.tasks[0])
+	assert.NoError(t, err)
+	_, err = s.Comments().Update(ctx, b2.comments[0])
+	assert.NoError(t, err)
+	task := model.Task{Name: "Task", Index: 3, ColumnID: b2.columns[0].ID}
+	task, err = s.Tasks().Create(ctx, task)
+	assert.NoError(t, err)
+
+	ps, err := s.Projects().GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Project{b1.project, b2.project, b3.project}, ps)
+	cs, err := s.Columns().GetByProjectID(ctx, b2.project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, b2.columns, cs)
+	ts, err := s.Tasks().GetByColumnID(ctx, b2.columns[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, append(b2.tasks, task), ts)
+	comments, err := s.Comments().GetByTaskID(ctx, b2.tasks[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, normalize(b2.comments[0]), normalize(comments...))
+
+	allTasks := append(append(append([]model.Task{}, b1.tasks...), b2.tasks...), b3.tasks...)
+	ts, err = s.Tasks().GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, append(allTasks, task), ts)
+	allComments := append(append(append([]model.Comment{}, b1.comments...), b2.comments...), b3.comments...)
+	comments, err = s.Comments().GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, normalize(allComments...), normalize(comments...))
+}
+
+func testCounts(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b1, b2 := createBoard(t, s, "Board 1"), createBoard(t, s, "Board 2")
+	_, err := s.Tasks().Create(ctx, model.Task{Name: "Task", Index: 1, ColumnID: b2.columns[1].ID})
+	require.NoError(t, err)
+	require.NoError(t, s.Tasks().DeleteByID(ctx, b1.tasks[0].ID))
+	p, err := s.Projects().Create(ctx, model.Project{Name: "Empty"})
+	require.NoError(t, err)
+
+	pcs, err := s.Projects().GetCounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ProjectCounts{
+		{ProjectID: b1.project.ID, Columns: 2, Tasks: 1},
+		{ProjectID: b2.project.ID, Columns: 2, Tasks: 3},
+		{ProjectID: p.ID, Columns: 0, Tasks: 0},
+	}, pcs)
+}
+
+func testIDsAreNotReused(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b := createBoard(t, s, "Board")
+
+	assert.NoError(t, s.Projects().DeleteByID(ctx, b.project.ID))
+	next := createBoard(t, s, "Next")
+
+	assert.Greater(t, next.project.ID, b.project.ID)
+	assert.Greater(t, next.columns[0].ID, b.columns[1].ID)
+	assert.Greater(t, next.tasks[0].ID, b.tasks[1].ID)
+	assert.Greater(t, next.comments[0].ID, b.comments[1].ID)
+}
+
+func testCanceledContext(t *testing.T, s store.Store) {
+	b := createBoard(t, s, "Board")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.Projects().GetAll(ctx)
+	assert.True(t, errors.Is(err, context.Canceled), "GetAll: %v", err)
+	_, err = s.Projects().Create(ctx, model.Project{Name: "Project"})
+	assert.True(t, errors.Is(err, context.Canceled), "Create: %v", err)
+	err = s.Comments().DeleteByID(ctx, b.comments[0].ID)
+	assert.True(t, errors.Is(err, context.Canceled), "DeleteByID: %v", err)
+
+	cs, err := s.Comments().GetByTaskID(context.Background(), b.tasks[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(cs))
+}
+
+func testConflict(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b := createBoard(t, s, "Board")
+
+	// Column names are unique within a project.
+	c := model.Column{Name: b.columns[0].Name, Index: 3, ProjectID: b.project.ID}
+	_, err := s.Columns().Create(ctx, c)
+	assert.Equal(t, store.ErrConflict, err, "column Create with duplicate name")
+	b.columns[1].Name = b.columns[0].Name
+	_, err = s.Columns().Update(ctx, b.columns[1])
+	assert.Equal(t, store.ErrConflict, err, "column Update with duplicate name")
+
+	// Indices may be duplicated within a transaction but not when it's committed.
+	err = s.InTx(ctx, func(tx store.Tx) error {
+		b.tasks[0].Index, b.tasks[1].Index = 2, 1
+		if _, err := tx.Tasks().Update(ctx, b.tasks[0]); err != nil {
+			return err
+		}
+		_, err := tx.Tasks().Update(ctx, b.tasks[1])
+		return err
+	})
+	assert.NoError(t, err, "task indices swap")
+	err = s.InTx(ctx, func(tx store.Tx) error {
+		c := model.Column{Name: "Column", Index: 1, ProjectID: b.project.ID}
+		_, err := tx.Columns().Create(ctx, c)
+		return err
+	})
+	assert.Equal(t, store.ErrConflict, err, "column with duplicate index")
+
+	cs, err := s.Columns().GetByProjectID(ctx, b.project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(cs))
+	task, err := s.Tasks().GetByIndexAndColumnID(ctx, 1, b.columns[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, b.tasks[1].ID, task.ID)
+}
+
+func testTx(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+
+	var p model.Project
+	err := s.InTx(ctx, func(tx store.Tx) error {
+		var err error
+		if p, err = tx.Projects().Create(ctx, model.Project{Name: "Committed"}); err != nil {
+			return err
+		}
+		_, err = tx.Columns().Create(ctx, model.Column{Name: "Column", Index: 1, ProjectID: p.ID})
+		return err
+	})
+	require.NoError(t, err)
+	cs, err := s.Columns().GetByProjectID(ctx, p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(cs), "committed columns")
+
+	var rolledBack model.Project
+	err = s.InTx(ctx, func(tx store.Tx) error {
+		var err error
+		if rolledBack, err = tx.Projects().Create(ctx, model.Project{Name: "Rolled back"}); err != nil {
+			return err
+		}
+		// Changes are visible within the transaction.
+		if _, err = tx.Projects().GetByID(ctx, rolledBack.ID); err != nil {
+			return err
+		}
+		if err = tx.Projects().DeleteByID(ctx, p.ID); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	assert.Equal(t, errRollback, err)
+
+	_, err = s.Projects().GetByID(ctx, rolledBack.ID)
+	assert.Equal(t, store.ErrNotFound, err, "rolled back project")
+	cs, err = s.Columns().GetByProjectID(ctx, p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(cs), "columns after rollback")
+}
+
+func testIdempotencyKeys(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	r := s.IdempotencyKeys()
+	old := model.IdempotencyKey{Key: "old", Fingerprint: "f1", Body: []byte{}, CreatedAt: createdAt()}
+	k := model.IdempotencyKey{Key: "key", Fingerprint: "f2", Body: []byte{}, CreatedAt: createdAt().Add(time.Hour)}
+
+	_, err := r.Create(ctx, old)
+	require.NoError(t, err)
+	_, err = r.Create(ctx, k)
+	require.NoError(t, err)
+	_, err = r.Create(ctx, model.IdempotencyKey{Key: "key", Fingerprint: "f3", CreatedAt: createdAt()})
+	assert.Equal(t, store.ErrConflict, err)
+
+	k.Status, k.ContentType, k.Body = 201, "application/json", []byte(`{"id": 1}`)
+	k.Headers = map[string][]string{"Location": {"/api/v1/projects/1"}}
+	_, err = r.Update(ctx, k)
+	assert.NoError(t, err)
+	stored, err := r.GetByKey(ctx, k.Key)
+	assert.NoError(t, err)
+	stored.CreatedAt = stored.CreatedAt.UTC()
+	assert.Equal(t, k, stored)
+
+	// Key taken over by another request isn't changed.
+	other := k
+	other.CreatedAt = k.CreatedAt.Add(time.Second)
+	_, err = r.Update(ctx, other)
+	assert.Equal(t, store.ErrNotFound, err)
+	assert.Equal(t, store.ErrNotFound, r.Delete(ctx, other))
+	other = k
+	other.Fingerprint = "f3"
+	assert.Equal(t, store.ErrNotFound, r.Delete(ctx, other))
+
+	// Key returned by Create can be updated.
+	now, err := r.Create(ctx, model.IdempotencyKey{Key: "now", Fingerprint: "f4", CreatedAt: time.Now()})
+	require.NoError(t, err)
+	now.Status = 204
+	_, err = r.Update(ctx, now)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Delete(ctx, now))
+
+	// Keys created at the time are kept.
+	assert.NoError(t, r.DeleteCreatedBefore(ctx, k.CreatedAt))
+	_, err = r.GetByKey(ctx, old.Key)
+	assert.Equal(t, store.ErrNotFound, err)
+	_, err = r.GetByKey(ctx, k.Key)
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.Delete(ctx, k))
+	assert.Equal(t, store.ErrNotFound, r.Delete(ctx, k))
+	_, err = r.Update(ctx, k)
+	assert.Equal(t, store.ErrNotFound, err)
+}
+
+func testTransitions(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b, other := createBoard(t, s, "Board"), createBoard(t, s, "Other")
+	at := createdAt()
+	r := s.Transitions()
+
+	// The first task is done in an hour after it's started, the second one is started.
+	done, started := b.tasks[0], b.tasks[1]
+	startedAt, completedAt := at.Add(time.Hour), at.Add(3*time.Hour)
+	done.ColumnID, done.StartedAt, done.CompletedAt = b.columns[1].ID, &startedAt, &completedAt
+	_, err := s.Tasks().Update(ctx, done)
+	require.NoError(t, err)
+	transitions := []model.Transition{
+		{TaskID: done.ID, ProjectID: b.project.ID, ToColumnID: b.columns[0].ID, At: at},
+		{TaskID: started.ID, ProjectID: b.project.ID, ToColumnID: b.columns[0].ID, At: at},
+		{
+			TaskID: done.ID, ProjectID: b.project.ID,
+			FromColumnID: b.columns[0].ID, ToColumnID: b.columns[1].ID, At: startedAt,
+		},
+		{
+			TaskID: started.ID, ProjectID: b.project.ID,
+			FromColumnID: b.columns[0].ID, ToColumnID: b.columns[1].ID, At: at.Add(2 * time.Hour),
+		},
+		{TaskID: other.tasks[0].ID, ProjectID: other.project.ID, ToColumnID: other.columns[0].ID, At: at},
+		{
+			TaskID: other.tasks[0].ID, ProjectID: other.project.ID,
+			FromColumnID: other.columns[0].ID, ToColumnID: other.columns[1].ID, At: at.Add(time.Minute),
+		},
+	}
+	for i := range transitions {
+		transitions[i], err = r.Create(ctx, transitions[i])
+		require.NoError(t, err)
+	}
+	_, err = r.Create(ctx, model.Transition{TaskID: 1000, ProjectID: b.project.ID, ToColumnID: b.columns[0].ID, At: at})
+	assert.Equal(t, store.ErrInvalidReference, err)
+
+	ts, err := r.GetByTaskID(ctx, done.ID)
+	assert.NoError(t, err)
+	for i := range ts {
+		ts[i].At = ts[i].At.UTC()
+	}
+	assert.Equal(t, []model.Transition{transitions[0], transitions[2]}, ts)
+	_, err = r.GetByTaskID(ctx, 1000)
+	assert.Equal(t, store.ErrNotFound, err)
+
+	a, err := r.Analytics(ctx, b.project.ID, at, at.Add(24*time.Hour))
+	assert.NoError(t, err)
+	require.Len(t, a.Columns, 1)
+	assert.Equal(t, b.columns[0].ID, a.Columns[0].ColumnID)
+	assert.Equal(t, 2, a.Columns[0].Count)
+	assert.InDelta(t, 1.5*3600, a.Columns[0].AverageSeconds, 0.01)
+	assert.Equal(t, 1, a.LeadTime.Count)
+	assert.InDelta(t, 3*3600, a.LeadTime.P50, 0.01)
+	assert.InDelta(t, 3*3600, a.LeadTime.P95, 0.01)
+	assert.Equal(t, 1, a.CycleTime.Count)
+	assert.InDelta(t, 2*3600, a.CycleTime.P85, 0.01)
+
+	// Stays ending and tasks completed outside of the period aren't counted.
+	a, err = r.Analytics(ctx, b.project.ID, at.Add(90*time.Minute), at.Add(3*time.Hour))
+	assert.NoError(t, err)
+	require.Len(t, a.Columns, 1)
+	assert.Equal(t, 1, a.Columns[0].Count)
+	assert.InDelta(t, 2*3600, a.Columns[0].AverageSeconds, 0.01)
+	assert.Equal(t, model.Percentiles{}, a.LeadTime)
+	assert.Equal(t, model.Percentiles{}, a.CycleTime)
+
+	// Transitions are deleted with their task.
+	require.NoError(t, s.Tasks().DeleteByID(ctx, started.ID))
+	a, err = r.Analytics(ctx, b.project.ID, at, at.Add(24*time.Hour))
+	assert.NoError(t, err)
+	require.Len(t, a.Columns, 1)
+	assert.Equal(t, 1, a.Columns[0].Count)
+	assert.InDelta(t, 3600, a.Columns[0].AverageSeconds, 0.01)
+}
+
+func testColumnSnapshots(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b, other := createBoard(t, s, "Board"), createBoard(t, s, "Other")
+	day := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	r := s.ColumnSnapshots()
+
+	snapshot := func(b board, c int, day time.Time, count int) model.ColumnSnapshot {
+		return model.ColumnSnapshot{
+			ProjectID: b.project.ID, ColumnID: b.columns[c].ID, ColumnName: b.columns[c].Name,
+			ColumnIndex: b.columns[c].Index, Day: day, Count: count,
+		}
+	}
+	snapshots := []model.ColumnSnapshot{
+		snapshot(b, 1, day, 0), snapshot(b, 0, day, 2),
+		snapshot(b, 0, day.AddDate(0, 0, 1), 1), snapshot(b, 1, day.AddDate(0, 0, 1), 1),
+		snapshot(b, 0, day.AddDate(0, 0, 2), 0), snapshot(other, 0, day, 2),
+	}
+	var err error
+	for i := range snapshots {
+		snapshots[i], err = r.Save(ctx, snapshots[i])
+		require.NoError(t, err)
+	}
+	_, err = r.Save(ctx, model.ColumnSnapshot{ProjectID: 1000, ColumnID: 1000, ColumnName: "Column", Day: day})
+	assert.Equal(t, store.ErrInvalidReference, err)
+
+	// Saving a snapshot of the same column and day replaces it.
+	replaced := snapshots[3]
+	replaced.ColumnName, replaced.Count = "Renamed", 2
+	replaced, err = r.Save(ctx, replaced)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshots[3].ID, replaced.ID)
+
+	ss, err := r.GetByProjectID(ctx, b.project.ID, day, day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	for i := range ss {
+		ss[i].Day = ss[i].Day.UTC()
+	}
+	assert.Equal(t, []model.ColumnSnapshot{snapshots[1], snapshots[0], snapshots[2], replaced}, ss)
+
+	// Snapshots are kept when their column is deleted and deleted with their project.
+	require.NoError(t, s.Columns().DeleteByID(ctx, b.columns[0].ID))
+	ss, err = r.GetByProjectID(ctx, b.project.ID, day.AddDate(0, 0, 2), day.AddDate(0, 0, 2))
+	assert.NoError(t, err)
+	assert.Len(t, ss, 1)
+	require.NoError(t, s.Projects().DeleteByID(ctx, b.project.ID))
+	ss, err = r.GetByProjectID(ctx, b.project.ID, day, day.AddDate(0, 0, 2))
+	assert.NoError(t, err)
+	assert.Empty(t, ss)
+	ss, err = r.GetByProjectID(ctx, other.project.ID, day, day)
+	assert.NoError(t, err)
+	assert.Len(t, ss, 1)
+}
+
+func testTaskLinks(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	b, other := createBoard(t, s, "Board"), createBoard(t, s, "Other")
+	r := s.TaskLinks()
+
+	p, err := s.Projects().Create(ctx, model.Project{Name: "Strict", StrictDependencies: true})
+	require.NoError(t, err)
+	p, err = s.Projects().GetByID(ctx, p.ID)
+	assert.NoError(t, err)
+	assert.True(t, p.StrictDependencies)
+
+	links := []model.TaskLink{
+		{BlockerID: b.tasks[0].ID, BlockedID: b.tasks[1].ID},
+		{BlockerID: other.tasks[0].ID, BlockedID: b.tasks[1].ID},
+	}
+	for _, l := range links {
+		created, err := r.Create(ctx, l)
+		require.NoError(t, err)
+		assert.Equal(t, l, created)
+	}
+	_, err = r.Create(ctx, links[0])
+	assert.Equal(t, store.ErrConflict, err)
+	_, err = r.Create(ctx, model.TaskLink{BlockerID: 1000, BlockedID: b.tasks[0].ID})
+	assert.Equal(t, store.ErrInvalidReference, err)
+
+	ls, err := r.GetByTaskID(ctx, b.tasks[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, links, ls)
+	ls, err = r.GetByTaskID(ctx, b.tasks[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, links[:1], ls)
+	_, err = r.GetByTaskID(ctx, 1000)
+	assert.Equal(t, store.ErrNotFound, err)
+
+	// A task is blocked until all tasks blocking it are completed.
+	blocked := func() bool {
+		task, err := s.Tasks().GetByID(ctx, b.tasks[1].ID)
+		require.NoError(t, err)
+		ts, err := s.Tasks().GetByColumnID(ctx, b.columns[0].ID)
+		require.NoError(t, err)
+		assert.False(t, ts[0].Blocked)
+		assert.Equal(t, task.Blocked, ts[1].Blocked)
+
+		return task.Blocked
+	}
+	assert.True(t, blocked())
+	completedAt := createdAt()
+	blocker := b.tasks[0]
+	blocker.CompletedAt = &completedAt
+	_, err = s.Tasks().Update(ctx, blocker)
+	require.NoError(t, err)
+	assert.True(t, blocked())
+	require.NoError(t, r.Delete(ctx, links[1]))
+	assert.False(t, blocked())
+	assert.Equal(t, store.ErrNotFound, r.Delete(ctx, links[1]))
+
+	// Links are deleted with either of their tasks.
+	_, err = r.Create(ctx, links[1])
+	require.NoError(t, err)
+	require.NoError(t, s.Tasks().DeleteByID(ctx, other.tasks[0].ID))
+	ls, err = r.GetByTaskID(ctx, b.tasks[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, links[:1], ls)
+}
@@ -0,0 +1,5 @@
+ALTER TABLE tasks DROP CONSTRAINT tasks_column_id_index_key;
+
+ALTER TABLE columns DROP CONSTRAINT columns_project_id_index_key;
+
+ALTER TABLE columns DROP CONSTRAINT columns_project_id_name_key;
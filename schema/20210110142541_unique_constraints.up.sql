@@ -0,0 +1,23 @@
+-- Boards created before the constraints may violate them. Columns sharing a name get
+-- their ID appended, indices are renumbered keeping the order by index and ID.
+UPDATE columns SET name = LEFT(name, 240) || ' (' || id || ')'
+WHERE EXISTS (
+    SELECT 1 FROM columns c WHERE c.project_id = columns.project_id AND c.name = columns.name AND c.id < columns.id
+);
+
+UPDATE columns SET index = numbered.position
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY index, id) AS position FROM columns) AS numbered
+WHERE columns.id = numbered.id AND columns.index <> numbered.position;
+
+UPDATE tasks SET index = numbered.position
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY column_id ORDER BY index, id) AS position FROM tasks) AS numbered
+WHERE tasks.id = numbered.id AND tasks.index <> numbered.position;
+
+ALTER TABLE columns ADD CONSTRAINT columns_project_id_name_key UNIQUE (project_id, name);
+
+-- Index constraints are checked on commit as moves swap indices in several statements.
+ALTER TABLE columns ADD CONSTRAINT columns_project_id_index_key UNIQUE (project_id, index)
+    DEFERRABLE INITIALLY DEFERRED;
+
+ALTER TABLE tasks ADD CONSTRAINT tasks_column_id_index_key UNIQUE (column_id, index)
+    DEFERRABLE INITIALLY DEFERRED;
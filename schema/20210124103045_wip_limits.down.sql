@@ -0,0 +1,3 @@
+ALTER TABLE columns DROP COLUMN wip_limit;
+
+ALTER TABLE projects DROP COLUMN soft_wip_limits;
@@ -0,0 +1,3 @@
+ALTER TABLE projects ADD COLUMN soft_wip_limits BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE columns ADD COLUMN wip_limit INTEGER NOT NULL DEFAULT 0;
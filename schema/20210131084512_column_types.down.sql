@@ -0,0 +1,4 @@
+ALTER TABLE tasks DROP COLUMN completed_at;
+ALTER TABLE tasks DROP COLUMN started_at;
+
+ALTER TABLE columns DROP COLUMN type;
@@ -0,0 +1,11 @@
+CREATE TABLE transitions (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INTEGER REFERENCES tasks (id) ON DELETE CASCADE NOT NULL,
+    project_id INTEGER REFERENCES projects (id) ON DELETE CASCADE NOT NULL,
+    from_column_id INTEGER NOT NULL,
+    to_column_id INTEGER NOT NULL,
+    at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX transitions_task_id_idx ON transitions (task_id);
+CREATE INDEX transitions_project_id_at_idx ON transitions (project_id, at);
@@ -0,0 +1,3 @@
+DROP TABLE task_links;
+
+ALTER TABLE projects DROP COLUMN strict_dependencies;
@@ -0,0 +1,10 @@
+ALTER TABLE projects ADD COLUMN strict_dependencies BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE task_links (
+    blocker_id INTEGER REFERENCES tasks (id) ON DELETE CASCADE NOT NULL,
+    blocked_id INTEGER REFERENCES tasks (id) ON DELETE CASCADE NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX task_links_blocked_id_idx ON task_links (blocked_id);
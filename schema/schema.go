@@ -0,0 +1,42 @@
+// Package schema embeds database schema migrations into the binary.
+package schema
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PostgreSQL contains PostgreSQL migrations.
+//
+//go:embed *.sql
+var PostgreSQL embed.FS
+
+// SQLite contains SQLite migrations in the sqlite directory.
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
+
+// Versions returns versions of the migrations in the directory of fsys in ascending
+// order. Migration files are named <version>_<title>.up.sql and .down.sql.
+func Versions(fsys fs.FS, dir string) ([]uint, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.up.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]uint, 0, len(names))
+	for _, name := range names {
+		v, err := strconv.ParseUint(strings.SplitN(path.Base(name), "_", 2)[0], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, uint(v))
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	return versions, nil
+}
@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersions(t *testing.T) {
+	pg, err := Versions(PostgreSQL, ".")
+	require.NoError(t, err)
+	sqlite, err := Versions(SQLite, "sqlite")
+	require.NoError(t, err)
+
+	require.NotEmpty(t, pg)
+	assert.Equal(t, uint(20201227073627), pg[0])
+	assert.Equal(t, pg, sqlite, "both stores have the same migrations")
+	for i := 1; i < len(pg); i++ {
+		assert.Less(t, pg[i-1], pg[i])
+	}
+}
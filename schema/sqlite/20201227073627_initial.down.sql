@@ -0,0 +1,7 @@
+DROP TABLE comments;
+
+DROP TABLE tasks;
+
+DROP TABLE columns;
+
+DROP TABLE projects;
@@ -0,0 +1,27 @@
+CREATE TABLE projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(500) NOT NULL,
+    description VARCHAR(1000)
+);
+
+CREATE TABLE columns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    "index" INTEGER NOT NULL,
+    project_id INTEGER REFERENCES projects (id) ON DELETE CASCADE NOT NULL
+);
+
+CREATE TABLE tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(500) NOT NULL,
+    description VARCHAR(5000),
+    "index" INTEGER NOT NULL,
+    column_id INTEGER REFERENCES columns (id) ON DELETE CASCADE NOT NULL
+);
+
+CREATE TABLE comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    text VARCHAR(5000) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    task_id INTEGER REFERENCES tasks (id) ON DELETE CASCADE NOT NULL
+);
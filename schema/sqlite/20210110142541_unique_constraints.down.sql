@@ -0,0 +1 @@
+DROP INDEX columns_project_id_name_key;
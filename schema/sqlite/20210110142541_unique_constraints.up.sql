@@ -0,0 +1,18 @@
+-- SQLite can't defer unique checks, uniqueness of indices is checked by Store.InTx on commit.
+
+-- Boards created before the constraints may violate them. Columns sharing a name get
+-- their ID appended, indices are renumbered keeping the order by index and ID.
+UPDATE columns SET name = name || ' (' || id || ')'
+WHERE EXISTS (
+    SELECT 1 FROM columns c WHERE c.project_id = columns.project_id AND c.name = columns.name AND c.id < columns.id
+);
+
+UPDATE columns SET "index" = numbered.position
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY "index", id) AS position FROM columns) AS numbered
+WHERE columns.id = numbered.id AND columns."index" <> numbered.position;
+
+UPDATE tasks SET "index" = numbered.position
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY column_id ORDER BY "index", id) AS position FROM tasks) AS numbered
+WHERE tasks.id = numbered.id AND tasks."index" <> numbered.position;
+
+CREATE UNIQUE INDEX columns_project_id_name_key ON columns (project_id, name);
@@ -0,0 +1,10 @@
+CREATE TABLE idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    fingerprint VARCHAR(64) NOT NULL,
+    status INTEGER NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    body BLOB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idempotency_keys_created_at_idx ON idempotency_keys (created_at);
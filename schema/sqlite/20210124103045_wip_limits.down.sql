@@ -0,0 +1,53 @@
+-- SQLite can't drop columns and migrations run in a transaction with foreign keys on, so
+-- dropping a table deletes rows referencing it. The tables are copied into new ones which
+-- reference each other, the old ones are dropped children first and the new ones renamed,
+-- which updates references to them.
+CREATE TABLE projects_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(500) NOT NULL,
+    description VARCHAR(1000)
+);
+CREATE TABLE columns_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    "index" INTEGER NOT NULL,
+    project_id INTEGER REFERENCES projects_new (id) ON DELETE CASCADE NOT NULL
+);
+CREATE TABLE tasks_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(500) NOT NULL,
+    description VARCHAR(5000),
+    "index" INTEGER NOT NULL,
+    column_id INTEGER REFERENCES columns_new (id) ON DELETE CASCADE NOT NULL
+);
+CREATE TABLE comments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    text VARCHAR(5000) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    task_id INTEGER REFERENCES tasks_new (id) ON DELETE CASCADE NOT NULL
+);
+
+INSERT INTO projects_new (id, name, description) SELECT id, name, description FROM projects;
+INSERT INTO columns_new (id, name, "index", project_id)
+    SELECT id, name, "index", project_id FROM columns;
+INSERT INTO tasks_new SELECT * FROM tasks;
+INSERT INTO comments_new SELECT * FROM comments;
+
+-- IDs of deleted rows must not be reused.
+DELETE FROM sqlite_sequence
+    WHERE name IN ('projects_new', 'columns_new', 'tasks_new', 'comments_new');
+INSERT INTO sqlite_sequence (name, seq)
+    SELECT name || '_new', seq FROM sqlite_sequence
+    WHERE name IN ('projects', 'columns', 'tasks', 'comments');
+
+DROP TABLE comments;
+DROP TABLE tasks;
+DROP TABLE columns;
+DROP TABLE projects;
+
+ALTER TABLE projects_new RENAME TO projects;
+ALTER TABLE columns_new RENAME TO columns;
+ALTER TABLE tasks_new RENAME TO tasks;
+ALTER TABLE comments_new RENAME TO comments;
+
+CREATE UNIQUE INDEX columns_project_id_name_key ON columns (project_id, name);
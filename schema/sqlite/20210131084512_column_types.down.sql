@@ -0,0 +1,44 @@
+-- SQLite can't drop columns, so columns and tasks are rebuilt the same way as in
+-- 20210124103045_wip_limits.down.sql. Comments are rebuilt too, since dropping tasks
+-- would delete them.
+CREATE TABLE columns_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    "index" INTEGER NOT NULL,
+    project_id INTEGER REFERENCES projects (id) ON DELETE CASCADE NOT NULL,
+    wip_limit INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE tasks_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(500) NOT NULL,
+    description VARCHAR(5000),
+    "index" INTEGER NOT NULL,
+    column_id INTEGER REFERENCES columns_new (id) ON DELETE CASCADE NOT NULL
+);
+CREATE TABLE comments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    text VARCHAR(5000) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    task_id INTEGER REFERENCES tasks_new (id) ON DELETE CASCADE NOT NULL
+);
+
+INSERT INTO columns_new (id, name, "index", project_id, wip_limit)
+    SELECT id, name, "index", project_id, wip_limit FROM columns;
+INSERT INTO tasks_new (id, name, description, "index", column_id)
+    SELECT id, name, description, "index", column_id FROM tasks;
+INSERT INTO comments_new SELECT * FROM comments;
+
+-- IDs of deleted rows must not be reused.
+DELETE FROM sqlite_sequence WHERE name IN ('columns_new', 'tasks_new', 'comments_new');
+INSERT INTO sqlite_sequence (name, seq)
+    SELECT name || '_new', seq FROM sqlite_sequence WHERE name IN ('columns', 'tasks', 'comments');
+
+DROP TABLE comments;
+DROP TABLE tasks;
+DROP TABLE columns;
+
+ALTER TABLE columns_new RENAME TO columns;
+ALTER TABLE tasks_new RENAME TO tasks;
+ALTER TABLE comments_new RENAME TO comments;
+
+CREATE UNIQUE INDEX columns_project_id_name_key ON columns (project_id, name);
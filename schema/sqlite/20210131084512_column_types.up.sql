@@ -0,0 +1,4 @@
+ALTER TABLE columns ADD COLUMN type VARCHAR(16) NOT NULL DEFAULT 'backlog';
+
+ALTER TABLE tasks ADD COLUMN started_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN completed_at TIMESTAMP;
@@ -0,0 +1 @@
+DROP TABLE column_snapshots;
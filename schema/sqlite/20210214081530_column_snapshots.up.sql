@@ -0,0 +1,12 @@
+CREATE TABLE column_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER REFERENCES projects (id) ON DELETE CASCADE NOT NULL,
+    column_id INTEGER NOT NULL,
+    column_name VARCHAR(255) NOT NULL,
+    column_index INTEGER NOT NULL,
+    day DATE NOT NULL,
+    count INTEGER NOT NULL,
+    UNIQUE (column_id, day)
+);
+
+CREATE INDEX column_snapshots_project_id_day_idx ON column_snapshots (project_id, day);
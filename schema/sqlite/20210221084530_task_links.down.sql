@@ -0,0 +1,88 @@
+DROP TABLE task_links;
+
+-- SQLite can't drop columns, so projects and every table referencing it directly or
+-- through another table are rebuilt the same way as in 20210124103045_wip_limits.down.sql.
+CREATE TABLE projects_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(500) NOT NULL,
+    description VARCHAR(1000),
+    soft_wip_limits BOOLEAN NOT NULL DEFAULT 0
+);
+CREATE TABLE columns_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    "index" INTEGER NOT NULL,
+    project_id INTEGER REFERENCES projects_new (id) ON DELETE CASCADE NOT NULL,
+    wip_limit INTEGER NOT NULL DEFAULT 0,
+    type VARCHAR(16) NOT NULL DEFAULT 'backlog'
+);
+CREATE TABLE tasks_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(500) NOT NULL,
+    description VARCHAR(5000),
+    "index" INTEGER NOT NULL,
+    column_id INTEGER REFERENCES columns_new (id) ON DELETE CASCADE NOT NULL,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+CREATE TABLE comments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    text VARCHAR(5000) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    task_id INTEGER REFERENCES tasks_new (id) ON DELETE CASCADE NOT NULL
+);
+CREATE TABLE transitions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER REFERENCES tasks_new (id) ON DELETE CASCADE NOT NULL,
+    project_id INTEGER REFERENCES projects_new (id) ON DELETE CASCADE NOT NULL,
+    from_column_id INTEGER NOT NULL,
+    to_column_id INTEGER NOT NULL,
+    at TIMESTAMP NOT NULL
+);
+CREATE TABLE column_snapshots_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER REFERENCES projects_new (id) ON DELETE CASCADE NOT NULL,
+    column_id INTEGER NOT NULL,
+    column_name VARCHAR(255) NOT NULL,
+    column_index INTEGER NOT NULL,
+    day DATE NOT NULL,
+    count INTEGER NOT NULL,
+    UNIQUE (column_id, day)
+);
+
+INSERT INTO projects_new (id, name, description, soft_wip_limits)
+    SELECT id, name, description, soft_wip_limits FROM projects;
+INSERT INTO columns_new (id, name, "index", project_id, wip_limit, type)
+    SELECT id, name, "index", project_id, wip_limit, type FROM columns;
+INSERT INTO tasks_new (id, name, description, "index", column_id, started_at, completed_at)
+    SELECT id, name, description, "index", column_id, started_at, completed_at FROM tasks;
+INSERT INTO comments_new SELECT * FROM comments;
+INSERT INTO transitions_new SELECT * FROM transitions;
+INSERT INTO column_snapshots_new SELECT * FROM column_snapshots;
+
+-- IDs of deleted rows must not be reused.
+DELETE FROM sqlite_sequence WHERE name IN (
+    'projects_new', 'columns_new', 'tasks_new', 'comments_new', 'transitions_new', 'column_snapshots_new'
+);
+INSERT INTO sqlite_sequence (name, seq)
+    SELECT name || '_new', seq FROM sqlite_sequence
+    WHERE name IN ('projects', 'columns', 'tasks', 'comments', 'transitions', 'column_snapshots');
+
+DROP TABLE column_snapshots;
+DROP TABLE transitions;
+DROP TABLE comments;
+DROP TABLE tasks;
+DROP TABLE columns;
+DROP TABLE projects;
+
+ALTER TABLE projects_new RENAME TO projects;
+ALTER TABLE columns_new RENAME TO columns;
+ALTER TABLE tasks_new RENAME TO tasks;
+ALTER TABLE comments_new RENAME TO comments;
+ALTER TABLE transitions_new RENAME TO transitions;
+ALTER TABLE column_snapshots_new RENAME TO column_snapshots;
+
+CREATE UNIQUE INDEX columns_project_id_name_key ON columns (project_id, name);
+CREATE INDEX transitions_task_id_idx ON transitions (task_id);
+CREATE INDEX transitions_project_id_at_idx ON transitions (project_id, at);
+CREATE INDEX column_snapshots_project_id_day_idx ON column_snapshots (project_id, day);
@@ -0,0 +1,18 @@
+-- SQLite can't drop columns, the table is copied into a new one without them. No tables
+-- reference idempotency keys.
+CREATE TABLE idempotency_keys_new (
+    key VARCHAR(255) PRIMARY KEY,
+    fingerprint VARCHAR(64) NOT NULL,
+    status INTEGER NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    body BLOB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+INSERT INTO idempotency_keys_new (key, fingerprint, status, content_type, body, created_at)
+    SELECT key, fingerprint, status, content_type, body, created_at FROM idempotency_keys;
+
+DROP TABLE idempotency_keys;
+ALTER TABLE idempotency_keys_new RENAME TO idempotency_keys;
+
+CREATE INDEX idempotency_keys_created_at_idx ON idempotency_keys (created_at);